@@ -0,0 +1,66 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package awsclient provides AWS SDK client construction shared by all AWS providers.
+// It resolves the region and identity a Component targets and caches one client per target,
+// so a single controller can manage resources across several accounts and regions.
+package awsclient
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AccessConfig selects the AWS region and identity used for a Component's resources.
+// It is embedded into every provider config, so the fields appear at the top level
+// of Component.Spec.Config. Empty fields fall back to the controller's own AWS config.
+type AccessConfig struct {
+	// Region overrides the controller's default AWS region (e.g. "eu-west-1")
+	Region string `json:"region,omitempty"`
+
+	// AssumeRoleArn is an IAM role assumed through STS before calling AWS.
+	// Use this to manage resources in another account.
+	AssumeRoleArn string `json:"assumeRoleArn,omitempty"`
+
+	// ExternalID is passed to STS AssumeRole when the role's trust policy requires it
+	ExternalID string `json:"externalId,omitempty"`
+}
+
+// Validate checks that the access settings are consistent
+func (a AccessConfig) Validate() error {
+	if a.AssumeRoleArn != "" && !isRoleArn(a.AssumeRoleArn) {
+		return fmt.Errorf("assumeRoleArn must be an IAM role ARN, got: %s", a.AssumeRoleArn)
+	}
+	if a.ExternalID != "" && a.AssumeRoleArn == "" {
+		return fmt.Errorf("externalId requires assumeRoleArn to be set")
+	}
+	return nil
+}
+
+// CheckUnchanged verifies that the access settings target the region and role a resource was created
+// with. A resource cannot follow a region or account change, so switching targets after creation would
+// create a duplicate in the new target and orphan the original. The external ID may change, e.g. when
+// it is rotated. A nil recorded value means the resource has not been created yet and any target is accepted.
+func (a AccessConfig) CheckUnchanged(recorded *AccessConfig) error {
+	if recorded == nil || (a.Region == recorded.Region && a.AssumeRoleArn == recorded.AssumeRoleArn) {
+		return nil
+	}
+	return fmt.Errorf("region and assumeRoleArn cannot be changed after creation "+
+		"(created in region %q with role %q)", recorded.Region, recorded.AssumeRoleArn)
+}
+
+// CheckUnchangedAccount is CheckUnchanged for resources of global services like IAM,
+// which live in an account but not in a region, so only the role has to stay the same
+func (a AccessConfig) CheckUnchangedAccount(recorded *AccessConfig) error {
+	if recorded == nil || a.AssumeRoleArn == recorded.AssumeRoleArn {
+		return nil
+	}
+	return fmt.Errorf("assumeRoleArn cannot be changed after creation (created with role %q)", recorded.AssumeRoleArn)
+}
+
+// isRoleArn checks for the arn:<partition>:iam::<account>:role/<name> form
+func isRoleArn(arn string) bool {
+	return strings.HasPrefix(arn, "arn:") &&
+		strings.Contains(arn, ":iam::") &&
+		strings.Contains(arn, ":role/")
+}
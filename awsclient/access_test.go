@@ -0,0 +1,82 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAwsClient(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "AWS Client Suite")
+}
+
+const testRoleArn = "arn:aws:iam::210987654321:role/provisioner"
+
+var _ = Describe("AccessConfig", func() {
+	Describe("Validate", func() {
+		It("should accept empty settings", func() {
+			Expect(AccessConfig{}.Validate()).To(Succeed())
+		})
+
+		It("should accept a role ARN with an external ID", func() {
+			access := AccessConfig{Region: "eu-west-1", AssumeRoleArn: testRoleArn, ExternalID: "staging"}
+			Expect(access.Validate()).To(Succeed())
+		})
+
+		It("should accept a role ARN with a path in another partition", func() {
+			access := AccessConfig{AssumeRoleArn: "arn:aws-us-gov:iam::210987654321:role/ops/provisioner"}
+			Expect(access.Validate()).To(Succeed())
+		})
+
+		DescribeTable("should reject ARNs that are not IAM roles",
+			func(arn string) {
+				err := AccessConfig{AssumeRoleArn: arn}.Validate()
+				Expect(err).To(MatchError(ContainSubstring("must be an IAM role ARN")))
+			},
+			Entry("plain name", "provisioner"),
+			Entry("policy ARN", "arn:aws:iam::210987654321:policy/provisioner"),
+			Entry("user ARN", "arn:aws:iam::210987654321:user/provisioner"),
+			Entry("non-IAM role-like ARN", "arn:aws:sts::210987654321:assumed-role/provisioner/session"),
+		)
+
+		It("should reject an external ID without a role", func() {
+			err := AccessConfig{ExternalID: "staging"}.Validate()
+			Expect(err).To(MatchError(ContainSubstring("externalId requires assumeRoleArn")))
+		})
+	})
+
+	Describe("CheckUnchanged", func() {
+		recorded := &AccessConfig{Region: "eu-west-1", AssumeRoleArn: testRoleArn, ExternalID: "staging"}
+
+		It("should accept any target before creation", func() {
+			Expect(AccessConfig{Region: "us-east-1"}.CheckUnchanged(nil)).To(Succeed())
+		})
+
+		It("should accept the recorded target", func() {
+			Expect(recorded.CheckUnchanged(recorded)).To(Succeed())
+		})
+
+		DescribeTable("should reject a changed target",
+			func(access AccessConfig) {
+				Expect(access.CheckUnchanged(recorded)).To(MatchError(ContainSubstring("cannot be changed after creation")))
+			},
+			Entry("region", AccessConfig{Region: "us-east-1", AssumeRoleArn: testRoleArn, ExternalID: "staging"}),
+			Entry("role", AccessConfig{Region: "eu-west-1", AssumeRoleArn: "arn:aws:iam::111111111111:role/x", ExternalID: "staging"}),
+		)
+
+		It("should accept a rotated external ID", func() {
+			Expect(AccessConfig{Region: "eu-west-1", AssumeRoleArn: testRoleArn, ExternalID: "rotated"}.CheckUnchanged(recorded)).To(Succeed())
+		})
+
+		It("should only compare the role of global resources", func() {
+			Expect(AccessConfig{Region: "us-east-1", AssumeRoleArn: testRoleArn}.CheckUnchangedAccount(recorded)).To(Succeed())
+			Expect(AccessConfig{Region: "eu-west-1"}.CheckUnchangedAccount(recorded)).To(
+				MatchError(ContainSubstring("assumeRoleArn cannot be changed after creation")))
+		})
+	})
+})
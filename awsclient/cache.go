@@ -0,0 +1,104 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+const (
+	// RoleSessionName identifies the controller in CloudTrail when assuming roles
+	RoleSessionName = "componator-aws-providers"
+)
+
+// Cache builds and caches one AWS service client per (region, role) target.
+// Clients are created lazily on first use and reused for the lifetime of the controller.
+// Assumed role credentials are refreshed automatically by the SDK credentials cache.
+type Cache[T any] struct {
+	base      aws.Config
+	endpoints Endpoints
+	service   string
+	newClient func(aws.Config) T
+
+	mu      sync.Mutex
+	clients map[AccessConfig]T
+}
+
+// NewCache creates a client cache on top of the controller's base AWS config.
+// The service identifier (e.g. ServiceRDS) selects which endpoint override applies to the clients.
+// The newClient function builds a service client (e.g. rds.NewFromConfig) from a resolved config.
+func NewCache[T any](cfg aws.Config, endpoints Endpoints, service string, newClient func(aws.Config) T) *Cache[T] {
+	return &Cache[T]{
+		base:      cfg,
+		endpoints: endpoints,
+		service:   service,
+		newClient: newClient,
+		clients:   make(map[AccessConfig]T),
+	}
+}
+
+// Get returns the client for the given access settings, creating it on first use
+func (c *Cache[T]) Get(access AccessConfig) T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if client, ok := c.clients[access]; ok {
+		return client
+	}
+
+	client := c.newClient(c.Config(access))
+	c.clients[access] = client
+	return client
+}
+
+// Region returns the region the given access settings resolve to
+func (c *Cache[T]) Region(access AccessConfig) string {
+	if access.Region != "" {
+		return access.Region
+	}
+	return c.base.Region
+}
+
+// Resolve returns the access settings with the region filled in from the controller default.
+// Providers record the resolved settings in their status so that later operations keep targeting
+// the same region even if the controller's default region changes.
+func (c *Cache[T]) Resolve(access AccessConfig) AccessConfig {
+	access.Region = c.Region(access)
+	return access
+}
+
+// Config resolves the AWS config for the given access settings.
+// The region override is applied first so that STS is called in the target region.
+// Endpoint overrides are applied separately to the STS client and to the service client.
+// Both clients report API call metrics.
+func (c *Cache[T]) Config(access AccessConfig) aws.Config {
+	cfg := c.base.Copy()
+	InstrumentConfig(&cfg)
+
+	if access.Region != "" {
+		cfg.Region = access.Region
+	}
+
+	if access.AssumeRoleArn != "" {
+		stsCfg := cfg.Copy()
+		c.endpoints.Configure(&stsCfg, ServiceSTS)
+
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(stsCfg), access.AssumeRoleArn,
+			func(o *stscreds.AssumeRoleOptions) {
+				o.RoleSessionName = RoleSessionName
+				if access.ExternalID != "" {
+					o.ExternalID = aws.String(access.ExternalID)
+				}
+			})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	c.endpoints.Configure(&cfg, c.service)
+
+	return cfg
+}
@@ -0,0 +1,90 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cache", func() {
+	var (
+		base    aws.Config
+		built   []aws.Config
+		clients *Cache[int]
+	)
+
+	BeforeEach(func() {
+		base = aws.Config{
+			Region:      "us-east-1",
+			Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		}
+		built = nil
+		clients = NewCache(base, Endpoints{}, ServiceRDS, func(cfg aws.Config) int {
+			built = append(built, cfg)
+			return len(built)
+		})
+	})
+
+	Describe("Get", func() {
+		It("should build one client per target and reuse it", func() {
+			first := clients.Get(AccessConfig{})
+			Expect(clients.Get(AccessConfig{})).To(Equal(first))
+			Expect(built).To(HaveLen(1))
+		})
+
+		It("should key clients by region, role and external ID", func() {
+			targets := []AccessConfig{
+				{},
+				{Region: "eu-west-1"},
+				{Region: "eu-west-1", AssumeRoleArn: testRoleArn},
+				{Region: "eu-west-1", AssumeRoleArn: testRoleArn, ExternalID: "staging"},
+				{Region: "eu-west-1", AssumeRoleArn: testRoleArn, ExternalID: "prod"},
+			}
+
+			seen := map[int]bool{}
+			for _, target := range targets {
+				seen[clients.Get(target)] = true
+			}
+			Expect(seen).To(HaveLen(len(targets)))
+
+			for _, target := range targets {
+				clients.Get(target)
+			}
+			Expect(built).To(HaveLen(len(targets)))
+		})
+	})
+
+	Describe("Config", func() {
+		It("should keep the base config without overrides", func() {
+			cfg := clients.Config(AccessConfig{})
+			Expect(cfg.Region).To(Equal("us-east-1"))
+			Expect(cfg.Credentials).To(Equal(base.Credentials))
+		})
+
+		It("should override the region", func() {
+			cfg := clients.Config(AccessConfig{Region: "eu-west-1"})
+			Expect(cfg.Region).To(Equal("eu-west-1"))
+			Expect(cfg.Credentials).To(Equal(base.Credentials))
+			Expect(base.Region).To(Equal("us-east-1"))
+		})
+
+		It("should replace credentials with an assumed role", func() {
+			cfg := clients.Config(AccessConfig{Region: "eu-west-1", AssumeRoleArn: testRoleArn, ExternalID: "staging"})
+			Expect(cfg.Region).To(Equal("eu-west-1"))
+			Expect(cfg.Credentials).To(BeAssignableToTypeOf(&aws.CredentialsCache{}))
+			Expect(cfg.Credentials).NotTo(Equal(base.Credentials))
+		})
+	})
+
+	Describe("Resolve", func() {
+		It("should fill in the default region", func() {
+			Expect(clients.Resolve(AccessConfig{AssumeRoleArn: testRoleArn})).To(Equal(
+				AccessConfig{Region: "us-east-1", AssumeRoleArn: testRoleArn}))
+			Expect(clients.Resolve(AccessConfig{Region: "eu-west-1"}).Region).To(Equal("eu-west-1"))
+		})
+	})
+})
@@ -0,0 +1,172 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go"
+)
+
+// CloudWatch reads metric statistics through the CloudWatch Query API.
+// The providers only read the latest value of a few metrics, so this small client
+// is used instead of the full CloudWatch SDK client. It reports API call metrics
+// like the SDK clients built by Cache.
+type CloudWatch struct {
+	cfg    aws.Config
+	signer *v4.Signer
+	now    func() time.Time
+}
+
+// NewCloudWatch creates a CloudWatch client from a resolved AWS config, e.g. one built by Cache.Config
+func NewCloudWatch(cfg aws.Config) *CloudWatch {
+	return &CloudWatch{cfg: cfg, signer: v4.NewSigner(), now: time.Now}
+}
+
+// LatestMaximum returns the maximum of the most recent datapoint of the metric series selected by
+// the namespace, name and dimensions. Each datapoint covers one period; the query looks back five
+// periods and returns false if the series has no datapoints in that time.
+func (c *CloudWatch) LatestMaximum(
+	ctx context.Context,
+	namespace, metricName string,
+	dimensions map[string]string,
+	period time.Duration) (float64, bool, error) {
+
+	start := time.Now()
+	value, ok, err := c.getMetricStatistics(ctx, namespace, metricName, dimensions, period)
+	apiCalls.WithLabelValues("CloudWatch", "GetMetricStatistics", errorCode(err)).Inc()
+	apiCallDuration.WithLabelValues("CloudWatch", "GetMetricStatistics").Observe(time.Since(start).Seconds())
+	return value, ok, err
+}
+
+func (c *CloudWatch) getMetricStatistics(
+	ctx context.Context,
+	namespace, metricName string,
+	dimensions map[string]string,
+	period time.Duration) (float64, bool, error) {
+
+	if period < time.Minute {
+		period = time.Minute
+	}
+	end := c.now().UTC().Truncate(time.Minute)
+
+	form := url.Values{
+		"Action":              {"GetMetricStatistics"},
+		"Version":             {"2010-08-01"},
+		"Namespace":           {namespace},
+		"MetricName":          {metricName},
+		"StartTime":           {end.Add(-5 * period).Format(time.RFC3339)},
+		"EndTime":             {end.Format(time.RFC3339)},
+		"Period":              {strconv.Itoa(int(period.Seconds()))},
+		"Statistics.member.1": {"Maximum"},
+	}
+	n := 1
+	for name, value := range dimensions {
+		form.Set(fmt.Sprintf("Dimensions.member.%d.Name", n), name)
+		form.Set(fmt.Sprintf("Dimensions.member.%d.Value", n), value)
+		n++
+	}
+
+	body, err := c.call(ctx, form.Encode())
+	if err != nil {
+		return 0, false, err
+	}
+
+	var response struct {
+		Datapoints []struct {
+			Timestamp time.Time `xml:"Timestamp"`
+			Maximum   float64   `xml:"Maximum"`
+		} `xml:"GetMetricStatisticsResult>Datapoints>member"`
+	}
+	if err := xml.Unmarshal(body, &response); err != nil {
+		return 0, false, fmt.Errorf("failed to decode CloudWatch response: %w", err)
+	}
+
+	// Datapoints come back unordered
+	latest := -1
+	for i, datapoint := range response.Datapoints {
+		if latest < 0 || datapoint.Timestamp.After(response.Datapoints[latest].Timestamp) {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return 0, false, nil
+	}
+	return response.Datapoints[latest].Maximum, true, nil
+}
+
+// call sends a signed Query API request and returns the response body of a successful call
+func (c *CloudWatch) call(ctx context.Context, payload string) ([]byte, error) {
+	endpoint := fmt.Sprintf("https://monitoring.%s.amazonaws.com/", c.cfg.Region)
+	if c.cfg.BaseEndpoint != nil {
+		endpoint = aws.ToString(c.cfg.BaseEndpoint)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	if c.cfg.Credentials == nil {
+		return nil, fmt.Errorf("no AWS credentials configured for CloudWatch")
+	}
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256([]byte(payload))
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "monitoring", c.cfg.Region, c.now()); err != nil {
+		return nil, fmt.Errorf("failed to sign CloudWatch request: %w", err)
+	}
+
+	var client aws.HTTPClient = http.DefaultClient
+	if c.cfg.HTTPClient != nil {
+		client = c.cfg.HTTPClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("CloudWatch request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CloudWatch response: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, queryError(resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// queryError converts a Query API error response into an API error,
+// so that callers classify and report it like SDK errors
+func queryError(statusCode int, body []byte) error {
+	var response struct {
+		Code    string `xml:"Error>Code"`
+		Message string `xml:"Error>Message"`
+	}
+	if err := xml.Unmarshal(body, &response); err != nil || response.Code == "" {
+		return &smithy.GenericAPIError{Code: http.StatusText(statusCode), Message: strings.TrimSpace(string(body))}
+	}
+
+	fault := smithy.FaultClient
+	if statusCode >= 500 {
+		fault = smithy.FaultServer
+	}
+	return &smithy.GenericAPIError{Code: response.Code, Message: response.Message, Fault: fault}
+}
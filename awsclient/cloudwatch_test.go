@@ -0,0 +1,99 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const metricStatisticsResponse = `<GetMetricStatisticsResponse xmlns="http://monitoring.amazonaws.com/doc/2010-08-01/">
+  <GetMetricStatisticsResult>
+    <Label>ReplicaLag</Label>
+    <Datapoints>
+      <member><Timestamp>2025-01-01T11:58:00Z</Timestamp><Maximum>42.0</Maximum><Unit>Seconds</Unit></member>
+      <member><Timestamp>2025-01-01T11:59:00Z</Timestamp><Maximum>7.5</Maximum><Unit>Seconds</Unit></member>
+      <member><Timestamp>2025-01-01T11:57:00Z</Timestamp><Maximum>90.0</Maximum><Unit>Seconds</Unit></member>
+    </Datapoints>
+  </GetMetricStatisticsResult>
+</GetMetricStatisticsResponse>`
+
+const throttlingResponse = `<ErrorResponse xmlns="http://monitoring.amazonaws.com/doc/2010-08-01/">
+  <Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error>
+</ErrorResponse>`
+
+var _ = Describe("CloudWatch", func() {
+	var (
+		status   int
+		response string
+		form     url.Values
+		header   http.Header
+		client   *CloudWatch
+	)
+
+	BeforeEach(func() {
+		status, response = http.StatusOK, metricStatisticsResponse
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.ParseForm()).To(Succeed())
+			form, header = r.PostForm, r.Header
+			w.Header().Set("Content-Type", "text/xml")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(response))
+		}))
+		DeferCleanup(server.Close)
+
+		cfg := aws.Config{
+			Region:      "us-east-1",
+			Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		}
+		Endpoints{Services: map[string]string{ServiceCloudWatch: server.URL}}.Configure(&cfg, ServiceCloudWatch)
+		client = NewCloudWatch(cfg)
+		client.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 30, 0, time.UTC) }
+	})
+
+	latestLag := func() (float64, bool, error) {
+		return client.LatestMaximum(context.Background(), "AWS/RDS", "ReplicaLag",
+			map[string]string{"DBInstanceIdentifier": "reports"}, time.Minute)
+	}
+
+	It("should return the maximum of the latest datapoint", func() {
+		value, ok, err := latestLag()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(7.5))
+
+		Expect(form.Get("Action")).To(Equal("GetMetricStatistics"))
+		Expect(form.Get("Namespace")).To(Equal("AWS/RDS"))
+		Expect(form.Get("Dimensions.member.1.Value")).To(Equal("reports"))
+		Expect(form.Get("StartTime")).To(Equal("2025-01-01T11:55:00Z"))
+		Expect(form.Get("EndTime")).To(Equal("2025-01-01T12:00:00Z"))
+		Expect(form.Get("Period")).To(Equal("60"))
+		Expect(header.Get("Authorization")).To(ContainSubstring("/us-east-1/monitoring/aws4_request"))
+	})
+
+	It("should report a metric without datapoints as missing", func() {
+		response = `<GetMetricStatisticsResponse><GetMetricStatisticsResult><Datapoints/></GetMetricStatisticsResult></GetMetricStatisticsResponse>`
+		_, ok, err := latestLag()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should return API errors with their code", func() {
+		status, response = http.StatusBadRequest, throttlingResponse
+		_, _, err := latestLag()
+
+		Expect(err).To(BeAssignableToTypeOf(&smithy.GenericAPIError{}))
+		Expect(err).To(MatchError(ContainSubstring("Rate exceeded")))
+		Expect(errorCode(err)).To(Equal("Throttling"))
+	})
+})
@@ -0,0 +1,49 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rinswind/componator/componentkit/controller"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DriftReporter turns out-of-band changes found by health checks into health results
+type DriftReporter struct {
+	events    *EventRecorder
+	remediate bool
+}
+
+// NewDriftReporter creates a reporter that restores the configured state when remediate is set,
+// and otherwise reports drift as Degraded
+func NewDriftReporter(events *EventRecorder, remediate bool) *DriftReporter {
+	return &DriftReporter{events: events, remediate: remediate}
+}
+
+// Report handles one drift found on the named Component. fix restores the configured state
+// in AWS; a nil fix marks drift that cannot be remediated by the health check.
+func (r *DriftReporter) Report(
+	ctx context.Context, name types.NamespacedName, reason, message string, fix func() error) (*controller.HealthCheckResult, error) {
+
+	log := logf.FromContext(ctx).WithValues("reason", reason)
+
+	if !r.remediate || fix == nil {
+		log.Info("Drift detected", "message", message)
+		r.events.Warning(ctx, name, ReasonDriftDetected, "%s", message)
+		return controller.HealthCheckDegraded(reason, message)
+	}
+
+	log.Info("Remediating drift", "message", message)
+	if err := fix(); err != nil {
+		message = fmt.Sprintf("%s; remediation failed: %v", message, err)
+		r.events.Warning(ctx, name, ReasonDriftDetected, "%s", message)
+		return controller.HealthCheckDegraded(reason, message)
+	}
+
+	r.events.Normal(ctx, name, ReasonDriftRemediated, "%s; restored the configured state", message)
+	return controller.HealthCheckHealthy("Remediated drift: " + message)
+}
@@ -0,0 +1,81 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rinswind/componator/componentkit/controller"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/rinswind/componator-aws-providers/awsfake"
+)
+
+var _ = Describe("DriftReporter", func() {
+	var (
+		ctx    context.Context
+		name   types.NamespacedName
+		fake   *record.FakeRecorder
+		events *EventRecorder
+		fixed  bool
+		fix    func() error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "default", Name: "app"}
+		fake = record.NewFakeRecorder(10)
+		events = NewEventRecorder(awsfake.NewComponents(name), fake)
+		fixed = false
+		fix = func() error {
+			fixed = true
+			return nil
+		}
+	})
+
+	It("should report drift as degraded without remediation", func() {
+		result, err := NewDriftReporter(events, false).Report(ctx, name, "ThingDrift", "thing changed", fix)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("ThingDrift", "thing changed")
+		Expect(result).To(Equal(degraded))
+		Expect(fixed).To(BeFalse())
+		Expect(fake.Events).To(Receive(Equal("Warning DriftDetected thing changed")))
+	})
+
+	It("should remediate drift when enabled", func() {
+		result, err := NewDriftReporter(events, true).Report(ctx, name, "ThingDrift", "thing changed", fix)
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Remediated drift: thing changed")
+		Expect(result).To(Equal(healthy))
+		Expect(fixed).To(BeTrue())
+		Expect(fake.Events).To(Receive(Equal("Normal DriftRemediated thing changed; restored the configured state")))
+	})
+
+	It("should stay degraded when remediation fails or is impossible", func() {
+		reporter := NewDriftReporter(events, true)
+
+		result, err := reporter.Report(ctx, name, "ThingDrift", "thing changed", func() error { return errors.New("denied") })
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("ThingDrift", "thing changed; remediation failed: denied")
+		Expect(result).To(Equal(degraded))
+
+		result, err = reporter.Report(ctx, name, "ThingGone", "thing deleted", nil)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ = controller.HealthCheckDegraded("ThingGone", "thing deleted")
+		Expect(result).To(Equal(degraded))
+	})
+})
+
+var _ = Describe("DecodePolicyDocument", func() {
+	It("should decode URL-encoded documents and keep JSON as is", func() {
+		document := `{"Version":"2012-10-17","Statement":[]}`
+		Expect(DecodePolicyDocument("%7B%22Version%22%3A%222012-10-17%22%2C%22Statement%22%3A%5B%5D%7D")).To(Equal(document))
+		Expect(DecodePolicyDocument(document)).To(Equal(document))
+		Expect(DecodePolicyDocument("%zz")).To(Equal("%zz"))
+	})
+})
@@ -0,0 +1,163 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// Service identifiers used as keys for per-service endpoint overrides
+const (
+	ServiceRDS            = "rds"
+	ServiceIAM            = "iam"
+	ServiceSecretsManager = "secretsmanager"
+	ServiceSTS            = "sts"
+	ServiceCloudWatch     = "cloudwatch"
+)
+
+// knownServices lists the services that accept endpoint overrides
+var knownServices = []string{ServiceRDS, ServiceIAM, ServiceSecretsManager, ServiceSTS, ServiceCloudWatch}
+
+// Endpoints redirects AWS API calls away from the default AWS endpoints.
+// This is used to run the providers against LocalStack or a similar local emulator.
+// The zero value leaves the SDK's default endpoint resolution untouched.
+type Endpoints struct {
+	// URL is the base endpoint for all services (e.g. "http://localstack:4566")
+	URL string
+
+	// Services holds per-service endpoint overrides keyed by service identifier
+	// (ServiceRDS, ServiceIAM, ...). A service override takes precedence over URL.
+	Services map[string]string
+
+	// InsecureSkipVerify disables TLS certificate verification.
+	// Only intended for local emulators serving self-signed certificates.
+	InsecureSkipVerify bool
+}
+
+// Configure applies the endpoint settings for one service to an AWS config.
+// The service override wins over URL; without either the config's own endpoint is kept.
+func (e Endpoints) Configure(cfg *aws.Config, service string) {
+	if endpoint := e.ServiceURL(service); endpoint != nil {
+		cfg.BaseEndpoint = endpoint
+	} else if e.URL != "" {
+		cfg.BaseEndpoint = aws.String(e.URL)
+	}
+
+	if e.InsecureSkipVerify {
+		cfg.HTTPClient = skipTLSVerify(cfg.HTTPClient)
+	}
+}
+
+// skipTLSVerify returns a copy of the HTTP client that skips TLS certificate verification, keeping
+// its timeouts, proxy and other transport settings. Without a client it builds the SDK default one.
+// Clients whose transport cannot be reached are returned as they are.
+func skipTLSVerify(client aws.HTTPClient) aws.HTTPClient {
+	skip := func(tr *http.Transport) {
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		tr.TLSClientConfig.InsecureSkipVerify = true // #nosec G402 -- explicit opt-in for local emulators
+	}
+
+	switch c := client.(type) {
+	case nil:
+		return awshttp.NewBuildableClient().WithTransportOptions(skip)
+	case *awshttp.BuildableClient:
+		return c.WithTransportOptions(skip)
+	case *http.Client:
+		transport := c.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		tr, ok := transport.(*http.Transport)
+		if !ok {
+			return client
+		}
+		tr = tr.Clone()
+		skip(tr)
+		clone := *c
+		clone.Transport = tr
+		return &clone
+	default:
+		return client
+	}
+}
+
+// ServiceURL returns the endpoint override for a single service, or nil if there is none
+func (e Endpoints) ServiceURL(service string) *string {
+	if endpoint, ok := e.Services[service]; ok && endpoint != "" {
+		return aws.String(endpoint)
+	}
+	return nil
+}
+
+// IsSet reports whether any endpoint override is configured
+func (e Endpoints) IsSet() bool {
+	return e.URL != "" || len(e.Services) > 0
+}
+
+// Validate checks that all configured endpoints are absolute URLs for known services
+func (e Endpoints) Validate() error {
+	if e.URL != "" {
+		if err := validateEndpointURL(e.URL); err != nil {
+			return fmt.Errorf("invalid AWS endpoint URL: %w", err)
+		}
+	}
+	for service, endpoint := range e.Services {
+		if !slices.Contains(knownServices, service) {
+			return fmt.Errorf("unknown service %q in AWS endpoint overrides, must be one of %v", service, knownServices)
+		}
+		if err := validateEndpointURL(endpoint); err != nil {
+			return fmt.Errorf("invalid AWS endpoint URL for service %s: %w", service, err)
+		}
+	}
+	return nil
+}
+
+// ParseServiceEndpoints parses per-service overrides in the form "rds=http://host:4566,iam=http://host:4566"
+func ParseServiceEndpoints(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	services := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		service, endpoint, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || service == "" || endpoint == "" {
+			return nil, fmt.Errorf("service endpoint %q must have the form service=url", entry)
+		}
+
+		service = strings.ToLower(service)
+		if !slices.Contains(knownServices, service) {
+			return nil, fmt.Errorf("unknown service %q in service endpoint %q, must be one of %v", service, entry, knownServices)
+		}
+		if _, duplicate := services[service]; duplicate {
+			return nil, fmt.Errorf("service %q has more than one endpoint", service)
+		}
+		services[service] = endpoint
+	}
+	return services, nil
+}
+
+func validateEndpointURL(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%s: scheme must be http or https", endpoint)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("%s: host is required", endpoint)
+	}
+	return nil
+}
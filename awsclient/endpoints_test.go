@@ -0,0 +1,195 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const assumeRoleResponse = `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASIAEMULATOR</AccessKeyId>
+      <SecretAccessKey>emulator-secret</SecretAccessKey>
+      <SessionToken>emulator-token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::210987654321:assumed-role/provisioner/componator-aws-providers</Arn>
+      <AssumedRoleId>AROAEMULATOR:componator-aws-providers</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`
+
+var _ = Describe("Endpoints", func() {
+	Describe("Validate", func() {
+		It("should accept empty settings", func() {
+			Expect(Endpoints{}.Validate()).To(Succeed())
+		})
+
+		It("should accept a base URL with service overrides", func() {
+			endpoints := Endpoints{
+				URL:      "http://localstack:4566",
+				Services: map[string]string{ServiceIAM: "https://iam.local:8443", ServiceSTS: "http://sts.local"},
+			}
+			Expect(endpoints.Validate()).To(Succeed())
+		})
+
+		DescribeTable("should reject invalid endpoints",
+			func(endpoints Endpoints, message string) {
+				Expect(endpoints.Validate()).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("missing scheme", Endpoints{URL: "localstack:4566"}, "scheme must be http or https"),
+			Entry("unsupported scheme", Endpoints{URL: "ftp://localstack"}, "scheme must be http or https"),
+			Entry("missing host", Endpoints{URL: "http://"}, "host is required"),
+			Entry("invalid service URL",
+				Endpoints{Services: map[string]string{ServiceRDS: "rds.local"}}, "for service rds"),
+			Entry("unknown service",
+				Endpoints{Services: map[string]string{"s3": "http://localstack:4566"}}, `unknown service "s3"`),
+		)
+	})
+
+	Describe("ParseServiceEndpoints", func() {
+		It("should return nil for an empty value", func() {
+			Expect(ParseServiceEndpoints("")).To(BeNil())
+		})
+
+		It("should parse service=url pairs", func() {
+			services, err := ParseServiceEndpoints("rds=http://rds.local:4566, IAM=http://iam.local:4566")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(services).To(Equal(map[string]string{
+				ServiceRDS: "http://rds.local:4566",
+				ServiceIAM: "http://iam.local:4566",
+			}))
+		})
+
+		DescribeTable("should reject malformed values",
+			func(value, message string) {
+				_, err := ParseServiceEndpoints(value)
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("missing separator", "rds", "must have the form service=url"),
+			Entry("missing URL", "rds=", "must have the form service=url"),
+			Entry("missing service", "=http://localstack:4566", "must have the form service=url"),
+			Entry("unknown service", "s3=http://localstack:4566", `unknown service "s3"`),
+			Entry("duplicate service", "rds=http://a:4566,rds=http://b:4566", "more than one endpoint"),
+		)
+	})
+
+	Describe("Configure", func() {
+		endpoints := Endpoints{
+			URL:      "http://localstack:4566",
+			Services: map[string]string{ServiceIAM: "http://iam.local:4566"},
+		}
+
+		It("should prefer the service override over the base URL", func() {
+			cfg := aws.Config{}
+			endpoints.Configure(&cfg, ServiceIAM)
+			Expect(cfg.BaseEndpoint).To(HaveValue(Equal("http://iam.local:4566")))
+		})
+
+		It("should fall back to the base URL", func() {
+			cfg := aws.Config{}
+			endpoints.Configure(&cfg, ServiceRDS)
+			Expect(cfg.BaseEndpoint).To(HaveValue(Equal("http://localstack:4566")))
+		})
+
+		It("should leave the endpoint unset without overrides", func() {
+			cfg := aws.Config{}
+			Endpoints{}.Configure(&cfg, ServiceRDS)
+			Expect(cfg.BaseEndpoint).To(BeNil())
+			Expect(cfg.HTTPClient).To(BeNil())
+		})
+
+		It("should install an HTTP client that skips TLS verification", func() {
+			cfg := aws.Config{}
+			Endpoints{InsecureSkipVerify: true}.Configure(&cfg, ServiceRDS)
+			Expect(cfg.HTTPClient).NotTo(BeNil())
+		})
+
+		It("should keep the settings of the loaded HTTP client when skipping TLS verification", func() {
+			loaded := awshttp.NewBuildableClient().WithTimeout(7 * time.Second)
+			cfg := aws.Config{HTTPClient: loaded}
+			Endpoints{InsecureSkipVerify: true}.Configure(&cfg, ServiceRDS)
+
+			client, ok := cfg.HTTPClient.(*awshttp.BuildableClient)
+			Expect(ok).To(BeTrue())
+			Expect(client.GetTimeout()).To(Equal(7 * time.Second))
+			Expect(client.GetTransport().TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+			Expect(loaded.GetTransport().TLSClientConfig).To(BeNil())
+
+			proxy := http.ProxyURL(&url.URL{Scheme: "http", Host: "proxy:3128"})
+			cfg = aws.Config{HTTPClient: &http.Client{Transport: &http.Transport{Proxy: proxy}}}
+			Endpoints{InsecureSkipVerify: true}.Configure(&cfg, ServiceRDS)
+
+			transport := cfg.HTTPClient.(*http.Client).Transport.(*http.Transport)
+			Expect(transport.Proxy).NotTo(BeNil())
+			Expect(transport.TLSClientConfig.InsecureSkipVerify).To(BeTrue())
+		})
+	})
+
+	Describe("Cache", func() {
+		var base aws.Config
+
+		BeforeEach(func() {
+			base = aws.Config{
+				Region:      "us-east-1",
+				Credentials: credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+			}
+		})
+
+		It("should apply the endpoint of the cache's service", func() {
+			endpoints := Endpoints{
+				URL:      "http://localstack:4566",
+				Services: map[string]string{ServiceRDS: "http://rds.local:4566"},
+			}
+			rdsClients := NewCache(base, endpoints, ServiceRDS, func(cfg aws.Config) aws.Config { return cfg })
+			iamClients := NewCache(base, endpoints, ServiceIAM, func(cfg aws.Config) aws.Config { return cfg })
+
+			Expect(rdsClients.Get(AccessConfig{}).BaseEndpoint).To(HaveValue(Equal("http://rds.local:4566")))
+			Expect(iamClients.Get(AccessConfig{}).BaseEndpoint).To(HaveValue(Equal("http://localstack:4566")))
+			Expect(base.BaseEndpoint).To(BeNil())
+		})
+
+		It("should assume roles through the STS endpoint override", func() {
+			var requests []*http.Request
+			sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.ParseForm()).To(Succeed())
+				requests = append(requests, r)
+				w.Header().Set("Content-Type", "text/xml")
+				_, _ = w.Write([]byte(assumeRoleResponse))
+			}))
+			DeferCleanup(sts.Close)
+
+			endpoints := Endpoints{
+				URL:      "http://rds.local:4566",
+				Services: map[string]string{ServiceSTS: sts.URL},
+			}
+			clients := NewCache(base, endpoints, ServiceRDS, func(cfg aws.Config) aws.Config { return cfg })
+
+			cfg := clients.Get(AccessConfig{AssumeRoleArn: testRoleArn, ExternalID: "staging"})
+			Expect(cfg.BaseEndpoint).To(HaveValue(Equal("http://rds.local:4566")))
+
+			creds, err := cfg.Credentials.Retrieve(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(creds.AccessKeyID).To(Equal("ASIAEMULATOR"))
+
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].PostForm.Get("Action")).To(Equal("AssumeRole"))
+			Expect(requests[0].PostForm.Get("RoleArn")).To(Equal(testRoleArn))
+			Expect(requests[0].PostForm.Get("ExternalId")).To(Equal("staging"))
+			Expect(requests[0].PostForm.Get("RoleSessionName")).To(Equal(RoleSessionName))
+		})
+	})
+})
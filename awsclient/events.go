@@ -0,0 +1,123 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	v1beta1 "github.com/rinswind/componator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Event reasons recorded on Components
+const (
+	ReasonCreated      = "Created"
+	ReasonUpdated      = "Updated"
+	ReasonDeleted      = "Deleted"
+	ReasonRetained     = "Retained"
+	ReasonAccessDenied = "AccessDenied"
+	ReasonThrottled    = "Throttled"
+	ReasonFailed       = "Failed"
+
+	ReasonAdopted         = "Adopted"
+	ReasonAdoptionRefused = "AdoptionRefused"
+
+	ReasonDriftDetected   = "DriftDetected"
+	ReasonDriftRemediated = "DriftRemediated"
+)
+
+// accessDeniedCodes are the AWS error codes reported when the caller lacks permissions
+var accessDeniedCodes = []string{
+	"AccessDenied",
+	"AccessDeniedException",
+	"UnauthorizedOperation",
+	"UnrecognizedClientException",
+	"InvalidClientTokenId",
+	"ExpiredToken",
+}
+
+// ComponentReader fetches Components; it is satisfied by the manager's client.Reader
+type ComponentReader interface {
+	Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error
+}
+
+// EventRecorder records Kubernetes Events on the Component a provider is working on,
+// so that `kubectl describe component` shows what the provider changed in AWS.
+// A nil recorder discards all events.
+type EventRecorder struct {
+	reader   ComponentReader
+	recorder record.EventRecorder
+}
+
+// NewEventRecorder creates a recorder that looks up Components through the given reader
+func NewEventRecorder(reader ComponentReader, recorder record.EventRecorder) *EventRecorder {
+	return &EventRecorder{reader: reader, recorder: recorder}
+}
+
+// Normal records a Normal event, used for changes made in AWS
+func (r *EventRecorder) Normal(ctx context.Context, name types.NamespacedName, reason, messageFmt string, args ...any) {
+	r.record(ctx, name, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+// Warning records a Warning event, used for problems that need attention
+func (r *EventRecorder) Warning(ctx context.Context, name types.NamespacedName, reason, messageFmt string, args ...any) {
+	r.record(ctx, name, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
+
+// AWSError records a Warning for permission and throttling errors.
+// Other errors are reported through the Component's conditions only.
+func (r *EventRecorder) AWSError(ctx context.Context, name types.NamespacedName, err error) {
+	if reason := errorReason(err); reason != "" {
+		r.Warning(ctx, name, reason, "%v", err)
+	}
+}
+
+func (r *EventRecorder) record(
+	ctx context.Context, name types.NamespacedName, eventType, reason, messageFmt string, args ...any) {
+
+	if r == nil {
+		return
+	}
+
+	// Events must reference the live object (with its UID) to show up on the Component
+	component := &v1beta1.Component{}
+	if err := r.reader.Get(ctx, name, component); err != nil {
+		logf.FromContext(ctx).V(1).Info("Cannot record event, Component not found",
+			"reason", reason, "message", fmt.Sprintf(messageFmt, args...), "error", err.Error())
+		return
+	}
+	r.recorder.Eventf(component, eventType, reason, messageFmt, args...)
+}
+
+// errorReason maps an AWS error to a Warning event reason, or "" if it does not warrant an event
+func errorReason(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		for _, code := range accessDeniedCodes {
+			if apiErr.ErrorCode() == code {
+				return ReasonAccessDenied
+			}
+		}
+	}
+
+	for _, throttle := range retry.DefaultThrottles {
+		if throttle.IsErrorThrottle(err) == aws.TrueTernary {
+			return ReasonThrottled
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/rinswind/componator-aws-providers/awsfake"
+)
+
+var _ = Describe("EventRecorder", func() {
+	var (
+		ctx      context.Context
+		name     types.NamespacedName
+		fake     *record.FakeRecorder
+		recorder *EventRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "default", Name: "app"}
+		fake = record.NewFakeRecorder(10)
+		recorder = NewEventRecorder(awsfake.NewComponents(name), fake)
+	})
+
+	It("should record events on an existing Component", func() {
+		recorder.Normal(ctx, name, ReasonCreated, "Created %s", "thing")
+		recorder.Warning(ctx, name, ReasonFailed, "Broken %s", "thing")
+		Expect(fake.Events).To(Receive(Equal("Normal Created Created thing")))
+		Expect(fake.Events).To(Receive(Equal("Warning Failed Broken thing")))
+	})
+
+	It("should skip events for a missing Component", func() {
+		recorder.Normal(ctx, types.NamespacedName{Namespace: "default", Name: "gone"}, ReasonDeleted, "Deleted")
+		Expect(fake.Events).NotTo(Receive())
+	})
+
+	It("should discard events on a nil recorder", func() {
+		var nilRecorder *EventRecorder
+		Expect(func() { nilRecorder.Normal(ctx, name, ReasonCreated, "Created") }).NotTo(Panic())
+	})
+
+	DescribeTable("should warn only about permission and throttling errors",
+		func(err error, expected string) {
+			recorder.AWSError(ctx, name, err)
+			if expected == "" {
+				Expect(fake.Events).NotTo(Receive())
+				return
+			}
+			Expect(fake.Events).To(Receive(HavePrefix("Warning " + expected + " ")))
+		},
+		Entry("access denied", &smithy.GenericAPIError{Code: "AccessDenied"}, ReasonAccessDenied),
+		Entry("wrapped access denied",
+			fmt.Errorf("failed: %w", &smithy.GenericAPIError{Code: "AccessDeniedException"}), ReasonAccessDenied),
+		Entry("throttling", &smithy.GenericAPIError{Code: "Throttling"}, ReasonThrottled),
+		Entry("other API error", &smithy.GenericAPIError{Code: "DBInstanceNotFound"}, ""),
+		Entry("plain error", errors.New("boom"), ""),
+		Entry("nil error", nil, ""),
+	)
+})
@@ -0,0 +1,70 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+)
+
+// LoadSettings customizes how the base AWS config is loaded.
+// The zero value uses the default credential chain and region with SDK retries disabled.
+type LoadSettings struct {
+	// Region overrides the default region (AWS_REGION, shared config or EC2 metadata)
+	Region string
+
+	// Profile selects a named profile from the shared config and credentials files
+	Profile string
+
+	// MaxRetries is the number of SDK retries per AWS call.
+	// Zero leaves retrying to the controller's requeue.
+	MaxRetries int
+}
+
+// LoadConfig validates the endpoint overrides and loads the controller's base AWS config.
+// Endpoint overrides are applied per service by Cache, not to the base config.
+//
+// Uses the default credential chain (environment variables, IRSA, EC2 instance metadata, etc.)
+// and auto-detects the region from EC2 metadata when running in EKS.
+func LoadConfig(ctx context.Context, settings LoadSettings, endpoints Endpoints) (aws.Config, error) {
+	if err := endpoints.Validate(); err != nil {
+		return aws.Config{}, err
+	}
+	if settings.MaxRetries < 0 {
+		return aws.Config{}, fmt.Errorf("AWS max retries must not be negative, got %d", settings.MaxRetries)
+	}
+
+	loadOpts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRetryMaxAttempts(settings.MaxRetries + 1),
+		awsconfig.WithEC2IMDSRegion(),
+	}
+	if settings.Region != "" {
+		loadOpts = append(loadOpts, awsconfig.WithRegion(settings.Region))
+	}
+	if settings.Profile != "" {
+		loadOpts = append(loadOpts, awsconfig.WithSharedConfigProfile(settings.Profile))
+	}
+
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// BaseConfig returns the prebuilt config supplied by an embedding program, or loads the default one.
+// The endpoint overrides are validated either way since Cache applies them on top of the base config.
+func BaseConfig(ctx context.Context, prebuilt *aws.Config, endpoints Endpoints) (aws.Config, error) {
+	if prebuilt == nil {
+		return LoadConfig(ctx, LoadSettings{}, endpoints)
+	}
+	if err := endpoints.Validate(); err != nil {
+		return aws.Config{}, err
+	}
+	return prebuilt.Copy(), nil
+}
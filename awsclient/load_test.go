@@ -0,0 +1,28 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BaseConfig", func() {
+	It("should use a prebuilt config as is", func() {
+		prebuilt := aws.Config{Region: "eu-central-1", RetryMaxAttempts: 5}
+		cfg, err := BaseConfig(context.Background(), &prebuilt, Endpoints{URL: "http://localstack:4566"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Region).To(Equal("eu-central-1"))
+		Expect(cfg.RetryMaxAttempts).To(Equal(5))
+		Expect(cfg.BaseEndpoint).To(BeNil())
+	})
+
+	It("should validate endpoints for a prebuilt config", func() {
+		_, err := BaseConfig(context.Background(), &aws.Config{}, Endpoints{URL: "localstack"})
+		Expect(err).To(MatchError(ContainSubstring("invalid AWS endpoint URL")))
+	})
+})
@@ -0,0 +1,164 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// MetricsNamespace prefixes all metrics exported by the providers
+	MetricsNamespace = "componator_aws"
+
+	metricsMiddlewareID = "ComponatorMetrics"
+)
+
+var (
+	apiCalls = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Name:      "api_calls_total",
+		Help:      "AWS API calls by service, operation and error code (empty for successful calls).",
+	}, []string{"service", "operation", "error_code"})
+
+	apiCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: MetricsNamespace,
+		Name:      "api_call_duration_seconds",
+		Help:      "AWS API call latency by service and operation, including SDK retries.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "operation"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(apiCalls, apiCallDuration)
+}
+
+// InstrumentConfig adds API call metrics to every client built from the config.
+// Cache instruments the clients it builds, so this is only needed for clients created elsewhere;
+// instrumenting a config twice counts every call twice.
+func InstrumentConfig(cfg *aws.Config) {
+	// Clone so that configs copied from the same base do not share the appended element
+	cfg.APIOptions = append(slices.Clone(cfg.APIOptions), addMetricsMiddleware)
+}
+
+func addMetricsMiddleware(stack *middleware.Stack) error {
+	// After the generated Initialize middlewares, so the service and operation names are in the context.
+	// Initialize runs once per call, so the latency includes SDK retries.
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(metricsMiddlewareID, recordMetrics), middleware.After)
+}
+
+func recordMetrics(
+	ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (middleware.InitializeOutput, middleware.Metadata, error) {
+
+	start := time.Now()
+	out, metadata, err := next.HandleInitialize(ctx, in)
+
+	service := awsmiddleware.GetServiceID(ctx)
+	operation := awsmiddleware.GetOperationName(ctx)
+	apiCalls.WithLabelValues(service, operation, errorCode(err)).Inc()
+	apiCallDuration.WithLabelValues(service, operation).Observe(time.Since(start).Seconds())
+
+	return out, metadata, err
+}
+
+// errorCode returns the AWS error code of a failed call, or a generic code for transport failures
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	var canceled *aws.RequestCanceledError
+	if errors.As(err, &canceled) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return "RequestCanceled"
+	}
+	return "ClientError"
+}
+
+// StateGauge counts Components by their current state, e.g. RDS instances by status.
+// Each Component is counted under exactly one state; providers call Set whenever they
+// observe a state and Delete once the resource is gone. The counts are rebuilt from
+// reconciles after a controller restart.
+type StateGauge struct {
+	gauge *prometheus.GaugeVec
+
+	mu     sync.Mutex
+	states map[string]string
+}
+
+// NewStateGauge creates a gauge named componator_aws_<name> with a single state label
+func NewStateGauge(name, help, label string) *StateGauge {
+	return &StateGauge{
+		gauge: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: MetricsNamespace,
+			Name:      name,
+			Help:      help,
+		}, []string{label}),
+		states: make(map[string]string),
+	}
+}
+
+// Set records the current state of a Component
+func (g *StateGauge) Set(component, state string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if previous, ok := g.states[component]; ok {
+		if previous == state {
+			return
+		}
+		g.gauge.WithLabelValues(previous).Dec()
+	}
+	g.states[component] = state
+	g.gauge.WithLabelValues(state).Inc()
+}
+
+// Delete stops counting a Component
+func (g *StateGauge) Delete(component string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if previous, ok := g.states[component]; ok {
+		g.gauge.WithLabelValues(previous).Dec()
+		delete(g.states, component)
+	}
+}
+
+// Count returns how many Components are currently in the given state
+func (g *StateGauge) Count(state string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	count := 0
+	for _, s := range g.states {
+		if s == state {
+			count++
+		}
+	}
+	return count
+}
+
+// Describe implements prometheus.Collector
+func (g *StateGauge) Describe(ch chan<- *prometheus.Desc) {
+	g.gauge.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (g *StateGauge) Collect(ch chan<- prometheus.Metric) {
+	g.gauge.Collect(ch)
+}
@@ -0,0 +1,121 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const callerIdentityResponse = `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <GetCallerIdentityResult>
+    <Arn>arn:aws:iam::123456789012:user/tester</Arn>
+    <UserId>AIDAEMULATOR</UserId>
+    <Account>123456789012</Account>
+  </GetCallerIdentityResult>
+</GetCallerIdentityResponse>`
+
+const accessDeniedResponse = `<ErrorResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <Error><Type>Sender</Type><Code>AccessDenied</Code><Message>not authorized</Message></Error>
+</ErrorResponse>`
+
+// metricValue reads the current value of a counter, gauge or histogram sample count
+func metricValue(metric prometheus.Metric) float64 {
+	m := &dto.Metric{}
+	Expect(metric.Write(m)).To(Succeed())
+	switch {
+	case m.Counter != nil:
+		return m.Counter.GetValue()
+	case m.Gauge != nil:
+		return m.Gauge.GetValue()
+	default:
+		return float64(m.Histogram.GetSampleCount())
+	}
+}
+
+var _ = Describe("Metrics", func() {
+	Describe("InstrumentConfig", func() {
+		var (
+			status int
+			client *sts.Client
+		)
+
+		BeforeEach(func() {
+			status = http.StatusOK
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/xml")
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					_, _ = w.Write([]byte(callerIdentityResponse))
+				} else {
+					_, _ = w.Write([]byte(accessDeniedResponse))
+				}
+			}))
+			DeferCleanup(server.Close)
+
+			cfg := aws.Config{
+				Region:           "us-east-1",
+				Credentials:      credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+				RetryMaxAttempts: 1,
+			}
+			client = NewSTSClient(cfg, Endpoints{Services: map[string]string{ServiceSTS: server.URL}})
+		})
+
+		It("should count calls by service, operation and error code", func() {
+			succeeded := apiCalls.WithLabelValues("STS", "GetCallerIdentity", "")
+			denied := apiCalls.WithLabelValues("STS", "GetCallerIdentity", "AccessDenied")
+			latency := apiCallDuration.WithLabelValues("STS", "GetCallerIdentity").(prometheus.Metric)
+			before := []float64{metricValue(succeeded), metricValue(denied), metricValue(latency)}
+
+			_, err := client.GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+			Expect(err).NotTo(HaveOccurred())
+
+			status = http.StatusForbidden
+			_, err = client.GetCallerIdentity(context.Background(), &sts.GetCallerIdentityInput{})
+			Expect(err).To(HaveOccurred())
+
+			Expect(metricValue(succeeded) - before[0]).To(Equal(1.0))
+			Expect(metricValue(denied) - before[1]).To(Equal(1.0))
+			Expect(metricValue(latency) - before[2]).To(Equal(2.0))
+		})
+
+		It("should not share options between configs copied from one base", func() {
+			base := aws.Config{}
+			first, second := base.Copy(), base.Copy()
+			InstrumentConfig(&first)
+			Expect(first.APIOptions).To(HaveLen(1))
+			Expect(second.APIOptions).To(BeEmpty())
+			Expect(base.APIOptions).To(BeEmpty())
+		})
+	})
+
+	Describe("StateGauge", func() {
+		It("should count each component under its latest state", func() {
+			gauge := NewStateGauge("test_resources", "Test resources by state.", "state")
+			value := func(state string) float64 { return metricValue(gauge.gauge.WithLabelValues(state)) }
+
+			gauge.Set("ns/a", "creating")
+			gauge.Set("ns/b", "creating")
+			gauge.Set("ns/a", "available")
+			gauge.Set("ns/a", "available")
+			Expect(value("creating")).To(Equal(1.0))
+			Expect(value("available")).To(Equal(1.0))
+			Expect(gauge.Count("creating")).To(Equal(1))
+
+			gauge.Delete("ns/a")
+			gauge.Delete("ns/missing")
+			Expect(value("available")).To(Equal(0.0))
+			Expect(value("creating")).To(Equal(1.0))
+		})
+	})
+})
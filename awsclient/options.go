@@ -0,0 +1,116 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator/componentkit/controller"
+)
+
+// ProviderOptions are the registration settings every provider accepts. Providers embed it in their
+// own options next to their typed clients and expose the With* functions below as their options.
+// Zero timings keep the framework defaults.
+type ProviderOptions struct {
+	ProviderName string
+	AWSConfig    *aws.Config
+	Endpoints    Endpoints
+	DryRun       bool
+	ClusterID    string
+
+	ErrorClassifier     controller.ErrorClassifier
+	HealthCheckInterval time.Duration
+	ErrorRequeue        time.Duration
+	DefaultRequeue      time.Duration
+	StatusCheckRequeue  time.Duration
+}
+
+// NewProviderOptions returns the shared settings of a provider registered without options
+func NewProviderOptions(providerName string, classifier controller.ErrorClassifier) ProviderOptions {
+	return ProviderOptions{ProviderName: providerName, ErrorClassifier: classifier}
+}
+
+// Shared returns the shared settings of provider options embedding ProviderOptions
+func (o *ProviderOptions) Shared() *ProviderOptions {
+	return o
+}
+
+// SharedOptions is implemented by pointers to provider options embedding ProviderOptions
+type SharedOptions interface {
+	Shared() *ProviderOptions
+}
+
+// WithProviderName sets the unique name used for Component claiming.
+// An empty name keeps the provider's default name.
+func WithProviderName[O SharedOptions](name string) func(O) {
+	return func(o O) {
+		if name != "" {
+			o.Shared().ProviderName = name
+		}
+	}
+}
+
+// WithAWSConfig uses a prebuilt AWS config instead of loading the default credential chain
+func WithAWSConfig[O SharedOptions](cfg aws.Config) func(O) {
+	return func(o O) {
+		o.Shared().AWSConfig = &cfg
+	}
+}
+
+// WithEndpoints redirects AWS calls to a local emulator such as LocalStack
+func WithEndpoints[O SharedOptions](endpoints Endpoints) func(O) {
+	return func(o O) {
+		o.Shared().Endpoints = endpoints
+	}
+}
+
+// WithDryRun plans every Component instead of applying it, as if all carried DryRunAnnotation
+func WithDryRun[O SharedOptions](enabled bool) func(O) {
+	return func(o O) {
+		o.Shared().DryRun = enabled
+	}
+}
+
+// WithClusterID sets the cluster identity stamped into the ownership tags of created resources
+func WithClusterID[O SharedOptions](id string) func(O) {
+	return func(o O) {
+		o.Shared().ClusterID = id
+	}
+}
+
+// WithErrorClassifier decides which AWS errors are retried (true) and which fail the Component (false)
+func WithErrorClassifier[O SharedOptions](classifier controller.ErrorClassifier) func(O) {
+	return func(o O) {
+		o.Shared().ErrorClassifier = classifier
+	}
+}
+
+// WithHealthCheckInterval sets how often Ready Components are checked
+func WithHealthCheckInterval[O SharedOptions](d time.Duration) func(O) {
+	return func(o O) {
+		o.Shared().HealthCheckInterval = d
+	}
+}
+
+// WithErrorRequeue sets the requeue delay after retryable errors
+func WithErrorRequeue[O SharedOptions](d time.Duration) func(O) {
+	return func(o O) {
+		o.Shared().ErrorRequeue = d
+	}
+}
+
+// WithDefaultRequeue sets the default requeue delay
+func WithDefaultRequeue[O SharedOptions](d time.Duration) func(O) {
+	return func(o O) {
+		o.Shared().DefaultRequeue = d
+	}
+}
+
+// WithStatusCheckRequeue sets the delay between progress checks
+func WithStatusCheckRequeue[O SharedOptions](d time.Duration) func(O) {
+	return func(o O) {
+		o.Shared().StatusCheckRequeue = d
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// testOptions stands in for the options of a provider
+type testOptions struct {
+	ProviderOptions
+
+	client string
+}
+
+var _ = Describe("ProviderOptions", func() {
+	apply := func(opts ...func(*testOptions)) testOptions {
+		o := testOptions{ProviderOptions: NewProviderOptions("test", nil)}
+		for _, opt := range opts {
+			opt(&o)
+		}
+		return o
+	}
+
+	It("should keep the default name when given an empty one", func() {
+		Expect(apply(WithProviderName[*testOptions]("")).ProviderName).To(Equal("test"))
+		Expect(apply(WithProviderName[*testOptions]("wordpress-test")).ProviderName).To(Equal("wordpress-test"))
+	})
+
+	It("should set the shared settings of the embedding options", func() {
+		endpoints := Endpoints{URL: "http://localstack:4566"}
+		o := apply(
+			WithAWSConfig[*testOptions](aws.Config{Region: "eu-west-1"}),
+			WithEndpoints[*testOptions](endpoints),
+			WithDryRun[*testOptions](true),
+			WithClusterID[*testOptions]("prod"),
+			WithHealthCheckInterval[*testOptions](time.Minute),
+			WithErrorRequeue[*testOptions](time.Second),
+			WithDefaultRequeue[*testOptions](2*time.Second),
+			WithStatusCheckRequeue[*testOptions](3*time.Second),
+			func(o *testOptions) { o.client = "injected" },
+		)
+		Expect(o.AWSConfig.Region).To(Equal("eu-west-1"))
+		Expect(o.Endpoints).To(Equal(endpoints))
+		Expect(o.DryRun).To(BeTrue())
+		Expect(o.ClusterID).To(Equal("prod"))
+		Expect(o.HealthCheckInterval).To(Equal(time.Minute))
+		Expect(o.ErrorRequeue).To(Equal(time.Second))
+		Expect(o.DefaultRequeue).To(Equal(2 * time.Second))
+		Expect(o.StatusCheckRequeue).To(Equal(3 * time.Second))
+		Expect(o.client).To(Equal("injected"))
+	})
+})
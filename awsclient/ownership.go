@@ -0,0 +1,154 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Ownership tags stamped on every AWS resource a provider creates or adopts
+const (
+	TagClusterID = "componator.io/cluster-id"
+	TagComponent = "componator.io/component"
+	TagProvider  = "componator.io/provider"
+)
+
+// DefaultClusterID identifies the cluster in ownership tags when none is configured
+const DefaultClusterID = "default"
+
+// Adoption policies for AWS resources that exist before the Component manages them
+const (
+	// AdoptionNever only manages resources tagged as owned by the Component (default)
+	AdoptionNever = "Never"
+	// AdoptionIfUntagged also adopts resources without ownership tags
+	AdoptionIfUntagged = "IfUntagged"
+	// AdoptionAlways also adopts resources owned by another Component, cluster or provider
+	AdoptionAlways = "Always"
+)
+
+// Deletion policies for the AWS resource when its Component is deleted
+const (
+	// DeletionPolicyDelete deletes the resource (default)
+	DeletionPolicyDelete = "Delete"
+	// DeletionPolicyRetain leaves the resource in place and removes its ownership tags,
+	// so that another Component can adopt it
+	DeletionPolicyRetain = "Retain"
+)
+
+// ResolveDeletionPolicy validates a deletionPolicy setting, defaulting to DeletionPolicyDelete
+func ResolveDeletionPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return DeletionPolicyDelete, nil
+	case DeletionPolicyDelete, DeletionPolicyRetain:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid deletionPolicy %q: must be one of %s, %s",
+			policy, DeletionPolicyDelete, DeletionPolicyRetain)
+	}
+}
+
+// ResolveAdoptionPolicy validates an adoptionPolicy setting, defaulting to AdoptionNever
+func ResolveAdoptionPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return AdoptionNever, nil
+	case AdoptionNever, AdoptionIfUntagged, AdoptionAlways:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid adoptionPolicy %q: must be one of %s, %s, %s",
+			policy, AdoptionNever, AdoptionIfUntagged, AdoptionAlways)
+	}
+}
+
+// Ownership identifies the resources a provider instance owns through AWS tags.
+// A nil Ownership stamps no tags and allows managing any resource.
+type Ownership struct {
+	clusterID string
+	provider  string
+}
+
+// NewOwnership creates the Ownership of a provider in a cluster.
+// An empty clusterID uses DefaultClusterID.
+func NewOwnership(clusterID, provider string) *Ownership {
+	if clusterID == "" {
+		clusterID = DefaultClusterID
+	}
+	return &Ownership{clusterID: clusterID, provider: provider}
+}
+
+// Tags returns the ownership tags for resources of the named Component
+func (o *Ownership) Tags(name types.NamespacedName) map[string]string {
+	if o == nil {
+		return nil
+	}
+	return map[string]string{
+		TagClusterID: o.clusterID,
+		TagComponent: name.String(),
+		TagProvider:  o.provider,
+	}
+}
+
+// WithTags merges the ownership tags into user tags; ownership tags take precedence
+func (o *Ownership) WithTags(name types.NamespacedName, tags map[string]string) map[string]string {
+	owned := o.Tags(name)
+	if len(owned) == 0 {
+		return tags
+	}
+
+	merged := make(map[string]string, len(tags)+len(owned))
+	for k, v := range tags {
+		merged[k] = v
+	}
+	for k, v := range owned {
+		merged[k] = v
+	}
+	return merged
+}
+
+// TagKeys returns the keys of the ownership tags, for removing them from retained resources
+func (o *Ownership) TagKeys() []string {
+	if o == nil {
+		return nil
+	}
+	return []string{TagClusterID, TagComponent, TagProvider}
+}
+
+// Owns reports whether the resource tags mark it as owned by the named Component
+func (o *Ownership) Owns(name types.NamespacedName, tags map[string]string) bool {
+	if o == nil {
+		return true
+	}
+	for k, v := range o.Tags(name) {
+		if tags[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckAdoption decides whether the named Component may manage an existing resource with the given tags.
+// It returns true when the resource has to be (re)tagged before it is managed, and an error when
+// the adoption policy does not allow taking it over.
+func (o *Ownership) CheckAdoption(name types.NamespacedName, tags map[string]string, policy string) (bool, error) {
+	if o.Owns(name, tags) {
+		return false, nil
+	}
+
+	owner, tagged := tags[TagComponent]
+	switch {
+	case !tagged && (policy == AdoptionIfUntagged || policy == AdoptionAlways):
+		return true, nil
+	case tagged && policy == AdoptionAlways:
+		return true, nil
+	case !tagged:
+		return false, fmt.Errorf("resource has no ownership tags; set adoptionPolicy to %s or %s to adopt it",
+			AdoptionIfUntagged, AdoptionAlways)
+	default:
+		return false, fmt.Errorf("resource is owned by Component %s (cluster %q, provider %q); set adoptionPolicy to %s to take it over",
+			owner, tags[TagClusterID], tags[TagProvider], AdoptionAlways)
+	}
+}
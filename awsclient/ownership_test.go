@@ -0,0 +1,82 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Ownership", func() {
+	var (
+		name      types.NamespacedName
+		ownership *Ownership
+	)
+
+	BeforeEach(func() {
+		name = types.NamespacedName{Namespace: "default", Name: "app"}
+		ownership = NewOwnership("prod", "rds")
+	})
+
+	It("should stamp cluster, Component and provider tags", func() {
+		Expect(ownership.Tags(name)).To(Equal(map[string]string{
+			TagClusterID: "prod",
+			TagComponent: "default/app",
+			TagProvider:  "rds",
+		}))
+		Expect(ownership.WithTags(name, map[string]string{"team": "data", TagProvider: "spoofed"})).To(Equal(map[string]string{
+			"team":       "data",
+			TagClusterID: "prod",
+			TagComponent: "default/app",
+			TagProvider:  "rds",
+		}))
+		Expect(NewOwnership("", "rds").Tags(name)).To(HaveKeyWithValue(TagClusterID, DefaultClusterID))
+	})
+
+	DescribeTable("should decide adoption by tags and policy",
+		func(tags map[string]string, policy string, adopt bool, refused bool) {
+			result, err := ownership.CheckAdoption(name, tags, policy)
+			Expect(result).To(Equal(adopt))
+			if refused {
+				Expect(err).To(HaveOccurred())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		},
+		Entry("owned", map[string]string{TagClusterID: "prod", TagComponent: "default/app", TagProvider: "rds"}, AdoptionNever, false, false),
+		Entry("untagged, never", map[string]string{"team": "data"}, AdoptionNever, false, true),
+		Entry("untagged, if untagged", nil, AdoptionIfUntagged, true, false),
+		Entry("untagged, always", nil, AdoptionAlways, true, false),
+		Entry("foreign, if untagged", map[string]string{TagClusterID: "staging", TagComponent: "default/app", TagProvider: "rds"}, AdoptionIfUntagged, false, true),
+		Entry("foreign, always", map[string]string{TagClusterID: "prod", TagComponent: "other/app", TagProvider: "rds"}, AdoptionAlways, true, false),
+	)
+
+	It("should name the foreign owner when refusing", func() {
+		_, err := ownership.CheckAdoption(name, map[string]string{TagClusterID: "staging", TagComponent: "team/db", TagProvider: "rds"}, AdoptionNever)
+		Expect(err).To(MatchError(ContainSubstring(`owned by Component team/db (cluster "staging", provider "rds")`)))
+	})
+
+	It("should manage everything without ownership", func() {
+		var none *Ownership
+		Expect(none.Tags(name)).To(BeNil())
+		Expect(none.TagKeys()).To(BeNil())
+		Expect(none.WithTags(name, map[string]string{"team": "data"})).To(Equal(map[string]string{"team": "data"}))
+		Expect(none.CheckAdoption(name, nil, AdoptionNever)).To(BeFalse())
+	})
+
+	It("should validate adoption policies", func() {
+		Expect(ResolveAdoptionPolicy("")).To(Equal(AdoptionNever))
+		Expect(ResolveAdoptionPolicy(AdoptionAlways)).To(Equal(AdoptionAlways))
+		_, err := ResolveAdoptionPolicy("Sometimes")
+		Expect(err).To(MatchError(ContainSubstring(`invalid adoptionPolicy "Sometimes"`)))
+	})
+
+	It("should validate deletion policies", func() {
+		Expect(ResolveDeletionPolicy("")).To(Equal(DeletionPolicyDelete))
+		Expect(ResolveDeletionPolicy(DeletionPolicyRetain)).To(Equal(DeletionPolicyRetain))
+		_, err := ResolveDeletionPolicy("Orphan")
+		Expect(err).To(MatchError(ContainSubstring(`invalid deletionPolicy "Orphan"`)))
+	})
+})
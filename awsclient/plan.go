@@ -0,0 +1,170 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1beta1 "github.com/rinswind/componator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DryRunAnnotation set to "true" on a Component makes its provider plan changes instead of applying them
+const DryRunAnnotation = "aws.componator.io/dry-run"
+
+// Plan actions
+const (
+	PlanCreate   = "Create"
+	PlanUpdate   = "Update"
+	PlanNoChange = "NoChange"
+	PlanDelete   = "Delete"
+	PlanRetain   = "Retain"
+)
+
+// Change actions
+const (
+	ChangeAdd    = "Add"
+	ChangeModify = "Modify"
+	ChangeRemove = "Remove"
+)
+
+// Change is one difference between the Component config and the current AWS state.
+// Values are omitted for sensitive fields such as secret contents.
+type Change struct {
+	Field   string `json:"field"`
+	Action  string `json:"action"`
+	Current string `json:"current,omitempty"`
+	Desired string `json:"desired,omitempty"`
+}
+
+// Plan is what an apply would do in AWS. Providers write it to status in dry-run mode
+// instead of calling mutating APIs.
+type Plan struct {
+	Action   string   `json:"action"`
+	Resource string   `json:"resource"`
+	Changes  []Change `json:"changes,omitempty"`
+}
+
+// NewPlan starts a plan for a resource; it is a Create if the resource does not exist yet
+func NewPlan(resource string, exists bool) *Plan {
+	action := PlanCreate
+	if exists {
+		action = PlanNoChange
+	}
+	return &Plan{Action: action, Resource: resource}
+}
+
+// NewDeletePlan creates a plan for deleting a resource
+func NewDeletePlan(resource string) *Plan {
+	return &Plan{Action: PlanDelete, Resource: resource}
+}
+
+// NewRetainPlan creates a plan for releasing a resource that is kept in AWS
+func NewRetainPlan(resource string) *Plan {
+	return &Plan{Action: PlanRetain, Resource: resource}
+}
+
+// Add records a field or item that would be added
+func (p *Plan) Add(field, desired string) {
+	p.change(Change{Field: field, Action: ChangeAdd, Desired: desired})
+}
+
+// Modify records a field that would change
+func (p *Plan) Modify(field, current, desired string) {
+	p.change(Change{Field: field, Action: ChangeModify, Current: current, Desired: desired})
+}
+
+// Remove records a field or item that would be removed
+func (p *Plan) Remove(field, current string) {
+	p.change(Change{Field: field, Action: ChangeRemove, Current: current})
+}
+
+func (p *Plan) change(c Change) {
+	p.Changes = append(p.Changes, c)
+	if p.Action == PlanNoChange {
+		p.Action = PlanUpdate
+	}
+}
+
+// String summarizes the plan for Component details and logs
+func (p *Plan) String() string {
+	switch p.Action {
+	case PlanNoChange:
+		return fmt.Sprintf("%s is up to date", p.Resource)
+	case PlanDelete:
+		return fmt.Sprintf("would delete %s", p.Resource)
+	case PlanRetain:
+		return fmt.Sprintf("would retain %s and remove its ownership tags", p.Resource)
+	default:
+		return fmt.Sprintf("would %s %s (%d changes)", strings.ToLower(p.Action), p.Resource, len(p.Changes))
+	}
+}
+
+// Diff records a change when desired is set and differs from current.
+// Unset desired values are left to AWS and never reported.
+func Diff[T comparable](p *Plan, field string, current, desired *T) {
+	switch {
+	case desired == nil:
+		return
+	case current == nil:
+		p.Add(field, fmt.Sprint(*desired))
+	case *current != *desired:
+		p.Modify(field, fmt.Sprint(*current), fmt.Sprint(*desired))
+	}
+}
+
+// DryRun decides whether changes to a Component are only planned.
+// A nil DryRun never plans.
+type DryRun struct {
+	reader ComponentReader
+	always bool
+}
+
+// NewDryRun creates a DryRun that plans every Component when always is set,
+// and otherwise only Components carrying DryRunAnnotation
+func NewDryRun(reader ComponentReader, always bool) *DryRun {
+	return &DryRun{reader: reader, always: always}
+}
+
+// Enabled reports whether changes to the named Component must only be planned
+func (d *DryRun) Enabled(ctx context.Context, name types.NamespacedName) (bool, error) {
+	if d == nil {
+		return false, nil
+	}
+	if d.always {
+		return true, nil
+	}
+
+	component := &v1beta1.Component{}
+	if err := d.reader.Get(ctx, name, component); err != nil {
+		return false, fmt.Errorf("failed to read %s annotation: %w", DryRunAnnotation, err)
+	}
+
+	value, ok := component.Annotations[DryRunAnnotation]
+	if !ok {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s annotation %q: %w", DryRunAnnotation, value, err)
+	}
+	return enabled, nil
+}
+
+// DeletionHeld returns the error a provider fails a deletion with in dry-run mode. Deleting the
+// Component must not release the resource in AWS, so the deletion is retried until dry run is off.
+func DeletionHeld(plan *Plan) error {
+	return fmt.Errorf("dry run: %s; deletion waits until dry run is turned off", plan)
+}
+
+// RetryDryRun classifies the errors of Enabled and DeletionHeld: a Component that cannot be read
+// and a held deletion are retried, while an invalid annotation fails until it is fixed
+func RetryDryRun(err error) bool {
+	var invalid *strconv.NumError
+	return !errors.As(err, &invalid)
+}
@@ -0,0 +1,103 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/rinswind/componator-aws-providers/awsfake"
+)
+
+var _ = Describe("Plan", func() {
+	It("should plan a creation with every desired value", func() {
+		plan := NewPlan("db", false)
+		Diff(plan, "instanceClass", nil, aws.String("db.t3.micro"))
+		Diff(plan, "multiAZ", nil, (*bool)(nil))
+
+		Expect(plan.Action).To(Equal(PlanCreate))
+		Expect(plan.Changes).To(Equal([]Change{{Field: "instanceClass", Action: ChangeAdd, Desired: "db.t3.micro"}}))
+		Expect(plan.String()).To(Equal("would create db (1 changes)"))
+	})
+
+	It("should report only the fields that differ", func() {
+		plan := NewPlan("db", true)
+		Diff(plan, "allocatedStorage", aws.Int32(20), aws.Int32(20))
+		Expect(plan.Action).To(Equal(PlanNoChange))
+		Expect(plan.String()).To(Equal("db is up to date"))
+
+		Diff(plan, "allocatedStorage", aws.Int32(20), aws.Int32(50))
+		plan.Remove("managedPolicyArns", "arn:aws:iam::aws:policy/ReadOnlyAccess")
+		Expect(plan.Action).To(Equal(PlanUpdate))
+		Expect(plan.Changes).To(Equal([]Change{
+			{Field: "allocatedStorage", Action: ChangeModify, Current: "20", Desired: "50"},
+			{Field: "managedPolicyArns", Action: ChangeRemove, Current: "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		}))
+	})
+
+	It("should describe deleting and retaining a resource", func() {
+		Expect(NewDeletePlan("db").Action).To(Equal(PlanDelete))
+		Expect(NewRetainPlan("db").Action).To(Equal(PlanRetain))
+		Expect(NewRetainPlan("db").String()).To(Equal("would retain db and remove its ownership tags"))
+	})
+})
+
+var _ = Describe("DryRun", func() {
+	var (
+		ctx        context.Context
+		name       types.NamespacedName
+		components *awsfake.Components
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		name = types.NamespacedName{Namespace: "default", Name: "app"}
+		components = awsfake.NewComponents(name)
+	})
+
+	It("should never plan when nil", func() {
+		var dryRun *DryRun
+		Expect(dryRun.Enabled(ctx, name)).To(BeFalse())
+	})
+
+	It("should plan every Component when always set", func() {
+		dryRun := NewDryRun(components, true)
+		Expect(dryRun.Enabled(ctx, types.NamespacedName{Namespace: "default", Name: "other"})).To(BeTrue())
+	})
+
+	It("should follow the Component annotation", func() {
+		dryRun := NewDryRun(components, false)
+		Expect(dryRun.Enabled(ctx, name)).To(BeFalse())
+
+		components.Annotate(name, DryRunAnnotation, "true")
+		Expect(dryRun.Enabled(ctx, name)).To(BeTrue())
+
+		components.Annotate(name, DryRunAnnotation, "false")
+		Expect(dryRun.Enabled(ctx, name)).To(BeFalse())
+
+		components.Annotate(name, DryRunAnnotation, "maybe")
+		_, err := dryRun.Enabled(ctx, name)
+		Expect(err).To(MatchError(ContainSubstring(`invalid aws.componator.io/dry-run annotation "maybe"`)))
+	})
+
+	It("should fail when the Component cannot be read", func() {
+		_, err := NewDryRun(components, false).Enabled(ctx, types.NamespacedName{Namespace: "default", Name: "gone"})
+		Expect(err).To(HaveOccurred())
+		Expect(RetryDryRun(err)).To(BeTrue())
+	})
+
+	It("should only retry errors that can clear up", func() {
+		components.Annotate(name, DryRunAnnotation, "maybe")
+		_, err := NewDryRun(components, false).Enabled(ctx, name)
+		Expect(RetryDryRun(err)).To(BeFalse())
+
+		err = DeletionHeld(NewDeletePlan("db"))
+		Expect(err).To(MatchError("dry run: would delete db; deletion waits until dry run is turned off"))
+		Expect(RetryDryRun(err)).To(BeTrue())
+	})
+})
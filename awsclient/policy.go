@@ -0,0 +1,21 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DecodePolicyDocument returns an IAM policy document as JSON.
+// IAM returns documents URL-encoded (RFC 3986); documents that are already JSON are returned as is.
+func DecodePolicyDocument(document string) string {
+	if strings.HasPrefix(strings.TrimSpace(document), "{") {
+		return document
+	}
+	if decoded, err := url.PathUnescape(document); err == nil {
+		return decoded
+	}
+	return document
+}
@@ -0,0 +1,166 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// readinessTimeout bounds a single round of identity and provider probes
+	readinessTimeout = 10 * time.Second
+)
+
+var readinessLog = logf.Log.WithName("aws-readiness")
+
+// CallerIdentityAPI is the STS call used to verify the controller's credentials.
+// It is satisfied by *sts.Client and by awsfake.STS in unit tests.
+type CallerIdentityAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// Probe is a cheap read-only AWS call that shows a provider can reach its service
+type Probe func(ctx context.Context) error
+
+// Identity is the AWS principal the controller runs as
+type Identity struct {
+	// Account is the AWS account ID
+	Account string
+
+	// Arn is the caller ARN as reported by STS (an assumed-role session for IRSA)
+	Arn string
+
+	// RoleArn is the IAM role behind an assumed-role session, or empty for other principals
+	RoleArn string
+}
+
+// ReadinessChecker reports the controller ready only when its AWS credentials work.
+// It calls STS GetCallerIdentity and the registered provider probes, and caches the
+// outcome for the configured interval so that frequent readiness probes stay cheap.
+type ReadinessChecker struct {
+	client   CallerIdentityAPI
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	probes    []namedProbe
+	checkedAt time.Time
+	lastErr   error
+	identity  *Identity
+}
+
+type namedProbe struct {
+	name  string
+	probe Probe
+}
+
+// NewSTSClient builds the STS client used for identity checks, honouring the STS endpoint override
+func NewSTSClient(cfg aws.Config, endpoints Endpoints) *sts.Client {
+	stsCfg := cfg.Copy()
+	InstrumentConfig(&stsCfg)
+	endpoints.Configure(&stsCfg, ServiceSTS)
+	return sts.NewFromConfig(stsCfg)
+}
+
+// NewReadinessChecker creates a checker that re-verifies the credentials at most once per interval
+func NewReadinessChecker(client CallerIdentityAPI, interval time.Duration) *ReadinessChecker {
+	return &ReadinessChecker{
+		client:   client,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// AddProbe registers a provider probe that runs after the identity check
+func (c *ReadinessChecker) AddProbe(name string, probe Probe) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.probes = append(c.probes, namedProbe{name: name, probe: probe})
+}
+
+// Identity returns the last successfully resolved identity, if any
+func (c *ReadinessChecker) Identity() (Identity, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.identity == nil {
+		return Identity{}, false
+	}
+	return *c.identity, true
+}
+
+// Check implements healthz.Checker
+func (c *ReadinessChecker) Check(req *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkedAt.IsZero() && c.now().Sub(c.checkedAt) < c.interval {
+		return c.lastErr
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), readinessTimeout)
+	defer cancel()
+
+	err := c.verify(ctx)
+	if err != nil && (c.lastErr == nil || c.lastErr.Error() != err.Error()) {
+		readinessLog.Error(err, "AWS readiness check failed")
+	}
+
+	c.checkedAt = c.now()
+	c.lastErr = err
+	return err
+}
+
+// verify resolves the caller identity and runs the provider probes; callers must hold the lock
+func (c *ReadinessChecker) verify(ctx context.Context) error {
+	output, err := c.client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return fmt.Errorf("failed to verify AWS credentials: %w", err)
+	}
+
+	identity := Identity{
+		Account: aws.ToString(output.Account),
+		Arn:     aws.ToString(output.Arn),
+		RoleArn: roleArnFromCaller(aws.ToString(output.Arn)),
+	}
+	if c.identity == nil || *c.identity != identity {
+		readinessLog.Info("Resolved AWS identity",
+			"account", identity.Account, "arn", identity.Arn, "roleArn", identity.RoleArn)
+	}
+	c.identity = &identity
+
+	for _, p := range c.probes {
+		if err := p.probe(ctx); err != nil {
+			return fmt.Errorf("provider %s cannot reach AWS: %w", p.name, err)
+		}
+	}
+	return nil
+}
+
+// roleArnFromCaller maps an assumed-role session ARN to the ARN of its IAM role.
+// arn:aws:sts::123456789012:assumed-role/name/session -> arn:aws:iam::123456789012:role/name
+// The role path is not part of the session ARN and is therefore omitted.
+func roleArnFromCaller(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[2] != "sts" {
+		return ""
+	}
+
+	resource, ok := strings.CutPrefix(parts[5], "assumed-role/")
+	if !ok {
+		return ""
+	}
+	roleName, _, _ := strings.Cut(resource, "/")
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], roleName)
+}
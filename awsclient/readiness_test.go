@@ -0,0 +1,92 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rinswind/componator-aws-providers/awsfake"
+)
+
+var _ = Describe("ReadinessChecker", func() {
+	var (
+		fake    *awsfake.STS
+		now     time.Time
+		checker *ReadinessChecker
+	)
+
+	check := func() error {
+		return checker.Check(httptest.NewRequest("GET", "/readyz", nil))
+	}
+
+	BeforeEach(func() {
+		fake = awsfake.NewSTS()
+		now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		checker = NewReadinessChecker(fake, time.Minute)
+		checker.now = func() time.Time { return now }
+	})
+
+	It("should resolve the caller identity", func() {
+		Expect(check()).To(Succeed())
+
+		identity, ok := checker.Identity()
+		Expect(ok).To(BeTrue())
+		Expect(identity).To(Equal(Identity{
+			Account: awsfake.AccountID,
+			Arn:     "arn:aws:sts::" + awsfake.AccountID + ":assumed-role/componator-aws-providers/session",
+			RoleArn: "arn:aws:iam::" + awsfake.AccountID + ":role/componator-aws-providers",
+		}))
+	})
+
+	It("should cache the outcome for the interval", func() {
+		Expect(check()).To(Succeed())
+		now = now.Add(30 * time.Second)
+		Expect(check()).To(Succeed())
+		Expect(fake.CallCount("GetCallerIdentity")).To(Equal(1))
+
+		now = now.Add(time.Minute)
+		Expect(check()).To(Succeed())
+		Expect(fake.CallCount("GetCallerIdentity")).To(Equal(2))
+	})
+
+	It("should report and cache credential failures", func() {
+		fake.FailNext("GetCallerIdentity", errors.New("AccessDenied: not authorized"))
+
+		Expect(check()).To(MatchError(ContainSubstring("failed to verify AWS credentials: AccessDenied")))
+		Expect(check()).To(HaveOccurred())
+		Expect(fake.CallCount("GetCallerIdentity")).To(Equal(1))
+
+		now = now.Add(time.Minute)
+		Expect(check()).To(Succeed())
+	})
+
+	It("should run provider probes after the identity check", func() {
+		probed := 0
+		checker.AddProbe("rds", func(ctx context.Context) error {
+			probed++
+			return nil
+		})
+		checker.AddProbe("secret-push", func(ctx context.Context) error {
+			return errors.New("connection refused")
+		})
+
+		Expect(check()).To(MatchError("provider secret-push cannot reach AWS: connection refused"))
+		Expect(probed).To(Equal(1))
+	})
+
+	DescribeTable("roleArnFromCaller",
+		func(arn, expected string) {
+			Expect(roleArnFromCaller(arn)).To(Equal(expected))
+		},
+		Entry("assumed role", "arn:aws:sts::123456789012:assumed-role/app/session", "arn:aws:iam::123456789012:role/app"),
+		Entry("other partition", "arn:aws-cn:sts::123456789012:assumed-role/app/s", "arn:aws-cn:iam::123456789012:role/app"),
+		Entry("IAM user", "arn:aws:iam::123456789012:user/alice", ""),
+		Entry("not an ARN", "alice", ""),
+	)
+})
@@ -0,0 +1,38 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registration guards the package-level state a provider sets up in Register: clients, ownership,
+// dry-run and event recorder. All Components of the provider share that state, so a second Register
+// with another name or cluster ID would silently take it over from the first. The zero value is unclaimed.
+type Registration struct {
+	mu   sync.Mutex
+	name string
+}
+
+// Claim records the registration of the named provider. It fails if the package is already registered.
+func (r *Registration) Claim(providerName string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.name != "" {
+		return fmt.Errorf("cannot register provider %q: the package is already registered as %q, "+
+			"and a provider package can only be registered once per process", providerName, r.name)
+	}
+	r.name = providerName
+	return nil
+}
+
+// Release gives up a claim after the registration failed, so that it can be retried
+func (r *Registration) Release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.name = ""
+}
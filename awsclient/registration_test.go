@@ -0,0 +1,21 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsclient
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registration", func() {
+	It("should refuse a second registration until the first is released", func() {
+		var registration Registration
+		Expect(registration.Claim("rds")).To(Succeed())
+		Expect(registration.Claim("wordpress-rds")).To(MatchError(ContainSubstring(`already registered as "rds"`)))
+		Expect(registration.Claim("rds")).NotTo(Succeed())
+
+		registration.Release()
+		Expect(registration.Claim("wordpress-rds")).To(Succeed())
+	})
+})
@@ -0,0 +1,63 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CloudWatch is an in-memory fake of the CloudWatch metrics read by the providers.
+// Tests set the latest value of a metric series with SetMetric; series without a
+// value report no datapoints.
+type CloudWatch struct {
+	faults
+
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCloudWatch creates a fake CloudWatch without any datapoints
+func NewCloudWatch() *CloudWatch {
+	return &CloudWatch{values: make(map[string]float64)}
+}
+
+// SetMetric sets the latest value of the metric series selected by the name and dimensions
+func (f *CloudWatch) SetMetric(namespace, metricName string, dimensions map[string]string, value float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.values[seriesKey(namespace, metricName, dimensions)] = value
+}
+
+// LatestMaximum returns the value set for the series, if any
+func (f *CloudWatch) LatestMaximum(
+	ctx context.Context,
+	namespace, metricName string,
+	dimensions map[string]string,
+	period time.Duration) (float64, bool, error) {
+
+	if err := f.record("GetMetricStatistics"); err != nil {
+		return 0, false, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	value, ok := f.values[seriesKey(namespace, metricName, dimensions)]
+	return value, ok, nil
+}
+
+// seriesKey identifies a metric series independent of the dimension order
+func seriesKey(namespace, metricName string, dimensions map[string]string) string {
+	parts := []string{namespace, metricName}
+	for name, value := range dimensions {
+		parts = append(parts, name+"="+value)
+	}
+	sort.Strings(parts[2:])
+	return strings.Join(parts, "|")
+}
@@ -0,0 +1,73 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+
+	v1beta1 "github.com/rinswind/componator/api/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Components is an in-memory Component reader for tests that record Kubernetes Events.
+// Components added to it get a deterministic UID.
+type Components struct {
+	mu         sync.Mutex
+	components map[types.NamespacedName]*v1beta1.Component
+}
+
+// NewComponents creates a reader holding Components with the given names
+func NewComponents(names ...types.NamespacedName) *Components {
+	c := &Components{components: make(map[types.NamespacedName]*v1beta1.Component)}
+	for _, name := range names {
+		c.Add(name)
+	}
+	return c
+}
+
+// Add stores a Component with the given name
+func (c *Components) Add(name types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	component := &v1beta1.Component{}
+	component.Namespace = name.Namespace
+	component.Name = name.Name
+	component.UID = types.UID(fmt.Sprintf("uid-%s-%s", name.Namespace, name.Name))
+	c.components[name] = component
+}
+
+// Annotate sets an annotation on a stored Component
+func (c *Components) Annotate(name types.NamespacedName, key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if component, ok := c.components[name]; ok {
+		if component.Annotations == nil {
+			component.Annotations = make(map[string]string)
+		}
+		component.Annotations[key] = value
+	}
+}
+
+// Get implements awsclient.ComponentReader
+func (c *Components) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	component, ok := c.components[key]
+	target, isComponent := obj.(*v1beta1.Component)
+	if !ok || !isComponent {
+		return apierrors.NewNotFound(schema.GroupResource{Group: v1beta1.GroupVersion.Group, Resource: "components"}, key.Name)
+	}
+	*target = *component
+	target.Annotations = maps.Clone(component.Annotations)
+	return nil
+}
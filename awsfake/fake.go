@@ -0,0 +1,89 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package awsfake provides stateful in-memory stand-ins for the AWS services used by the providers.
+// The fakes model resource state transitions (creating, modifying, deleting) closely enough to
+// drive a provider's full apply/check/delete lifecycle in unit tests without an AWS account.
+//
+// Asynchronous AWS operations do not complete on their own: tests call Advance to move every
+// resource to its next state, mimicking the passage of time between reconcile loops.
+package awsfake
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	// AccountID is the account ID used in all ARNs generated by the fakes
+	AccountID = "123456789012"
+
+	// Region is the region used in all ARNs generated by the fakes
+	Region = "us-east-1"
+)
+
+// faults holds errors injected by tests, keyed by operation name
+type faults struct {
+	mu     sync.Mutex
+	errors map[string][]error
+	calls  []string
+}
+
+// FailNext makes the next call to the named operation (e.g. "CreateDBInstance") return err.
+// Multiple calls queue multiple failures.
+func (f *faults) FailNext(operation string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.errors == nil {
+		f.errors = make(map[string][]error)
+	}
+	f.errors[operation] = append(f.errors[operation], err)
+}
+
+// Calls returns the names of all operations invoked so far, in order
+func (f *faults) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.calls...)
+}
+
+// CallCount returns how many times the named operation was invoked
+func (f *faults) CallCount(operation string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+	for _, call := range f.calls {
+		if call == operation {
+			count++
+		}
+	}
+	return count
+}
+
+// record registers a call and returns the injected error for it, if any
+func (f *faults) record(operation string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, operation)
+
+	queued := f.errors[operation]
+	if len(queued) == 0 {
+		return nil
+	}
+	f.errors[operation] = queued[1:]
+	return queued[0]
+}
+
+// idGenerator produces deterministic identifiers for versions and resource IDs
+type idGenerator struct {
+	next int
+}
+
+func (g *idGenerator) id(prefix string) string {
+	g.next++
+	return fmt.Sprintf("%s%012d", prefix, g.next)
+}
@@ -0,0 +1,736 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+const (
+	// maxPolicyVersions mirrors the IAM limit of versions kept per managed policy
+	maxPolicyVersions = 5
+
+	// policyPageSize is deliberately small so that callers must follow ListPolicies pagination
+	policyPageSize = 2
+)
+
+// IAM is an in-memory fake of the AWS IAM API covering customer managed policies and roles.
+// IAM is eventually consistent but synchronous, so no Advance step is needed.
+//
+// The fake enforces the same preconditions as IAM: at most five policy versions,
+// no deletion of policies with non-default versions, and no deletion of roles
+// with attached policies.
+type IAM struct {
+	faults
+
+	mu       sync.Mutex
+	ids      idGenerator
+	clock    time.Time
+	policies map[string]*fakePolicy
+	roles    map[string]*fakeRole
+}
+
+type fakePolicy struct {
+	policy   types.Policy
+	versions []types.PolicyVersion
+	nextVer  int
+}
+
+type fakeRole struct {
+	role     types.Role
+	attached []string
+}
+
+// NewIAM creates an empty fake IAM backend
+func NewIAM() *IAM {
+	return &IAM{
+		clock:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		policies: make(map[string]*fakePolicy),
+		roles:    make(map[string]*fakeRole),
+	}
+}
+
+// PolicyArn returns the ARN the fake assigns to a customer managed policy
+func (f *IAM) PolicyArn(path, name string) string {
+	return fmt.Sprintf("arn:aws:iam::%s:policy%s%s", AccountID, path, name)
+}
+
+// Policy returns a deep copy of the policy with the given ARN, or nil if it does not exist
+func (f *IAM) Policy(arn string) *types.Policy {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[arn]
+	if !ok {
+		return nil
+	}
+	return copyPolicy(&p.policy)
+}
+
+// PolicyVersions returns copies of all versions of the policy with the given ARN
+func (f *IAM) PolicyVersions(arn string) []types.PolicyVersion {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[arn]
+	if !ok {
+		return nil
+	}
+	return slices.Clone(p.versions)
+}
+
+// Role returns a deep copy of the named role, or nil if it does not exist
+func (f *IAM) Role(name string) *types.Role {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[name]
+	if !ok {
+		return nil
+	}
+	return copyRole(&r.role)
+}
+
+// AttachedPolicies returns the ARNs of managed policies attached to the named role
+func (f *IAM) AttachedPolicies(roleName string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[roleName]
+	if !ok {
+		return nil
+	}
+	return slices.Clone(r.attached)
+}
+
+// tick advances the fake clock so version creation dates are strictly ordered
+func (f *IAM) tick() *time.Time {
+	f.clock = f.clock.Add(time.Minute)
+	now := f.clock
+	return &now
+}
+
+// ListPolicies lists policies under PathPrefix in creation order, a page at a time.
+// The fake only holds customer managed policies, so the AWS scope always yields an empty list.
+// Pages hold at most policyPageSize entries (or MaxItems if smaller); Marker is the next index.
+// Like IAM, listed policies carry no tags; GetPolicy returns them.
+func (f *IAM) ListPolicies(
+	ctx context.Context, params *iam.ListPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListPoliciesOutput, error) {
+
+	if err := f.record("ListPolicies"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	output := &iam.ListPoliciesOutput{}
+	if params.Scope == types.PolicyScopeTypeAws {
+		return output, nil
+	}
+
+	prefix := aws.ToString(params.PathPrefix)
+	var matching []*types.Policy
+	for _, p := range f.policies {
+		if strings.HasPrefix(aws.ToString(p.policy.Path), prefix) {
+			matching = append(matching, &p.policy)
+		}
+	}
+	slices.SortFunc(matching, func(a, b *types.Policy) int {
+		return a.CreateDate.Compare(*b.CreateDate)
+	})
+
+	start := 0
+	if params.Marker != nil {
+		var err error
+		if start, err = strconv.Atoi(aws.ToString(params.Marker)); err != nil || start > len(matching) {
+			return nil, &types.InvalidInputException{Message: aws.String("Invalid marker.")}
+		}
+	}
+
+	pageSize := policyPageSize
+	if maxItems := int(aws.ToInt32(params.MaxItems)); maxItems > 0 && maxItems < pageSize {
+		pageSize = maxItems
+	}
+
+	end := min(start+pageSize, len(matching))
+	for _, policy := range matching[start:end] {
+		listed := *copyPolicy(policy)
+		listed.Tags = nil
+		output.Policies = append(output.Policies, listed)
+	}
+	if end < len(matching) {
+		output.IsTruncated = true
+		output.Marker = aws.String(strconv.Itoa(end))
+	}
+	return output, nil
+}
+
+// GetPolicy returns the policy with the given ARN
+func (f *IAM) GetPolicy(
+	ctx context.Context, params *iam.GetPolicyInput, optFns ...func(*iam.Options)) (*iam.GetPolicyOutput, error) {
+
+	if err := f.record("GetPolicy"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[aws.ToString(params.PolicyArn)]
+	if !ok {
+		return nil, noSuchEntity("policy", aws.ToString(params.PolicyArn))
+	}
+	return &iam.GetPolicyOutput{Policy: copyPolicy(&p.policy)}, nil
+}
+
+// CreatePolicy creates a policy with a single default version v1
+func (f *IAM) CreatePolicy(
+	ctx context.Context, params *iam.CreatePolicyInput, optFns ...func(*iam.Options)) (*iam.CreatePolicyOutput, error) {
+
+	if err := f.record("CreatePolicy"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := aws.ToString(params.Path)
+	if path == "" {
+		path = "/"
+	}
+	arn := f.PolicyArn(path, aws.ToString(params.PolicyName))
+	if _, exists := f.policies[arn]; exists {
+		return nil, &types.EntityAlreadyExistsException{Message: aws.String(fmt.Sprintf("A policy called %s already exists.", aws.ToString(params.PolicyName)))}
+	}
+
+	created := f.tick()
+	p := &fakePolicy{
+		policy: types.Policy{
+			Arn:              aws.String(arn),
+			PolicyId:         aws.String(f.ids.id("ANPA")),
+			PolicyName:       params.PolicyName,
+			Path:             aws.String(path),
+			Description:      params.Description,
+			DefaultVersionId: aws.String("v1"),
+			Tags:             slices.Clone(params.Tags),
+			CreateDate:       created,
+		},
+		versions: []types.PolicyVersion{{
+			VersionId:        aws.String("v1"),
+			Document:         params.PolicyDocument,
+			IsDefaultVersion: true,
+			CreateDate:       created,
+		}},
+		nextVer: 2,
+	}
+	f.policies[arn] = p
+
+	return &iam.CreatePolicyOutput{Policy: copyPolicy(&p.policy)}, nil
+}
+
+// DeletePolicy deletes a policy that has no non-default versions left
+func (f *IAM) DeletePolicy(
+	ctx context.Context, params *iam.DeletePolicyInput, optFns ...func(*iam.Options)) (*iam.DeletePolicyOutput, error) {
+
+	if err := f.record("DeletePolicy"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	arn := aws.ToString(params.PolicyArn)
+	p, ok := f.policies[arn]
+	if !ok {
+		return nil, noSuchEntity("policy", arn)
+	}
+	if len(p.versions) > 1 {
+		return nil, &types.DeleteConflictException{Message: aws.String("This policy has more than one version. Before you delete a policy, you must delete the policy's versions.")}
+	}
+	for _, r := range f.roles {
+		if slices.Contains(r.attached, arn) {
+			return nil, &types.DeleteConflictException{Message: aws.String("Cannot delete a policy attached to entities.")}
+		}
+	}
+
+	delete(f.policies, arn)
+	return &iam.DeletePolicyOutput{}, nil
+}
+
+// GetPolicyVersion returns a single policy version including its document,
+// URL-encoded like the real API
+func (f *IAM) GetPolicyVersion(
+	ctx context.Context, params *iam.GetPolicyVersionInput, optFns ...func(*iam.Options)) (*iam.GetPolicyVersionOutput, error) {
+
+	if err := f.record("GetPolicyVersion"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[aws.ToString(params.PolicyArn)]
+	if !ok {
+		return nil, noSuchEntity("policy", aws.ToString(params.PolicyArn))
+	}
+	for _, v := range p.versions {
+		if aws.ToString(v.VersionId) == aws.ToString(params.VersionId) {
+			copied := v
+			copied.Document = aws.String(url.PathEscape(aws.ToString(v.Document)))
+			return &iam.GetPolicyVersionOutput{PolicyVersion: &copied}, nil
+		}
+	}
+	return nil, noSuchEntity("policy version", aws.ToString(params.VersionId))
+}
+
+// ListPolicyVersions lists all versions of a policy without their documents
+func (f *IAM) ListPolicyVersions(
+	ctx context.Context, params *iam.ListPolicyVersionsInput, optFns ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error) {
+
+	if err := f.record("ListPolicyVersions"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[aws.ToString(params.PolicyArn)]
+	if !ok {
+		return nil, noSuchEntity("policy", aws.ToString(params.PolicyArn))
+	}
+
+	output := &iam.ListPolicyVersionsOutput{}
+	for _, v := range p.versions {
+		v.Document = nil
+		output.Versions = append(output.Versions, v)
+	}
+	return output, nil
+}
+
+// CreatePolicyVersion adds a version, failing when the five version limit is reached
+func (f *IAM) CreatePolicyVersion(
+	ctx context.Context, params *iam.CreatePolicyVersionInput, optFns ...func(*iam.Options)) (*iam.CreatePolicyVersionOutput, error) {
+
+	if err := f.record("CreatePolicyVersion"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[aws.ToString(params.PolicyArn)]
+	if !ok {
+		return nil, noSuchEntity("policy", aws.ToString(params.PolicyArn))
+	}
+	if len(p.versions) >= maxPolicyVersions {
+		return nil, &types.LimitExceededException{Message: aws.String("A managed policy can have up to 5 versions.")}
+	}
+
+	version := types.PolicyVersion{
+		VersionId:  aws.String(fmt.Sprintf("v%d", p.nextVer)),
+		Document:   params.PolicyDocument,
+		CreateDate: f.tick(),
+	}
+	p.nextVer++
+
+	if params.SetAsDefault {
+		for i := range p.versions {
+			p.versions[i].IsDefaultVersion = false
+		}
+		version.IsDefaultVersion = true
+		p.policy.DefaultVersionId = version.VersionId
+	}
+	p.versions = append(p.versions, version)
+
+	copied := version
+	return &iam.CreatePolicyVersionOutput{PolicyVersion: &copied}, nil
+}
+
+// DeletePolicyVersion deletes a non-default policy version
+func (f *IAM) DeletePolicyVersion(
+	ctx context.Context, params *iam.DeletePolicyVersionInput, optFns ...func(*iam.Options)) (*iam.DeletePolicyVersionOutput, error) {
+
+	if err := f.record("DeletePolicyVersion"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[aws.ToString(params.PolicyArn)]
+	if !ok {
+		return nil, noSuchEntity("policy", aws.ToString(params.PolicyArn))
+	}
+
+	for i, v := range p.versions {
+		if aws.ToString(v.VersionId) != aws.ToString(params.VersionId) {
+			continue
+		}
+		if v.IsDefaultVersion {
+			return nil, &types.DeleteConflictException{Message: aws.String("Cannot delete the default version of a policy.")}
+		}
+		p.versions = slices.Delete(p.versions, i, i+1)
+		return &iam.DeletePolicyVersionOutput{}, nil
+	}
+	return nil, noSuchEntity("policy version", aws.ToString(params.VersionId))
+}
+
+// GetRole returns the named role with its trust policy URL-encoded like the real API
+func (f *IAM) GetRole(
+	ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+
+	if err := f.record("GetRole"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[aws.ToString(params.RoleName)]
+	if !ok {
+		return nil, noSuchEntity("role", aws.ToString(params.RoleName))
+	}
+	role := copyRole(&r.role)
+	role.AssumeRolePolicyDocument = aws.String(url.PathEscape(aws.ToString(r.role.AssumeRolePolicyDocument)))
+	return &iam.GetRoleOutput{Role: role}, nil
+}
+
+// ListRoles lists roles under PathPrefix in creation order.
+// Only the first page is modelled; Marker is not supported.
+func (f *IAM) ListRoles(
+	ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
+
+	if err := f.record("ListRoles"); err != nil {
+		return nil, err
+	}
+	if params.Marker != nil {
+		return nil, &types.InvalidInputException{Message: aws.String("Invalid marker.")}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prefix := aws.ToString(params.PathPrefix)
+	var matching []*types.Role
+	for _, r := range f.roles {
+		if strings.HasPrefix(aws.ToString(r.role.Path), prefix) {
+			matching = append(matching, &r.role)
+		}
+	}
+	slices.SortFunc(matching, func(a, b *types.Role) int {
+		return a.CreateDate.Compare(*b.CreateDate)
+	})
+
+	output := &iam.ListRolesOutput{}
+	pageSize := len(matching)
+	if maxItems := int(aws.ToInt32(params.MaxItems)); maxItems > 0 && maxItems < pageSize {
+		pageSize = maxItems
+		output.IsTruncated = true
+		output.Marker = aws.String(strconv.Itoa(pageSize))
+	}
+	for _, role := range matching[:pageSize] {
+		output.Roles = append(output.Roles, *copyRole(role))
+	}
+	return output, nil
+}
+
+// CreateRole creates a role with the given trust policy
+func (f *IAM) CreateRole(
+	ctx context.Context, params *iam.CreateRoleInput, optFns ...func(*iam.Options)) (*iam.CreateRoleOutput, error) {
+
+	if err := f.record("CreateRole"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.RoleName)
+	if _, exists := f.roles[name]; exists {
+		return nil, &types.EntityAlreadyExistsException{Message: aws.String(fmt.Sprintf("Role with name %s already exists.", name))}
+	}
+
+	path := aws.ToString(params.Path)
+	if path == "" {
+		path = "/"
+	}
+
+	r := &fakeRole{
+		role: types.Role{
+			Arn:                      aws.String(fmt.Sprintf("arn:aws:iam::%s:role%s%s", AccountID, path, name)),
+			RoleId:                   aws.String(f.ids.id("AROA")),
+			RoleName:                 params.RoleName,
+			Path:                     aws.String(path),
+			AssumeRolePolicyDocument: params.AssumeRolePolicyDocument,
+			Description:              params.Description,
+			MaxSessionDuration:       params.MaxSessionDuration,
+			Tags:                     slices.Clone(params.Tags),
+			CreateDate:               f.tick(),
+		},
+	}
+	f.roles[name] = r
+
+	return &iam.CreateRoleOutput{Role: copyRole(&r.role)}, nil
+}
+
+// DeleteRole deletes a role that has no attached managed policies
+func (f *IAM) DeleteRole(
+	ctx context.Context, params *iam.DeleteRoleInput, optFns ...func(*iam.Options)) (*iam.DeleteRoleOutput, error) {
+
+	if err := f.record("DeleteRole"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.RoleName)
+	r, ok := f.roles[name]
+	if !ok {
+		return nil, noSuchEntity("role", name)
+	}
+	if len(r.attached) > 0 {
+		return nil, &types.DeleteConflictException{Message: aws.String("Cannot delete entity, must detach all policies first.")}
+	}
+
+	delete(f.roles, name)
+	return &iam.DeleteRoleOutput{}, nil
+}
+
+// UpdateAssumeRolePolicy replaces the role's trust policy
+func (f *IAM) UpdateAssumeRolePolicy(
+	ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error) {
+
+	if err := f.record("UpdateAssumeRolePolicy"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[aws.ToString(params.RoleName)]
+	if !ok {
+		return nil, noSuchEntity("role", aws.ToString(params.RoleName))
+	}
+	r.role.AssumeRolePolicyDocument = params.PolicyDocument
+	return &iam.UpdateAssumeRolePolicyOutput{}, nil
+}
+
+// ListAttachedRolePolicies lists the managed policies attached to a role
+func (f *IAM) ListAttachedRolePolicies(
+	ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error) {
+
+	if err := f.record("ListAttachedRolePolicies"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[aws.ToString(params.RoleName)]
+	if !ok {
+		return nil, noSuchEntity("role", aws.ToString(params.RoleName))
+	}
+
+	output := &iam.ListAttachedRolePoliciesOutput{}
+	for _, arn := range r.attached {
+		output.AttachedPolicies = append(output.AttachedPolicies, types.AttachedPolicy{
+			PolicyArn:  aws.String(arn),
+			PolicyName: aws.String(arn[strings.LastIndex(arn, "/")+1:]),
+		})
+	}
+	return output, nil
+}
+
+// AttachRolePolicy attaches a managed policy to a role.
+// Customer managed policies must exist in the fake; AWS managed policy ARNs are accepted as-is.
+func (f *IAM) AttachRolePolicy(
+	ctx context.Context, params *iam.AttachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error) {
+
+	if err := f.record("AttachRolePolicy"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[aws.ToString(params.RoleName)]
+	if !ok {
+		return nil, noSuchEntity("role", aws.ToString(params.RoleName))
+	}
+
+	arn := aws.ToString(params.PolicyArn)
+	if strings.HasPrefix(arn, "arn:aws:iam::"+AccountID+":") {
+		if _, exists := f.policies[arn]; !exists {
+			return nil, noSuchEntity("policy", arn)
+		}
+	}
+
+	if !slices.Contains(r.attached, arn) {
+		r.attached = append(r.attached, arn)
+	}
+	return &iam.AttachRolePolicyOutput{}, nil
+}
+
+// DetachRolePolicy detaches a managed policy from a role
+func (f *IAM) DetachRolePolicy(
+	ctx context.Context, params *iam.DetachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error) {
+
+	if err := f.record("DetachRolePolicy"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[aws.ToString(params.RoleName)]
+	if !ok {
+		return nil, noSuchEntity("role", aws.ToString(params.RoleName))
+	}
+
+	arn := aws.ToString(params.PolicyArn)
+	i := slices.Index(r.attached, arn)
+	if i < 0 {
+		return nil, noSuchEntity("attached policy", arn)
+	}
+	r.attached = slices.Delete(r.attached, i, i+1)
+	return &iam.DetachRolePolicyOutput{}, nil
+}
+
+// TagPolicy adds tags to a policy, replacing the values of existing keys
+func (f *IAM) TagPolicy(
+	ctx context.Context, params *iam.TagPolicyInput, optFns ...func(*iam.Options)) (*iam.TagPolicyOutput, error) {
+
+	if err := f.record("TagPolicy"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[aws.ToString(params.PolicyArn)]
+	if !ok {
+		return nil, noSuchEntity("policy", aws.ToString(params.PolicyArn))
+	}
+	p.policy.Tags = mergeTags(p.policy.Tags, params.Tags)
+	return &iam.TagPolicyOutput{}, nil
+}
+
+// TagRole adds tags to a role, replacing the values of existing keys
+func (f *IAM) TagRole(
+	ctx context.Context, params *iam.TagRoleInput, optFns ...func(*iam.Options)) (*iam.TagRoleOutput, error) {
+
+	if err := f.record("TagRole"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[aws.ToString(params.RoleName)]
+	if !ok {
+		return nil, noSuchEntity("role", aws.ToString(params.RoleName))
+	}
+	r.role.Tags = mergeTags(r.role.Tags, params.Tags)
+	return &iam.TagRoleOutput{}, nil
+}
+
+// UntagPolicy removes tags from a policy
+func (f *IAM) UntagPolicy(
+	ctx context.Context, params *iam.UntagPolicyInput, optFns ...func(*iam.Options)) (*iam.UntagPolicyOutput, error) {
+
+	if err := f.record("UntagPolicy"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.policies[aws.ToString(params.PolicyArn)]
+	if !ok {
+		return nil, noSuchEntity("policy", aws.ToString(params.PolicyArn))
+	}
+	p.policy.Tags = removeTags(p.policy.Tags, params.TagKeys)
+	return &iam.UntagPolicyOutput{}, nil
+}
+
+// UntagRole removes tags from a role
+func (f *IAM) UntagRole(
+	ctx context.Context, params *iam.UntagRoleInput, optFns ...func(*iam.Options)) (*iam.UntagRoleOutput, error) {
+
+	if err := f.record("UntagRole"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r, ok := f.roles[aws.ToString(params.RoleName)]
+	if !ok {
+		return nil, noSuchEntity("role", aws.ToString(params.RoleName))
+	}
+	r.role.Tags = removeTags(r.role.Tags, params.TagKeys)
+	return &iam.UntagRoleOutput{}, nil
+}
+
+// removeTags drops the tags with the given keys from a tag list
+func removeTags(existing []types.Tag, keys []string) []types.Tag {
+	return slices.DeleteFunc(slices.Clone(existing), func(t types.Tag) bool {
+		return slices.Contains(keys, aws.ToString(t.Key))
+	})
+}
+
+// mergeTags adds tags to a tag list, replacing the values of existing keys
+func mergeTags(existing, added []types.Tag) []types.Tag {
+	merged := slices.Clone(existing)
+	for _, tag := range added {
+		i := slices.IndexFunc(merged, func(t types.Tag) bool { return aws.ToString(t.Key) == aws.ToString(tag.Key) })
+		if i >= 0 {
+			merged[i] = tag
+		} else {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// copyPolicy copies a policy together with its tags,
+// so callers cannot change the fake's state through the returned value
+func copyPolicy(policy *types.Policy) *types.Policy {
+	copied := *policy
+	copied.Tags = slices.Clone(policy.Tags)
+	return &copied
+}
+
+// copyRole copies a role together with its nested structs and tags,
+// so callers cannot change the fake's state through the returned value
+func copyRole(role *types.Role) *types.Role {
+	copied := *role
+	copied.Tags = slices.Clone(role.Tags)
+	if role.PermissionsBoundary != nil {
+		boundary := *role.PermissionsBoundary
+		copied.PermissionsBoundary = &boundary
+	}
+	if role.RoleLastUsed != nil {
+		lastUsed := *role.RoleLastUsed
+		copied.RoleLastUsed = &lastUsed
+	}
+	return &copied
+}
+
+func noSuchEntity(kind, name string) error {
+	return &types.NoSuchEntityException{Message: aws.String(fmt.Sprintf("The %s %s cannot be found.", kind, name))}
+}
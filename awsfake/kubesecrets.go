@@ -0,0 +1,117 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"maps"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubeSecrets is an in-memory store of Kubernetes Secrets for tests of providers that write them.
+// Every write bumps the resource version; updates with a stale version fail with a conflict.
+type KubeSecrets struct {
+	mu      sync.Mutex
+	version int
+	secrets map[types.NamespacedName]*corev1.Secret
+}
+
+// NewKubeSecrets creates an empty Secret store
+func NewKubeSecrets() *KubeSecrets {
+	return &KubeSecrets{secrets: make(map[types.NamespacedName]*corev1.Secret)}
+}
+
+// Secret returns a copy of the named Secret, or nil if it does not exist
+func (k *KubeSecrets) Secret(namespace, name string) *corev1.Secret {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	secret, ok := k.secrets[types.NamespacedName{Namespace: namespace, Name: name}]
+	if !ok {
+		return nil
+	}
+	return secret.DeepCopy()
+}
+
+// Get implements client.Reader for Secrets
+func (k *KubeSecrets) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	secret, ok := k.secrets[key]
+	target, isSecret := obj.(*corev1.Secret)
+	if !ok || !isSecret {
+		return kubeSecretNotFound(key.Name)
+	}
+	secret.DeepCopyInto(target)
+	return nil
+}
+
+// Create stores a new Secret
+func (k *KubeSecrets) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	secret := obj.(*corev1.Secret)
+	key := client.ObjectKeyFromObject(secret)
+	if _, exists := k.secrets[key]; exists {
+		return apierrors.NewAlreadyExists(secretsResource, key.Name)
+	}
+	k.store(key, secret)
+	return nil
+}
+
+// Update replaces an existing Secret
+func (k *KubeSecrets) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	secret := obj.(*corev1.Secret)
+	key := client.ObjectKeyFromObject(secret)
+	existing, ok := k.secrets[key]
+	if !ok {
+		return kubeSecretNotFound(key.Name)
+	}
+	if secret.ResourceVersion != existing.ResourceVersion {
+		return apierrors.NewConflict(secretsResource, key.Name, nil)
+	}
+	k.store(key, secret)
+	return nil
+}
+
+// Delete removes a Secret
+func (k *KubeSecrets) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	key := client.ObjectKeyFromObject(obj)
+	if _, ok := k.secrets[key]; !ok {
+		return kubeSecretNotFound(key.Name)
+	}
+	delete(k.secrets, key)
+	return nil
+}
+
+// store saves a copy of the Secret under a new resource version; callers must hold the lock
+func (k *KubeSecrets) store(key types.NamespacedName, secret *corev1.Secret) {
+	k.version++
+	stored := secret.DeepCopy()
+	stored.ResourceVersion = strconv.Itoa(k.version)
+	stored.Data = maps.Clone(secret.Data)
+	k.secrets[key] = stored
+	secret.ResourceVersion = stored.ResourceVersion
+}
+
+var secretsResource = schema.GroupResource{Resource: "secrets"}
+
+func kubeSecretNotFound(name string) error {
+	return apierrors.NewNotFound(secretsResource, name)
+}
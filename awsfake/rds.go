@@ -0,0 +1,1032 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+)
+
+// RDS is an in-memory fake of the AWS RDS API.
+//
+// Instance and cluster lifecycle:
+//   - CreateDBInstance and CreateDBCluster put the resource into "creating"
+//   - ModifyDBInstance and ModifyDBCluster apply changes and put it into "modifying"
+//   - ModifyDBInstance with NewDBInstanceIdentifier renames the instance and puts it into "renaming"
+//   - ModifyDBInstance without ApplyImmediately keeps the instance available and leaves class, storage,
+//     engine version and Multi-AZ changes in PendingModifiedValues until ApplyPendingModifications;
+//     a later request with ApplyImmediately applies them too
+//   - ModifyDBCluster without ApplyImmediately leaves an engine version change in PendingModifiedValues
+//   - DeleteDBInstance and DeleteDBCluster put it into "deleting"
+//   - Advance moves creating/modifying/renaming to "available" and removes deleting resources
+//
+// Instances created with DBClusterIdentifier join the cluster, which has to be available.
+// The first member becomes the writer; removing the writer fails over to the next member.
+//
+// Snapshots (CreateDBSnapshot, CopyDBSnapshot and final snapshots of deleted instances) go from
+// "creating" to "available" on Advance. Snapshots without tags of their own get the tags of an instance
+// with CopyTagsToSnapshot. DeleteDBSnapshot removes an available snapshot right away. Restores create
+// instances from an available snapshot or an instance with automated backups.
+//
+// Parameter groups know a small catalog of postgres and mysql parameters. Instances report the
+// apply status of their group: parameter changes are "applying" until Advance, or "pending-reboot"
+// for the pending-reboot apply method and for a group change of the instance.
+//
+// Read replicas need an available source and report a "replicating" read replication status.
+// PromoteReadReplica detaches a replica from its source and puts it into "modifying".
+//
+// Blue/green deployments create a green copy of their source with the target engine version, and
+// are "AVAILABLE" once Advance made it available. A switchover renames the blue instance with an
+// -old1 suffix and moves its identifier and endpoint to the green one. Engine version changes across
+// major versions need AllowMajorVersionUpgrade, like the real API.
+//
+// The engine versions and orderable instance options come from a small catalog of postgres, mysql
+// and mariadb versions, general purpose and memory optimized classes, and all storage types.
+// Creating instances does not check the catalog.
+type RDS struct {
+	faults
+
+	mu        sync.Mutex
+	ids       idGenerator
+	instances map[string]*types.DBInstance
+	clusters  map[string]*types.DBCluster
+	snapshots map[string]*types.DBSnapshot
+
+	deployments     map[string]*types.BlueGreenDeployment
+	parameterGroups map[string]*fakeParameterGroup
+	singleAZClasses map[string]bool
+}
+
+// NewRDS creates an empty fake RDS backend
+func NewRDS() *RDS {
+	return &RDS{
+		instances: make(map[string]*types.DBInstance),
+		clusters:  make(map[string]*types.DBCluster),
+		snapshots: make(map[string]*types.DBSnapshot),
+
+		deployments:     make(map[string]*types.BlueGreenDeployment),
+		parameterGroups: make(map[string]*fakeParameterGroup),
+		singleAZClasses: make(map[string]bool),
+	}
+}
+
+// Instance returns a deep copy of the named instance, or nil if it does not exist
+func (f *RDS) Instance(id string) *types.DBInstance {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	instance, ok := f.instances[id]
+	if !ok {
+		return nil
+	}
+	return copyInstance(instance)
+}
+
+// SetInstanceStatus forces the status of an existing instance (e.g. "storage-full")
+func (f *RDS) SetInstanceStatus(id, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if instance, ok := f.instances[id]; ok {
+		instance.DBInstanceStatus = aws.String(status)
+	}
+}
+
+// SetReplicationStatus forces the read replication status of an existing replica (e.g. "error")
+func (f *RDS) SetReplicationStatus(id, status string, normal bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if instance, ok := f.instances[id]; ok && instance.ReadReplicaSourceDBInstanceIdentifier != nil {
+		instance.StatusInfos = []types.DBInstanceStatusInfo{replicationStatus(status, normal)}
+	}
+}
+
+// Cluster returns a deep copy of the named cluster, or nil if it does not exist
+func (f *RDS) Cluster(id string) *types.DBCluster {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cluster, ok := f.clusters[id]
+	if !ok {
+		return nil
+	}
+	return copyCluster(cluster)
+}
+
+// SetClusterStatus forces the status of an existing cluster (e.g. "stopped")
+func (f *RDS) SetClusterStatus(id, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cluster, ok := f.clusters[id]; ok {
+		cluster.Status = aws.String(status)
+	}
+}
+
+// Advance completes all in-flight asynchronous operations
+func (f *RDS) Advance() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for id, instance := range f.instances {
+		switch aws.ToString(instance.DBInstanceStatus) {
+		case "creating", "modifying", "renaming":
+			instance.DBInstanceStatus = aws.String("available")
+		case "deleting":
+			delete(f.instances, id)
+			if cluster, ok := f.clusters[aws.ToString(instance.DBClusterIdentifier)]; ok {
+				removeMember(cluster, id)
+			}
+			if source := aws.ToString(instance.ReadReplicaSourceDBInstanceIdentifier); source != "" {
+				f.removeReplica(source, id)
+			}
+		}
+		for i := range instance.DBParameterGroups {
+			if aws.ToString(instance.DBParameterGroups[i].ParameterApplyStatus) == parametersApplying {
+				instance.DBParameterGroups[i].ParameterApplyStatus = aws.String(parametersInSync)
+			}
+		}
+	}
+
+	for id, cluster := range f.clusters {
+		switch aws.ToString(cluster.Status) {
+		case "creating", "modifying":
+			cluster.Status = aws.String("available")
+		case "deleting":
+			delete(f.clusters, id)
+		}
+	}
+
+	for _, snapshot := range f.snapshots {
+		if aws.ToString(snapshot.Status) == "creating" {
+			snapshot.Status = aws.String("available")
+			snapshot.PercentProgress = aws.Int32(100)
+		}
+	}
+
+	f.advanceDeployments()
+}
+
+// DescribeDBInstances returns the instance named by DBInstanceIdentifier, or all instances.
+// The db-cluster-id filter limits the result to the members of a cluster.
+func (f *RDS) DescribeDBInstances(
+	ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+
+	if err := f.record("DescribeDBInstances"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if params.DBInstanceIdentifier != nil {
+		instance, ok := f.instances[aws.ToString(params.DBInstanceIdentifier)]
+		if !ok {
+			return nil, instanceNotFound(aws.ToString(params.DBInstanceIdentifier))
+		}
+		return &rds.DescribeDBInstancesOutput{DBInstances: []types.DBInstance{*copyInstance(instance)}}, nil
+	}
+
+	var clusterIDs []string
+	for _, filter := range params.Filters {
+		if aws.ToString(filter.Name) == "db-cluster-id" {
+			clusterIDs = filter.Values
+		}
+	}
+
+	output := &rds.DescribeDBInstancesOutput{}
+	for _, id := range sortedKeys(f.instances) {
+		instance := f.instances[id]
+		if clusterIDs != nil && !slices.Contains(clusterIDs, aws.ToString(instance.DBClusterIdentifier)) {
+			continue
+		}
+		output.DBInstances = append(output.DBInstances, *copyInstance(instance))
+	}
+	return output, nil
+}
+
+// CreateDBInstance registers a new instance in "creating" state
+func (f *RDS) CreateDBInstance(
+	ctx context.Context, params *rds.CreateDBInstanceInput, optFns ...func(*rds.Options)) (*rds.CreateDBInstanceOutput, error) {
+
+	if err := f.record("CreateDBInstance"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBInstanceIdentifier)
+	if _, exists := f.instances[id]; exists {
+		return nil, &types.DBInstanceAlreadyExistsFault{Message: aws.String(fmt.Sprintf("DB instance %s already exists", id))}
+	}
+
+	var cluster *types.DBCluster
+	if params.DBClusterIdentifier != nil {
+		clusterID := aws.ToString(params.DBClusterIdentifier)
+		cluster = f.clusters[clusterID]
+		if cluster == nil {
+			return nil, clusterNotFound(clusterID)
+		}
+		if aws.ToString(cluster.Status) != "available" {
+			return nil, &types.InvalidDBClusterStateFault{Message: aws.String(fmt.Sprintf(
+				"DB cluster %s is not in available state: %s", clusterID, aws.ToString(cluster.Status)))}
+		}
+	}
+
+	port := aws.ToInt32(params.Port)
+	if port == 0 {
+		port = 5432
+	}
+
+	parameterGroups, err := f.instanceParameterGroups(params.DBParameterGroupName, params.Engine)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &types.DBInstance{
+		DBInstanceIdentifier:             params.DBInstanceIdentifier,
+		DBInstanceArn:                    aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", Region, AccountID, id)),
+		DBInstanceStatus:                 aws.String("creating"),
+		DbiResourceId:                    aws.String(f.ids.id("db-")),
+		DBInstanceClass:                  params.DBInstanceClass,
+		Engine:                           params.Engine,
+		EngineVersion:                    params.EngineVersion,
+		AllocatedStorage:                 params.AllocatedStorage,
+		MaxAllocatedStorage:              params.MaxAllocatedStorage,
+		StorageType:                      params.StorageType,
+		Iops:                             params.Iops,
+		StorageThroughput:                params.StorageThroughput,
+		StorageEncrypted:                 params.StorageEncrypted,
+		KmsKeyId:                         params.KmsKeyId,
+		MasterUsername:                   params.MasterUsername,
+		DBName:                           params.DBName,
+		MultiAZ:                          params.MultiAZ,
+		BackupRetentionPeriod:            params.BackupRetentionPeriod,
+		PreferredBackupWindow:            params.PreferredBackupWindow,
+		PreferredMaintenanceWindow:       params.PreferredMaintenanceWindow,
+		AutoMinorVersionUpgrade:          params.AutoMinorVersionUpgrade,
+		DeletionProtection:               params.DeletionProtection,
+		CopyTagsToSnapshot:               params.CopyTagsToSnapshot,
+		PubliclyAccessible:               params.PubliclyAccessible,
+		IAMDatabaseAuthenticationEnabled: params.EnableIAMDatabaseAuthentication,
+		EnabledCloudwatchLogsExports:     slices.Clone(params.EnableCloudwatchLogsExports),
+		DBParameterGroups:                parameterGroups,
+		DBSubnetGroup:                    subnetGroup(params.DBSubnetGroupName),
+		TagList:                          slices.Clone(params.Tags),
+		AvailabilityZone:                 aws.String(Region + "a"),
+		Endpoint: &types.Endpoint{
+			Address: aws.String(fmt.Sprintf("%s.fake.%s.rds.amazonaws.com", id, Region)),
+			Port:    aws.Int32(port),
+		},
+	}
+
+	if aws.ToBool(params.ManageMasterUserPassword) {
+		instance.MasterUserSecret = &types.MasterUserSecret{
+			SecretArn:    aws.String(fmt.Sprintf("arn:aws:secretsmanager:%s:%s:secret:rds!db-%s", Region, AccountID, id)),
+			SecretStatus: aws.String("active"),
+		}
+	}
+
+	// Cluster members take engine, storage and credentials from the cluster
+	if cluster != nil {
+		instance.DBClusterIdentifier = cluster.DBClusterIdentifier
+		instance.Engine = cluster.Engine
+		instance.EngineVersion = cluster.EngineVersion
+		instance.Endpoint.Port = cluster.Port
+		cluster.DBClusterMembers = append(cluster.DBClusterMembers, types.DBClusterMember{
+			DBInstanceIdentifier: aws.String(id),
+			IsClusterWriter:      aws.Bool(len(cluster.DBClusterMembers) == 0),
+			PromotionTier:        params.PromotionTier,
+		})
+	}
+
+	f.instances[id] = instance
+
+	return &rds.CreateDBInstanceOutput{DBInstance: copyInstance(instance)}, nil
+}
+
+// ModifyDBInstance applies the requested changes and puts the instance into "modifying" state
+func (f *RDS) ModifyDBInstance(
+	ctx context.Context, params *rds.ModifyDBInstanceInput, optFns ...func(*rds.Options)) (*rds.ModifyDBInstanceOutput, error) {
+
+	if err := f.record("ModifyDBInstance"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBInstanceIdentifier)
+	instance, ok := f.instances[id]
+	if !ok {
+		return nil, instanceNotFound(id)
+	}
+	if aws.ToString(instance.DBInstanceStatus) == "deleting" {
+		return nil, &types.InvalidDBInstanceStateFault{Message: aws.String(fmt.Sprintf("DB instance %s is being deleted", id))}
+	}
+	if newID := aws.ToString(params.NewDBInstanceIdentifier); newID != "" {
+		if _, exists := f.instances[newID]; exists {
+			return nil, &types.DBInstanceAlreadyExistsFault{Message: aws.String(fmt.Sprintf("DB instance %s already exists", newID))}
+		}
+	}
+
+	if params.EngineVersion != nil && !aws.ToBool(params.AllowMajorVersionUpgrade) &&
+		majorVersion(aws.ToString(instance.Engine), aws.ToString(instance.EngineVersion)) !=
+			majorVersion(aws.ToString(instance.Engine), aws.ToString(params.EngineVersion)) {
+		return nil, &smithy.GenericAPIError{
+			Code:    "InvalidParameterCombination",
+			Message: "The AllowMajorVersionUpgrade flag must be present when upgrading to a new major version.",
+		}
+	}
+
+	if params.DBParameterGroupName != nil && !usesParameterGroup(instance, aws.ToString(params.DBParameterGroupName)) {
+		groupName := aws.ToString(params.DBParameterGroupName)
+		if _, ok := f.parameterGroups[groupName]; !ok {
+			return nil, parameterGroupNotFound(groupName)
+		}
+		// The instance switches groups on its next reboot
+		instance.DBParameterGroups = []types.DBParameterGroupStatus{{
+			DBParameterGroupName: aws.String(groupName),
+			ParameterApplyStatus: aws.String(parametersPendingReboot),
+		}}
+	}
+
+	pending := instance.PendingModifiedValues
+	if pending == nil {
+		pending = &types.PendingModifiedValues{}
+	}
+	if params.DBInstanceClass != nil {
+		pending.DBInstanceClass = params.DBInstanceClass
+	}
+	if params.AllocatedStorage != nil {
+		pending.AllocatedStorage = params.AllocatedStorage
+	}
+	if params.StorageType != nil {
+		pending.StorageType = params.StorageType
+	}
+	if params.Iops != nil {
+		pending.Iops = params.Iops
+	}
+	if params.StorageThroughput != nil {
+		pending.StorageThroughput = params.StorageThroughput
+	}
+	if params.EngineVersion != nil {
+		pending.EngineVersion = params.EngineVersion
+	}
+	if params.MultiAZ != nil {
+		pending.MultiAZ = params.MultiAZ
+	}
+	instance.PendingModifiedValues = pending
+
+	if params.BackupRetentionPeriod != nil {
+		instance.BackupRetentionPeriod = params.BackupRetentionPeriod
+	}
+	if params.PreferredBackupWindow != nil {
+		instance.PreferredBackupWindow = params.PreferredBackupWindow
+	}
+	if params.PreferredMaintenanceWindow != nil {
+		instance.PreferredMaintenanceWindow = params.PreferredMaintenanceWindow
+	}
+	if params.AutoMinorVersionUpgrade != nil {
+		instance.AutoMinorVersionUpgrade = params.AutoMinorVersionUpgrade
+	}
+	if params.DeletionProtection != nil {
+		instance.DeletionProtection = params.DeletionProtection
+	}
+	if params.EnableIAMDatabaseAuthentication != nil {
+		instance.IAMDatabaseAuthenticationEnabled = params.EnableIAMDatabaseAuthentication
+	}
+	if logs := params.CloudwatchLogsExportConfiguration; logs != nil {
+		instance.EnabledCloudwatchLogsExports = slices.DeleteFunc(instance.EnabledCloudwatchLogsExports, func(logType string) bool {
+			return slices.Contains(logs.DisableLogTypes, logType) || slices.Contains(logs.EnableLogTypes, logType)
+		})
+		instance.EnabledCloudwatchLogsExports = append(instance.EnabledCloudwatchLogsExports, logs.EnableLogTypes...)
+	}
+	if params.MaxAllocatedStorage != nil {
+		// A maximum at the allocated storage turns autoscaling off, which RDS reports as no maximum
+		instance.MaxAllocatedStorage = params.MaxAllocatedStorage
+		if aws.ToInt32(params.MaxAllocatedStorage) <= aws.ToInt32(instance.AllocatedStorage) {
+			instance.MaxAllocatedStorage = nil
+		}
+	}
+
+	// Without ApplyImmediately the instance stays available until its maintenance window
+	if aws.ToBool(params.ApplyImmediately) {
+		applyPending(instance)
+		instance.DBInstanceStatus = aws.String("modifying")
+	}
+
+	if params.NewDBInstanceIdentifier != nil {
+		newID := aws.ToString(params.NewDBInstanceIdentifier)
+		delete(f.instances, id)
+		f.instances[newID] = instance
+		instance.DBInstanceIdentifier = aws.String(newID)
+		instance.DBInstanceArn = aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", Region, AccountID, newID))
+		instance.Endpoint.Address = aws.String(fmt.Sprintf("%s.fake.%s.rds.amazonaws.com", newID, Region))
+		instance.DBInstanceStatus = aws.String("renaming")
+	}
+
+	return &rds.ModifyDBInstanceOutput{DBInstance: copyInstance(instance)}, nil
+}
+
+// ApplyPendingModifications applies the modifications an instance or cluster keeps for its maintenance window
+func (f *RDS) ApplyPendingModifications(id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if instance, ok := f.instances[id]; ok {
+		applyPending(instance)
+	}
+	if cluster, ok := f.clusters[id]; ok && cluster.PendingModifiedValues != nil {
+		if version := cluster.PendingModifiedValues.EngineVersion; version != nil {
+			cluster.EngineVersion = version
+		}
+		cluster.PendingModifiedValues = nil
+	}
+}
+
+// applyPending applies the pending modifications of an instance; callers must hold the lock
+func applyPending(instance *types.DBInstance) {
+	pending := instance.PendingModifiedValues
+	if pending == nil {
+		return
+	}
+	if pending.DBInstanceClass != nil {
+		instance.DBInstanceClass = pending.DBInstanceClass
+	}
+	if pending.AllocatedStorage != nil {
+		instance.AllocatedStorage = pending.AllocatedStorage
+	}
+	if pending.StorageType != nil {
+		instance.StorageType = pending.StorageType
+	}
+	if pending.Iops != nil {
+		instance.Iops = pending.Iops
+	}
+	if pending.StorageThroughput != nil {
+		instance.StorageThroughput = pending.StorageThroughput
+	}
+	if pending.EngineVersion != nil {
+		instance.EngineVersion = pending.EngineVersion
+	}
+	if pending.MultiAZ != nil {
+		instance.MultiAZ = pending.MultiAZ
+	}
+	instance.PendingModifiedValues = nil
+}
+
+// DeleteDBInstance puts the instance into "deleting" state.
+// Fails while deletion protection is enabled, like the real API.
+func (f *RDS) DeleteDBInstance(
+	ctx context.Context, params *rds.DeleteDBInstanceInput, optFns ...func(*rds.Options)) (*rds.DeleteDBInstanceOutput, error) {
+
+	if err := f.record("DeleteDBInstance"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBInstanceIdentifier)
+	instance, ok := f.instances[id]
+	if !ok {
+		return nil, instanceNotFound(id)
+	}
+
+	if aws.ToString(instance.DBInstanceStatus) == "deleting" {
+		return nil, &types.InvalidDBInstanceStateFault{
+			Message: aws.String(fmt.Sprintf("Instance %s is already being deleted.", id)),
+		}
+	}
+	if aws.ToBool(instance.DeletionProtection) {
+		return nil, &smithy.GenericAPIError{
+			Code:    "InvalidParameterCombination",
+			Message: "Cannot delete protected DB Instance, please disable deletion protection and try again.",
+		}
+	}
+	// Snapshots of cluster members are taken of the whole cluster; replicas have none
+	if instance.DBClusterIdentifier == nil && instance.ReadReplicaSourceDBInstanceIdentifier == nil &&
+		!aws.ToBool(params.SkipFinalSnapshot) && aws.ToString(params.FinalDBSnapshotIdentifier) == "" {
+		return nil, &smithy.GenericAPIError{
+			Code:    "InvalidParameterCombination",
+			Message: "FinalDBSnapshotIdentifier is required unless SkipFinalSnapshot is specified.",
+		}
+	}
+
+	if !aws.ToBool(params.SkipFinalSnapshot) && params.FinalDBSnapshotIdentifier != nil {
+		if _, exists := f.snapshots[aws.ToString(params.FinalDBSnapshotIdentifier)]; exists {
+			return nil, &types.DBSnapshotAlreadyExistsFault{Message: aws.String(fmt.Sprintf(
+				"Cannot create the snapshot because a snapshot with the identifier %s already exists.",
+				aws.ToString(params.FinalDBSnapshotIdentifier)))}
+		}
+		f.addSnapshot(instance, aws.ToString(params.FinalDBSnapshotIdentifier), "manual", nil)
+	}
+
+	instance.DBInstanceStatus = aws.String("deleting")
+
+	return &rds.DeleteDBInstanceOutput{DBInstance: copyInstance(instance)}, nil
+}
+
+// CreateDBInstanceReadReplica registers a new replica of an available source instance in "creating" state.
+// The source may be given by identifier or ARN; the replica inherits its engine, storage and credentials.
+func (f *RDS) CreateDBInstanceReadReplica(
+	ctx context.Context,
+	params *rds.CreateDBInstanceReadReplicaInput,
+	optFns ...func(*rds.Options)) (*rds.CreateDBInstanceReadReplicaOutput, error) {
+
+	if err := f.record("CreateDBInstanceReadReplica"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBInstanceIdentifier)
+	if _, exists := f.instances[id]; exists {
+		return nil, &types.DBInstanceAlreadyExistsFault{Message: aws.String(fmt.Sprintf("DB instance %s already exists", id))}
+	}
+
+	sourceID := instanceIDFromRef(aws.ToString(params.SourceDBInstanceIdentifier))
+	source, ok := f.instances[sourceID]
+	if !ok {
+		return nil, instanceNotFound(sourceID)
+	}
+	if aws.ToString(source.DBInstanceStatus) != "available" {
+		return nil, &types.InvalidDBInstanceStateFault{Message: aws.String(fmt.Sprintf(
+			"DB instance %s is not in available state: %s", sourceID, aws.ToString(source.DBInstanceStatus)))}
+	}
+
+	parameterGroups, err := f.instanceParameterGroups(params.DBParameterGroupName, source.Engine)
+	if err != nil {
+		return nil, err
+	}
+
+	allocated := source.AllocatedStorage
+	if params.AllocatedStorage != nil {
+		allocated = params.AllocatedStorage
+	}
+	port := source.Endpoint.Port
+	if params.Port != nil {
+		port = params.Port
+	}
+
+	instance := &types.DBInstance{
+		DBInstanceIdentifier:                  params.DBInstanceIdentifier,
+		DBInstanceArn:                         aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", Region, AccountID, id)),
+		DBInstanceStatus:                      aws.String("creating"),
+		DbiResourceId:                         aws.String(f.ids.id("db-")),
+		DBInstanceClass:                       params.DBInstanceClass,
+		Engine:                                source.Engine,
+		EngineVersion:                         source.EngineVersion,
+		AllocatedStorage:                      allocated,
+		StorageType:                           source.StorageType,
+		StorageEncrypted:                      source.StorageEncrypted,
+		KmsKeyId:                              source.KmsKeyId,
+		MasterUsername:                        source.MasterUsername,
+		DBName:                                source.DBName,
+		MultiAZ:                               params.MultiAZ,
+		BackupRetentionPeriod:                 aws.Int32(0),
+		AutoMinorVersionUpgrade:               params.AutoMinorVersionUpgrade,
+		DeletionProtection:                    params.DeletionProtection,
+		CopyTagsToSnapshot:                    params.CopyTagsToSnapshot,
+		PubliclyAccessible:                    params.PubliclyAccessible,
+		IAMDatabaseAuthenticationEnabled:      params.EnableIAMDatabaseAuthentication,
+		EnabledCloudwatchLogsExports:          slices.Clone(params.EnableCloudwatchLogsExports),
+		DBParameterGroups:                     parameterGroups,
+		TagList:                               slices.Clone(params.Tags),
+		AvailabilityZone:                      aws.String(Region + "a"),
+		ReadReplicaSourceDBInstanceIdentifier: params.SourceDBInstanceIdentifier,
+		StatusInfos:                           []types.DBInstanceStatusInfo{replicationStatus("replicating", true)},
+		Endpoint: &types.Endpoint{
+			Address: aws.String(fmt.Sprintf("%s.fake.%s.rds.amazonaws.com", id, Region)),
+			Port:    port,
+		},
+	}
+	if params.StorageType != nil {
+		instance.StorageType = params.StorageType
+	}
+
+	source.ReadReplicaDBInstanceIdentifiers = append(source.ReadReplicaDBInstanceIdentifiers, id)
+	f.instances[id] = instance
+
+	return &rds.CreateDBInstanceReadReplicaOutput{DBInstance: copyInstance(instance)}, nil
+}
+
+// PromoteReadReplica detaches an available replica from its source and puts it into "modifying" state
+func (f *RDS) PromoteReadReplica(
+	ctx context.Context, params *rds.PromoteReadReplicaInput, optFns ...func(*rds.Options)) (*rds.PromoteReadReplicaOutput, error) {
+
+	if err := f.record("PromoteReadReplica"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBInstanceIdentifier)
+	instance, ok := f.instances[id]
+	if !ok {
+		return nil, instanceNotFound(id)
+	}
+	if instance.ReadReplicaSourceDBInstanceIdentifier == nil {
+		return nil, &types.InvalidDBInstanceStateFault{Message: aws.String(fmt.Sprintf("DB instance %s is not a read replica", id))}
+	}
+	if aws.ToString(instance.DBInstanceStatus) != "available" {
+		return nil, &types.InvalidDBInstanceStateFault{Message: aws.String(fmt.Sprintf(
+			"DB instance %s is not in available state: %s", id, aws.ToString(instance.DBInstanceStatus)))}
+	}
+
+	f.removeReplica(aws.ToString(instance.ReadReplicaSourceDBInstanceIdentifier), id)
+	instance.ReadReplicaSourceDBInstanceIdentifier = nil
+	instance.StatusInfos = nil
+	if params.BackupRetentionPeriod != nil {
+		instance.BackupRetentionPeriod = params.BackupRetentionPeriod
+	}
+	if params.PreferredBackupWindow != nil {
+		instance.PreferredBackupWindow = params.PreferredBackupWindow
+	}
+	instance.DBInstanceStatus = aws.String("modifying")
+
+	return &rds.PromoteReadReplicaOutput{DBInstance: copyInstance(instance)}, nil
+}
+
+// removeReplica removes a replica from the replica list of its source, given by identifier or ARN
+func (f *RDS) removeReplica(sourceRef, replicaID string) {
+	if source, ok := f.instances[instanceIDFromRef(sourceRef)]; ok {
+		source.ReadReplicaDBInstanceIdentifiers = slices.DeleteFunc(source.ReadReplicaDBInstanceIdentifiers,
+			func(replica string) bool { return replica == replicaID })
+	}
+}
+
+// instanceIDFromRef returns the instance identifier of an identifier or instance ARN
+func instanceIDFromRef(ref string) string {
+	return ref[strings.LastIndex(ref, ":")+1:]
+}
+
+// replicationStatus builds the read replication status info of a replica
+func replicationStatus(status string, normal bool) types.DBInstanceStatusInfo {
+	return types.DBInstanceStatusInfo{
+		StatusType: aws.String("read replication"),
+		Status:     aws.String(status),
+		Normal:     aws.Bool(normal),
+	}
+}
+
+// AddTagsToResource adds tags to the instance with the given ARN, replacing the values of existing keys
+func (f *RDS) AddTagsToResource(
+	ctx context.Context, params *rds.AddTagsToResourceInput, optFns ...func(*rds.Options)) (*rds.AddTagsToResourceOutput, error) {
+
+	if err := f.record("AddTagsToResource"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tagList, err := f.tagsByArn(aws.ToString(params.ResourceName))
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range params.Tags {
+		i := slices.IndexFunc(*tagList, func(t types.Tag) bool { return aws.ToString(t.Key) == aws.ToString(tag.Key) })
+		if i >= 0 {
+			(*tagList)[i] = tag
+		} else {
+			*tagList = append(*tagList, tag)
+		}
+	}
+	return &rds.AddTagsToResourceOutput{}, nil
+}
+
+// RemoveTagsFromResource removes tags from the instance with the given ARN
+func (f *RDS) RemoveTagsFromResource(
+	ctx context.Context, params *rds.RemoveTagsFromResourceInput, optFns ...func(*rds.Options)) (*rds.RemoveTagsFromResourceOutput, error) {
+
+	if err := f.record("RemoveTagsFromResource"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tagList, err := f.tagsByArn(aws.ToString(params.ResourceName))
+	if err != nil {
+		return nil, err
+	}
+	*tagList = slices.DeleteFunc(*tagList, func(t types.Tag) bool {
+		return slices.Contains(params.TagKeys, aws.ToString(t.Key))
+	})
+	return &rds.RemoveTagsFromResourceOutput{}, nil
+}
+
+// tagsByArn finds the tags of an instance, cluster or parameter group by ARN; callers must hold the lock
+func (f *RDS) tagsByArn(arn string) (*[]types.Tag, error) {
+	for _, instance := range f.instances {
+		if aws.ToString(instance.DBInstanceArn) == arn {
+			return &instance.TagList, nil
+		}
+	}
+	for _, cluster := range f.clusters {
+		if aws.ToString(cluster.DBClusterArn) == arn {
+			return &cluster.TagList, nil
+		}
+	}
+	for _, group := range f.parameterGroups {
+		if aws.ToString(group.group.DBParameterGroupArn) == arn {
+			return &group.tags, nil
+		}
+	}
+	if strings.Contains(arn, ":pg:") {
+		return nil, parameterGroupNotFound(arn)
+	}
+	if strings.Contains(arn, ":cluster:") {
+		return nil, clusterNotFound(arn)
+	}
+	return nil, instanceNotFound(arn)
+}
+
+// copyInstance copies an instance together with its nested structs and slices,
+// so callers cannot change the fake's state through the returned value
+func copyInstance(instance *types.DBInstance) *types.DBInstance {
+	copied := *instance
+	if instance.Endpoint != nil {
+		endpoint := *instance.Endpoint
+		copied.Endpoint = &endpoint
+	}
+	if instance.MasterUserSecret != nil {
+		secret := *instance.MasterUserSecret
+		copied.MasterUserSecret = &secret
+	}
+	if instance.PendingModifiedValues != nil {
+		pending := *instance.PendingModifiedValues
+		copied.PendingModifiedValues = &pending
+	}
+	if instance.DBSubnetGroup != nil {
+		group := *instance.DBSubnetGroup
+		copied.DBSubnetGroup = &group
+	}
+	copied.VpcSecurityGroups = slices.Clone(instance.VpcSecurityGroups)
+	copied.StatusInfos = slices.Clone(instance.StatusInfos)
+	copied.DBParameterGroups = slices.Clone(instance.DBParameterGroups)
+	copied.ReadReplicaDBInstanceIdentifiers = slices.Clone(instance.ReadReplicaDBInstanceIdentifiers)
+	copied.TagList = slices.Clone(instance.TagList)
+	copied.EnabledCloudwatchLogsExports = slices.Clone(instance.EnabledCloudwatchLogsExports)
+	return &copied
+}
+
+// subnetGroup describes the named DB subnet group of an instance, or nil for the default one
+func subnetGroup(name *string) *types.DBSubnetGroup {
+	if name == nil {
+		return nil
+	}
+	return &types.DBSubnetGroup{DBSubnetGroupName: name}
+}
+
+func instanceNotFound(id string) error {
+	return &types.DBInstanceNotFoundFault{Message: aws.String(fmt.Sprintf("DBInstance %s not found.", id))}
+}
+
+// DescribeDBClusters returns the cluster named by DBClusterIdentifier, or all clusters
+func (f *RDS) DescribeDBClusters(
+	ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+
+	if err := f.record("DescribeDBClusters"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if params.DBClusterIdentifier != nil {
+		cluster, ok := f.clusters[aws.ToString(params.DBClusterIdentifier)]
+		if !ok {
+			return nil, clusterNotFound(aws.ToString(params.DBClusterIdentifier))
+		}
+		return &rds.DescribeDBClustersOutput{DBClusters: []types.DBCluster{*copyCluster(cluster)}}, nil
+	}
+
+	output := &rds.DescribeDBClustersOutput{}
+	for _, id := range sortedKeys(f.clusters) {
+		output.DBClusters = append(output.DBClusters, *copyCluster(f.clusters[id]))
+	}
+	return output, nil
+}
+
+// CreateDBCluster registers a new cluster without members in "creating" state
+func (f *RDS) CreateDBCluster(
+	ctx context.Context, params *rds.CreateDBClusterInput, optFns ...func(*rds.Options)) (*rds.CreateDBClusterOutput, error) {
+
+	if err := f.record("CreateDBCluster"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBClusterIdentifier)
+	if _, exists := f.clusters[id]; exists {
+		return nil, &types.DBClusterAlreadyExistsFault{Message: aws.String(fmt.Sprintf("DB cluster %s already exists", id))}
+	}
+
+	port := aws.ToInt32(params.Port)
+	if port == 0 {
+		port = 5432
+		if aws.ToString(params.Engine) == "aurora-mysql" {
+			port = 3306
+		}
+	}
+
+	cluster := &types.DBCluster{
+		DBClusterIdentifier:        params.DBClusterIdentifier,
+		DBClusterArn:               aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:cluster:%s", Region, AccountID, id)),
+		DbClusterResourceId:        aws.String(f.ids.id("cluster-")),
+		Status:                     aws.String("creating"),
+		Engine:                     params.Engine,
+		EngineVersion:              params.EngineVersion,
+		DatabaseName:               params.DatabaseName,
+		MasterUsername:             params.MasterUsername,
+		StorageEncrypted:           params.StorageEncrypted,
+		KmsKeyId:                   params.KmsKeyId,
+		DBSubnetGroup:              params.DBSubnetGroupName,
+		BackupRetentionPeriod:      params.BackupRetentionPeriod,
+		PreferredBackupWindow:      params.PreferredBackupWindow,
+		PreferredMaintenanceWindow: params.PreferredMaintenanceWindow,
+		DeletionProtection:         params.DeletionProtection,
+		TagList:                    slices.Clone(params.Tags),
+		Endpoint:                   aws.String(fmt.Sprintf("%s.cluster-fake.%s.rds.amazonaws.com", id, Region)),
+		ReaderEndpoint:             aws.String(fmt.Sprintf("%s.cluster-ro-fake.%s.rds.amazonaws.com", id, Region)),
+		Port:                       aws.Int32(port),
+	}
+
+	if scaling := params.ServerlessV2ScalingConfiguration; scaling != nil {
+		cluster.ServerlessV2ScalingConfiguration = &types.ServerlessV2ScalingConfigurationInfo{
+			MinCapacity: scaling.MinCapacity,
+			MaxCapacity: scaling.MaxCapacity,
+		}
+	}
+
+	if aws.ToBool(params.ManageMasterUserPassword) {
+		cluster.MasterUserSecret = &types.MasterUserSecret{
+			SecretArn:    aws.String(fmt.Sprintf("arn:aws:secretsmanager:%s:%s:secret:rds!cluster-%s", Region, AccountID, id)),
+			SecretStatus: aws.String("active"),
+		}
+	}
+
+	f.clusters[id] = cluster
+
+	return &rds.CreateDBClusterOutput{DBCluster: copyCluster(cluster)}, nil
+}
+
+// ModifyDBCluster applies the requested changes and puts the cluster into "modifying" state
+func (f *RDS) ModifyDBCluster(
+	ctx context.Context, params *rds.ModifyDBClusterInput, optFns ...func(*rds.Options)) (*rds.ModifyDBClusterOutput, error) {
+
+	if err := f.record("ModifyDBCluster"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBClusterIdentifier)
+	cluster, ok := f.clusters[id]
+	if !ok {
+		return nil, clusterNotFound(id)
+	}
+	if aws.ToString(cluster.Status) == "deleting" {
+		return nil, &types.InvalidDBClusterStateFault{Message: aws.String(fmt.Sprintf("DB cluster %s is being deleted", id))}
+	}
+
+	// Without ApplyImmediately an engine upgrade waits for the maintenance window
+	if params.EngineVersion != nil {
+		if aws.ToBool(params.ApplyImmediately) {
+			cluster.EngineVersion = params.EngineVersion
+			cluster.PendingModifiedValues = nil
+		} else {
+			cluster.PendingModifiedValues = &types.ClusterPendingModifiedValues{EngineVersion: params.EngineVersion}
+		}
+	}
+	if params.BackupRetentionPeriod != nil {
+		cluster.BackupRetentionPeriod = params.BackupRetentionPeriod
+	}
+	if params.PreferredBackupWindow != nil {
+		cluster.PreferredBackupWindow = params.PreferredBackupWindow
+	}
+	if params.PreferredMaintenanceWindow != nil {
+		cluster.PreferredMaintenanceWindow = params.PreferredMaintenanceWindow
+	}
+	if params.DeletionProtection != nil {
+		cluster.DeletionProtection = params.DeletionProtection
+	}
+	if scaling := params.ServerlessV2ScalingConfiguration; scaling != nil {
+		cluster.ServerlessV2ScalingConfiguration = &types.ServerlessV2ScalingConfigurationInfo{
+			MinCapacity: scaling.MinCapacity,
+			MaxCapacity: scaling.MaxCapacity,
+		}
+	}
+
+	cluster.Status = aws.String("modifying")
+
+	return &rds.ModifyDBClusterOutput{DBCluster: copyCluster(cluster)}, nil
+}
+
+// DeleteDBCluster puts the cluster into "deleting" state.
+// Like the real API it fails while deletion protection is enabled or members are not being deleted.
+func (f *RDS) DeleteDBCluster(
+	ctx context.Context, params *rds.DeleteDBClusterInput, optFns ...func(*rds.Options)) (*rds.DeleteDBClusterOutput, error) {
+
+	if err := f.record("DeleteDBCluster"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBClusterIdentifier)
+	cluster, ok := f.clusters[id]
+	if !ok {
+		return nil, clusterNotFound(id)
+	}
+
+	if aws.ToString(cluster.Status) == "deleting" {
+		return nil, &types.InvalidDBClusterStateFault{
+			Message: aws.String(fmt.Sprintf("Cluster %s is already being deleted.", id)),
+		}
+	}
+	if aws.ToBool(cluster.DeletionProtection) {
+		return nil, &smithy.GenericAPIError{
+			Code:    "InvalidParameterCombination",
+			Message: "Cannot delete protected Cluster, please disable deletion protection and try again.",
+		}
+	}
+	for _, member := range cluster.DBClusterMembers {
+		if instance := f.instances[aws.ToString(member.DBInstanceIdentifier)]; aws.ToString(instance.DBInstanceStatus) != "deleting" {
+			return nil, &types.InvalidDBClusterStateFault{
+				Message: aws.String("Cluster cannot be deleted, it still contains DB instances in non-deleting state."),
+			}
+		}
+	}
+	if !aws.ToBool(params.SkipFinalSnapshot) && aws.ToString(params.FinalDBSnapshotIdentifier) == "" {
+		return nil, &smithy.GenericAPIError{
+			Code:    "InvalidParameterCombination",
+			Message: "FinalDBSnapshotIdentifier is required unless SkipFinalSnapshot is specified.",
+		}
+	}
+
+	cluster.Status = aws.String("deleting")
+
+	return &rds.DeleteDBClusterOutput{DBCluster: copyCluster(cluster)}, nil
+}
+
+// removeMember removes an instance from the cluster members, failing over to the
+// next member when it was the writer
+func removeMember(cluster *types.DBCluster, instanceID string) {
+	i := slices.IndexFunc(cluster.DBClusterMembers, func(m types.DBClusterMember) bool {
+		return aws.ToString(m.DBInstanceIdentifier) == instanceID
+	})
+	if i < 0 {
+		return
+	}
+
+	writer := aws.ToBool(cluster.DBClusterMembers[i].IsClusterWriter)
+	cluster.DBClusterMembers = slices.Delete(cluster.DBClusterMembers, i, i+1)
+	if writer && len(cluster.DBClusterMembers) > 0 {
+		cluster.DBClusterMembers[0].IsClusterWriter = aws.Bool(true)
+	}
+}
+
+// copyCluster copies a cluster together with its nested structs and slices,
+// so callers cannot change the fake's state through the returned value
+func copyCluster(cluster *types.DBCluster) *types.DBCluster {
+	copied := *cluster
+	if cluster.MasterUserSecret != nil {
+		secret := *cluster.MasterUserSecret
+		copied.MasterUserSecret = &secret
+	}
+	if cluster.ServerlessV2ScalingConfiguration != nil {
+		scaling := *cluster.ServerlessV2ScalingConfiguration
+		copied.ServerlessV2ScalingConfiguration = &scaling
+	}
+	if cluster.PendingModifiedValues != nil {
+		pending := *cluster.PendingModifiedValues
+		copied.PendingModifiedValues = &pending
+	}
+	copied.DBClusterMembers = slices.Clone(cluster.DBClusterMembers)
+	copied.TagList = slices.Clone(cluster.TagList)
+	return &copied
+}
+
+func clusterNotFound(id string) error {
+	return &types.DBClusterNotFoundFault{Message: aws.String(fmt.Sprintf("DBCluster %s not found.", id))}
+}
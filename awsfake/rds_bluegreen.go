@@ -0,0 +1,290 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+)
+
+// BlueGreenDeployment returns a copy of the blue/green deployment with the given identifier, or nil if it does not exist
+func (f *RDS) BlueGreenDeployment(id string) *types.BlueGreenDeployment {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	deployment, ok := f.deployments[id]
+	if !ok {
+		return nil
+	}
+	return copyDeployment(deployment)
+}
+
+// CreateBlueGreenDeployment creates a green copy of an available source instance, given by ARN, with the
+// target engine version and parameter group. The deployment is "PROVISIONING" until Advance made the
+// green instance available.
+func (f *RDS) CreateBlueGreenDeployment(
+	ctx context.Context,
+	params *rds.CreateBlueGreenDeploymentInput,
+	optFns ...func(*rds.Options)) (*rds.CreateBlueGreenDeploymentOutput, error) {
+
+	if err := f.record("CreateBlueGreenDeployment"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.BlueGreenDeploymentName)
+	for _, deployment := range f.deployments {
+		if aws.ToString(deployment.BlueGreenDeploymentName) == name {
+			return nil, &types.BlueGreenDeploymentAlreadyExistsFault{Message: aws.String(fmt.Sprintf(
+				"A blue/green deployment with the name %s already exists.", name))}
+		}
+	}
+
+	sourceID := instanceIDFromRef(aws.ToString(params.Source))
+	source, ok := f.instances[sourceID]
+	if !ok || aws.ToString(source.DBInstanceArn) != aws.ToString(params.Source) {
+		return nil, &types.SourceDatabaseNotSupportedFault{Message: aws.String(fmt.Sprintf(
+			"The source %s is not a DB instance.", aws.ToString(params.Source)))}
+	}
+	if aws.ToString(source.DBInstanceStatus) != "available" {
+		return nil, &types.InvalidDBInstanceStateFault{Message: aws.String(fmt.Sprintf(
+			"DB instance %s is not in available state: %s", sourceID, aws.ToString(source.DBInstanceStatus)))}
+	}
+
+	green := copyInstance(source)
+	greenID := fmt.Sprintf("%s-green-%s", sourceID, f.ids.id("")[6:])
+	green.DBInstanceIdentifier = aws.String(greenID)
+	green.DBInstanceArn = aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", Region, AccountID, greenID))
+	green.DbiResourceId = aws.String(f.ids.id("db-"))
+	green.DBInstanceStatus = aws.String("creating")
+	green.Endpoint.Address = aws.String(fmt.Sprintf("%s.fake.%s.rds.amazonaws.com", greenID, Region))
+	green.PendingModifiedValues = nil
+	green.ReadReplicaDBInstanceIdentifiers = nil
+	if params.TargetEngineVersion != nil {
+		green.EngineVersion = params.TargetEngineVersion
+	}
+	if params.TargetDBParameterGroupName != nil {
+		groupName := aws.ToString(params.TargetDBParameterGroupName)
+		if _, ok := f.parameterGroups[groupName]; !ok {
+			return nil, parameterGroupNotFound(groupName)
+		}
+		green.DBParameterGroups = []types.DBParameterGroupStatus{{
+			DBParameterGroupName: aws.String(groupName),
+			ParameterApplyStatus: aws.String(parametersInSync),
+		}}
+	}
+	f.instances[greenID] = green
+
+	id := f.ids.id("bgd-")
+	deployment := &types.BlueGreenDeployment{
+		BlueGreenDeploymentIdentifier: aws.String(id),
+		BlueGreenDeploymentName:       aws.String(name),
+		Source:                        source.DBInstanceArn,
+		Target:                        green.DBInstanceArn,
+		Status:                        aws.String("PROVISIONING"),
+		CreateTime:                    aws.Time(time.Now().UTC()),
+		SwitchoverDetails: []types.SwitchoverDetail{{
+			SourceMember: source.DBInstanceArn,
+			TargetMember: green.DBInstanceArn,
+			Status:       aws.String("PROVISIONING"),
+		}},
+		TagList: slices.Clone(params.Tags),
+	}
+	f.deployments[id] = deployment
+
+	return &rds.CreateBlueGreenDeploymentOutput{BlueGreenDeployment: copyDeployment(deployment)}, nil
+}
+
+// DescribeBlueGreenDeployments returns the deployment named by BlueGreenDeploymentIdentifier, or all deployments
+func (f *RDS) DescribeBlueGreenDeployments(
+	ctx context.Context,
+	params *rds.DescribeBlueGreenDeploymentsInput,
+	optFns ...func(*rds.Options)) (*rds.DescribeBlueGreenDeploymentsOutput, error) {
+
+	if err := f.record("DescribeBlueGreenDeployments"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if params.BlueGreenDeploymentIdentifier != nil {
+		id := aws.ToString(params.BlueGreenDeploymentIdentifier)
+		deployment, ok := f.deployments[id]
+		if !ok {
+			return nil, deploymentNotFound(id)
+		}
+		return &rds.DescribeBlueGreenDeploymentsOutput{BlueGreenDeployments: []types.BlueGreenDeployment{*copyDeployment(deployment)}}, nil
+	}
+
+	output := &rds.DescribeBlueGreenDeploymentsOutput{}
+	for _, id := range sortedKeys(f.deployments) {
+		output.BlueGreenDeployments = append(output.BlueGreenDeployments, *copyDeployment(f.deployments[id]))
+	}
+	return output, nil
+}
+
+// SwitchoverBlueGreenDeployment switches an available deployment over: the blue instance is renamed
+// with an -old1 suffix and the green instance takes over its identifier and endpoint. The deployment
+// is "SWITCHOVER_IN_PROGRESS" until Advance; its source then names the old blue instance.
+func (f *RDS) SwitchoverBlueGreenDeployment(
+	ctx context.Context,
+	params *rds.SwitchoverBlueGreenDeploymentInput,
+	optFns ...func(*rds.Options)) (*rds.SwitchoverBlueGreenDeploymentOutput, error) {
+
+	if err := f.record("SwitchoverBlueGreenDeployment"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.BlueGreenDeploymentIdentifier)
+	deployment, ok := f.deployments[id]
+	if !ok {
+		return nil, deploymentNotFound(id)
+	}
+	if aws.ToString(deployment.Status) != "AVAILABLE" {
+		return nil, &types.InvalidBlueGreenDeploymentStateFault{Message: aws.String(fmt.Sprintf(
+			"The blue/green deployment %s is not in available state: %s", id, aws.ToString(deployment.Status)))}
+	}
+
+	blueID := instanceIDFromRef(aws.ToString(deployment.Source))
+	greenID := instanceIDFromRef(aws.ToString(deployment.Target))
+	blue, green := f.instances[blueID], f.instances[greenID]
+	if blue == nil || green == nil {
+		return nil, &smithy.GenericAPIError{Code: "InvalidParameterValue", Message: "The blue/green deployment lost one of its instances."}
+	}
+
+	oldID := blueID + "-old1"
+	delete(f.instances, blueID)
+	delete(f.instances, greenID)
+
+	blueEndpoint := aws.ToString(blue.Endpoint.Address)
+	blue.DBInstanceIdentifier = aws.String(oldID)
+	blue.DBInstanceArn = aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", Region, AccountID, oldID))
+	blue.Endpoint.Address = aws.String(fmt.Sprintf("%s.fake.%s.rds.amazonaws.com", oldID, Region))
+	blue.DBInstanceStatus = aws.String("renaming")
+	f.instances[oldID] = blue
+
+	green.DBInstanceIdentifier = aws.String(blueID)
+	green.DBInstanceArn = aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", Region, AccountID, blueID))
+	green.Endpoint.Address = aws.String(blueEndpoint)
+	green.DBInstanceStatus = aws.String("renaming")
+	f.instances[blueID] = green
+
+	deployment.Source = blue.DBInstanceArn
+	deployment.Target = green.DBInstanceArn
+	deployment.Status = aws.String("SWITCHOVER_IN_PROGRESS")
+	deployment.SwitchoverDetails = []types.SwitchoverDetail{{
+		SourceMember: blue.DBInstanceArn,
+		TargetMember: green.DBInstanceArn,
+		Status:       aws.String("SWITCHOVER_IN_PROGRESS"),
+	}}
+
+	return &rds.SwitchoverBlueGreenDeploymentOutput{BlueGreenDeployment: copyDeployment(deployment)}, nil
+}
+
+// DeleteBlueGreenDeployment puts a deployment into "DELETING" state, removed on Advance. DeleteTarget
+// also deletes the green instance, which is only allowed before the switchover.
+func (f *RDS) DeleteBlueGreenDeployment(
+	ctx context.Context,
+	params *rds.DeleteBlueGreenDeploymentInput,
+	optFns ...func(*rds.Options)) (*rds.DeleteBlueGreenDeploymentOutput, error) {
+
+	if err := f.record("DeleteBlueGreenDeployment"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.BlueGreenDeploymentIdentifier)
+	deployment, ok := f.deployments[id]
+	if !ok {
+		return nil, deploymentNotFound(id)
+	}
+
+	status := aws.ToString(deployment.Status)
+	if status == "SWITCHOVER_IN_PROGRESS" {
+		return nil, &types.InvalidBlueGreenDeploymentStateFault{Message: aws.String(fmt.Sprintf(
+			"The blue/green deployment %s is switching over.", id))}
+	}
+	if aws.ToBool(params.DeleteTarget) {
+		if status == "SWITCHOVER_COMPLETED" {
+			return nil, &types.InvalidBlueGreenDeploymentStateFault{Message: aws.String(fmt.Sprintf(
+				"The target of blue/green deployment %s cannot be deleted after the switchover.", id))}
+		}
+		if green, ok := f.instances[instanceIDFromRef(aws.ToString(deployment.Target))]; ok {
+			green.DBInstanceStatus = aws.String("deleting")
+		}
+	}
+	deployment.Status = aws.String("DELETING")
+
+	return &rds.DeleteBlueGreenDeploymentOutput{BlueGreenDeployment: copyDeployment(deployment)}, nil
+}
+
+// advanceDeployments moves provisioned deployments with an available green instance to "AVAILABLE",
+// completes switchovers and removes deleted deployments; callers must hold the lock
+func (f *RDS) advanceDeployments() {
+	for id, deployment := range f.deployments {
+		switch aws.ToString(deployment.Status) {
+		case "PROVISIONING":
+			green, ok := f.instances[instanceIDFromRef(aws.ToString(deployment.Target))]
+			if ok && aws.ToString(green.DBInstanceStatus) == "available" {
+				setDeploymentStatus(deployment, "AVAILABLE")
+			}
+		case "SWITCHOVER_IN_PROGRESS":
+			setDeploymentStatus(deployment, "SWITCHOVER_COMPLETED")
+		case "DELETING":
+			delete(f.deployments, id)
+		}
+	}
+}
+
+// setDeploymentStatus sets the status of a deployment and its switchover details
+func setDeploymentStatus(deployment *types.BlueGreenDeployment, status string) {
+	deployment.Status = aws.String(status)
+	for i := range deployment.SwitchoverDetails {
+		deployment.SwitchoverDetails[i].Status = aws.String(status)
+	}
+}
+
+// copyDeployment copies a deployment together with its slices,
+// so callers cannot change the fake's state through the returned value
+func copyDeployment(deployment *types.BlueGreenDeployment) *types.BlueGreenDeployment {
+	copied := *deployment
+	copied.SwitchoverDetails = slices.Clone(deployment.SwitchoverDetails)
+	copied.Tasks = slices.Clone(deployment.Tasks)
+	copied.TagList = slices.Clone(deployment.TagList)
+	return &copied
+}
+
+// majorVersion returns the major version of an engine version: the first number for Postgres 10
+// and later, the first two for other engines and older Postgres
+func majorVersion(engine, version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if first, err := strconv.Atoi(parts[0]); err == nil && engine == "postgres" && first >= 10 {
+		return parts[0]
+	}
+	if len(parts) >= 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return parts[0]
+}
+
+func deploymentNotFound(id string) error {
+	return &types.BlueGreenDeploymentNotFoundFault{Message: aws.String(fmt.Sprintf("BlueGreenDeployment %s not found.", id))}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+)
+
+// engineVersions is the engine version catalog of the fake, oldest first
+var engineVersions = map[string][]string{
+	"postgres": {"15.7", "15.8", "16.3", "16.4", "17.2", "18.1"},
+	"mysql":    {"8.0.39", "8.0.40", "8.4.3"},
+	"mariadb":  {"10.11.9", "11.4.4"},
+}
+
+// orderableClasses and orderableStorageTypes are offered for every engine version of the catalog
+var (
+	orderableClasses = []string{
+		"db.t3.micro", "db.t3.small", "db.t3.medium", "db.t3.large",
+		"db.m5.large", "db.m5.xlarge", "db.r6g.large", "db.r6g.xlarge",
+	}
+	orderableStorageTypes = []string{"standard", "gp2", "gp3", "io1", "io2"}
+)
+
+// defaultPageSize is the number of records the describe calls return without MaxRecords
+const defaultPageSize = 100
+
+// DisableMultiAZ makes an instance class of an engine orderable for single-AZ instances only
+func (f *RDS) DisableMultiAZ(engine, instanceClass string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.singleAZClasses[engine+"/"+instanceClass] = true
+}
+
+// DescribeDBEngineVersions returns the catalog versions of the engine, limited to EngineVersion if given
+func (f *RDS) DescribeDBEngineVersions(
+	ctx context.Context,
+	params *rds.DescribeDBEngineVersionsInput,
+	optFns ...func(*rds.Options)) (*rds.DescribeDBEngineVersionsOutput, error) {
+
+	if err := f.record("DescribeDBEngineVersions"); err != nil {
+		return nil, err
+	}
+
+	engine := aws.ToString(params.Engine)
+	var versions []types.DBEngineVersion
+	for _, version := range engineVersions[engine] {
+		if params.EngineVersion != nil && aws.ToString(params.EngineVersion) != version {
+			continue
+		}
+		versions = append(versions, types.DBEngineVersion{
+			Engine:                 aws.String(engine),
+			EngineVersion:          aws.String(version),
+			DBParameterGroupFamily: aws.String(engine + majorVersion(engine, version)),
+			Status:                 aws.String("available"),
+		})
+	}
+
+	page, marker, err := paginate(versions, params.Marker, params.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	return &rds.DescribeDBEngineVersionsOutput{DBEngineVersions: page, Marker: marker}, nil
+}
+
+// DescribeOrderableDBInstanceOptions returns the catalog classes and storage types of the engine,
+// limited to EngineVersion and DBInstanceClass if given
+func (f *RDS) DescribeOrderableDBInstanceOptions(
+	ctx context.Context,
+	params *rds.DescribeOrderableDBInstanceOptionsInput,
+	optFns ...func(*rds.Options)) (*rds.DescribeOrderableDBInstanceOptionsOutput, error) {
+
+	if err := f.record("DescribeOrderableDBInstanceOptions"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	engine := aws.ToString(params.Engine)
+	var options []types.OrderableDBInstanceOption
+	for _, version := range engineVersions[engine] {
+		if params.EngineVersion != nil && aws.ToString(params.EngineVersion) != version {
+			continue
+		}
+		for _, class := range orderableClasses {
+			if params.DBInstanceClass != nil && aws.ToString(params.DBInstanceClass) != class {
+				continue
+			}
+			for _, storageType := range orderableStorageTypes {
+				options = append(options, types.OrderableDBInstanceOption{
+					Engine:                    aws.String(engine),
+					EngineVersion:             aws.String(version),
+					DBInstanceClass:           aws.String(class),
+					StorageType:               aws.String(storageType),
+					MultiAZCapable:            aws.Bool(!f.singleAZClasses[engine+"/"+class]),
+					SupportsIops:              aws.Bool(storageType != "standard" && storageType != "gp2"),
+					SupportsStorageThroughput: aws.Bool(storageType == "gp3"),
+				})
+			}
+		}
+	}
+
+	page, marker, err := paginate(options, params.Marker, params.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	return &rds.DescribeOrderableDBInstanceOptionsOutput{OrderableDBInstanceOptions: page, Marker: marker}, nil
+}
+
+// paginate returns the page of items starting at the marker, and the marker of the next page if any
+func paginate[T any](items []T, marker *string, maxRecords *int32) ([]T, *string, error) {
+	start := 0
+	if marker != nil {
+		var err error
+		if start, err = strconv.Atoi(aws.ToString(marker)); err != nil || start < 0 || start > len(items) {
+			return nil, nil, &smithy.GenericAPIError{Code: "InvalidParameterValue", Message: fmt.Sprintf("Invalid marker %s.", aws.ToString(marker))}
+		}
+	}
+
+	size := int(aws.ToInt32(maxRecords))
+	if size <= 0 {
+		size = defaultPageSize
+	}
+	end := min(start+size, len(items))
+	if end == len(items) {
+		return slices.Clone(items[start:]), nil, nil
+	}
+	return slices.Clone(items[start:end]), aws.String(strconv.Itoa(end)), nil
+}
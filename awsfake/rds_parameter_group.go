@@ -0,0 +1,394 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/smithy-go"
+)
+
+// Parameter apply statuses of an instance's parameter group
+const (
+	parametersInSync        = "in-sync"
+	parametersApplying      = "applying"
+	parametersPendingReboot = "pending-reboot"
+)
+
+// engineParameter is a parameter every group of an engine family has, with its engine default
+type engineParameter struct {
+	applyType    string
+	dataType     string
+	defaultValue string
+}
+
+// engineParameters is the small parameter catalog of the fake, by engine family prefix.
+// Groups of other families cannot be created.
+var engineParameters = map[string]map[string]engineParameter{
+	"postgres": {
+		"max_connections":            {applyType: "static", dataType: "integer", defaultValue: "100"},
+		"shared_buffers":             {applyType: "static", dataType: "integer", defaultValue: "16384"},
+		"work_mem":                   {applyType: "dynamic", dataType: "integer", defaultValue: "4096"},
+		"log_min_duration_statement": {applyType: "dynamic", dataType: "integer", defaultValue: "-1"},
+		"rds.force_ssl":              {applyType: "dynamic", dataType: "boolean", defaultValue: "1"},
+	},
+	"mysql": {
+		"max_connections":         {applyType: "dynamic", dataType: "integer", defaultValue: "150"},
+		"innodb_buffer_pool_size": {applyType: "static", dataType: "integer", defaultValue: "134217728"},
+		"slow_query_log":          {applyType: "dynamic", dataType: "boolean", defaultValue: "0"},
+		"long_query_time":         {applyType: "dynamic", dataType: "float", defaultValue: "10"},
+	},
+}
+
+// fakeParameterGroup is a DB parameter group with the parameters set on it
+type fakeParameterGroup struct {
+	group      types.DBParameterGroup
+	tags       []types.Tag
+	catalog    map[string]engineParameter
+	parameters map[string]types.Parameter
+}
+
+// SetParameterApplyStatus sets the parameter apply status of an instance's parameter group
+func (f *RDS) SetParameterApplyStatus(id, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if instance, ok := f.instances[id]; ok {
+		for i := range instance.DBParameterGroups {
+			instance.DBParameterGroups[i].ParameterApplyStatus = aws.String(status)
+		}
+	}
+}
+
+// DescribeDBParameterGroups returns the group named by DBParameterGroupName, or all groups
+func (f *RDS) DescribeDBParameterGroups(
+	ctx context.Context,
+	params *rds.DescribeDBParameterGroupsInput,
+	optFns ...func(*rds.Options)) (*rds.DescribeDBParameterGroupsOutput, error) {
+
+	if err := f.record("DescribeDBParameterGroups"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if params.DBParameterGroupName != nil {
+		group, ok := f.parameterGroups[aws.ToString(params.DBParameterGroupName)]
+		if !ok {
+			return nil, parameterGroupNotFound(aws.ToString(params.DBParameterGroupName))
+		}
+		return &rds.DescribeDBParameterGroupsOutput{DBParameterGroups: []types.DBParameterGroup{group.group}}, nil
+	}
+
+	output := &rds.DescribeDBParameterGroupsOutput{}
+	for _, name := range sortedKeys(f.parameterGroups) {
+		output.DBParameterGroups = append(output.DBParameterGroups, f.parameterGroups[name].group)
+	}
+	return output, nil
+}
+
+// CreateDBParameterGroup creates a group of a known engine family with all parameters at their defaults
+func (f *RDS) CreateDBParameterGroup(
+	ctx context.Context,
+	params *rds.CreateDBParameterGroupInput,
+	optFns ...func(*rds.Options)) (*rds.CreateDBParameterGroupOutput, error) {
+
+	if err := f.record("CreateDBParameterGroup"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.DBParameterGroupName)
+	if _, exists := f.parameterGroups[name]; exists {
+		return nil, &types.DBParameterGroupAlreadyExistsFault{Message: aws.String(fmt.Sprintf(
+			"Parameter group %s already exists", name))}
+	}
+
+	family := aws.ToString(params.DBParameterGroupFamily)
+	catalog := familyParameters(family)
+	if catalog == nil {
+		return nil, &smithy.GenericAPIError{
+			Code:    "InvalidParameterValue",
+			Message: fmt.Sprintf("DBParameterGroupFamily %s is not a valid parameter group family", family),
+		}
+	}
+
+	group := &fakeParameterGroup{
+		group: types.DBParameterGroup{
+			DBParameterGroupName:   aws.String(name),
+			DBParameterGroupArn:    aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:pg:%s", Region, AccountID, name)),
+			DBParameterGroupFamily: aws.String(family),
+			Description:            params.Description,
+		},
+		tags:       slices.Clone(params.Tags),
+		catalog:    catalog,
+		parameters: make(map[string]types.Parameter),
+	}
+	f.parameterGroups[name] = group
+
+	return &rds.CreateDBParameterGroupOutput{DBParameterGroup: &group.group}, nil
+}
+
+// DescribeDBParameters returns every parameter of the group; Source "user" limits it to the modified ones
+func (f *RDS) DescribeDBParameters(
+	ctx context.Context,
+	params *rds.DescribeDBParametersInput,
+	optFns ...func(*rds.Options)) (*rds.DescribeDBParametersOutput, error) {
+
+	if err := f.record("DescribeDBParameters"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.DBParameterGroupName)
+	group, ok := f.parameterGroups[name]
+	if !ok {
+		return nil, parameterGroupNotFound(name)
+	}
+
+	output := &rds.DescribeDBParametersOutput{}
+	for _, parameterName := range sortedKeys(group.catalog) {
+		parameter, modified := group.parameters[parameterName]
+		if !modified {
+			if aws.ToString(params.Source) == "user" {
+				continue
+			}
+			catalog := group.catalog[parameterName]
+			parameter = types.Parameter{
+				ParameterName:  aws.String(parameterName),
+				ParameterValue: aws.String(catalog.defaultValue),
+				ApplyType:      aws.String(catalog.applyType),
+				DataType:       aws.String(catalog.dataType),
+				IsModifiable:   aws.Bool(true),
+				Source:         aws.String("engine-default"),
+				ApplyMethod:    types.ApplyMethodPendingReboot,
+			}
+		}
+		output.Parameters = append(output.Parameters, parameter)
+	}
+	return output, nil
+}
+
+// ModifyDBParameterGroup sets up to 20 parameters. Static parameters need the pending-reboot
+// apply method. Instances using the group start applying the change or wait for a reboot.
+func (f *RDS) ModifyDBParameterGroup(
+	ctx context.Context,
+	params *rds.ModifyDBParameterGroupInput,
+	optFns ...func(*rds.Options)) (*rds.ModifyDBParameterGroupOutput, error) {
+
+	if err := f.record("ModifyDBParameterGroup"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.DBParameterGroupName)
+	group, err := f.modifiableGroup(name, len(params.Parameters))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, parameter := range params.Parameters {
+		parameterName := aws.ToString(parameter.ParameterName)
+		catalog, known := group.catalog[parameterName]
+		if !known {
+			return nil, &smithy.GenericAPIError{
+				Code:    "InvalidParameterValue",
+				Message: fmt.Sprintf("Could not find parameter with name: %s", parameterName),
+			}
+		}
+		if catalog.applyType == "static" && parameter.ApplyMethod != types.ApplyMethodPendingReboot {
+			return nil, &smithy.GenericAPIError{
+				Code:    "InvalidParameterCombination",
+				Message: fmt.Sprintf("cannot use immediate apply method for static parameter %s", parameterName),
+			}
+		}
+	}
+
+	for _, parameter := range params.Parameters {
+		parameterName := aws.ToString(parameter.ParameterName)
+		catalog := group.catalog[parameterName]
+		group.parameters[parameterName] = types.Parameter{
+			ParameterName:  aws.String(parameterName),
+			ParameterValue: parameter.ParameterValue,
+			ApplyType:      aws.String(catalog.applyType),
+			DataType:       aws.String(catalog.dataType),
+			IsModifiable:   aws.Bool(true),
+			Source:         aws.String("user"),
+			ApplyMethod:    parameter.ApplyMethod,
+		}
+		f.applyParameterChange(name, parameter.ApplyMethod)
+	}
+
+	return &rds.ModifyDBParameterGroupOutput{DBParameterGroupName: aws.String(name)}, nil
+}
+
+// ResetDBParameterGroup returns the listed parameters, or all of them, to their engine defaults
+func (f *RDS) ResetDBParameterGroup(
+	ctx context.Context,
+	params *rds.ResetDBParameterGroupInput,
+	optFns ...func(*rds.Options)) (*rds.ResetDBParameterGroupOutput, error) {
+
+	if err := f.record("ResetDBParameterGroup"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.DBParameterGroupName)
+	group, err := f.modifiableGroup(name, len(params.Parameters))
+	if err != nil {
+		return nil, err
+	}
+
+	reset := params.Parameters
+	if aws.ToBool(params.ResetAllParameters) {
+		reset = nil
+		for _, parameter := range group.parameters {
+			reset = append(reset, types.Parameter{ParameterName: parameter.ParameterName, ApplyMethod: types.ApplyMethodPendingReboot})
+		}
+	}
+	for _, parameter := range reset {
+		delete(group.parameters, aws.ToString(parameter.ParameterName))
+		f.applyParameterChange(name, parameter.ApplyMethod)
+	}
+
+	return &rds.ResetDBParameterGroupOutput{DBParameterGroupName: aws.String(name)}, nil
+}
+
+// DeleteDBParameterGroup deletes a group that no instance uses
+func (f *RDS) DeleteDBParameterGroup(
+	ctx context.Context,
+	params *rds.DeleteDBParameterGroupInput,
+	optFns ...func(*rds.Options)) (*rds.DeleteDBParameterGroupOutput, error) {
+
+	if err := f.record("DeleteDBParameterGroup"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.DBParameterGroupName)
+	if _, ok := f.parameterGroups[name]; !ok {
+		return nil, parameterGroupNotFound(name)
+	}
+	for _, id := range sortedKeys(f.instances) {
+		if usesParameterGroup(f.instances[id], name) {
+			return nil, &types.InvalidDBParameterGroupStateFault{Message: aws.String(fmt.Sprintf(
+				"One or more database instances are still members of this parameter group %s, so the group cannot be deleted", name))}
+		}
+	}
+
+	delete(f.parameterGroups, name)
+	return &rds.DeleteDBParameterGroupOutput{}, nil
+}
+
+// ListTagsForResource returns the tags of the instance, cluster or parameter group with the given ARN
+func (f *RDS) ListTagsForResource(
+	ctx context.Context, params *rds.ListTagsForResourceInput, optFns ...func(*rds.Options)) (*rds.ListTagsForResourceOutput, error) {
+
+	if err := f.record("ListTagsForResource"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tagList, err := f.tagsByArn(aws.ToString(params.ResourceName))
+	if err != nil {
+		return nil, err
+	}
+	return &rds.ListTagsForResourceOutput{TagList: slices.Clone(*tagList)}, nil
+}
+
+// modifiableGroup returns a group that accepts a change of count parameters; callers must hold the lock
+func (f *RDS) modifiableGroup(name string, count int) (*fakeParameterGroup, error) {
+	group, ok := f.parameterGroups[name]
+	if !ok {
+		return nil, parameterGroupNotFound(name)
+	}
+	if strings.HasPrefix(name, "default.") {
+		return nil, &smithy.GenericAPIError{
+			Code:    "InvalidParameterValue",
+			Message: fmt.Sprintf("Default parameter groups cannot be modified: %s", name),
+		}
+	}
+	if count > 20 {
+		return nil, &smithy.GenericAPIError{
+			Code:    "InvalidParameterValue",
+			Message: "A maximum of 20 parameters can be modified in a single request",
+		}
+	}
+	return group, nil
+}
+
+// applyParameterChange moves the instances using the group to applying a change, or to wait for a
+// reboot; a pending reboot is never downgraded to applying. Callers must hold the lock.
+func (f *RDS) applyParameterChange(groupName string, method types.ApplyMethod) {
+	for _, instance := range f.instances {
+		for i := range instance.DBParameterGroups {
+			status := &instance.DBParameterGroups[i]
+			if aws.ToString(status.DBParameterGroupName) != groupName {
+				continue
+			}
+			switch {
+			case method == types.ApplyMethodPendingReboot:
+				status.ParameterApplyStatus = aws.String(parametersPendingReboot)
+			case aws.ToString(status.ParameterApplyStatus) != parametersPendingReboot:
+				status.ParameterApplyStatus = aws.String(parametersApplying)
+			}
+		}
+	}
+}
+
+// instanceParameterGroups returns the parameter group status of a new instance.
+// Instances without a group use the engine's default group. Callers must hold the lock.
+func (f *RDS) instanceParameterGroups(name, engine *string) ([]types.DBParameterGroupStatus, error) {
+	groupName := "default." + aws.ToString(engine)
+	if name != nil {
+		groupName = aws.ToString(name)
+		if _, ok := f.parameterGroups[groupName]; !ok {
+			return nil, parameterGroupNotFound(groupName)
+		}
+	}
+	return []types.DBParameterGroupStatus{{
+		DBParameterGroupName: aws.String(groupName),
+		ParameterApplyStatus: aws.String(parametersInSync),
+	}}, nil
+}
+
+// usesParameterGroup reports whether the instance is a member of the named group
+func usesParameterGroup(instance *types.DBInstance, name string) bool {
+	return slices.ContainsFunc(instance.DBParameterGroups, func(g types.DBParameterGroupStatus) bool {
+		return aws.ToString(g.DBParameterGroupName) == name
+	})
+}
+
+// familyParameters returns the parameter catalog of a family such as postgres16 or mysql8.0
+func familyParameters(family string) map[string]engineParameter {
+	for prefix, catalog := range engineParameters {
+		if strings.HasPrefix(family, prefix) && len(family) > len(prefix) {
+			return catalog
+		}
+	}
+	return nil
+}
+
+func parameterGroupNotFound(name string) error {
+	return &types.DBParameterGroupNotFoundFault{Message: aws.String(fmt.Sprintf("DBParameterGroup not found: %s", name))}
+}
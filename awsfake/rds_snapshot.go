@@ -0,0 +1,418 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// Snapshot returns a copy of the named DB snapshot, or nil if it does not exist
+func (f *RDS) Snapshot(id string) *types.DBSnapshot {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	snapshot, ok := f.snapshots[instanceIDFromRef(id)]
+	if !ok {
+		return nil
+	}
+	return copySnapshot(snapshot)
+}
+
+// CreateDBSnapshot starts a manual snapshot of an available instance in "creating" state
+func (f *RDS) CreateDBSnapshot(
+	ctx context.Context, params *rds.CreateDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.CreateDBSnapshotOutput, error) {
+
+	if err := f.record("CreateDBSnapshot"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	instanceID := aws.ToString(params.DBInstanceIdentifier)
+	instance, ok := f.instances[instanceID]
+	if !ok {
+		return nil, instanceNotFound(instanceID)
+	}
+	if aws.ToString(instance.DBInstanceStatus) != "available" {
+		return nil, &types.InvalidDBInstanceStateFault{Message: aws.String(fmt.Sprintf(
+			"DB instance %s is not in available state: %s", instanceID, aws.ToString(instance.DBInstanceStatus)))}
+	}
+
+	snapshotID := aws.ToString(params.DBSnapshotIdentifier)
+	if _, exists := f.snapshots[snapshotID]; exists {
+		return nil, &types.DBSnapshotAlreadyExistsFault{Message: aws.String(fmt.Sprintf(
+			"Cannot create the snapshot because a snapshot with the identifier %s already exists.", snapshotID))}
+	}
+
+	snapshot := f.addSnapshot(instance, snapshotID, "manual", params.Tags)
+
+	return &rds.CreateDBSnapshotOutput{DBSnapshot: copySnapshot(snapshot)}, nil
+}
+
+// DescribeDBSnapshots returns the snapshot named by DBSnapshotIdentifier, or the snapshots
+// of the instance named by DBInstanceIdentifier, limited to SnapshotType if given
+func (f *RDS) DescribeDBSnapshots(
+	ctx context.Context, params *rds.DescribeDBSnapshotsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error) {
+
+	if err := f.record("DescribeDBSnapshots"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if params.DBSnapshotIdentifier != nil {
+		snapshotID := instanceIDFromRef(aws.ToString(params.DBSnapshotIdentifier))
+		snapshot, ok := f.snapshots[snapshotID]
+		if !ok {
+			return nil, snapshotNotFound(snapshotID)
+		}
+		return &rds.DescribeDBSnapshotsOutput{DBSnapshots: []types.DBSnapshot{*copySnapshot(snapshot)}}, nil
+	}
+
+	var snapshots []types.DBSnapshot
+	for _, id := range sortedKeys(f.snapshots) {
+		snapshot := f.snapshots[id]
+		if params.DBInstanceIdentifier != nil && aws.ToString(snapshot.DBInstanceIdentifier) != aws.ToString(params.DBInstanceIdentifier) {
+			continue
+		}
+		if params.SnapshotType != nil && aws.ToString(snapshot.SnapshotType) != aws.ToString(params.SnapshotType) {
+			continue
+		}
+		snapshots = append(snapshots, *copySnapshot(snapshot))
+	}
+
+	page, marker, err := paginate(snapshots, params.Marker, params.MaxRecords)
+	if err != nil {
+		return nil, err
+	}
+	return &rds.DescribeDBSnapshotsOutput{DBSnapshots: page, Marker: marker}, nil
+}
+
+// DeleteDBSnapshot deletes an available or failed manual snapshot right away
+func (f *RDS) DeleteDBSnapshot(
+	ctx context.Context, params *rds.DeleteDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.DeleteDBSnapshotOutput, error) {
+
+	if err := f.record("DeleteDBSnapshot"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	snapshotID := aws.ToString(params.DBSnapshotIdentifier)
+	snapshot, ok := f.snapshots[snapshotID]
+	if !ok {
+		return nil, snapshotNotFound(snapshotID)
+	}
+	if status := aws.ToString(snapshot.Status); status != "available" && status != "failed" {
+		return nil, &types.InvalidDBSnapshotStateFault{Message: aws.String(fmt.Sprintf(
+			"Cannot delete the snapshot because it is not in available or failed state: %s", status))}
+	}
+
+	delete(f.snapshots, snapshotID)
+	deleted := copySnapshot(snapshot)
+	deleted.Status = aws.String("deleted")
+	return &rds.DeleteDBSnapshotOutput{DBSnapshot: deleted}, nil
+}
+
+// CopyDBSnapshot copies an available snapshot in "creating" state.
+// A KmsKeyId encrypts the copy with that key.
+func (f *RDS) CopyDBSnapshot(
+	ctx context.Context, params *rds.CopyDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.CopyDBSnapshotOutput, error) {
+
+	if err := f.record("CopyDBSnapshot"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sourceID := instanceIDFromRef(aws.ToString(params.SourceDBSnapshotIdentifier))
+	source, ok := f.snapshots[sourceID]
+	if !ok {
+		return nil, snapshotNotFound(sourceID)
+	}
+	if aws.ToString(source.Status) != "available" {
+		return nil, &types.InvalidDBSnapshotStateFault{Message: aws.String(fmt.Sprintf(
+			"DB snapshot %s is not in available state: %s", sourceID, aws.ToString(source.Status)))}
+	}
+
+	targetID := aws.ToString(params.TargetDBSnapshotIdentifier)
+	if _, exists := f.snapshots[targetID]; exists {
+		return nil, &types.DBSnapshotAlreadyExistsFault{Message: aws.String(fmt.Sprintf(
+			"Cannot create the snapshot because a snapshot with the identifier %s already exists.", targetID))}
+	}
+
+	snapshot := copySnapshot(source)
+	snapshot.DBSnapshotIdentifier = aws.String(targetID)
+	snapshot.DBSnapshotArn = aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:snapshot:%s", Region, AccountID, targetID))
+	snapshot.Status = aws.String("creating")
+	snapshot.PercentProgress = aws.Int32(0)
+	snapshot.TagList = slices.Clone(params.Tags)
+	if aws.ToBool(params.CopyTags) {
+		snapshot.TagList = append(snapshot.TagList, source.TagList...)
+	}
+	if params.KmsKeyId != nil {
+		snapshot.Encrypted = aws.Bool(true)
+		snapshot.KmsKeyId = params.KmsKeyId
+	}
+	f.snapshots[targetID] = snapshot
+
+	return &rds.CopyDBSnapshotOutput{DBSnapshot: copySnapshot(snapshot)}, nil
+}
+
+// RestoreDBInstanceFromDBSnapshot creates an instance from an available snapshot, given by identifier or ARN.
+// The instance takes its engine, storage and credentials from the snapshot.
+func (f *RDS) RestoreDBInstanceFromDBSnapshot(
+	ctx context.Context,
+	params *rds.RestoreDBInstanceFromDBSnapshotInput,
+	optFns ...func(*rds.Options)) (*rds.RestoreDBInstanceFromDBSnapshotOutput, error) {
+
+	if err := f.record("RestoreDBInstanceFromDBSnapshot"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.DBInstanceIdentifier)
+	if _, exists := f.instances[id]; exists {
+		return nil, &types.DBInstanceAlreadyExistsFault{Message: aws.String(fmt.Sprintf("DB instance %s already exists", id))}
+	}
+
+	snapshotID := instanceIDFromRef(aws.ToString(params.DBSnapshotIdentifier))
+	snapshot, ok := f.snapshots[snapshotID]
+	if !ok {
+		return nil, snapshotNotFound(snapshotID)
+	}
+	if aws.ToString(snapshot.Status) != "available" {
+		return nil, &types.InvalidDBSnapshotStateFault{Message: aws.String(fmt.Sprintf(
+			"DB snapshot %s is not in available state: %s", snapshotID, aws.ToString(snapshot.Status)))}
+	}
+
+	instance, err := f.restoredInstance(id, restoreSource{
+		engine:        snapshot.Engine,
+		engineVersion: snapshot.EngineVersion,
+		storage:       snapshot.AllocatedStorage,
+		storageType:   snapshot.StorageType,
+		encrypted:     snapshot.Encrypted,
+		kmsKeyID:      snapshot.KmsKeyId,
+		username:      snapshot.MasterUsername,
+		port:          snapshot.Port,
+	}, restoreParams{
+		instanceClass:      params.DBInstanceClass,
+		storage:            params.AllocatedStorage,
+		storageType:        params.StorageType,
+		port:               params.Port,
+		multiAZ:            params.MultiAZ,
+		publiclyAccessible: params.PubliclyAccessible,
+		autoMinorUpgrade:   params.AutoMinorVersionUpgrade,
+		deletionProtection: params.DeletionProtection,
+		manageMasterSecret: params.ManageMasterUserPassword,
+		tags:               params.Tags,
+		copyTagsToSnapshot: params.CopyTagsToSnapshot,
+		parameterGroup:     params.DBParameterGroupName,
+		subnetGroup:        params.DBSubnetGroupName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rds.RestoreDBInstanceFromDBSnapshotOutput{DBInstance: copyInstance(instance)}, nil
+}
+
+// RestoreDBInstanceToPointInTime creates an instance from the automated backups of a source instance.
+// The source needs a positive backup retention period.
+func (f *RDS) RestoreDBInstanceToPointInTime(
+	ctx context.Context,
+	params *rds.RestoreDBInstanceToPointInTimeInput,
+	optFns ...func(*rds.Options)) (*rds.RestoreDBInstanceToPointInTimeOutput, error) {
+
+	if err := f.record("RestoreDBInstanceToPointInTime"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := aws.ToString(params.TargetDBInstanceIdentifier)
+	if _, exists := f.instances[id]; exists {
+		return nil, &types.DBInstanceAlreadyExistsFault{Message: aws.String(fmt.Sprintf("DB instance %s already exists", id))}
+	}
+
+	sourceID := aws.ToString(params.SourceDBInstanceIdentifier)
+	source, ok := f.instances[sourceID]
+	if !ok {
+		return nil, instanceNotFound(sourceID)
+	}
+	if aws.ToInt32(source.BackupRetentionPeriod) == 0 {
+		return nil, &types.PointInTimeRestoreNotEnabledFault{Message: aws.String(fmt.Sprintf(
+			"Point-in-time restore is not enabled for DB instance %s", sourceID))}
+	}
+	if aws.ToBool(params.UseLatestRestorableTime) == (params.RestoreTime != nil) {
+		return nil, &types.InvalidRestoreFault{Message: aws.String(
+			"Exactly one of RestoreTime and UseLatestRestorableTime must be specified")}
+	}
+
+	instance, err := f.restoredInstance(id, restoreSource{
+		engine:        source.Engine,
+		engineVersion: source.EngineVersion,
+		storage:       source.AllocatedStorage,
+		storageType:   source.StorageType,
+		encrypted:     source.StorageEncrypted,
+		kmsKeyID:      source.KmsKeyId,
+		username:      source.MasterUsername,
+		dbName:        source.DBName,
+		port:          source.Endpoint.Port,
+	}, restoreParams{
+		instanceClass:      params.DBInstanceClass,
+		storage:            params.AllocatedStorage,
+		storageType:        params.StorageType,
+		port:               params.Port,
+		multiAZ:            params.MultiAZ,
+		publiclyAccessible: params.PubliclyAccessible,
+		autoMinorUpgrade:   params.AutoMinorVersionUpgrade,
+		deletionProtection: params.DeletionProtection,
+		manageMasterSecret: params.ManageMasterUserPassword,
+		tags:               params.Tags,
+		copyTagsToSnapshot: params.CopyTagsToSnapshot,
+		parameterGroup:     params.DBParameterGroupName,
+		subnetGroup:        params.DBSubnetGroupName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &rds.RestoreDBInstanceToPointInTimeOutput{DBInstance: copyInstance(instance)}, nil
+}
+
+// restoreSource holds the settings a restored instance inherits from its snapshot or source instance
+type restoreSource struct {
+	engine, engineVersion, storageType, kmsKeyID, username, dbName *string
+	storage, port                                                  *int32
+	encrypted                                                      *bool
+}
+
+// restoreParams holds the settings of a restore request that apply to the new instance
+type restoreParams struct {
+	instanceClass, storageType  *string
+	parameterGroup, subnetGroup *string
+	storage, port               *int32
+	multiAZ                     *bool
+	publiclyAccessible          *bool
+	autoMinorUpgrade            *bool
+	deletionProtection          *bool
+	manageMasterSecret          *bool
+	copyTagsToSnapshot          *bool
+	tags                        []types.Tag
+}
+
+// restoredInstance registers an instance restored from a source in "creating" state.
+// Settings the restore does not accept, like the backup retention, get the RDS defaults.
+func (f *RDS) restoredInstance(id string, source restoreSource, params restoreParams) (*types.DBInstance, error) {
+	parameterGroups, err := f.instanceParameterGroups(params.parameterGroup, source.engine)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := &types.DBInstance{
+		DBInstanceIdentifier:    aws.String(id),
+		DBInstanceArn:           aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", Region, AccountID, id)),
+		DBInstanceStatus:        aws.String("creating"),
+		DbiResourceId:           aws.String(f.ids.id("db-")),
+		DBInstanceClass:         params.instanceClass,
+		Engine:                  source.engine,
+		EngineVersion:           source.engineVersion,
+		AllocatedStorage:        source.storage,
+		StorageType:             source.storageType,
+		StorageEncrypted:        source.encrypted,
+		KmsKeyId:                source.kmsKeyID,
+		MasterUsername:          source.username,
+		DBName:                  source.dbName,
+		MultiAZ:                 params.multiAZ,
+		BackupRetentionPeriod:   aws.Int32(1),
+		AutoMinorVersionUpgrade: params.autoMinorUpgrade,
+		DeletionProtection:      params.deletionProtection,
+		CopyTagsToSnapshot:      params.copyTagsToSnapshot,
+		PubliclyAccessible:      params.publiclyAccessible,
+		DBParameterGroups:       parameterGroups,
+		DBSubnetGroup:           subnetGroup(params.subnetGroup),
+		TagList:                 slices.Clone(params.tags),
+		AvailabilityZone:        aws.String(Region + "a"),
+		Endpoint: &types.Endpoint{
+			Address: aws.String(fmt.Sprintf("%s.fake.%s.rds.amazonaws.com", id, Region)),
+			Port:    source.port,
+		},
+	}
+	if params.storage != nil {
+		instance.AllocatedStorage = params.storage
+	}
+	if params.storageType != nil {
+		instance.StorageType = params.storageType
+	}
+	if params.port != nil {
+		instance.Endpoint.Port = params.port
+	}
+	if aws.ToBool(params.manageMasterSecret) {
+		instance.MasterUserSecret = &types.MasterUserSecret{
+			SecretArn:    aws.String(fmt.Sprintf("arn:aws:secretsmanager:%s:%s:secret:rds!db-%s", Region, AccountID, id)),
+			SecretStatus: aws.String("active"),
+		}
+	}
+
+	f.instances[id] = instance
+	return instance, nil
+}
+
+// addSnapshot registers a snapshot of the instance in "creating" state. Without tags of its own
+// the snapshot gets the instance tags if the instance copies tags to snapshots.
+func (f *RDS) addSnapshot(instance *types.DBInstance, snapshotID, snapshotType string, tags []types.Tag) *types.DBSnapshot {
+	if len(tags) == 0 && aws.ToBool(instance.CopyTagsToSnapshot) {
+		tags = instance.TagList
+	}
+	snapshot := &types.DBSnapshot{
+		DBSnapshotIdentifier: aws.String(snapshotID),
+		DBSnapshotArn:        aws.String(fmt.Sprintf("arn:aws:rds:%s:%s:snapshot:%s", Region, AccountID, snapshotID)),
+		DBInstanceIdentifier: instance.DBInstanceIdentifier,
+		DbiResourceId:        instance.DbiResourceId,
+		SnapshotType:         aws.String(snapshotType),
+		Status:               aws.String("creating"),
+		PercentProgress:      aws.Int32(0),
+		SnapshotCreateTime:   aws.Time(time.Now().UTC()),
+		Engine:               instance.Engine,
+		EngineVersion:        instance.EngineVersion,
+		AllocatedStorage:     instance.AllocatedStorage,
+		StorageType:          instance.StorageType,
+		Encrypted:            instance.StorageEncrypted,
+		KmsKeyId:             instance.KmsKeyId,
+		MasterUsername:       instance.MasterUsername,
+		TagList:              slices.Clone(tags),
+	}
+	if instance.Endpoint != nil {
+		snapshot.Port = instance.Endpoint.Port
+	}
+	f.snapshots[snapshotID] = snapshot
+	return snapshot
+}
+
+// copySnapshot copies a snapshot together with its slices,
+// so callers cannot change the fake's state through the returned value
+func copySnapshot(snapshot *types.DBSnapshot) *types.DBSnapshot {
+	copied := *snapshot
+	copied.TagList = slices.Clone(snapshot.TagList)
+	return &copied
+}
+
+func snapshotNotFound(id string) error {
+	return &types.DBSnapshotNotFoundFault{Message: aws.String(fmt.Sprintf("DBSnapshot %s not found.", id))}
+}
@@ -0,0 +1,410 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// SecretsManager is an in-memory fake of the AWS Secrets Manager API.
+// Secrets can be addressed by name or ARN. Every write produces a new version ID;
+// the current and previous versions carry the AWSCURRENT and AWSPREVIOUS stages.
+// Forced deletion is immediate; other deletions only schedule the secret for deletion
+// until RestoreSecret (the recovery window never expires).
+type SecretsManager struct {
+	faults
+
+	mu      sync.Mutex
+	ids     idGenerator
+	secrets map[string]*Secret
+}
+
+// Secret is a snapshot of a secret stored in the fake
+type Secret struct {
+	ARN       string
+	Name      string
+	Value     string
+	VersionId string
+	KmsKeyId  string
+	Tags      map[string]string
+
+	// ScheduledForDeletion is set by a DeleteSecret call with a recovery window
+	ScheduledForDeletion bool
+
+	previousVersionId string
+	versions          map[string]string
+}
+
+// NewSecretsManager creates an empty fake Secrets Manager backend
+func NewSecretsManager() *SecretsManager {
+	return &SecretsManager{
+		secrets: make(map[string]*Secret),
+	}
+}
+
+// Secret returns a snapshot of the secret with the given name or ARN, or nil if it does not exist
+func (f *SecretsManager) Secret(id string) *Secret {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.lookup(id)
+	if s == nil {
+		return nil
+	}
+	copied := *s
+	copied.Tags = make(map[string]string, len(s.Tags))
+	for k, v := range s.Tags {
+		copied.Tags[k] = v
+	}
+	copied.versions = maps.Clone(s.versions)
+	return &copied
+}
+
+// PutValue overwrites a secret's value out of band, producing a new version
+func (f *SecretsManager) PutValue(id, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if s := f.lookup(id); s != nil {
+		f.putValue(s, value)
+	}
+}
+
+// putValue stores a new current version; callers must hold the lock
+func (f *SecretsManager) putValue(s *Secret, value string) {
+	if s.versions == nil {
+		s.versions = make(map[string]string)
+	}
+	s.previousVersionId = s.VersionId
+	s.Value = value
+	s.VersionId = f.ids.id("ver-")
+	s.versions[s.VersionId] = value
+}
+
+// lookup finds a secret by name, ARN, or partial ARN without the random suffix, like the ARNs
+// RDS reports for the master user secrets it manages; callers must hold the lock
+func (f *SecretsManager) lookup(id string) *Secret {
+	if s, ok := f.secrets[id]; ok {
+		return s
+	}
+	for _, s := range f.secrets {
+		if s.ARN == id || (strings.HasPrefix(id, "arn:") && strings.HasPrefix(s.ARN, id+"-")) {
+			return s
+		}
+	}
+	return nil
+}
+
+// DescribeSecret returns secret metadata
+func (f *SecretsManager) DescribeSecret(
+	ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error) {
+
+	if err := f.record("DescribeSecret"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.lookup(aws.ToString(params.SecretId))
+	if s == nil {
+		return nil, secretNotFound(aws.ToString(params.SecretId))
+	}
+
+	output := &secretsmanager.DescribeSecretOutput{
+		ARN:                aws.String(s.ARN),
+		Name:               aws.String(s.Name),
+		KmsKeyId:           optional(s.KmsKeyId),
+		VersionIdsToStages: map[string][]string{s.VersionId: {"AWSCURRENT"}},
+	}
+	if s.previousVersionId != "" {
+		output.VersionIdsToStages[s.previousVersionId] = []string{"AWSPREVIOUS"}
+	}
+	if s.ScheduledForDeletion {
+		output.DeletedDate = aws.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	}
+	for _, k := range sortedKeys(s.Tags) {
+		output.Tags = append(output.Tags, types.Tag{Key: aws.String(k), Value: aws.String(s.Tags[k])})
+	}
+	return output, nil
+}
+
+// ListSecrets lists secrets in name order.
+// Only the first page is modelled; NextToken is not supported.
+func (f *SecretsManager) ListSecrets(
+	ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+
+	if err := f.record("ListSecrets"); err != nil {
+		return nil, err
+	}
+	if params.NextToken != nil {
+		return nil, &types.InvalidNextTokenException{Message: aws.String("Invalid next token.")}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	names := make([]string, 0, len(f.secrets))
+	for name := range f.secrets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	output := &secretsmanager.ListSecretsOutput{}
+	if maxResults := int(aws.ToInt32(params.MaxResults)); maxResults > 0 && maxResults < len(names) {
+		names = names[:maxResults]
+		output.NextToken = aws.String(names[maxResults-1])
+	}
+	for _, name := range names {
+		s := f.secrets[name]
+		output.SecretList = append(output.SecretList, types.SecretListEntry{
+			ARN:  aws.String(s.ARN),
+			Name: aws.String(s.Name),
+		})
+	}
+	return output, nil
+}
+
+// GetSecretValue returns the current value of the secret
+func (f *SecretsManager) GetSecretValue(
+	ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+
+	if err := f.record("GetSecretValue"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.lookup(aws.ToString(params.SecretId))
+	if s == nil {
+		return nil, secretNotFound(aws.ToString(params.SecretId))
+	}
+
+	return &secretsmanager.GetSecretValueOutput{
+		ARN:           aws.String(s.ARN),
+		Name:          aws.String(s.Name),
+		SecretString:  aws.String(s.Value),
+		VersionId:     aws.String(s.VersionId),
+		VersionStages: []string{"AWSCURRENT"},
+	}, nil
+}
+
+// CreateSecret stores a new secret
+func (f *SecretsManager) CreateSecret(
+	ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+
+	if err := f.record("CreateSecret"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := aws.ToString(params.Name)
+	if _, exists := f.secrets[name]; exists {
+		return nil, &types.ResourceExistsException{Message: aws.String(fmt.Sprintf("The operation failed because the secret %s already exists.", name))}
+	}
+
+	s := &Secret{
+		ARN:      fmt.Sprintf("arn:aws:secretsmanager:%s:%s:secret:%s-%s", Region, AccountID, name, f.ids.id("")[6:]),
+		Name:     name,
+		KmsKeyId: aws.ToString(params.KmsKeyId),
+		Tags:     make(map[string]string),
+	}
+	f.putValue(s, aws.ToString(params.SecretString))
+	for _, tag := range params.Tags {
+		s.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	f.secrets[name] = s
+
+	return &secretsmanager.CreateSecretOutput{
+		ARN:       aws.String(s.ARN),
+		Name:      aws.String(s.Name),
+		VersionId: aws.String(s.VersionId),
+	}, nil
+}
+
+// UpdateSecret replaces the secret value and produces a new version
+func (f *SecretsManager) UpdateSecret(
+	ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error) {
+
+	if err := f.record("UpdateSecret"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.lookup(aws.ToString(params.SecretId))
+	if s == nil {
+		return nil, secretNotFound(aws.ToString(params.SecretId))
+	}
+
+	if params.SecretString != nil {
+		f.putValue(s, aws.ToString(params.SecretString))
+	}
+	if params.KmsKeyId != nil {
+		s.KmsKeyId = aws.ToString(params.KmsKeyId)
+	}
+
+	return &secretsmanager.UpdateSecretOutput{
+		ARN:       aws.String(s.ARN),
+		Name:      aws.String(s.Name),
+		VersionId: aws.String(s.VersionId),
+	}, nil
+}
+
+// DeleteSecret removes the secret immediately with ForceDeleteWithoutRecovery,
+// and otherwise schedules it for deletion
+func (f *SecretsManager) DeleteSecret(
+	ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+
+	if err := f.record("DeleteSecret"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.lookup(aws.ToString(params.SecretId))
+	if s == nil {
+		return nil, secretNotFound(aws.ToString(params.SecretId))
+	}
+
+	if aws.ToBool(params.ForceDeleteWithoutRecovery) {
+		delete(f.secrets, s.Name)
+	} else {
+		s.ScheduledForDeletion = true
+	}
+	return &secretsmanager.DeleteSecretOutput{ARN: aws.String(s.ARN), Name: aws.String(s.Name)}, nil
+}
+
+// RestoreSecret cancels a scheduled deletion
+func (f *SecretsManager) RestoreSecret(
+	ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error) {
+
+	if err := f.record("RestoreSecret"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.lookup(aws.ToString(params.SecretId))
+	if s == nil {
+		return nil, secretNotFound(aws.ToString(params.SecretId))
+	}
+
+	s.ScheduledForDeletion = false
+	return &secretsmanager.RestoreSecretOutput{ARN: aws.String(s.ARN), Name: aws.String(s.Name)}, nil
+}
+
+// UntagResource removes tags from a secret
+func (f *SecretsManager) UntagResource(
+	ctx context.Context, params *secretsmanager.UntagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UntagResourceOutput, error) {
+
+	if err := f.record("UntagResource"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.lookup(aws.ToString(params.SecretId))
+	if s == nil {
+		return nil, secretNotFound(aws.ToString(params.SecretId))
+	}
+	for _, key := range params.TagKeys {
+		delete(s.Tags, key)
+	}
+	return &secretsmanager.UntagResourceOutput{}, nil
+}
+
+// UpdateSecretVersionStage moves the AWSCURRENT stage to an earlier version.
+// Like the real API it requires RemoveFromVersionId to name the current version.
+func (f *SecretsManager) UpdateSecretVersionStage(
+	ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error) {
+
+	if err := f.record("UpdateSecretVersionStage"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := f.lookup(aws.ToString(params.SecretId))
+	if s == nil {
+		return nil, secretNotFound(aws.ToString(params.SecretId))
+	}
+	if aws.ToString(params.VersionStage) != "AWSCURRENT" {
+		return nil, &types.InvalidParameterException{Message: aws.String("The fake only moves the AWSCURRENT stage.")}
+	}
+	if aws.ToString(params.RemoveFromVersionId) != s.VersionId {
+		return nil, &types.InvalidParameterException{Message: aws.String(fmt.Sprintf(
+			"The parameter RemoveFromVersionId can't be %s: AWSCURRENT is attached to %s.",
+			aws.ToString(params.RemoveFromVersionId), s.VersionId))}
+	}
+	target := aws.ToString(params.MoveToVersionId)
+	value, ok := s.versions[target]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("Secrets Manager can't find the specified secret version %s.", target))}
+	}
+
+	s.previousVersionId = s.VersionId
+	s.VersionId = target
+	s.Value = value
+	return &secretsmanager.UpdateSecretVersionStageOutput{ARN: aws.String(s.ARN), Name: aws.String(s.Name)}, nil
+}
+
+// GetRandomPassword returns a deterministic password of the requested length
+func (f *SecretsManager) GetRandomPassword(
+	ctx context.Context, params *secretsmanager.GetRandomPasswordInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetRandomPasswordOutput, error) {
+
+	if err := f.record("GetRandomPassword"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	length := int(aws.ToInt64(params.PasswordLength))
+	if length <= 0 {
+		length = 32
+	}
+
+	seed := f.ids.id("pw")
+	password := strings.Repeat(seed, length/len(seed)+1)[:length]
+	return &secretsmanager.GetRandomPasswordOutput{RandomPassword: aws.String(password)}, nil
+}
+
+func secretNotFound(id string) error {
+	return &types.ResourceNotFoundException{Message: aws.String(fmt.Sprintf("Secrets Manager can't find the specified secret %s.", id))}
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,55 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package awsfake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// STS is an in-memory fake of the AWS STS identity API.
+// It reports a fixed caller identity that tests can change with SetCaller.
+type STS struct {
+	faults
+
+	mu  sync.Mutex
+	arn string
+}
+
+// NewSTS creates a fake STS backend whose caller is an assumed controller role
+func NewSTS() *STS {
+	return &STS{
+		arn: fmt.Sprintf("arn:aws:sts::%s:assumed-role/componator-aws-providers/session", AccountID),
+	}
+}
+
+// SetCaller changes the ARN reported by GetCallerIdentity
+func (f *STS) SetCaller(arn string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.arn = arn
+}
+
+// GetCallerIdentity returns the configured caller in the fake account
+func (f *STS) GetCallerIdentity(
+	ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+
+	if err := f.record("GetCallerIdentity"); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &sts.GetCallerIdentityOutput{
+		Account: aws.String(AccountID),
+		Arn:     aws.String(f.arn),
+		UserId:  aws.String("AROA000000000001:session"),
+	}, nil
+}
@@ -4,8 +4,10 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"time"
 
 	"os"
 
@@ -20,10 +22,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
-	"github.com/rinswind/componator-aws-providers/iampolicy"
-	"github.com/rinswind/componator-aws-providers/iamrole"
-	"github.com/rinswind/componator-aws-providers/rds"
-	"github.com/rinswind/componator-aws-providers/secretpush"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -47,6 +46,15 @@ func main() {
 	var secureMetrics bool
 	var enableHTTP2 bool
 	var providerPrefix string
+	var awsEndpointURL, awsServiceEndpoints string
+	var awsInsecureSkipTLSVerify bool
+	var awsRegion, awsProfile string
+	var awsMaxRetries int
+	var enableProviders, disableProviders string
+	var awsReadinessInterval time.Duration
+	var awsReadinessProbeProviders bool
+	var dryRun bool
+	var clusterID string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -66,6 +74,34 @@ func main() {
 		"Prefix for all provider names (default: none). "+
 			"Example: 'team-a' creates 'team-a-iam-policy', 'team-a-rds', etc. "+
 			"Use this to avoid conflicts when running multiple instances in the same cluster.")
+	flag.StringVar(&enableProviders, "enable-providers", "",
+		"Comma-separated providers to run (default: all). "+
+			"Providers: iam-policy, iam-role, secret-push, rds, rds-cluster, rds-parameter-group.")
+	flag.StringVar(&disableProviders, "disable-providers", "",
+		"Comma-separated providers to skip, applied after --enable-providers.")
+	flag.StringVar(&awsRegion, "aws-region", "",
+		"Default AWS region (default: AWS_REGION, the shared config or EC2 instance metadata).")
+	flag.StringVar(&awsProfile, "aws-profile", "",
+		"Named profile from the shared AWS config and credentials files (default: AWS_PROFILE or 'default').")
+	flag.IntVar(&awsMaxRetries, "aws-max-retries", 0,
+		"SDK retries per AWS call (default: 0, failed calls are retried by requeueing the Component).")
+	flag.DurationVar(&awsReadinessInterval, "aws-readiness-interval", time.Minute,
+		"How long a successful or failed AWS credentials check is cached by the readiness probe.")
+	flag.BoolVar(&awsReadinessProbeProviders, "aws-readiness-probe-providers", false,
+		"Also make one cheap read call per enabled provider in the readiness probe.")
+	flag.StringVar(&awsEndpointURL, "aws-endpoint-url", "",
+		"Base endpoint for all AWS services (default: AWS endpoints). "+
+			"Example: 'http://localstack.localstack:4566' to run against LocalStack.")
+	flag.StringVar(&awsServiceEndpoints, "aws-service-endpoints", "",
+		"Per-service AWS endpoint overrides as service=url pairs, taking precedence over --aws-endpoint-url. "+
+			"Services: rds, iam, secretsmanager, sts, cloudwatch. Example: 'iam=http://iam-emulator:4566,sts=http://iam-emulator:4566'.")
+	flag.BoolVar(&awsInsecureSkipTLSVerify, "aws-insecure-skip-tls-verify", false,
+		"Skip TLS certificate verification for AWS endpoints. Only for local emulators with self-signed certificates.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Plan every Component instead of applying it: write the changes to the provider status "+
+			"without calling mutating AWS APIs. Single Components opt in with the "+awsclient.DryRunAnnotation+" annotation.")
+	flag.StringVar(&clusterID, "cluster-id", awsclient.DefaultClusterID,
+		"Identity of this cluster in the ownership tags stamped on AWS resources. Must be unique among clusters sharing an AWS account.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -147,25 +183,52 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := iampolicy.Register(mgr, buildProviderName(providerPrefix, "iam-policy")); err != nil {
-		setupLog.Error(err, "unable to register iam-policy controller")
+	selected, err := selectProviders(enableProviders, disableProviders)
+	if err != nil {
+		setupLog.Error(err, "unable to select providers")
 		os.Exit(1)
 	}
 
-	if err := iamrole.Register(mgr, buildProviderName(providerPrefix, "iam-role")); err != nil {
-		setupLog.Error(err, "unable to register iam-role controller")
+	serviceEndpoints, err := awsclient.ParseServiceEndpoints(awsServiceEndpoints)
+	if err != nil {
+		setupLog.Error(err, "invalid --aws-service-endpoints")
 		os.Exit(1)
 	}
+	endpoints := awsclient.Endpoints{
+		URL:                awsEndpointURL,
+		Services:           serviceEndpoints,
+		InsecureSkipVerify: awsInsecureSkipTLSVerify,
+	}
+	if endpoints.IsSet() {
+		setupLog.Info("using custom AWS endpoints", "url", endpoints.URL, "services", endpoints.Services)
+	}
 
-	if err := secretpush.Register(mgr, buildProviderName(providerPrefix, "secret-push")); err != nil {
-		setupLog.Error(err, "unable to register secret-push controller")
+	// Load the AWS config once and share it between all selected providers
+	awsConfig, err := awsclient.LoadConfig(context.Background(), awsclient.LoadSettings{
+		Region:     awsRegion,
+		Profile:    awsProfile,
+		MaxRetries: awsMaxRetries,
+	}, endpoints)
+	if err != nil {
+		setupLog.Error(err, "unable to load AWS configuration")
 		os.Exit(1)
 	}
 
-	if err := rds.Register(mgr, buildProviderName(providerPrefix, "rds")); err != nil {
-		setupLog.Error(err, "unable to register rds controller")
-		os.Exit(1)
+	settings := providerSettings{
+		prefix:    providerPrefix,
+		awsConfig: awsConfig,
+		endpoints: endpoints,
+		dryRun:    dryRun,
+		clusterID: clusterID,
+	}
+	for _, p := range selected {
+		if err := p.register(mgr, settings); err != nil {
+			setupLog.Error(err, "unable to register controller", "provider", p.name)
+			os.Exit(1)
+		}
 	}
+	setupLog.Info("registered providers",
+		"providers", providerNames(selected), "defaultRegion", awsConfig.Region, "dryRun", dryRun, "clusterID", clusterID)
 
 	// +kubebuilder:scaffold:builder
 
@@ -178,19 +241,21 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Report not ready while the AWS credentials do not work (e.g. misconfigured IRSA)
+	awsReadiness := awsclient.NewReadinessChecker(awsclient.NewSTSClient(awsConfig, endpoints), awsReadinessInterval)
+	if awsReadinessProbeProviders {
+		for _, p := range selected {
+			awsReadiness.AddProbe(p.name, p.probe)
+		}
+	}
+	if err := mgr.AddReadyzCheck("aws", awsReadiness.Check); err != nil {
+		setupLog.Error(err, "unable to set up AWS ready check")
+		os.Exit(1)
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
 }
-
-// buildProviderName constructs a provider name from prefix and base name.
-// If prefix is empty, returns empty string to use the provider's default name.
-// Otherwise returns "prefix-base" (e.g., "team-a-iam-policy").
-func buildProviderName(prefix, base string) string {
-	if prefix == "" {
-		return "" // Use default from provider package
-	}
-	return prefix + "-" + base
-}
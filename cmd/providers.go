@@ -0,0 +1,177 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator-aws-providers/iampolicy"
+	"github.com/rinswind/componator-aws-providers/iamrole"
+	"github.com/rinswind/componator-aws-providers/rds"
+	"github.com/rinswind/componator-aws-providers/rdscluster"
+	"github.com/rinswind/componator-aws-providers/rdsparametergroup"
+	"github.com/rinswind/componator-aws-providers/secretpush"
+)
+
+// providerSettings is the shared wiring passed to every selected provider
+type providerSettings struct {
+	prefix    string
+	awsConfig aws.Config
+	endpoints awsclient.Endpoints
+	dryRun    bool
+	clusterID string
+}
+
+// provider couples a provider's base name with its registration and readiness probe
+type provider struct {
+	name     string
+	register func(mgr ctrl.Manager, s providerSettings) error
+	probe    awsclient.Probe
+}
+
+// providers lists all providers bundled with the controller
+var providers = []provider{
+	{
+		name: "iam-policy",
+		register: func(mgr ctrl.Manager, s providerSettings) error {
+			return iampolicy.Register(mgr,
+				iampolicy.WithProviderName(buildProviderName(s.prefix, "iam-policy")),
+				iampolicy.WithAWSConfig(s.awsConfig),
+				iampolicy.WithEndpoints(s.endpoints),
+				iampolicy.WithDryRun(s.dryRun),
+				iampolicy.WithClusterID(s.clusterID))
+		},
+		probe: iampolicy.ReadinessProbe,
+	},
+	{
+		name: "iam-role",
+		register: func(mgr ctrl.Manager, s providerSettings) error {
+			return iamrole.Register(mgr,
+				iamrole.WithProviderName(buildProviderName(s.prefix, "iam-role")),
+				iamrole.WithAWSConfig(s.awsConfig),
+				iamrole.WithEndpoints(s.endpoints),
+				iamrole.WithDryRun(s.dryRun),
+				iamrole.WithClusterID(s.clusterID))
+		},
+		probe: iamrole.ReadinessProbe,
+	},
+	{
+		name: "secret-push",
+		register: func(mgr ctrl.Manager, s providerSettings) error {
+			return secretpush.Register(mgr,
+				secretpush.WithProviderName(buildProviderName(s.prefix, "secret-push")),
+				secretpush.WithAWSConfig(s.awsConfig),
+				secretpush.WithEndpoints(s.endpoints),
+				secretpush.WithDryRun(s.dryRun),
+				secretpush.WithClusterID(s.clusterID))
+		},
+		probe: secretpush.ReadinessProbe,
+	},
+	{
+		name: "rds",
+		register: func(mgr ctrl.Manager, s providerSettings) error {
+			return rds.Register(mgr,
+				rds.WithProviderName(buildProviderName(s.prefix, "rds")),
+				rds.WithAWSConfig(s.awsConfig),
+				rds.WithEndpoints(s.endpoints),
+				rds.WithDryRun(s.dryRun),
+				rds.WithClusterID(s.clusterID))
+		},
+		probe: rds.ReadinessProbe,
+	},
+	{
+		name: "rds-cluster",
+		register: func(mgr ctrl.Manager, s providerSettings) error {
+			return rdscluster.Register(mgr,
+				rdscluster.WithProviderName(buildProviderName(s.prefix, "rds-cluster")),
+				rdscluster.WithAWSConfig(s.awsConfig),
+				rdscluster.WithEndpoints(s.endpoints),
+				rdscluster.WithDryRun(s.dryRun),
+				rdscluster.WithClusterID(s.clusterID))
+		},
+		probe: rdscluster.ReadinessProbe,
+	},
+	{
+		name: "rds-parameter-group",
+		register: func(mgr ctrl.Manager, s providerSettings) error {
+			return rdsparametergroup.Register(mgr,
+				rdsparametergroup.WithProviderName(buildProviderName(s.prefix, "rds-parameter-group")),
+				rdsparametergroup.WithAWSConfig(s.awsConfig),
+				rdsparametergroup.WithEndpoints(s.endpoints),
+				rdsparametergroup.WithDryRun(s.dryRun),
+				rdsparametergroup.WithClusterID(s.clusterID))
+		},
+		probe: rdsparametergroup.ReadinessProbe,
+	},
+}
+
+// selectProviders resolves the --enable-providers and --disable-providers flags.
+// An empty enable list selects all providers; the disable list is removed afterwards.
+func selectProviders(enable, disable string) ([]provider, error) {
+	enabled, err := parseProviderNames(enable)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --enable-providers: %w", err)
+	}
+	disabled, err := parseProviderNames(disable)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --disable-providers: %w", err)
+	}
+
+	var selected []provider
+	for _, p := range providers {
+		if len(enabled) > 0 && !slices.Contains(enabled, p.name) {
+			continue
+		}
+		if slices.Contains(disabled, p.name) {
+			continue
+		}
+		selected = append(selected, p)
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no providers selected")
+	}
+	return selected, nil
+}
+
+// parseProviderNames splits a comma-separated list of provider names and rejects unknown ones
+func parseProviderNames(value string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.ContainsFunc(providers, func(p provider) bool { return p.name == name }) {
+			return nil, fmt.Errorf("unknown provider %q, must be one of %v", name, providerNames(providers))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// providerNames returns the base names of the given providers
+func providerNames(ps []provider) []string {
+	names := make([]string, 0, len(ps))
+	for _, p := range ps {
+		names = append(names, p.name)
+	}
+	return names
+}
+
+// buildProviderName constructs a provider name from prefix and base name.
+// If prefix is empty, returns empty string to use the provider's default name.
+// Otherwise returns "prefix-base" (e.g., "team-a-iam-policy").
+func buildProviderName(prefix, base string) string {
+	if prefix == "" {
+		return "" // Use default from provider package
+	}
+	return prefix + "-" + base
+}
@@ -0,0 +1,41 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestManager(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Manager Suite")
+}
+
+var _ = Describe("selectProviders", func() {
+	DescribeTable("should resolve the enabled providers",
+		func(enable, disable string, expected []string) {
+			selected, err := selectProviders(enable, disable)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(providerNames(selected)).To(Equal(expected))
+		},
+		Entry("all by default", "", "", []string{"iam-policy", "iam-role", "secret-push", "rds", "rds-cluster", "rds-parameter-group"}),
+		Entry("only enabled", "secret-push", "", []string{"secret-push"}),
+		Entry("enabled in bundled order", "rds, iam-role", "", []string{"iam-role", "rds"}),
+		Entry("all but disabled", "", "rds,rds-cluster,rds-parameter-group,iam-policy", []string{"iam-role", "secret-push"}),
+		Entry("disable after enable", "rds,secret-push", "rds", []string{"secret-push"}),
+	)
+
+	DescribeTable("should reject invalid selections",
+		func(enable, disable, message string) {
+			_, err := selectProviders(enable, disable)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("unknown enabled provider", "s3", "", `invalid --enable-providers: unknown provider "s3"`),
+		Entry("unknown disabled provider", "", "iam", `invalid --disable-providers: unknown provider "iam"`),
+		Entry("nothing left", "rds", "rds", "no providers selected"),
+	)
+})
@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/controller"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -22,29 +23,58 @@ const (
 	MaxNumberOfPolicyVersions = 5
 )
 
+// API is the subset of the AWS IAM API used by this provider.
+// It is satisfied by *iam.Client and by awsfake.IAM in unit tests.
+// Embedding programs may supply their own implementation with WithClient.
+type API interface {
+	ListPolicies(ctx context.Context, params *iam.ListPoliciesInput, optFns ...func(*iam.Options)) (*iam.ListPoliciesOutput, error)
+	GetPolicy(ctx context.Context, params *iam.GetPolicyInput, optFns ...func(*iam.Options)) (*iam.GetPolicyOutput, error)
+	CreatePolicy(ctx context.Context, params *iam.CreatePolicyInput, optFns ...func(*iam.Options)) (*iam.CreatePolicyOutput, error)
+	DeletePolicy(ctx context.Context, params *iam.DeletePolicyInput, optFns ...func(*iam.Options)) (*iam.DeletePolicyOutput, error)
+	GetPolicyVersion(ctx context.Context, params *iam.GetPolicyVersionInput, optFns ...func(*iam.Options)) (*iam.GetPolicyVersionOutput, error)
+	ListPolicyVersions(ctx context.Context, params *iam.ListPolicyVersionsInput, optFns ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error)
+	CreatePolicyVersion(ctx context.Context, params *iam.CreatePolicyVersionInput, optFns ...func(*iam.Options)) (*iam.CreatePolicyVersionOutput, error)
+	DeletePolicyVersion(ctx context.Context, params *iam.DeletePolicyVersionInput, optFns ...func(*iam.Options)) (*iam.DeletePolicyVersionOutput, error)
+	TagPolicy(ctx context.Context, params *iam.TagPolicyInput, optFns ...func(*iam.Options)) (*iam.TagPolicyOutput, error)
+	UntagPolicy(ctx context.Context, params *iam.UntagPolicyInput, optFns ...func(*iam.Options)) (*iam.UntagPolicyOutput, error)
+}
+
 // Package-level singletons initialized during registration
 var (
-	iamClient *iam.Client
+	iamClients   *awsclient.Cache[API]
+	iamOwnership *awsclient.Ownership
 )
 
+// ReadinessProbe checks that the IAM API is reachable with the controller's own credentials
+func ReadinessProbe(ctx context.Context) error {
+	_, err := iamClients.Get(awsclient.AccessConfig{}).ListPolicies(ctx, &iam.ListPoliciesInput{
+		Scope:    types.PolicyScopeTypeLocal,
+		MaxItems: aws.Int32(1),
+	})
+	return err
+}
+
 // getPolicyByName retrieves policy by name (searches by path and name)
-func getPolicyByName(ctx context.Context, policyName, path string) (*types.Policy, error) {
-	// List policies to find a match
+func getPolicyByName(ctx context.Context, client API, policyName, path string) (*types.Policy, error) {
+	// List policies to find a match, following pagination across all pages
 	input := &iam.ListPoliciesInput{
 		Scope:      types.PolicyScopeTypeLocal,
 		PathPrefix: aws.String(path),
 	}
 
-	output, err := iamClient.ListPolicies(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list policies: %w", err)
-	}
+	paginator := iam.NewListPoliciesPaginator(client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policies: %w", err)
+		}
 
-	// Find policy with matching name
-	for i := range output.Policies {
-		policy := &output.Policies[i]
-		if aws.ToString(policy.PolicyName) == policyName {
-			return policy, nil
+		// Find policy with matching name
+		for i := range output.Policies {
+			policy := &output.Policies[i]
+			if aws.ToString(policy.PolicyName) == policyName {
+				return policy, nil
+			}
 		}
 	}
 
@@ -52,12 +82,12 @@ func getPolicyByName(ctx context.Context, policyName, path string) (*types.Polic
 }
 
 // getPolicyByArn retrieves policy by ARN
-func getPolicyByArn(ctx context.Context, arn string) (*types.Policy, error) {
+func getPolicyByArn(ctx context.Context, client API, arn string) (*types.Policy, error) {
 	input := &iam.GetPolicyInput{
 		PolicyArn: aws.String(arn),
 	}
 
-	output, err := iamClient.GetPolicy(ctx, input)
+	output, err := client.GetPolicy(ctx, input)
 	if err == nil {
 		return output.Policy, nil
 	}
@@ -73,6 +103,7 @@ func getPolicyByArn(ctx context.Context, arn string) (*types.Policy, error) {
 // createPolicy creates a new IAM policy and returns the created policy
 func createPolicy(
 	ctx context.Context,
+	client API,
 	policyName, policyDocument, path, description string,
 	tags map[string]string) (*types.Policy, error) {
 
@@ -88,7 +119,7 @@ func createPolicy(
 		Tags:           toIAMTags(tags),
 	}
 
-	output, err := iamClient.CreatePolicy(ctx, input)
+	output, err := client.CreatePolicy(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create policy: %w", err)
 	}
@@ -102,13 +133,13 @@ func createPolicy(
 
 // createPolicyVersion creates a new version of an existing policy and returns the version ID.
 // Returns the current version ID if policy document is unchanged (no new version created).
-func createPolicyVersion(ctx context.Context, policyArn, desiredDocument string) (string, error) {
+func createPolicyVersion(ctx context.Context, client API, policyArn, desiredDocument string) (string, error) {
 	log := logf.FromContext(ctx).WithValues("policyArn", policyArn)
 
 	log.Info("Checking if policy update needed")
 
 	// Get current default version to check for drift
-	currentDocument, versionId, err := getCurrentPolicyDocument(ctx, policyArn)
+	currentDocument, versionId, err := getCurrentPolicyDocument(ctx, client, policyArn)
 	if err != nil {
 		return "", fmt.Errorf("failed to get current policy document: %w", err)
 	}
@@ -122,7 +153,7 @@ func createPolicyVersion(ctx context.Context, policyArn, desiredDocument string)
 	log.Info("Policy document changed, creating new version")
 
 	// Check current version count and cleanup if needed
-	if err := deleteOldestPolicyVersion(ctx, policyArn); err != nil {
+	if err := deleteOldestPolicyVersion(ctx, client, policyArn); err != nil {
 		return "", fmt.Errorf("failed to cleanup old versions: %w", err)
 	}
 
@@ -133,7 +164,7 @@ func createPolicyVersion(ctx context.Context, policyArn, desiredDocument string)
 		SetAsDefault:   true, // Set new version as default
 	}
 
-	output, err := iamClient.CreatePolicyVersion(ctx, input)
+	output, err := client.CreatePolicyVersion(ctx, input)
 	if err != nil {
 		return "", fmt.Errorf("failed to create policy version: %w", err)
 	}
@@ -146,7 +177,7 @@ func createPolicyVersion(ctx context.Context, policyArn, desiredDocument string)
 }
 
 // deleteOldestPolicyVersion removes oldest non-default version if at 5 version limit
-func deleteOldestPolicyVersion(ctx context.Context, policyArn string) error {
+func deleteOldestPolicyVersion(ctx context.Context, client API, policyArn string) error {
 	log := logf.FromContext(ctx).WithValues("policyArn", policyArn)
 
 	// List all versions
@@ -154,7 +185,7 @@ func deleteOldestPolicyVersion(ctx context.Context, policyArn string) error {
 		PolicyArn: aws.String(policyArn),
 	}
 
-	listOutput, err := iamClient.ListPolicyVersions(ctx, listInput)
+	listOutput, err := client.ListPolicyVersions(ctx, listInput)
 	if err != nil {
 		return fmt.Errorf("failed to list policy versions: %w", err)
 	}
@@ -190,7 +221,7 @@ func deleteOldestPolicyVersion(ctx context.Context, policyArn string) error {
 		VersionId: oldestVersion.VersionId,
 	}
 
-	_, err = iamClient.DeletePolicyVersion(ctx, deleteInput)
+	_, err = client.DeletePolicyVersion(ctx, deleteInput)
 	if err != nil {
 		return fmt.Errorf("failed to delete old policy version %s: %w", aws.ToString(oldestVersion.VersionId), err)
 	}
@@ -202,9 +233,9 @@ func deleteOldestPolicyVersion(ctx context.Context, policyArn string) error {
 }
 
 // getCurrentPolicyDocument retrieves the current default policy document
-func getCurrentPolicyDocument(ctx context.Context, policyArn string) (string, string, error) {
+func getCurrentPolicyDocument(ctx context.Context, client API, policyArn string) (string, string, error) {
 	// First get policy to find default version
-	policy, err := getPolicyByArn(ctx, policyArn)
+	policy, err := getPolicyByArn(ctx, client, policyArn)
 	if err != nil {
 		return "", "", err
 	}
@@ -220,24 +251,24 @@ func getCurrentPolicyDocument(ctx context.Context, policyArn string) (string, st
 		VersionId: aws.String(defaultVersionId),
 	}
 
-	output, err := iamClient.GetPolicyVersion(ctx, input)
+	output, err := client.GetPolicyVersion(ctx, input)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get policy version: %w", err)
 	}
 
-	document := aws.ToString(output.PolicyVersion.Document)
+	document := awsclient.DecodePolicyDocument(aws.ToString(output.PolicyVersion.Document))
 	return document, defaultVersionId, nil
 }
 
 // deletePolicy deletes an IAM policy by ARN
-func deletePolicy(ctx context.Context, policyArn string) error {
+func deletePolicy(ctx context.Context, client API, policyArn string) error {
 	log := logf.FromContext(ctx).WithValues("policyArn", policyArn)
 
 	deleteInput := &iam.DeletePolicyInput{
 		PolicyArn: aws.String(policyArn),
 	}
 
-	_, err := iamClient.DeletePolicy(ctx, deleteInput)
+	_, err := client.DeletePolicy(ctx, deleteInput)
 	if err == nil {
 		log.Info("Successfully deleted IAM policy", "policyArn", policyArn)
 		return nil
@@ -253,7 +284,7 @@ func deletePolicy(ctx context.Context, policyArn string) error {
 }
 
 // deletePolicyAllVersions deletes all non-default versions of a policy
-func deletePolicyAllVersions(ctx context.Context, policyArn string) error {
+func deletePolicyAllVersions(ctx context.Context, client API, policyArn string) error {
 	log := logf.FromContext(ctx).WithValues("policyArn", policyArn)
 
 	// List all versions
@@ -261,7 +292,7 @@ func deletePolicyAllVersions(ctx context.Context, policyArn string) error {
 		PolicyArn: aws.String(policyArn),
 	}
 
-	listOutput, err := iamClient.ListPolicyVersions(ctx, listInput)
+	listOutput, err := client.ListPolicyVersions(ctx, listInput)
 	if err != nil {
 		// If policy not found, versions already gone
 		if isNotFoundError(err) {
@@ -286,7 +317,7 @@ func deletePolicyAllVersions(ctx context.Context, policyArn string) error {
 			VersionId: version.VersionId,
 		}
 
-		_, err := iamClient.DeletePolicyVersion(ctx, deleteInput)
+		_, err := client.DeletePolicyVersion(ctx, deleteInput)
 		if err != nil {
 			// If version not found, it's already deleted - continue
 			if isNotFoundError(err) {
@@ -307,6 +338,42 @@ func deletePolicyAllVersions(ctx context.Context, policyArn string) error {
 	return nil
 }
 
+// tagPolicy adds tags to an existing policy
+func tagPolicy(ctx context.Context, client API, policyArn string, tags map[string]string) error {
+	_, err := client.TagPolicy(ctx, &iam.TagPolicyInput{
+		PolicyArn: aws.String(policyArn),
+		Tags:      toIAMTags(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag policy: %w", err)
+	}
+	return nil
+}
+
+// untagPolicy removes tags from a policy; a missing policy has no tags to remove
+func untagPolicy(ctx context.Context, client API, policyArn string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := client.UntagPolicy(ctx, &iam.UntagPolicyInput{
+		PolicyArn: aws.String(policyArn),
+		TagKeys:   keys,
+	})
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("failed to untag policy: %w", err)
+	}
+	return nil
+}
+
+// fromIAMTags converts an IAM tag slice to a map
+func fromIAMTags(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
 // toIAMTags converts map to IAM tag slice
 func toIAMTags(tags map[string]string) []types.Tag {
 	if len(tags) == 0 {
@@ -356,6 +423,7 @@ func isNotFoundError(err error) bool {
 }
 
 // iamErrorClassifier wraps the AWS SDK retry logic for use with result builder utilities.
+// Register replaces it when an embedding program supplies WithErrorClassifier.
 var iamErrorClassifier = controller.ErrorClassifier(isRetryable)
 
 // isRetryable determines if an error is retryable using AWS SDK's built-in error classification.
@@ -10,11 +10,16 @@ package iampolicy
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
 )
 
 // IamPolicyConfig represents the configuration structure for IAM policy components
 // that gets unmarshaled from Component.Spec.Config
 type IamPolicyConfig struct {
+	// AccessConfig optionally selects the AWS region and a role to assume in another account
+	awsclient.AccessConfig
+
 	// PolicyName is the name of the IAM policy to create/update
 	PolicyName string `json:"policyName"`
 
@@ -30,6 +35,18 @@ type IamPolicyConfig struct {
 
 	// Tags are optional key-value pairs to tag the IAM policy
 	Tags map[string]string `json:"tags,omitempty"`
+
+	// AdoptionPolicy controls whether an existing policy that is not tagged as owned by this Component
+	// is taken over: Never (default), IfUntagged or Always
+	AdoptionPolicy string `json:"adoptionPolicy,omitempty"`
+
+	// DeletionPolicy controls what happens to the policy when the Component is deleted:
+	// Delete (default) or Retain, which keeps the policy and removes its ownership tags
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// RemediateDrift makes health checks restore the policy document when it is changed outside the provider.
+	// By default drift is only reported as Degraded.
+	RemediateDrift bool `json:"remediateDrift,omitempty"`
 }
 
 // IamPolicyStatus contains handler-specific status data for IAM policy deployments.
@@ -39,6 +56,12 @@ type IamPolicyStatus struct {
 	PolicyId         string `json:"policyId,omitempty"`
 	PolicyName       string `json:"policyName,omitempty"`
 	CurrentVersionId string `json:"currentVersionId,omitempty"`
+
+	// Access is the AWS target the policy was created in
+	Access *awsclient.AccessConfig `json:"access,omitempty"`
+
+	// Plan lists the changes a dry-run apply would make; it is cleared by a real apply
+	Plan *awsclient.Plan `json:"plan,omitempty"`
 }
 
 // resolveSpec validates config and applies defaults
@@ -56,6 +79,21 @@ func resolveSpec(config *IamPolicyConfig) error {
 		return fmt.Errorf("policyDocument must be valid JSON")
 	}
 
+	// Validate AWS access settings
+	if err := config.AccessConfig.Validate(); err != nil {
+		return err
+	}
+
+	policy, err := awsclient.ResolveAdoptionPolicy(config.AdoptionPolicy)
+	if err != nil {
+		return err
+	}
+	config.AdoptionPolicy = policy
+
+	if config.DeletionPolicy, err = awsclient.ResolveDeletionPolicy(config.DeletionPolicy); err != nil {
+		return err
+	}
+
 	// Apply defaults
 	if err := applyDefaults(config); err != nil {
 		return err
@@ -64,6 +102,24 @@ func resolveSpec(config *IamPolicyConfig) error {
 	return nil
 }
 
+// resolveAccess returns the AWS target the policy was created in.
+// Falls back to the config for policies that have not been created yet.
+func resolveAccess(spec IamPolicyConfig, status IamPolicyStatus) awsclient.AccessConfig {
+	if status.Access != nil {
+		return *status.Access
+	}
+	return iamClients.Resolve(spec.AccessConfig)
+}
+
+// adoptionPolicy returns the adoption policy for an existing policy. A policy already recorded
+// in status was managed before ownership tags were introduced, so it is tagged instead of refused.
+func adoptionPolicy(spec IamPolicyConfig, status IamPolicyStatus, policyArn string) string {
+	if spec.AdoptionPolicy == awsclient.AdoptionNever && status.PolicyArn == policyArn {
+		return awsclient.AdoptionIfUntagged
+	}
+	return spec.AdoptionPolicy
+}
+
 // applyDefaults sets sensible defaults for optional IAM policy configuration fields
 func applyDefaults(config *IamPolicyConfig) error {
 	// Default path to root if not specified
@@ -0,0 +1,34 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iampolicy
+
+import (
+	"context"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	"github.com/rinswind/componator/componentkit/functional"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// Package-level event recorder initialized during registration; nil discards events
+var iamEvents *awsclient.EventRecorder
+
+// actionError records a Warning event for AWS errors that need attention and builds the action result
+func actionError(ctx context.Context, name k8stypes.NamespacedName, status IamPolicyStatus, err error) (*functional.ActionResult[IamPolicyStatus], error) {
+	iamEvents.AWSError(ctx, name, err)
+	return functional.ActionResultForError(status, err, iamErrorClassifier)
+}
+
+// checkError records a Warning event for AWS errors that need attention and builds the check result
+func checkError(ctx context.Context, name k8stypes.NamespacedName, status IamPolicyStatus, err error) (*functional.CheckResult[IamPolicyStatus], error) {
+	iamEvents.AWSError(ctx, name, err)
+	return functional.CheckResultForError(status, err, iamErrorClassifier)
+}
+
+// healthError records a Warning event for AWS errors that need attention and builds the health check result
+func healthError(ctx context.Context, name k8stypes.NamespacedName, err error) (*controller.HealthCheckResult, error) {
+	iamEvents.AWSError(ctx, name, err)
+	return controller.HealthCheckResultForError(err, iamErrorClassifier, "APIError")
+}
@@ -0,0 +1,87 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iampolicy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkHealth detects out-of-band changes to Ready IAM policies.
+// This is called periodically while the Component is in Ready state.
+//
+// Drift is reported as Degraded:
+//   - PolicyDeleted: the policy no longer exists
+//   - PolicyDocumentDrift: the default version document differs from policyDocument
+//
+// With remediateDrift set, the configured policy is restored instead.
+func checkHealth(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec IamPolicyConfig,
+	status IamPolicyStatus) (*controller.HealthCheckResult, error) {
+
+	// A dry-run Component does not manage the policy yet
+	if status.Plan != nil {
+		return controller.HealthCheckHealthy("Dry run: " + status.Plan.String())
+	}
+
+	if err := resolveSpec(&spec); err != nil {
+		return controller.HealthCheckDegraded("InvalidConfig", fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	log := logf.FromContext(ctx).WithValues("policyName", spec.PolicyName, "policyArn", status.PolicyArn)
+	client := iamClients.Get(resolveAccess(spec, status))
+	drift := driftReporter(ctx, name, spec.RemediateDrift)
+
+	policy, err := getPolicyByArn(ctx, client, status.PolicyArn)
+	if err != nil {
+		return healthError(ctx, name, err)
+	}
+
+	if policy == nil {
+		return drift.Report(ctx, name, "PolicyDeleted",
+			fmt.Sprintf("IAM policy %s not found in AWS", status.PolicyArn),
+			func() error {
+				tags := iamOwnership.WithTags(name, spec.Tags)
+				_, err := createPolicy(ctx, client, spec.PolicyName, spec.PolicyDocument, spec.Path, spec.Description, tags)
+				return err
+			})
+	}
+
+	document, versionId, err := getCurrentPolicyDocument(ctx, client, status.PolicyArn)
+	if err != nil {
+		return healthError(ctx, name, err)
+	}
+
+	if !jsonEquals(document, spec.PolicyDocument) {
+		return drift.Report(ctx, name, "PolicyDocumentDrift",
+			fmt.Sprintf("Default version %s of IAM policy %s differs from policyDocument", versionId, status.PolicyArn),
+			func() error {
+				_, err := createPolicyVersion(ctx, client, status.PolicyArn, spec.PolicyDocument)
+				return err
+			})
+	}
+
+	log.V(1).Info("IAM policy matches config", "versionId", versionId)
+	return controller.HealthCheckHealthy(
+		fmt.Sprintf("Policy %s matches config (version %s)", aws.ToString(policy.PolicyName), versionId))
+}
+
+// driftReporter reports drift on the named Component. Remediation is disabled while
+// the Component is in dry-run mode, since it would change AWS.
+func driftReporter(ctx context.Context, name k8stypes.NamespacedName, remediate bool) *awsclient.DriftReporter {
+	if remediate {
+		if dryRun, err := iamDryRun.Enabled(ctx, name); err != nil || dryRun {
+			remediate = false
+		}
+	}
+	return awsclient.NewDriftReporter(iamEvents, remediate)
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/functional"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -25,20 +26,70 @@ func applyAction(
 		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
 	}
 
+	// The policy cannot follow an account change
+	access := iamClients.Resolve(spec.AccessConfig)
+	if err := access.CheckUnchangedAccount(status.Access); err != nil {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	dryRun, err := iamDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+
 	log := logf.FromContext(ctx).WithValues("policyName", spec.PolicyName)
-	log.Info("Starting IAM policy deployment")
+	log.Info("Starting IAM policy deployment", "dryRun", dryRun)
+
+	client := iamClients.Get(access)
 
 	// Check if policy already exists
-	existingPolicy, err := getPolicyByName(ctx, spec.PolicyName, spec.Path)
+	existingPolicy, err := getPolicyByName(ctx, client, spec.PolicyName, spec.Path)
 	if err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to check if policy exists: %w", err), iamErrorClassifier)
+		return actionError(ctx, name, status, fmt.Errorf("failed to check if policy exists: %w", err))
+	}
+
+	// Only manage an existing policy owned by this Component, or one the adoption policy allows taking over
+	adopt := false
+	if existingPolicy != nil {
+		policyArn := aws.ToString(existingPolicy.Arn)
+
+		// ListPolicies returns no tags
+		tagged, err := getPolicyByArn(ctx, client, policyArn)
+		if err != nil {
+			return actionError(ctx, name, status, fmt.Errorf("failed to read policy tags: %w", err))
+		}
+		if tagged != nil {
+			existingPolicy = tagged
+		}
+
+		adopt, err = iamOwnership.CheckAdoption(name, fromIAMTags(existingPolicy.Tags), adoptionPolicy(spec, status, policyArn))
+		if err != nil {
+			iamEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused, "Not managing IAM policy %s: %v", policyArn, err)
+			return functional.ActionFailure(status, fmt.Sprintf("cannot manage policy %s: %v", policyArn, err))
+		}
 	}
 
+	// In dry-run mode only record what would change
+	if dryRun {
+		plan, err := planPolicy(ctx, client, &spec, existingPolicy)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		if adopt {
+			plan.Add("ownership", name.String())
+		}
+		status.Plan = plan
+		log.Info("Dry run, not applying changes", "plan", plan.String())
+		return functional.ActionSuccess(status, "Dry run: "+plan.String())
+	}
+	status.Plan = nil
+
 	if existingPolicy == nil {
 		// Policy doesn't exist - create it
-		policy, err := createPolicy(ctx, spec.PolicyName, string(spec.PolicyDocument), spec.Path, spec.Description, spec.Tags)
+		tags := iamOwnership.WithTags(name, spec.Tags)
+		policy, err := createPolicy(ctx, client, spec.PolicyName, string(spec.PolicyDocument), spec.Path, spec.Description, tags)
 		if err != nil {
-			return functional.ActionResultForError(status, fmt.Errorf("failed to create policy: %w", err), iamErrorClassifier)
+			return actionError(ctx, name, status, fmt.Errorf("failed to create policy: %w", err))
 		}
 
 		// Update status with created policy info
@@ -46,6 +97,9 @@ func applyAction(
 		status.PolicyId = aws.ToString(policy.PolicyId)
 		status.PolicyName = aws.ToString(policy.PolicyName)
 		status.CurrentVersionId = aws.ToString(policy.DefaultVersionId)
+		status.Access = &access
+
+		iamEvents.Normal(ctx, name, awsclient.ReasonCreated, "Created IAM policy %s", status.PolicyArn)
 
 		details := fmt.Sprintf("Created policy %s", status.PolicyName)
 		return functional.ActionSuccess(status, details)
@@ -58,13 +112,26 @@ func applyAction(
 
 	log.Info("Policy already exists, checking for updates", "policyArn", status.PolicyArn)
 
-	versionId, err := createPolicyVersion(ctx, status.PolicyArn, string(spec.PolicyDocument))
+	if adopt {
+		if err := tagPolicy(ctx, client, status.PolicyArn, iamOwnership.Tags(name)); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		iamEvents.Normal(ctx, name, awsclient.ReasonAdopted, "Adopted IAM policy %s", status.PolicyArn)
+	}
+
+	versionId, err := createPolicyVersion(ctx, client, status.PolicyArn, string(spec.PolicyDocument))
 	if err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to update policy version: %w", err), iamErrorClassifier)
+		return actionError(ctx, name, status, fmt.Errorf("failed to update policy version: %w", err))
+	}
+
+	if versionId != aws.ToString(existingPolicy.DefaultVersionId) {
+		iamEvents.Normal(ctx, name, awsclient.ReasonUpdated,
+			"Created version %s of IAM policy %s", versionId, status.PolicyArn)
 	}
 
 	// Update status with current version
 	status.CurrentVersionId = versionId
+	status.Access = &access
 
 	details := fmt.Sprintf("Updated policy %s to version %s", status.PolicyName, versionId)
 	return functional.ActionSuccess(status, details)
@@ -79,21 +146,30 @@ func checkApplied(
 
 	log := logf.FromContext(ctx).WithValues("policyName", spec.PolicyName)
 
+	// A dry-run apply changed nothing. A resource that was never created is not ready.
+	if status.Plan != nil {
+		if status.Plan.Action == awsclient.PlanCreate {
+			return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+		}
+		return functional.CheckComplete(status, "Dry run: "+status.Plan.String())
+	}
+
 	// If we don't have a policy ARN yet, deployment hasn't started
 	if status.PolicyArn == "" {
 		log.V(1).Info("No policy ARN in status, deployment not started")
 		return functional.CheckInProgress(status, "")
 	}
 
+	client := iamClients.Get(resolveAccess(spec, status))
+
 	// Verify policy exists
-	policy, err := getPolicyByArn(ctx, status.PolicyArn)
+	policy, err := getPolicyByArn(ctx, client, status.PolicyArn)
 	if err != nil {
-		return functional.CheckResultForError(status, fmt.Errorf("failed to check policy status: %w", err), iamErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("failed to check policy status: %w", err))
 	}
 
 	if policy == nil {
-		return functional.CheckResultForError(status,
-			fmt.Errorf("policy not found at ARN %s", status.PolicyArn), iamErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("policy not found at ARN %s", status.PolicyArn))
 	}
 
 	// Update status with current policy info
@@ -120,12 +196,33 @@ func deleteAction(
 		return functional.ActionSuccess(status, "No policy to delete")
 	}
 
+	retain := spec.DeletionPolicy == awsclient.DeletionPolicyRetain
+
+	dryRun, err := iamDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+	if dryRun {
+		status.Plan = awsclient.NewDeletePlan(status.PolicyArn)
+		if retain {
+			status.Plan = awsclient.NewRetainPlan(status.PolicyArn)
+		}
+		log.Info("Dry run, holding back deletion of policy", "plan", status.Plan.String())
+		return functional.ActionResultForError(status, awsclient.DeletionHeld(status.Plan), awsclient.RetryDryRun)
+	}
+
+	client := iamClients.Get(resolveAccess(spec, status))
+
+	if retain {
+		return retainPolicy(ctx, name, client, status)
+	}
+
 	log.Info("Starting IAM policy deletion")
 
 	// Verify policy exists before attempting deletion
-	policy, err := getPolicyByArn(ctx, status.PolicyArn)
+	policy, err := getPolicyByArn(ctx, client, status.PolicyArn)
 	if err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to check policy existence: %w", err), iamErrorClassifier)
+		return actionError(ctx, name, status, fmt.Errorf("failed to check policy existence: %w", err))
 	}
 
 	// If policy doesn't exist, deletion is already complete
@@ -135,19 +232,50 @@ func deleteAction(
 	}
 
 	// Delete all non-default versions first
-	if err := deletePolicyAllVersions(ctx, status.PolicyArn); err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to delete policy versions: %w", err), iamErrorClassifier)
+	if err := deletePolicyAllVersions(ctx, client, status.PolicyArn); err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to delete policy versions: %w", err))
 	}
 
 	// Delete the policy itself
-	if err := deletePolicy(ctx, status.PolicyArn); err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to delete policy: %w", err), iamErrorClassifier)
+	if err := deletePolicy(ctx, client, status.PolicyArn); err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to delete policy: %w", err))
 	}
 
+	iamEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleted IAM policy %s", status.PolicyArn)
+
 	details := fmt.Sprintf("Deleting policy %s", status.PolicyName)
 	return functional.ActionSuccess(status, details)
 }
 
+// retainPolicy keeps the policy in AWS and removes the ownership tags,
+// so that another Component can adopt it
+func retainPolicy(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	client API,
+	status IamPolicyStatus) (*functional.ActionResult[IamPolicyStatus], error) {
+
+	log := logf.FromContext(ctx).WithValues("policyArn", status.PolicyArn)
+
+	policy, err := getPolicyByArn(ctx, client, status.PolicyArn)
+	if err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to check policy existence: %w", err))
+	}
+
+	// Tags of a policy adopted by another Component since are left alone
+	if policy != nil && iamOwnership.Owns(name, fromIAMTags(policy.Tags)) {
+		if err := untagPolicy(ctx, client, status.PolicyArn, iamOwnership.TagKeys()); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+	}
+
+	log.Info("DeletionPolicy is Retain, leaving policy in place")
+	iamEvents.Normal(ctx, name, awsclient.ReasonRetained, "Retained IAM policy %s", status.PolicyArn)
+
+	details := fmt.Sprintf("Policy %s retained (Retain policy)", status.PolicyName)
+	return functional.ActionSuccess(status, details)
+}
+
 // checkDeleted verifies deletion is complete
 func checkDeleted(
 	ctx context.Context,
@@ -157,16 +285,28 @@ func checkDeleted(
 
 	log := logf.FromContext(ctx).WithValues("policyName", spec.PolicyName)
 
+	// A dry-run delete is held back until dry run is turned off
+	if status.Plan != nil && (status.Plan.Action == awsclient.PlanDelete || status.Plan.Action == awsclient.PlanRetain) {
+		return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+	}
+
+	// A retained policy stays in AWS
+	if spec.DeletionPolicy == awsclient.DeletionPolicyRetain {
+		return functional.CheckComplete(status, fmt.Sprintf("Policy %s retained", status.PolicyName))
+	}
+
 	// If no policy ARN in status, deletion is complete
 	if status.PolicyArn == "" {
 		log.V(1).Info("No policy ARN in status, deletion complete")
 		return functional.CheckComplete(status, "")
 	}
 
+	client := iamClients.Get(resolveAccess(spec, status))
+
 	// Verify policy no longer exists
-	policy, err := getPolicyByArn(ctx, status.PolicyArn)
+	policy, err := getPolicyByArn(ctx, client, status.PolicyArn)
 	if err != nil {
-		return functional.CheckResultForError(status, fmt.Errorf("failed to check policy deletion status: %w", err), iamErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("failed to check policy deletion status: %w", err))
 	}
 
 	// Policy still exists - deletion in progress
@@ -0,0 +1,327 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iampolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator-aws-providers/awsfake"
+	"github.com/rinswind/componator/componentkit/controller"
+)
+
+func TestIamPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IAM Policy Suite")
+}
+
+const (
+	readOnlyDocument = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`
+	readOnlyReformat = `{
+		"Statement": [{"Resource": "*", "Action": "s3:GetObject", "Effect": "Allow"}],
+		"Version": "2012-10-17"
+	}`
+	readWriteDocument = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":"*"}]}`
+)
+
+var _ = Describe("IAM Policy Operations", func() {
+	var (
+		ctx  context.Context
+		fake *awsfake.IAM
+		name k8stypes.NamespacedName
+		spec IamPolicyConfig
+		arn  string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = awsfake.NewIAM()
+		iamClients = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceIAM, func(aws.Config) API { return fake })
+
+		name = k8stypes.NamespacedName{Namespace: "default", Name: "app-policy"}
+		spec = IamPolicyConfig{
+			PolicyName:     "app-policy",
+			PolicyDocument: readOnlyDocument,
+			Path:           "/componator/",
+		}
+		arn = fake.PolicyArn("/componator/", "app-policy")
+
+		// Unrelated policies created first push app-policy past the fake's first ListPolicies page
+		for _, other := range []string{"other-a", "other-b", "other-c"} {
+			_, err := createPolicy(ctx, fake, other, readOnlyDocument, "/componator/", "", nil)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should create, version and delete a policy", func() {
+		By("creating the policy")
+		_, err := applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Policy(arn)).NotTo(BeNil())
+		Expect(aws.ToString(fake.Policy(arn).DefaultVersionId)).To(Equal("v1"))
+
+		status := IamPolicyStatus{PolicyArn: arn}
+		_, err = checkApplied(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("finding the existing policy on a later ListPolicies page")
+		listCalls := fake.CallCount("ListPolicies")
+		policy, err := getPolicyByName(ctx, fake, "app-policy", "/componator/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policy).NotTo(BeNil())
+		Expect(aws.ToString(policy.Arn)).To(Equal(arn))
+		Expect(fake.CallCount("ListPolicies") - listCalls).To(Equal(2))
+
+		By("skipping a new version for a semantically equal document")
+		spec.PolicyDocument = readOnlyReformat
+		_, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.PolicyVersions(arn)).To(HaveLen(1))
+
+		By("creating a new version for a changed document")
+		spec.PolicyDocument = readWriteDocument
+		_, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.PolicyVersions(arn)).To(HaveLen(2))
+		Expect(aws.ToString(fake.Policy(arn).DefaultVersionId)).To(Equal("v2"))
+		Expect(fake.CallCount("CreatePolicy")).To(Equal(4))
+
+		By("deleting the policy and its versions")
+		_, err = deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Policy(arn)).To(BeNil())
+
+		_, err = checkDeleted(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should record events only for real changes", func() {
+		recorder := record.NewFakeRecorder(10)
+		iamEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { iamEvents = nil })
+
+		_, err := applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Created IAM policy " + arn)))
+
+		spec.PolicyDocument = readOnlyReformat
+		_, err = applyAction(ctx, name, spec, IamPolicyStatus{PolicyArn: arn})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		spec.PolicyDocument = readWriteDocument
+		_, err = applyAction(ctx, name, spec, IamPolicyStatus{PolicyArn: arn})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Normal Updated Created version v2 of IAM policy " + arn)))
+
+		fake.FailNext("DeletePolicy", &smithy.GenericAPIError{Code: "AccessDenied", Message: "not allowed"})
+		_, err = deleteAction(ctx, name, spec, IamPolicyStatus{PolicyArn: arn})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning AccessDenied ")))
+	})
+
+	It("should plan a document change without creating a version in dry-run mode", func() {
+		_, err := applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		iamDryRun = awsclient.NewDryRun(awsfake.NewComponents(name), true)
+		DeferCleanup(func() { iamDryRun = nil })
+
+		spec.PolicyDocument = readOnlyReformat
+		result, err := applyAction(ctx, name, spec, IamPolicyStatus{PolicyArn: arn})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan).To(Equal(&awsclient.Plan{Action: awsclient.PlanNoChange, Resource: arn}))
+
+		spec.PolicyDocument = readWriteDocument
+		result, err = applyAction(ctx, name, spec, IamPolicyStatus{PolicyArn: arn})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan.Changes).To(Equal([]awsclient.Change{{
+			Field: "policyDocument", Action: awsclient.ChangeModify, Current: readOnlyDocument, Desired: readWriteDocument,
+		}}))
+		Expect(fake.PolicyVersions(arn)).To(HaveLen(1))
+
+		checked, err := checkApplied(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Details).To(Equal("Dry run: would update " + arn + " (1 changes)"))
+
+		_, err = deleteAction(ctx, name, spec, result.Status)
+		Expect(err).To(MatchError(ContainSubstring("deletion waits until dry run is turned off")))
+		Expect(fake.Policy(arn)).NotTo(BeNil())
+	})
+
+	It("should report and remediate policy document drift", func() {
+		recorder := record.NewFakeRecorder(10)
+		iamEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { iamEvents = nil })
+
+		_, err := applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive())
+		status := IamPolicyStatus{PolicyArn: arn}
+
+		result, err := checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Policy app-policy matches config (version v1)")
+		Expect(result).To(Equal(healthy))
+
+		By("detecting a default version created outside the provider")
+		_, err = fake.CreatePolicyVersion(ctx, &iam.CreatePolicyVersionInput{
+			PolicyArn: aws.String(arn), PolicyDocument: aws.String(readWriteDocument), SetAsDefault: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		message := "Default version v2 of IAM policy " + arn + " differs from policyDocument"
+		result, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("PolicyDocumentDrift", message)
+		Expect(result).To(Equal(degraded))
+		Expect(recorder.Events).To(Receive(Equal("Warning DriftDetected " + message)))
+
+		By("restoring the configured document when remediation is enabled")
+		spec.RemediateDrift = true
+		result, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Healthy).To(BeTrue())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal DriftRemediated " + message)))
+
+		document, versionId, err := getCurrentPolicyDocument(ctx, fake, arn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(versionId).To(Equal("v3"))
+		Expect(document).To(MatchJSON(readOnlyDocument))
+
+		By("recreating a deleted policy")
+		Expect(deletePolicyAllVersions(ctx, fake, arn)).To(Succeed())
+		Expect(deletePolicy(ctx, fake, arn)).To(Succeed())
+		result, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Healthy).To(BeTrue())
+		Expect(fake.Policy(arn)).NotTo(BeNil())
+	})
+
+	It("should not remediate drift in dry-run mode", func() {
+		_, err := applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deletePolicy(ctx, fake, arn)).To(Succeed())
+
+		iamDryRun = awsclient.NewDryRun(awsfake.NewComponents(name), true)
+		DeferCleanup(func() { iamDryRun = nil })
+
+		spec.RemediateDrift = true
+		result, err := checkHealth(ctx, name, spec, IamPolicyStatus{PolicyArn: arn})
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("PolicyDeleted", "IAM policy "+arn+" not found in AWS")
+		Expect(result).To(Equal(degraded))
+		Expect(fake.Policy(arn)).To(BeNil())
+	})
+
+	It("should only take over a policy it does not own when adoption allows it", func() {
+		recorder := record.NewFakeRecorder(10)
+		iamEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		iamOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { iamEvents, iamOwnership = nil, nil })
+
+		_, err := createPolicy(ctx, fake, "app-policy", readOnlyDocument, "/componator/", "", map[string]string{"team": "legacy"})
+		Expect(err).NotTo(HaveOccurred())
+
+		By("refusing an untagged policy by default")
+		spec.PolicyDocument = readWriteDocument
+		result, err := applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(HavePrefix("cannot manage policy " + arn + ": resource has no ownership tags"))
+		Expect(fake.PolicyVersions(arn)).To(HaveLen(1))
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning AdoptionRefused ")))
+
+		By("adopting it with IfUntagged")
+		spec.AdoptionPolicy = awsclient.AdoptionIfUntagged
+		_, err = applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fromIAMTags(fake.Policy(arn).Tags)).To(Equal(map[string]string{
+			"team":                 "legacy",
+			awsclient.TagClusterID: "test",
+			awsclient.TagComponent: "default/app-policy",
+			awsclient.TagProvider:  DefaultProviderName,
+		}))
+		Expect(recorder.Events).To(Receive(Equal("Normal Adopted Adopted IAM policy " + arn)))
+		Expect(fake.PolicyVersions(arn)).To(HaveLen(2))
+
+		By("refusing it for another Component")
+		other := k8stypes.NamespacedName{Namespace: "other", Name: "app-policy"}
+		result, err = applyAction(ctx, other, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("owned by Component default/app-policy"))
+	})
+
+	It("should keep a retained policy and remove its ownership tags", func() {
+		recorder := record.NewFakeRecorder(10)
+		iamEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		iamOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { iamEvents, iamOwnership = nil, nil })
+
+		spec.Tags = map[string]string{"team": "data"}
+		_, err := applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal Created ")))
+		status := IamPolicyStatus{PolicyArn: arn, PolicyName: "app-policy"}
+
+		spec.DeletionPolicy = awsclient.DeletionPolicyRetain
+		result, err := deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Policy app-policy retained (Retain policy)"))
+		Expect(fake.Policy(arn)).NotTo(BeNil())
+		Expect(fromIAMTags(fake.Policy(arn).Tags)).To(Equal(map[string]string{"team": "data"}))
+		Expect(fake.CallCount("DeletePolicy")).To(BeZero())
+		Expect(recorder.Events).To(Receive(Equal("Normal Retained Retained IAM policy " + arn)))
+
+		check, err := checkDeleted(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Status).To(Equal(status))
+		Expect(check.Details).To(Equal("Policy app-policy retained"))
+	})
+
+	It("should prune the oldest version at the version limit", func() {
+		_, err := applyAction(ctx, name, spec, IamPolicyStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		for i, action := range []string{"a", "b", "c", "d", "e", "f"} {
+			document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:` + action + `","Resource":"*"}]}`
+			versionId, err := createPolicyVersion(ctx, fake, arn, document)
+			Expect(err).NotTo(HaveOccurred(), "iteration %d", i)
+			Expect(aws.ToString(fake.Policy(arn).DefaultVersionId)).To(Equal(versionId))
+		}
+
+		versions := fake.PolicyVersions(arn)
+		Expect(versions).To(HaveLen(MaxNumberOfPolicyVersions))
+		for _, v := range versions {
+			Expect(aws.ToString(v.VersionId)).NotTo(BeElementOf("v1", "v2"))
+		}
+	})
+
+	It("should treat a missing policy as deleted", func() {
+		status := IamPolicyStatus{PolicyArn: arn}
+		_, err := deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("DeletePolicy")).To(BeZero())
+
+		Expect(deletePolicy(ctx, fake, arn)).To(Succeed())
+		Expect(deletePolicyAllVersions(ctx, fake, arn)).To(Succeed())
+	})
+
+	It("should surface AWS errors from create", func() {
+		fake.FailNext("CreatePolicy", errors.New("boom"))
+
+		_, err := createPolicy(ctx, fake, spec.PolicyName, spec.PolicyDocument, spec.Path, "", nil)
+		Expect(err).To(MatchError(ContainSubstring("boom")))
+		Expect(fake.Policy(arn)).To(BeNil())
+	})
+})
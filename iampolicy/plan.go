@@ -0,0 +1,40 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iampolicy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
+
+// Package-level dry-run switch initialized during registration; nil never plans
+var iamDryRun *awsclient.DryRun
+
+// planPolicy computes what applyAction would change on the policy.
+// A nil policy plans its creation.
+func planPolicy(ctx context.Context, client API, spec *IamPolicyConfig, policy *types.Policy) (*awsclient.Plan, error) {
+	desired := string(spec.PolicyDocument)
+
+	if policy == nil {
+		plan := awsclient.NewPlan(spec.PolicyName, false)
+		plan.Add("policyDocument", desired)
+		return plan, nil
+	}
+
+	policyArn := aws.ToString(policy.Arn)
+	plan := awsclient.NewPlan(policyArn, true)
+
+	current, _, err := getCurrentPolicyDocument(ctx, client, policyArn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current policy document: %w", err)
+	}
+	if !jsonEquals(current, desired) {
+		plan.Modify("policyDocument", current, desired)
+	}
+	return plan, nil
+}
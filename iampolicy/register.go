@@ -5,10 +5,10 @@ package iampolicy
 
 import (
 	"context"
-	"fmt"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	v1beta1 "github.com/rinswind/componator/api/v1beta1"
 	"github.com/rinswind/componator/componentkit/functional"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -20,18 +20,28 @@ const (
 	DefaultProviderName = "iam-policy"
 )
 
+// iamRegistration refuses a second Register, which would take over the package state of the first
+var iamRegistration awsclient.Registration
+
 // Register registers the iam-policy Component provider with the controller manager.
 //
-// The providerName parameter specifies the unique name used for Component claiming.
-// Pass empty string to use the default "iam-policy". For setkit embedding, use a
-// prefixed name (e.g., "wordpress-iam-policy") to avoid conflicts with other providers.
+// Without options the provider is claimed as "iam-policy" and initializes AWS IAM clients
+// using the default credential chain (environment variables, EC2 instance metadata, etc.).
+// Components may override the region and assume a role in another account; one client
+// is cached per target. Embedding programs customize the name, AWS config or client,
+// timings and error classification through options.
 //
 // Provider names must be unique across all providers in the cluster. Multiple providers
 // with the same name will conflict during Component claiming.
 //
-// Initializes AWS IAM client using the default credential chain
-// (environment variables, EC2 instance metadata, etc.).
-func Register(mgr ctrl.Manager, providerName string) error {
+// The provider keeps its clients, ownership and dry-run settings in package state, so a package
+// can only be registered once per process; a second Register fails.
+func Register(mgr ctrl.Manager, opts ...Option) error {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// Ensure required schemes are registered (safe to call multiple times)
 	if err := clientgoscheme.AddToScheme(mgr.GetScheme()); err != nil {
 		return err
@@ -40,34 +50,59 @@ func Register(mgr ctrl.Manager, providerName string) error {
 		return err
 	}
 
-	// Use default provider name if not specified
-	if providerName == "" {
-		providerName = DefaultProviderName
+	// An injected client needs no credentials of its own
+	cfg := aws.Config{}
+	if o.client == nil || o.AWSConfig != nil {
+		var err error
+		if cfg, err = awsclient.BaseConfig(context.Background(), o.AWSConfig, o.Endpoints); err != nil {
+			return err
+		}
 	}
 
-	// Load AWS config with default chain (uses AWS_REGION, EC2 metadata, etc.)
-	// Disable retries - controller handles requeue
-	// Use WithEC2IMDSRegion to auto-detect region from EC2 metadata when in EKS
-	cfg, err := awsconfig.LoadDefaultConfig(
-		context.Background(),
-		awsconfig.WithRetryMaxAttempts(1),
-		awsconfig.WithEC2IMDSRegion(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to load AWS configuration: %w", err)
+	if err := iamRegistration.Claim(o.ProviderName); err != nil {
+		return err
 	}
 
-	iamClient = iam.NewFromConfig(cfg)
+	iamClients = awsclient.NewCache(cfg, o.Endpoints, awsclient.ServiceIAM, func(cfg aws.Config) API {
+		if o.client != nil {
+			return o.client
+		}
+		return iam.NewFromConfig(cfg)
+	})
+	iamErrorClassifier = o.ErrorClassifier
+	iamEvents = awsclient.NewEventRecorder(mgr.GetClient(), mgr.GetEventRecorderFor(o.ProviderName))
+	iamDryRun = awsclient.NewDryRun(mgr.GetClient(), o.DryRun)
+	iamOwnership = awsclient.NewOwnership(o.ClusterID, o.ProviderName)
 
 	// Log client initialization
 	log := logf.Log.WithName("iam-policy")
-	log.Info("Initialized AWS IAM client", "region", cfg.Region)
+	log.Info("Initialized AWS IAM client cache",
+		"defaultRegion", cfg.Region, "endpointOverride", o.Endpoints.IsSet(), "injectedClient", o.client != nil)
 
 	// Register with functional API
-	return functional.NewBuilder[IamPolicyConfig, IamPolicyStatus](providerName).
+	builder := functional.NewBuilder[IamPolicyConfig, IamPolicyStatus](o.ProviderName).
 		WithApply(applyAction).
 		WithApplyCheck(checkApplied).
 		WithDelete(deleteAction).
 		WithDeleteCheck(checkDeleted).
-		Register(mgr)
+		WithHealthCheck(checkHealth)
+
+	if o.HealthCheckInterval > 0 {
+		builder = builder.WithHealthCheckInterval(o.HealthCheckInterval)
+	}
+	if o.ErrorRequeue > 0 {
+		builder = builder.WithErrorRequeue(o.ErrorRequeue)
+	}
+	if o.DefaultRequeue > 0 {
+		builder = builder.WithDefaultRequeue(o.DefaultRequeue)
+	}
+	if o.StatusCheckRequeue > 0 {
+		builder = builder.WithStatusCheckRequeue(o.StatusCheckRequeue)
+	}
+
+	if err := builder.Register(mgr); err != nil {
+		iamRegistration.Release()
+		return err
+	}
+	return nil
 }
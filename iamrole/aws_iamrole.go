@@ -14,24 +14,52 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/controller"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// API is the subset of the AWS IAM API used by this provider.
+// It is satisfied by *iam.Client and by awsfake.IAM in unit tests.
+// Embedding programs may supply their own implementation with WithClient.
+type API interface {
+	GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	CreateRole(ctx context.Context, params *iam.CreateRoleInput, optFns ...func(*iam.Options)) (*iam.CreateRoleOutput, error)
+	DeleteRole(ctx context.Context, params *iam.DeleteRoleInput, optFns ...func(*iam.Options)) (*iam.DeleteRoleOutput, error)
+	UpdateAssumeRolePolicy(ctx context.Context, params *iam.UpdateAssumeRolePolicyInput, optFns ...func(*iam.Options)) (*iam.UpdateAssumeRolePolicyOutput, error)
+	ListAttachedRolePolicies(ctx context.Context, params *iam.ListAttachedRolePoliciesInput, optFns ...func(*iam.Options)) (*iam.ListAttachedRolePoliciesOutput, error)
+	AttachRolePolicy(ctx context.Context, params *iam.AttachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.AttachRolePolicyOutput, error)
+	DetachRolePolicy(ctx context.Context, params *iam.DetachRolePolicyInput, optFns ...func(*iam.Options)) (*iam.DetachRolePolicyOutput, error)
+	ListRoles(ctx context.Context, params *iam.ListRolesInput, optFns ...func(*iam.Options)) (*iam.ListRolesOutput, error)
+	TagRole(ctx context.Context, params *iam.TagRoleInput, optFns ...func(*iam.Options)) (*iam.TagRoleOutput, error)
+	UntagRole(ctx context.Context, params *iam.UntagRoleInput, optFns ...func(*iam.Options)) (*iam.UntagRoleOutput, error)
+}
+
 // Package-level singletons initialized during registration
 var (
-	iamClient *iam.Client
+	iamClients   *awsclient.Cache[API]
+	iamOwnership *awsclient.Ownership
 )
 
-// getRoleByName retrieves role by name
-func getRoleByName(ctx context.Context, roleName string) (*types.Role, error) {
+// ReadinessProbe checks that the IAM API is reachable with the controller's own credentials
+func ReadinessProbe(ctx context.Context) error {
+	_, err := iamClients.Get(awsclient.AccessConfig{}).ListRoles(ctx, &iam.ListRolesInput{
+		MaxItems: aws.Int32(1),
+	})
+	return err
+}
+
+// getRoleByName retrieves role by name, with its trust policy decoded to JSON
+func getRoleByName(ctx context.Context, client API, roleName string) (*types.Role, error) {
 	input := &iam.GetRoleInput{
 		RoleName: aws.String(roleName),
 	}
 
-	output, err := iamClient.GetRole(ctx, input)
+	output, err := client.GetRole(ctx, input)
 	if err == nil {
-		return output.Role, nil
+		role := output.Role
+		role.AssumeRolePolicyDocument = aws.String(awsclient.DecodePolicyDocument(aws.ToString(role.AssumeRolePolicyDocument)))
+		return role, nil
 	}
 
 	// Check if role not found
@@ -43,12 +71,12 @@ func getRoleByName(ctx context.Context, roleName string) (*types.Role, error) {
 }
 
 // listAttachedPolicies retrieves all managed policies currently attached to the role
-func listAttachedPolicies(ctx context.Context, roleName string) ([]string, error) {
+func listAttachedPolicies(ctx context.Context, client API, roleName string) ([]string, error) {
 	input := &iam.ListAttachedRolePoliciesInput{
 		RoleName: aws.String(roleName),
 	}
 
-	output, err := iamClient.ListAttachedRolePolicies(ctx, input)
+	output, err := client.ListAttachedRolePolicies(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list attached policies: %w", err)
 	}
@@ -64,6 +92,7 @@ func listAttachedPolicies(ctx context.Context, roleName string) ([]string, error
 // createRole creates a new IAM role with the specified trust policy and returns the created role
 func createRole(
 	ctx context.Context,
+	client API,
 	roleName, assumeRolePolicy, path, description string,
 	maxSessionDuration int32,
 	tags map[string]string) (*types.Role, error) {
@@ -81,7 +110,7 @@ func createRole(
 		Tags:                     toIAMTags(tags),
 	}
 
-	output, err := iamClient.CreateRole(ctx, input)
+	output, err := client.CreateRole(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create role: %w", err)
 	}
@@ -92,13 +121,13 @@ func createRole(
 }
 
 // updateTrustPolicy updates the assume role policy document for an existing role
-func updateTrustPolicy(ctx context.Context, roleName, currentPolicy, desiredPolicy string) error {
+func updateTrustPolicy(ctx context.Context, client API, roleName, currentPolicy, desiredPolicy string) (bool, error) {
 	log := logf.FromContext(ctx).WithValues("roleName", roleName)
 
 	// Compare policies (URL-decoded JSON from AWS vs our config)
 	if jsonEquals(currentPolicy, desiredPolicy) {
 		log.V(1).Info("Trust policy unchanged, skipping update")
-		return nil
+		return false, nil
 	}
 
 	log.Info("Trust policy changed, updating")
@@ -108,23 +137,23 @@ func updateTrustPolicy(ctx context.Context, roleName, currentPolicy, desiredPoli
 		PolicyDocument: aws.String(desiredPolicy),
 	}
 
-	_, err := iamClient.UpdateAssumeRolePolicy(ctx, input)
+	_, err := client.UpdateAssumeRolePolicy(ctx, input)
 	if err != nil {
-		return fmt.Errorf("failed to update assume role policy: %w", err)
+		return false, fmt.Errorf("failed to update assume role policy: %w", err)
 	}
 
 	log.Info("Successfully updated trust policy")
-	return nil
+	return true, nil
 }
 
 // attachPolicy attaches a managed policy to the role
-func attachPolicy(ctx context.Context, roleName, policyArn string) error {
+func attachPolicy(ctx context.Context, client API, roleName, policyArn string) error {
 	input := &iam.AttachRolePolicyInput{
 		RoleName:  aws.String(roleName),
 		PolicyArn: aws.String(policyArn),
 	}
 
-	_, err := iamClient.AttachRolePolicy(ctx, input)
+	_, err := client.AttachRolePolicy(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to attach policy: %w", err)
 	}
@@ -133,13 +162,13 @@ func attachPolicy(ctx context.Context, roleName, policyArn string) error {
 }
 
 // detachPolicy detaches a managed policy from the role
-func detachPolicy(ctx context.Context, roleName, policyArn string) error {
+func detachPolicy(ctx context.Context, client API, roleName, policyArn string) error {
 	input := &iam.DetachRolePolicyInput{
 		RoleName:  aws.String(roleName),
 		PolicyArn: aws.String(policyArn),
 	}
 
-	_, err := iamClient.DetachRolePolicy(ctx, input)
+	_, err := client.DetachRolePolicy(ctx, input)
 	if err != nil {
 		return fmt.Errorf("failed to detach policy: %w", err)
 	}
@@ -148,12 +177,12 @@ func detachPolicy(ctx context.Context, roleName, policyArn string) error {
 }
 
 // deleteRole deletes the IAM role
-func deleteRole(ctx context.Context, roleName string) error {
+func deleteRole(ctx context.Context, client API, roleName string) error {
 	input := &iam.DeleteRoleInput{
 		RoleName: aws.String(roleName),
 	}
 
-	_, err := iamClient.DeleteRole(ctx, input)
+	_, err := client.DeleteRole(ctx, input)
 	if err != nil {
 		// If role already deleted, treat as success
 		if isNotFoundError(err) {
@@ -165,6 +194,42 @@ func deleteRole(ctx context.Context, roleName string) error {
 	return nil
 }
 
+// tagRole adds tags to an existing role
+func tagRole(ctx context.Context, client API, roleName string, tags map[string]string) error {
+	_, err := client.TagRole(ctx, &iam.TagRoleInput{
+		RoleName: aws.String(roleName),
+		Tags:     toIAMTags(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag role: %w", err)
+	}
+	return nil
+}
+
+// untagRole removes tags from a role; a missing role has no tags to remove
+func untagRole(ctx context.Context, client API, roleName string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := client.UntagRole(ctx, &iam.UntagRoleInput{
+		RoleName: aws.String(roleName),
+		TagKeys:  keys,
+	})
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("failed to untag role: %w", err)
+	}
+	return nil
+}
+
+// fromIAMTags converts an IAM tag slice to a map
+func fromIAMTags(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
 // toIAMTags converts map to IAM tag slice
 func toIAMTags(tags map[string]string) []types.Tag {
 	if len(tags) == 0 {
@@ -214,6 +279,7 @@ func isNotFoundError(err error) bool {
 }
 
 // iamErrorClassifier wraps the AWS SDK retry logic for use with result builder utilities.
+// Register replaces it when an embedding program supplies WithErrorClassifier.
 var iamErrorClassifier = controller.ErrorClassifier(isRetryable)
 
 // isRetryable determines if an error is retryable using AWS SDK's built-in error classification.
@@ -22,39 +22,16 @@ type PolicyReconciliationResult struct {
 // reconcilePolicyAttachments ensures the role has exactly the desired managed policies attached.
 // Returns the actual list of attached policies after reconciliation (which may be partial on failure).
 // This allows status to reflect reality even when reconciliation fails partway through.
-func reconcilePolicyAttachments(ctx context.Context, roleName string, desiredPolicies []string) (*PolicyReconciliationResult, error) {
+func reconcilePolicyAttachments(ctx context.Context, client API, roleName string, desiredPolicies []string) (*PolicyReconciliationResult, error) {
 	log := logf.FromContext(ctx).WithValues("roleName", roleName)
 
 	// Get currently attached policies
-	currentPolicies, err := listAttachedPolicies(ctx, roleName)
+	currentPolicies, err := listAttachedPolicies(ctx, client, roleName)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to sets for comparison
-	desiredSet := make(map[string]bool)
-	for _, arn := range desiredPolicies {
-		desiredSet[arn] = true
-	}
-
-	currentSet := make(map[string]bool)
-	for _, arn := range currentPolicies {
-		currentSet[arn] = true
-	}
-
-	// Compute policies that need to be attached
-	toAttachSet := maps.Clone(desiredSet)
-	maps.DeleteFunc(toAttachSet, func(k string, _ bool) bool {
-		return currentSet[k] // Delete if exists in current
-	})
-	toAttach := slices.Sorted(maps.Keys(toAttachSet))
-
-	// Compute policies that need to be detached
-	toDetachSet := maps.Clone(currentSet)
-	maps.DeleteFunc(toDetachSet, func(k string, _ bool) bool {
-		return desiredSet[k] // Delete if exists in desired
-	})
-	toDetach := slices.Sorted(maps.Keys(toDetachSet))
+	toAttach, toDetach := diffPolicyAttachments(currentPolicies, desiredPolicies)
 
 	// Track actual state - starts with current, updated as we make changes
 	actuallyAttached := make(map[string]bool)
@@ -75,7 +52,7 @@ func reconcilePolicyAttachments(ctx context.Context, roleName string, desiredPol
 	// Detach removed policies first (cleanup before adding)
 	for _, arn := range toDetach {
 		log.Info("Detaching policy", "policyArn", arn)
-		if err := detachPolicy(ctx, roleName, arn); err != nil {
+		if err := detachPolicy(ctx, client, roleName, arn); err != nil {
 			// Return current actual state even on failure
 			return &PolicyReconciliationResult{
 				AttachedPolicies: slices.Sorted(maps.Keys(actuallyAttached)),
@@ -90,7 +67,7 @@ func reconcilePolicyAttachments(ctx context.Context, roleName string, desiredPol
 	attachedInThisOp := 0
 	for _, arn := range toAttach {
 		log.Info("Attaching policy", "policyArn", arn)
-		if err := attachPolicy(ctx, roleName, arn); err != nil {
+		if err := attachPolicy(ctx, client, roleName, arn); err != nil {
 			// Return partial progress - what we actually have attached
 			return &PolicyReconciliationResult{
 				AttachedPolicies: slices.Sorted(maps.Keys(actuallyAttached)),
@@ -110,3 +87,32 @@ func reconcilePolicyAttachments(ctx context.Context, roleName string, desiredPol
 		DetachedCount:    len(toDetach),
 	}, nil
 }
+
+// diffPolicyAttachments returns the sorted policies to attach and to detach
+// to get from the current to the desired attachments
+func diffPolicyAttachments(currentPolicies, desiredPolicies []string) ([]string, []string) {
+	// Convert to sets for comparison
+	desiredSet := make(map[string]bool)
+	for _, arn := range desiredPolicies {
+		desiredSet[arn] = true
+	}
+
+	currentSet := make(map[string]bool)
+	for _, arn := range currentPolicies {
+		currentSet[arn] = true
+	}
+
+	// Compute policies that need to be attached
+	toAttachSet := maps.Clone(desiredSet)
+	maps.DeleteFunc(toAttachSet, func(k string, _ bool) bool {
+		return currentSet[k] // Delete if exists in current
+	})
+
+	// Compute policies that need to be detached
+	toDetachSet := maps.Clone(currentSet)
+	maps.DeleteFunc(toDetachSet, func(k string, _ bool) bool {
+		return desiredSet[k] // Delete if exists in desired
+	})
+
+	return slices.Sorted(maps.Keys(toAttachSet)), slices.Sorted(maps.Keys(toDetachSet))
+}
@@ -10,11 +10,16 @@ package iamrole
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
 )
 
 // IamRoleConfig represents the configuration structure for IAM role components
 // that gets unmarshaled from Component.Spec.Config
 type IamRoleConfig struct {
+	// AccessConfig optionally selects the AWS region and a role to assume in another account
+	awsclient.AccessConfig
+
 	// RoleName is the name of the IAM role to create/update
 	RoleName string `json:"roleName"`
 
@@ -36,6 +41,18 @@ type IamRoleConfig struct {
 
 	// Tags are optional key-value pairs to tag the IAM role
 	Tags map[string]string `json:"tags,omitempty"`
+
+	// AdoptionPolicy controls whether an existing role that is not tagged as owned by this Component
+	// is taken over: Never (default), IfUntagged or Always
+	AdoptionPolicy string `json:"adoptionPolicy,omitempty"`
+
+	// DeletionPolicy controls what happens to the role when the Component is deleted: Delete (default)
+	// or Retain, which keeps the role with its policies attached and removes its ownership tags
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// RemediateDrift makes health checks restore the trust policy and policy attachments
+	// when they are changed outside the provider. By default drift is only reported as Degraded.
+	RemediateDrift bool `json:"remediateDrift,omitempty"`
 }
 
 // IamRoleStatus contains handler-specific status data for IAM role deployments.
@@ -45,6 +62,12 @@ type IamRoleStatus struct {
 	RoleId           string   `json:"roleId,omitempty"`
 	RoleName         string   `json:"roleName,omitempty"`
 	AttachedPolicies []string `json:"attachedPolicies,omitempty"`
+
+	// Access is the AWS target the role was created in
+	Access *awsclient.AccessConfig `json:"access,omitempty"`
+
+	// Plan lists the changes a dry-run apply would make; it is cleared by a real apply
+	Plan *awsclient.Plan `json:"plan,omitempty"`
 }
 
 // resolveSpec validates config and applies defaults
@@ -65,9 +88,24 @@ func resolveSpec(config *IamRoleConfig) error {
 		return fmt.Errorf("assumeRolePolicy must be valid JSON")
 	}
 
+	// Validate AWS access settings
+	if err := config.AccessConfig.Validate(); err != nil {
+		return err
+	}
+
 	// Note: We don't validate maxSessionDuration range - let AWS enforce current limits
 	// AWS limits change over time and hardcoding them creates maintenance burden
 
+	policy, err := awsclient.ResolveAdoptionPolicy(config.AdoptionPolicy)
+	if err != nil {
+		return err
+	}
+	config.AdoptionPolicy = policy
+
+	if config.DeletionPolicy, err = awsclient.ResolveDeletionPolicy(config.DeletionPolicy); err != nil {
+		return err
+	}
+
 	// Apply defaults
 	if err := applyDefaults(config); err != nil {
 		return err
@@ -76,6 +114,24 @@ func resolveSpec(config *IamRoleConfig) error {
 	return nil
 }
 
+// resolveAccess returns the AWS target the role was created in.
+// Falls back to the config for roles that have not been created yet.
+func resolveAccess(spec IamRoleConfig, status IamRoleStatus) awsclient.AccessConfig {
+	if status.Access != nil {
+		return *status.Access
+	}
+	return iamClients.Resolve(spec.AccessConfig)
+}
+
+// adoptionPolicy returns the adoption policy for an existing role. A role already recorded
+// in status was managed before ownership tags were introduced, so it is tagged instead of refused.
+func adoptionPolicy(spec IamRoleConfig, status IamRoleStatus, roleArn string) string {
+	if spec.AdoptionPolicy == awsclient.AdoptionNever && status.RoleArn == roleArn {
+		return awsclient.AdoptionIfUntagged
+	}
+	return spec.AdoptionPolicy
+}
+
 // applyDefaults sets sensible defaults for optional IAM role configuration fields
 func applyDefaults(config *IamRoleConfig) error {
 	// Default path to root if not specified
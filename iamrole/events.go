@@ -0,0 +1,43 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iamrole
+
+import (
+	"context"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	"github.com/rinswind/componator/componentkit/functional"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// Package-level event recorder initialized during registration; nil discards events
+var iamEvents *awsclient.EventRecorder
+
+// actionError records a Warning event for AWS errors that need attention and builds the action result
+func actionError(ctx context.Context, name k8stypes.NamespacedName, status IamRoleStatus, err error) (*functional.ActionResult[IamRoleStatus], error) {
+	iamEvents.AWSError(ctx, name, err)
+	return functional.ActionResultForError(status, err, iamErrorClassifier)
+}
+
+// checkError records a Warning event for AWS errors that need attention and builds the check result
+func checkError(ctx context.Context, name k8stypes.NamespacedName, status IamRoleStatus, err error) (*functional.CheckResult[IamRoleStatus], error) {
+	iamEvents.AWSError(ctx, name, err)
+	return functional.CheckResultForError(status, err, iamErrorClassifier)
+}
+
+// recordPolicyEvent records the managed policies attached and detached by one reconciliation
+func recordPolicyEvent(ctx context.Context, name k8stypes.NamespacedName, roleArn string, result *PolicyReconciliationResult) {
+	if result == nil || (result.AttachedCount == 0 && result.DetachedCount == 0) {
+		return
+	}
+	iamEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Attached %d and detached %d managed policies on IAM role %s",
+		result.AttachedCount, result.DetachedCount, roleArn)
+}
+
+// healthError records a Warning event for AWS errors that need attention and builds the health check result
+func healthError(ctx context.Context, name k8stypes.NamespacedName, err error) (*controller.HealthCheckResult, error) {
+	iamEvents.AWSError(ctx, name, err)
+	return controller.HealthCheckResultForError(err, iamErrorClassifier, "APIError")
+}
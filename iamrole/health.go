@@ -0,0 +1,112 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iamrole
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkHealth detects out-of-band changes to Ready IAM roles.
+// This is called periodically while the Component is in Ready state.
+//
+// Drift is reported as Degraded:
+//   - RoleDeleted: the role no longer exists
+//   - TrustPolicyDrift: the trust policy differs from assumeRolePolicy
+//   - PolicyAttachmentDrift: the attached managed policies differ from managedPolicyArns
+//
+// With remediateDrift set, the configured role is restored instead.
+func checkHealth(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec IamRoleConfig,
+	status IamRoleStatus) (*controller.HealthCheckResult, error) {
+
+	// A dry-run Component does not manage the role yet
+	if status.Plan != nil {
+		return controller.HealthCheckHealthy("Dry run: " + status.Plan.String())
+	}
+
+	if err := resolveSpec(&spec); err != nil {
+		return controller.HealthCheckDegraded("InvalidConfig", fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	log := logf.FromContext(ctx).WithValues("roleName", spec.RoleName)
+	client := iamClients.Get(resolveAccess(spec, status))
+	drift := driftReporter(ctx, name, spec.RemediateDrift)
+
+	role, err := getRoleByName(ctx, client, spec.RoleName)
+	if err != nil {
+		return healthError(ctx, name, err)
+	}
+
+	if role == nil {
+		return drift.Report(ctx, name, "RoleDeleted",
+			fmt.Sprintf("IAM role %s not found in AWS", spec.RoleName),
+			func() error {
+				tags := iamOwnership.WithTags(name, spec.Tags)
+				if _, err := createRole(ctx, client, spec.RoleName, spec.AssumeRolePolicy, spec.Path, spec.Description, spec.MaxSessionDuration, tags); err != nil {
+					return err
+				}
+				_, err := reconcilePolicyAttachments(ctx, client, spec.RoleName, spec.ManagedPolicyArns)
+				return err
+			})
+	}
+
+	currentPolicies, err := listAttachedPolicies(ctx, client, spec.RoleName)
+	if err != nil {
+		return healthError(ctx, name, err)
+	}
+
+	var reason string
+	var findings []string
+
+	currentTrust := aws.ToString(role.AssumeRolePolicyDocument)
+	if !jsonEquals(currentTrust, spec.AssumeRolePolicy) {
+		reason = "TrustPolicyDrift"
+		findings = append(findings, "trust policy differs from assumeRolePolicy")
+	}
+
+	toAttach, toDetach := diffPolicyAttachments(currentPolicies, spec.ManagedPolicyArns)
+	if len(toAttach) > 0 || len(toDetach) > 0 {
+		if reason == "" {
+			reason = "PolicyAttachmentDrift"
+		}
+		findings = append(findings, fmt.Sprintf("managed policies missing %v, unexpected %v", toAttach, toDetach))
+	}
+
+	if len(findings) > 0 {
+		return drift.Report(ctx, name, reason,
+			fmt.Sprintf("IAM role %s: %s", spec.RoleName, strings.Join(findings, "; ")),
+			func() error {
+				if _, err := updateTrustPolicy(ctx, client, spec.RoleName, currentTrust, spec.AssumeRolePolicy); err != nil {
+					return err
+				}
+				_, err := reconcilePolicyAttachments(ctx, client, spec.RoleName, spec.ManagedPolicyArns)
+				return err
+			})
+	}
+
+	log.V(1).Info("IAM role matches config", "policies", len(currentPolicies))
+	return controller.HealthCheckHealthy(
+		fmt.Sprintf("Role %s matches config with %d policies", spec.RoleName, len(currentPolicies)))
+}
+
+// driftReporter reports drift on the named Component. Remediation is disabled while
+// the Component is in dry-run mode, since it would change AWS.
+func driftReporter(ctx context.Context, name k8stypes.NamespacedName, remediate bool) *awsclient.DriftReporter {
+	if remediate {
+		if dryRun, err := iamDryRun.Enabled(ctx, name); err != nil || dryRun {
+			remediate = false
+		}
+	}
+	return awsclient.NewDriftReporter(iamEvents, remediate)
+}
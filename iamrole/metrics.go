@@ -0,0 +1,30 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iamrole
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// policyAttachmentChanges counts managed policy attachments and detachments made by reconciliation
+var policyAttachmentChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: awsclient.MetricsNamespace,
+	Name:      "iamrole_policy_attachment_changes_total",
+	Help:      "Managed policies attached to or detached from IAM roles during reconciliation.",
+}, []string{"action"})
+
+func init() {
+	metrics.Registry.MustRegister(policyAttachmentChanges)
+}
+
+// recordPolicyChanges adds the attachments and detachments of one reconciliation
+func recordPolicyChanges(result *PolicyReconciliationResult) {
+	if result == nil {
+		return
+	}
+	policyAttachmentChanges.WithLabelValues("attach").Add(float64(result.AttachedCount))
+	policyAttachmentChanges.WithLabelValues("detach").Add(float64(result.DetachedCount))
+}
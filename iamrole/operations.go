@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/functional"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -25,38 +26,83 @@ func applyAction(
 		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
 	}
 
+	// The role cannot follow an account change
+	access := iamClients.Resolve(spec.AccessConfig)
+	if err := access.CheckUnchangedAccount(status.Access); err != nil {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	dryRun, err := iamDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+
 	log := logf.FromContext(ctx).WithValues("roleName", spec.RoleName)
-	log.Info("Starting IAM role deployment")
+	log.Info("Starting IAM role deployment", "dryRun", dryRun)
+
+	client := iamClients.Get(access)
 
 	// Check if role already exists
-	existingRole, err := getRoleByName(ctx, spec.RoleName)
+	existingRole, err := getRoleByName(ctx, client, spec.RoleName)
 	if err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to check if role exists: %w", err), iamErrorClassifier)
+		return actionError(ctx, name, status, fmt.Errorf("failed to check if role exists: %w", err))
+	}
+
+	// Only manage an existing role owned by this Component, or one the adoption policy allows taking over
+	adopt := false
+	if existingRole != nil {
+		roleArn := aws.ToString(existingRole.Arn)
+		adopt, err = iamOwnership.CheckAdoption(name, fromIAMTags(existingRole.Tags), adoptionPolicy(spec, status, roleArn))
+		if err != nil {
+			iamEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused, "Not managing IAM role %s: %v", roleArn, err)
+			return functional.ActionFailure(status, fmt.Sprintf("cannot manage role %s: %v", roleArn, err))
+		}
+	}
+
+	// In dry-run mode only record what would change
+	if dryRun {
+		plan, err := planRole(ctx, client, &spec, existingRole)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		if adopt {
+			plan.Add("ownership", name.String())
+		}
+		status.Plan = plan
+		log.Info("Dry run, not applying changes", "plan", plan.String())
+		return functional.ActionSuccess(status, "Dry run: "+plan.String())
 	}
+	status.Plan = nil
 
 	if existingRole == nil {
 		// Role doesn't exist - create it
-		role, err := createRole(ctx, spec.RoleName, string(spec.AssumeRolePolicy), spec.Path, spec.Description, spec.MaxSessionDuration, spec.Tags)
+		tags := iamOwnership.WithTags(name, spec.Tags)
+		role, err := createRole(ctx, client, spec.RoleName, string(spec.AssumeRolePolicy), spec.Path, spec.Description, spec.MaxSessionDuration, tags)
 		if err != nil {
-			return functional.ActionResultForError(status, fmt.Errorf("failed to create role: %w", err), iamErrorClassifier)
+			return actionError(ctx, name, status, fmt.Errorf("failed to create role: %w", err))
 		}
 
 		// Update status with created role info
 		status.RoleArn = aws.ToString(role.Arn)
 		status.RoleId = aws.ToString(role.RoleId)
 		status.RoleName = aws.ToString(role.RoleName)
+		status.Access = &access
+
+		iamEvents.Normal(ctx, name, awsclient.ReasonCreated, "Created IAM role %s", status.RoleArn)
 
 		// Attach all managed policies
 		log.Info("Attaching managed policies to new role", "count", len(spec.ManagedPolicyArns))
-		result, err := reconcilePolicyAttachments(ctx, spec.RoleName, spec.ManagedPolicyArns)
+		result, err := reconcilePolicyAttachments(ctx, client, spec.RoleName, spec.ManagedPolicyArns)
 
 		// Always update status with actual attached policies (even on partial failure)
 		if result != nil {
 			status.AttachedPolicies = result.AttachedPolicies
 		}
+		recordPolicyChanges(result)
+		recordPolicyEvent(ctx, name, status.RoleArn, result)
 
 		if err != nil {
-			return functional.ActionResultForError(status, fmt.Errorf("failed to attach policies: %w", err), iamErrorClassifier)
+			return actionError(ctx, name, status, fmt.Errorf("failed to attach policies: %w", err))
 		}
 
 		details := fmt.Sprintf("Created role %s with %d policies", status.RoleName, len(result.AttachedPolicies))
@@ -67,25 +113,39 @@ func applyAction(
 	status.RoleArn = aws.ToString(existingRole.Arn)
 	status.RoleId = aws.ToString(existingRole.RoleId)
 	status.RoleName = aws.ToString(existingRole.RoleName)
+	status.Access = &access
 
 	log.Info("Role already exists, reconciling configuration", "roleArn", status.RoleArn)
 
+	if adopt {
+		if err := tagRole(ctx, client, spec.RoleName, iamOwnership.Tags(name)); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		iamEvents.Normal(ctx, name, awsclient.ReasonAdopted, "Adopted IAM role %s", status.RoleArn)
+	}
+
 	// Update trust policy if changed
 	currentPolicy := aws.ToString(existingRole.AssumeRolePolicyDocument)
-	if err := updateTrustPolicy(ctx, spec.RoleName, currentPolicy, string(spec.AssumeRolePolicy)); err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to update trust policy: %w", err), iamErrorClassifier)
+	updated, err := updateTrustPolicy(ctx, client, spec.RoleName, currentPolicy, string(spec.AssumeRolePolicy))
+	if err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to update trust policy: %w", err))
+	}
+	if updated {
+		iamEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Updated trust policy of IAM role %s", status.RoleArn)
 	}
 
 	// Reconcile policy attachments
-	result, err := reconcilePolicyAttachments(ctx, spec.RoleName, spec.ManagedPolicyArns)
+	result, err := reconcilePolicyAttachments(ctx, client, spec.RoleName, spec.ManagedPolicyArns)
 
 	// Always update status with actual attached policies (even on partial failure)
 	if result != nil {
 		status.AttachedPolicies = result.AttachedPolicies
 	}
+	recordPolicyChanges(result)
+	recordPolicyEvent(ctx, name, status.RoleArn, result)
 
 	if err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to reconcile policy attachments: %w", err), iamErrorClassifier)
+		return actionError(ctx, name, status, fmt.Errorf("failed to reconcile policy attachments: %w", err))
 	}
 
 	// Build detailed message about changes
@@ -108,21 +168,30 @@ func checkApplied(
 
 	log := logf.FromContext(ctx).WithValues("roleName", spec.RoleName)
 
+	// A dry-run apply changed nothing. A resource that was never created is not ready.
+	if status.Plan != nil {
+		if status.Plan.Action == awsclient.PlanCreate {
+			return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+		}
+		return functional.CheckComplete(status, "Dry run: "+status.Plan.String())
+	}
+
 	// If we don't have a role ARN yet, deployment hasn't started
 	if status.RoleArn == "" {
 		log.V(1).Info("No role ARN in status, deployment not started")
 		return functional.CheckInProgress(status, "")
 	}
 
+	client := iamClients.Get(resolveAccess(spec, status))
+
 	// Verify role exists
-	role, err := getRoleByName(ctx, spec.RoleName)
+	role, err := getRoleByName(ctx, client, spec.RoleName)
 	if err != nil {
-		return functional.CheckResultForError(status, fmt.Errorf("failed to check role status: %w", err), iamErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("failed to check role status: %w", err))
 	}
 
 	if role == nil {
-		return functional.CheckResultForError(status,
-			fmt.Errorf("role not found: %s", spec.RoleName), iamErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("role not found: %s", spec.RoleName))
 	}
 
 	// Update status with current role info
@@ -142,12 +211,40 @@ func deleteAction(
 	status IamRoleStatus) (*functional.ActionResult[IamRoleStatus], error) {
 
 	log := logf.FromContext(ctx).WithValues("roleName", spec.RoleName)
+
+	retain := spec.DeletionPolicy == awsclient.DeletionPolicyRetain
+
+	dryRun, err := iamDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+	if dryRun {
+		status.Plan = awsclient.NewDeletePlan(spec.RoleName)
+		if retain {
+			status.Plan = awsclient.NewRetainPlan(spec.RoleName)
+		}
+		log.Info("Dry run, holding back deletion of role", "plan", status.Plan.String())
+		return functional.ActionResultForError(status, awsclient.DeletionHeld(status.Plan), awsclient.RetryDryRun)
+	}
+
+	client := iamClients.Get(resolveAccess(spec, status))
+
+	if retain {
+		return retainRole(ctx, name, client, spec.RoleName, status)
+	}
+
+	// A role this Component never created is not deleted
+	if status.RoleArn == "" {
+		log.Info("No IAM role recorded in status, nothing to delete")
+		return functional.ActionSuccess(status, "No role to delete")
+	}
+
 	log.Info("Starting IAM role deletion")
 
 	// Check if role exists - if not, deletion is already complete
-	role, err := getRoleByName(ctx, spec.RoleName)
+	role, err := getRoleByName(ctx, client, spec.RoleName)
 	if err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to check if role exists: %w", err), iamErrorClassifier)
+		return actionError(ctx, name, status, fmt.Errorf("failed to check if role exists: %w", err))
 	}
 
 	if role == nil {
@@ -155,10 +252,18 @@ func deleteAction(
 		return functional.ActionSuccess(status, "Role already deleted")
 	}
 
+	// A role under the same name that is not the recorded one, or that another Component
+	// has adopted since, is left alone
+	if aws.ToString(role.Arn) != status.RoleArn || !iamOwnership.Owns(name, fromIAMTags(role.Tags)) {
+		log.Info("IAM role is not owned by this Component, leaving it in place", "roleArn", aws.ToString(role.Arn))
+		iamEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused, "Not deleting IAM role %s: it is not owned by this Component", spec.RoleName)
+		return functional.ActionSuccess(status, fmt.Sprintf("Role %s is not owned by this Component, left in place", spec.RoleName))
+	}
+
 	// List all attached managed policies
-	attachedPolicies, err := listAttachedPolicies(ctx, spec.RoleName)
+	attachedPolicies, err := listAttachedPolicies(ctx, client, spec.RoleName)
 	if err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to list attached policies: %w", err), iamErrorClassifier)
+		return actionError(ctx, name, status, fmt.Errorf("failed to list attached policies: %w", err))
 	}
 
 	detachedCount := 0
@@ -167,8 +272,8 @@ func deleteAction(
 		log.Info("Detaching managed policies before deletion", "count", len(attachedPolicies))
 		for _, policyArn := range attachedPolicies {
 			log.V(1).Info("Detaching policy", "policyArn", policyArn)
-			if err := detachPolicy(ctx, spec.RoleName, policyArn); err != nil {
-				return functional.ActionResultForError(status, fmt.Errorf("failed to detach policy %s: %w", policyArn, err), iamErrorClassifier)
+			if err := detachPolicy(ctx, client, spec.RoleName, policyArn); err != nil {
+				return actionError(ctx, name, status, fmt.Errorf("failed to detach policy %s: %w", policyArn, err))
 			}
 			detachedCount++
 		}
@@ -176,14 +281,46 @@ func deleteAction(
 	}
 
 	// Delete the role
-	if err := deleteRole(ctx, spec.RoleName); err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to delete role: %w", err), iamErrorClassifier)
+	if err := deleteRole(ctx, client, spec.RoleName); err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to delete role: %w", err))
 	}
 
+	iamEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleted IAM role %s (detached %d policies)", spec.RoleName, detachedCount)
+
 	details := fmt.Sprintf("Deleting role %s (detached %d policies)", spec.RoleName, detachedCount)
 	return functional.ActionSuccess(status, details)
 }
 
+// retainRole keeps the role and its policy attachments in AWS and removes the ownership tags,
+// so that another Component can adopt it
+func retainRole(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	client API,
+	roleName string,
+	status IamRoleStatus) (*functional.ActionResult[IamRoleStatus], error) {
+
+	log := logf.FromContext(ctx).WithValues("roleName", roleName)
+
+	role, err := getRoleByName(ctx, client, roleName)
+	if err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to check if role exists: %w", err))
+	}
+
+	// Tags of a role adopted by another Component since are left alone
+	if role != nil && iamOwnership.Owns(name, fromIAMTags(role.Tags)) {
+		if err := untagRole(ctx, client, roleName, iamOwnership.TagKeys()); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+	}
+
+	log.Info("DeletionPolicy is Retain, leaving role in place")
+	iamEvents.Normal(ctx, name, awsclient.ReasonRetained, "Retained IAM role %s", roleName)
+
+	details := fmt.Sprintf("Role %s retained (Retain policy)", roleName)
+	return functional.ActionSuccess(status, details)
+}
+
 // checkDeleted verifies deletion is complete
 func checkDeleted(
 	ctx context.Context,
@@ -193,10 +330,22 @@ func checkDeleted(
 
 	log := logf.FromContext(ctx).WithValues("roleName", spec.RoleName)
 
+	// A dry-run delete is held back until dry run is turned off
+	if status.Plan != nil && (status.Plan.Action == awsclient.PlanDelete || status.Plan.Action == awsclient.PlanRetain) {
+		return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+	}
+
+	// A retained role stays in AWS
+	if spec.DeletionPolicy == awsclient.DeletionPolicyRetain {
+		return functional.CheckComplete(status, fmt.Sprintf("Role %s retained", spec.RoleName))
+	}
+
+	client := iamClients.Get(resolveAccess(spec, status))
+
 	// Check if role still exists
-	role, err := getRoleByName(ctx, spec.RoleName)
+	role, err := getRoleByName(ctx, client, spec.RoleName)
 	if err != nil {
-		return functional.CheckResultForError(status, fmt.Errorf("failed to check role deletion status: %w", err), iamErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("failed to check role deletion status: %w", err))
 	}
 
 	if role == nil {
@@ -0,0 +1,312 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iamrole
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator-aws-providers/awsfake"
+	"github.com/rinswind/componator/componentkit/controller"
+)
+
+func TestIamRole(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "IAM Role Suite")
+}
+
+const (
+	ec2TrustPolicy    = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+	lambdaTrustPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":"lambda.amazonaws.com"},"Action":"sts:AssumeRole"}]}`
+
+	readOnlyAccess = "arn:aws:iam::aws:policy/ReadOnlyAccess"
+	s3ReadOnly     = "arn:aws:iam::aws:policy/AmazonS3ReadOnlyAccess"
+	ssmCore        = "arn:aws:iam::aws:policy/AmazonSSMManagedInstanceCore"
+)
+
+var _ = Describe("IAM Role Operations", func() {
+	var (
+		ctx  context.Context
+		fake *awsfake.IAM
+		name k8stypes.NamespacedName
+		spec IamRoleConfig
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = awsfake.NewIAM()
+		iamClients = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceIAM, func(aws.Config) API { return fake })
+
+		name = k8stypes.NamespacedName{Namespace: "default", Name: "app-role"}
+		spec = IamRoleConfig{
+			RoleName:          "app-role",
+			AssumeRolePolicy:  ec2TrustPolicy,
+			ManagedPolicyArns: []string{readOnlyAccess, s3ReadOnly},
+		}
+	})
+
+	It("should create, reconcile and delete a role", func() {
+		By("creating the role with its policies")
+		_, err := applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Role("app-role")).NotTo(BeNil())
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(readOnlyAccess, s3ReadOnly))
+
+		status := IamRoleStatus{RoleArn: aws.ToString(fake.Role("app-role").Arn)}
+		_, err = checkApplied(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+
+		By("updating the trust policy and policy attachments")
+		spec.AssumeRolePolicy = lambdaTrustPolicy
+		spec.ManagedPolicyArns = []string{s3ReadOnly, ssmCore}
+		_, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(aws.ToString(fake.Role("app-role").AssumeRolePolicyDocument)).To(Equal(lambdaTrustPolicy))
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(s3ReadOnly, ssmCore))
+
+		By("deleting the role after detaching policies")
+		_, err = deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Role("app-role")).To(BeNil())
+
+		_, err = checkDeleted(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should record events only for real changes", func() {
+		recorder := record.NewFakeRecorder(10)
+		iamEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { iamEvents = nil })
+
+		_, err := applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		arn := aws.ToString(fake.Role("app-role").Arn)
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Created IAM role " + arn)))
+		Expect(recorder.Events).To(Receive(Equal("Normal Updated Attached 2 and detached 0 managed policies on IAM role " + arn)))
+
+		_, err = applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		spec.AssumeRolePolicy = lambdaTrustPolicy
+		_, err = applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Normal Updated Updated trust policy of IAM role " + arn)))
+		Expect(recorder.Events).NotTo(Receive())
+
+		_, err = deleteAction(ctx, name, spec, IamRoleStatus{RoleArn: arn})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Normal Deleted Deleted IAM role app-role (detached 2 policies)")))
+	})
+
+	It("should plan trust policy and attachment changes in dry-run mode", func() {
+		iamDryRun = awsclient.NewDryRun(awsfake.NewComponents(name), true)
+		DeferCleanup(func() { iamDryRun = nil })
+
+		result, err := applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Role("app-role")).To(BeNil())
+		Expect(result.Status.Plan.Action).To(Equal(awsclient.PlanCreate))
+		Expect(result.Status.Plan.Changes).To(HaveLen(3))
+
+		iamDryRun = nil
+		_, err = applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		iamDryRun = awsclient.NewDryRun(awsfake.NewComponents(name), true)
+
+		spec.AssumeRolePolicy = lambdaTrustPolicy
+		spec.ManagedPolicyArns = []string{s3ReadOnly, ssmCore}
+		result, err = applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan.Changes).To(Equal([]awsclient.Change{
+			{Field: "assumeRolePolicy", Action: awsclient.ChangeModify, Current: ec2TrustPolicy, Desired: lambdaTrustPolicy},
+			{Field: "managedPolicyArns", Action: awsclient.ChangeRemove, Current: readOnlyAccess},
+			{Field: "managedPolicyArns", Action: awsclient.ChangeAdd, Desired: ssmCore},
+		}))
+		Expect(aws.ToString(fake.Role("app-role").AssumeRolePolicyDocument)).To(Equal(ec2TrustPolicy))
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(readOnlyAccess, s3ReadOnly))
+	})
+
+	It("should report and remediate trust policy and attachment drift", func() {
+		recorder := record.NewFakeRecorder(10)
+		iamEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { iamEvents = nil })
+
+		_, err := applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(HaveLen(2))
+		for len(recorder.Events) > 0 {
+			<-recorder.Events
+		}
+
+		result, err := checkHealth(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Role app-role matches config with 2 policies")
+		Expect(result).To(Equal(healthy))
+
+		By("detecting an attachment change made outside the provider")
+		Expect(detachPolicy(ctx, fake, "app-role", s3ReadOnly)).To(Succeed())
+		Expect(attachPolicy(ctx, fake, "app-role", ssmCore)).To(Succeed())
+
+		message := "IAM role app-role: managed policies missing [" + s3ReadOnly + "], unexpected [" + ssmCore + "]"
+		result, err = checkHealth(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("PolicyAttachmentDrift", message)
+		Expect(result).To(Equal(degraded))
+		Expect(recorder.Events).To(Receive(Equal("Warning DriftDetected " + message)))
+
+		By("reporting trust policy drift first")
+		_, err = updateTrustPolicy(ctx, fake, "app-role", ec2TrustPolicy, lambdaTrustPolicy)
+		Expect(err).NotTo(HaveOccurred())
+		result, err = checkHealth(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Healthy).To(BeFalse())
+		Expect(result.Reason).To(Equal("TrustPolicyDrift"))
+
+		By("restoring the configured role when remediation is enabled")
+		spec.RemediateDrift = true
+		result, err = checkHealth(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Healthy).To(BeTrue())
+		Expect(aws.ToString(fake.Role("app-role").AssumeRolePolicyDocument)).To(Equal(ec2TrustPolicy))
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(readOnlyAccess, s3ReadOnly))
+
+		By("recreating a deleted role")
+		for _, arn := range fake.AttachedPolicies("app-role") {
+			Expect(detachPolicy(ctx, fake, "app-role", arn)).To(Succeed())
+		}
+		Expect(deleteRole(ctx, fake, "app-role")).To(Succeed())
+		result, err = checkHealth(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Healthy).To(BeTrue())
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(readOnlyAccess, s3ReadOnly))
+	})
+
+	It("should only take over a role it does not own when adoption allows it", func() {
+		iamOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { iamOwnership = nil })
+
+		_, err := createRole(ctx, fake, "app-role", ec2TrustPolicy, "/", "", 3600, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("resource has no ownership tags"))
+		Expect(fake.AttachedPolicies("app-role")).To(BeEmpty())
+
+		By("tagging a role recorded in status instead of refusing it")
+		status := IamRoleStatus{RoleArn: aws.ToString(fake.Role("app-role").Arn)}
+		_, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fromIAMTags(fake.Role("app-role").Tags)).To(HaveKeyWithValue(awsclient.TagComponent, "default/app-role"))
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(readOnlyAccess, s3ReadOnly))
+
+		By("refusing it for a Component in another cluster")
+		iamOwnership = awsclient.NewOwnership("other", DefaultProviderName)
+		result, err = applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring(`cluster "test"`))
+
+		spec.AdoptionPolicy = awsclient.AdoptionAlways
+		_, err = applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fromIAMTags(fake.Role("app-role").Tags)).To(HaveKeyWithValue(awsclient.TagClusterID, "other"))
+	})
+
+	It("should keep a retained role with its policies and remove its ownership tags", func() {
+		iamOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { iamOwnership = nil })
+
+		_, err := applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		status := IamRoleStatus{RoleArn: aws.ToString(fake.Role("app-role").Arn)}
+
+		spec.DeletionPolicy = awsclient.DeletionPolicyRetain
+		_, err = deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Role("app-role")).NotTo(BeNil())
+		Expect(fromIAMTags(fake.Role("app-role").Tags)).To(BeEmpty())
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(readOnlyAccess, s3ReadOnly))
+		Expect(fake.CallCount("DetachRolePolicy")).To(BeZero())
+		Expect(fake.CallCount("DeleteRole")).To(BeZero())
+
+		check, err := checkDeleted(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Role app-role retained"))
+
+		By("letting another Component adopt the untagged role")
+		other := k8stypes.NamespacedName{Namespace: "other", Name: "app-role"}
+		spec.AdoptionPolicy = awsclient.AdoptionIfUntagged
+		_, err = applyAction(ctx, other, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fromIAMTags(fake.Role("app-role").Tags)).To(HaveKeyWithValue(awsclient.TagComponent, "other/app-role"))
+	})
+
+	It("should report attach and detach counts", func() {
+		_, err := applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := reconcilePolicyAttachments(ctx, fake, "app-role", []string{readOnlyAccess, ssmCore})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.AttachedCount).To(Equal(1))
+		Expect(result.DetachedCount).To(Equal(1))
+		Expect(result.AttachedPolicies).To(ConsistOf(readOnlyAccess, ssmCore))
+
+		result, err = reconcilePolicyAttachments(ctx, fake, "app-role", []string{readOnlyAccess, ssmCore})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.AttachedCount).To(BeZero())
+		Expect(result.DetachedCount).To(BeZero())
+	})
+
+	It("should keep partial progress when an attachment fails", func() {
+		_, err := applyAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		fake.FailNext("AttachRolePolicy", errors.New("boom"))
+		result, err := reconcilePolicyAttachments(ctx, fake, "app-role", []string{readOnlyAccess, ssmCore})
+		Expect(err).To(MatchError(ContainSubstring("boom")))
+		Expect(result).NotTo(BeNil())
+		Expect(result.AttachedPolicies).To(Equal([]string{readOnlyAccess}))
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(readOnlyAccess))
+	})
+
+	It("should leave a role it does not own in place", func() {
+		iamOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { iamOwnership = nil })
+
+		other := k8stypes.NamespacedName{Namespace: "other", Name: "app-role"}
+		_, err := applyAction(ctx, other, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		arn := aws.ToString(fake.Role("app-role").Arn)
+
+		By("skipping a role it never created")
+		_, err = deleteAction(ctx, name, spec, IamRoleStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Role("app-role")).NotTo(BeNil())
+
+		By("skipping a role another Component owns")
+		result, err := deleteAction(ctx, name, spec, IamRoleStatus{RoleArn: arn})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Role app-role is not owned by this Component, left in place"))
+		Expect(fake.AttachedPolicies("app-role")).To(ConsistOf(readOnlyAccess, s3ReadOnly))
+		Expect(fake.CallCount("DetachRolePolicy")).To(BeZero())
+		Expect(fake.CallCount("DeleteRole")).To(BeZero())
+	})
+
+	It("should treat a missing role as deleted", func() {
+		_, err := deleteAction(ctx, name, spec, IamRoleStatus{RoleArn: "arn:aws:iam::123456789012:role/app-role"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("DeleteRole")).To(BeZero())
+
+		Expect(deleteRole(ctx, fake, "app-role")).To(Succeed())
+	})
+})
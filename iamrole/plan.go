@@ -0,0 +1,51 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package iamrole
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
+
+// Package-level dry-run switch initialized during registration; nil never plans
+var iamDryRun *awsclient.DryRun
+
+// planRole computes what applyAction would change on the role: the trust policy
+// and the managed policies to attach and detach. A nil role plans its creation.
+func planRole(ctx context.Context, client API, spec *IamRoleConfig, role *types.Role) (*awsclient.Plan, error) {
+	desired := string(spec.AssumeRolePolicy)
+
+	if role == nil {
+		plan := awsclient.NewPlan(spec.RoleName, false)
+		plan.Add("assumeRolePolicy", desired)
+		for _, arn := range slices.Sorted(slices.Values(spec.ManagedPolicyArns)) {
+			plan.Add("managedPolicyArns", arn)
+		}
+		return plan, nil
+	}
+
+	plan := awsclient.NewPlan(aws.ToString(role.Arn), true)
+
+	current := aws.ToString(role.AssumeRolePolicyDocument)
+	if !jsonEquals(current, desired) {
+		plan.Modify("assumeRolePolicy", current, desired)
+	}
+
+	attached, err := listAttachedPolicies(ctx, client, spec.RoleName)
+	if err != nil {
+		return nil, err
+	}
+	toAttach, toDetach := diffPolicyAttachments(attached, spec.ManagedPolicyArns)
+	for _, arn := range toDetach {
+		plan.Remove("managedPolicyArns", arn)
+	}
+	for _, arn := range toAttach {
+		plan.Add("managedPolicyArns", arn)
+	}
+	return plan, nil
+}
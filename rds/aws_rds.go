@@ -8,20 +8,66 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/controller"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// API is the subset of the AWS RDS API used by this provider.
+// It is satisfied by *rds.Client and by awsfake.RDS in unit tests.
+// Embedding programs may supply their own implementation with WithClient.
+type API interface {
+	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	CreateDBInstance(ctx context.Context, params *rds.CreateDBInstanceInput, optFns ...func(*rds.Options)) (*rds.CreateDBInstanceOutput, error)
+	ModifyDBInstance(ctx context.Context, params *rds.ModifyDBInstanceInput, optFns ...func(*rds.Options)) (*rds.ModifyDBInstanceOutput, error)
+	DeleteDBInstance(ctx context.Context, params *rds.DeleteDBInstanceInput, optFns ...func(*rds.Options)) (*rds.DeleteDBInstanceOutput, error)
+	AddTagsToResource(ctx context.Context, params *rds.AddTagsToResourceInput, optFns ...func(*rds.Options)) (*rds.AddTagsToResourceOutput, error)
+	RemoveTagsFromResource(ctx context.Context, params *rds.RemoveTagsFromResourceInput, optFns ...func(*rds.Options)) (*rds.RemoveTagsFromResourceOutput, error)
+	CreateDBInstanceReadReplica(ctx context.Context, params *rds.CreateDBInstanceReadReplicaInput, optFns ...func(*rds.Options)) (*rds.CreateDBInstanceReadReplicaOutput, error)
+	PromoteReadReplica(ctx context.Context, params *rds.PromoteReadReplicaInput, optFns ...func(*rds.Options)) (*rds.PromoteReadReplicaOutput, error)
+	RestoreDBInstanceFromDBSnapshot(ctx context.Context, params *rds.RestoreDBInstanceFromDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.RestoreDBInstanceFromDBSnapshotOutput, error)
+	RestoreDBInstanceToPointInTime(ctx context.Context, params *rds.RestoreDBInstanceToPointInTimeInput, optFns ...func(*rds.Options)) (*rds.RestoreDBInstanceToPointInTimeOutput, error)
+	CreateDBSnapshot(ctx context.Context, params *rds.CreateDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.CreateDBSnapshotOutput, error)
+	DescribeDBSnapshots(ctx context.Context, params *rds.DescribeDBSnapshotsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBSnapshotsOutput, error)
+	CopyDBSnapshot(ctx context.Context, params *rds.CopyDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.CopyDBSnapshotOutput, error)
+	DeleteDBSnapshot(ctx context.Context, params *rds.DeleteDBSnapshotInput, optFns ...func(*rds.Options)) (*rds.DeleteDBSnapshotOutput, error)
+	CreateBlueGreenDeployment(ctx context.Context, params *rds.CreateBlueGreenDeploymentInput, optFns ...func(*rds.Options)) (*rds.CreateBlueGreenDeploymentOutput, error)
+	DescribeBlueGreenDeployments(ctx context.Context, params *rds.DescribeBlueGreenDeploymentsInput, optFns ...func(*rds.Options)) (*rds.DescribeBlueGreenDeploymentsOutput, error)
+	SwitchoverBlueGreenDeployment(ctx context.Context, params *rds.SwitchoverBlueGreenDeploymentInput, optFns ...func(*rds.Options)) (*rds.SwitchoverBlueGreenDeploymentOutput, error)
+	DeleteBlueGreenDeployment(ctx context.Context, params *rds.DeleteBlueGreenDeploymentInput, optFns ...func(*rds.Options)) (*rds.DeleteBlueGreenDeploymentOutput, error)
+	DescribeDBEngineVersions(ctx context.Context, params *rds.DescribeDBEngineVersionsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBEngineVersionsOutput, error)
+	DescribeOrderableDBInstanceOptions(ctx context.Context, params *rds.DescribeOrderableDBInstanceOptionsInput, optFns ...func(*rds.Options)) (*rds.DescribeOrderableDBInstanceOptionsOutput, error)
+}
+
+// MetricsAPI reads the CloudWatch metrics of instances, i.e. the replication lag of replicas.
+// It is satisfied by *awsclient.CloudWatch and by awsfake.CloudWatch in unit tests.
+// Embedding programs may supply their own implementation with WithMetricsClient.
+type MetricsAPI interface {
+	LatestMaximum(ctx context.Context, namespace, metricName string, dimensions map[string]string, period time.Duration) (float64, bool, error)
+}
+
 // Package-level singletons initialized during registration
 var (
-	rdsClient *rds.Client
+	rdsClients   *awsclient.Cache[API]
+	rdsMetrics   *awsclient.Cache[MetricsAPI]
+	rdsOwnership *awsclient.Ownership
 )
 
+// ReadinessProbe checks that the RDS API is reachable with the controller's own credentials
+func ReadinessProbe(ctx context.Context) error {
+	_, err := rdsClients.Get(awsclient.AccessConfig{}).DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		MaxRecords: aws.Int32(20), // smallest page RDS accepts
+	})
+	return err
+}
+
 // RDSInstanceStatus represents AWS RDS instance status values.
 // These constants provide a canonical list of all known RDS instance states.
 // Each lifecycle function (checkApplied, checkHealth, checkDeleted) interprets
@@ -74,12 +120,12 @@ const (
 )
 
 // getInstanceData retrieves RDS instance data, handling not-found cases consistently
-func getInstanceData(ctx context.Context, instanceID string) (*types.DBInstance, error) {
+func getInstanceData(ctx context.Context, client API, instanceID string) (*types.DBInstance, error) {
 	input := &rds.DescribeDBInstancesInput{
 		DBInstanceIdentifier: stringPtr(instanceID),
 	}
 
-	result, err := rdsClient.DescribeDBInstances(ctx, input)
+	result, err := client.DescribeDBInstances(ctx, input)
 	if err != nil {
 		if isInstanceNotFoundError(err) {
 			return nil, nil // Instance not found - return nil without error
@@ -94,8 +140,8 @@ func getInstanceData(ctx context.Context, instanceID string) (*types.DBInstance,
 	return &result.DBInstances[0], nil
 }
 
-// createInstance creates an RDS instance
-func createInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance, error) {
+// createInstance creates an RDS instance with the given tags
+func createInstance(ctx context.Context, client API, config *RdsConfig, tags map[string]string) (*types.DBInstance, error) {
 	instanceID := config.InstanceID
 
 	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
@@ -121,9 +167,12 @@ func createInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 		ManageMasterUserPassword: passthroughBoolPtr(config.ManageMasterUserPassword),
 
 		// Optional storage configuration
-		StorageType:      optionalStringPtr(config.StorageType),
-		StorageEncrypted: passthroughBoolPtr(config.StorageEncrypted),
-		KmsKeyId:         optionalStringPtr(config.KmsKeyId),
+		StorageType:         stringPtr(createStorageType(config)),
+		MaxAllocatedStorage: createMaxAllocatedStorage(config),
+		Iops:                passthroughPositiveInt32Ptr(config.Iops),
+		StorageThroughput:   passthroughPositiveInt32Ptr(config.StorageThroughput),
+		StorageEncrypted:    createStorageEncrypted(config),
+		KmsKeyId:            optionalStringPtr(config.KmsKeyId),
 
 		// Optional networking configuration
 		VpcSecurityGroupIds: config.VpcSecurityGroupIds, // Already []string type
@@ -131,6 +180,13 @@ func createInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 		PubliclyAccessible:  passthroughBoolPtr(config.PubliclyAccessible),
 		Port:                passthroughInt32Ptr(config.Port),
 
+		// Optional security and logging configuration
+		EnableIAMDatabaseAuthentication: passthroughBoolPtr(config.IAMDatabaseAuthenticationEnabled),
+		EnableCloudwatchLogsExports:     config.EnableCloudwatchLogsExports,
+
+		// Optional parameter group
+		DBParameterGroupName: optionalStringPtr(config.ParameterGroupName),
+
 		// Optional backup configuration
 		BackupRetentionPeriod: passthroughInt32Ptr(config.BackupRetentionPeriod),
 		PreferredBackupWindow: optionalStringPtr(config.PreferredBackupWindow),
@@ -146,6 +202,9 @@ func createInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 
 		// Deletion protection
 		DeletionProtection: passthroughBoolPtr(config.DeletionProtection),
+
+		Tags:               toRDSTags(tags),
+		CopyTagsToSnapshot: boolPtr(true),
 	}
 
 	// AWS doesn't ignore a nil KMS ID for this arg, so we must set it only if provided
@@ -153,7 +212,7 @@ func createInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 		createInput.MasterUserSecretKmsKeyId = stringPtr(config.MasterUserSecretKmsKeyId)
 	}
 
-	result, err := rdsClient.CreateDBInstance(ctx, createInput)
+	result, err := client.CreateDBInstance(ctx, createInput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create RDS instance: %w", err)
 	}
@@ -163,19 +222,92 @@ func createInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 	return result.DBInstance, nil
 }
 
-// modifyInstance modifies an existing RDS instance
-func modifyInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance, error) {
-	instanceID := config.InstanceID
+// modifyInstance sends the modification requests built by buildModifyInputs in order
+// and returns the instance as reported by the last one
+func modifyInstance(ctx context.Context, client API, inputs []*rds.ModifyDBInstanceInput) (*types.DBInstance, error) {
+	var instance *types.DBInstance
+	for _, input := range inputs {
+		log := logf.FromContext(ctx).WithValues("instanceId", stringValue(input.DBInstanceIdentifier))
+		log.Info("Modifying RDS instance", "applyImmediately", boolValue(input.ApplyImmediately))
+
+		result, err := client.ModifyDBInstance(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to modify RDS instance: %w", err)
+		}
+		instance = result.DBInstance
 
-	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+		log.Info("RDS instance modification initiated successfully")
+	}
+
+	return instance, nil
+}
+
+// buildModifyInputs builds the ModifyDBInstance requests for the settings that differ between the
+// config and the instance. Changes applied immediately and changes left for the maintenance window
+// need separate requests. The deferred request goes last, since a request with ApplyImmediately
+// also applies every pending modification.
+func buildModifyInputs(config *RdsConfig, instance *types.DBInstance) []*rds.ModifyDBInstanceInput {
+	desired := desiredSettings(config)
+	current := currentSettings(instance)
+
+	immediate := &rds.ModifyDBInstanceInput{DBInstanceIdentifier: stringPtr(config.InstanceID), ApplyImmediately: boolPtr(true)}
+	deferred := &rds.ModifyDBInstanceInput{DBInstanceIdentifier: stringPtr(config.InstanceID), ApplyImmediately: boolPtr(false)}
+	timed := func(timing string) *rds.ModifyDBInstanceInput {
+		if timing == ApplyDuringMaintenanceWindow {
+			return deferred
+		}
+		return immediate
+	}
 
-	log.Info("Modifying RDS instance")
+	timed(config.ApplyChanges.InstanceClass).DBInstanceClass = changed(current.DBInstanceClass, desired.DBInstanceClass)
+	storage := timed(config.ApplyChanges.Storage)
+	storage.AllocatedStorage = storageGrowth(current.AllocatedStorage, desired.AllocatedStorage)
+	storage.StorageType = changed(current.StorageType, desired.StorageType)
+	storage.Iops = changed(current.Iops, desired.Iops)
+	storage.StorageThroughput = changed(current.StorageThroughput, desired.StorageThroughput)
+	if storage.StorageType != nil {
+		// A new storage type needs its provisioned performance in the same request
+		storage.Iops = desired.Iops
+		storage.StorageThroughput = desired.StorageThroughput
+	}
+	engine := timed(config.ApplyChanges.EngineVersion)
+	engine.EngineVersion = changed(current.EngineVersion, desired.EngineVersion)
+	if isMajorUpgrade(stringValue(instance.Engine), current.EngineVersion, engine.EngineVersion) {
+		engine.AllowMajorVersionUpgrade = boolPtr(true)
+	}
+	timed(config.ApplyChanges.MultiAZ).MultiAZ = changed(current.MultiAZ, desired.MultiAZ)
+
+	// These take effect right away, or at the next reboot, whatever ApplyImmediately says.
+	// Deletion protection in particular must drop fast ahead of a cleanup.
+	immediate.BackupRetentionPeriod = changed(current.BackupRetentionPeriod, desired.BackupRetentionPeriod)
+	immediate.PreferredBackupWindow = changed(current.PreferredBackupWindow, desired.PreferredBackupWindow)
+	immediate.PreferredMaintenanceWindow = changed(current.PreferredMaintenanceWindow, desired.PreferredMaintenanceWindow)
+	immediate.AutoMinorVersionUpgrade = changed(current.AutoMinorVersionUpgrade, desired.AutoMinorVersionUpgrade)
+	immediate.DeletionProtection = changed(current.DeletionProtection, desired.DeletionProtection)
+	immediate.MaxAllocatedStorage = maxStorageChange(current, desired)
+	immediate.EnableIAMDatabaseAuthentication = changed(current.EnableIAMDatabaseAuthentication, desired.EnableIAMDatabaseAuthentication)
+	immediate.CloudwatchLogsExportConfiguration = logExportChanges(config, instance)
+	immediate.DBParameterGroupName = changed(current.DBParameterGroupName, desired.DBParameterGroupName)
+
+	var inputs []*rds.ModifyDBInstanceInput
+	for _, input := range []*rds.ModifyDBInstanceInput{immediate, deferred} {
+		if hasModifications(input) {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
 
-	// Build modify input with all config values - AWS RDS handles idempotency
-	input := &rds.ModifyDBInstanceInput{
-		DBInstanceIdentifier:       stringPtr(instanceID),
+// desiredSettings returns the modifiable settings the config asks for.
+// Unset settings are left to AWS. Plans compare the same settings against the instance.
+func desiredSettings(config *RdsConfig) *rds.ModifyDBInstanceInput {
+	desired := &rds.ModifyDBInstanceInput{
 		DBInstanceClass:            stringPtr(config.InstanceClass),
 		AllocatedStorage:           int32Ptr(config.AllocatedStorage),
+		MaxAllocatedStorage:        passthroughInt32Ptr(config.MaxAllocatedStorage),
+		StorageType:                optionalStringPtr(config.StorageType),
+		Iops:                       passthroughPositiveInt32Ptr(config.Iops),
+		StorageThroughput:          passthroughPositiveInt32Ptr(config.StorageThroughput),
 		EngineVersion:              stringPtr(config.EngineVersion),
 		BackupRetentionPeriod:      passthroughInt32Ptr(config.BackupRetentionPeriod),
 		MultiAZ:                    passthroughBoolPtr(config.MultiAZ),
@@ -183,23 +315,102 @@ func modifyInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 		PreferredMaintenanceWindow: optionalStringPtr(config.PreferredMaintenanceWindow),
 		AutoMinorVersionUpgrade:    passthroughBoolPtr(config.AutoMinorVersionUpgrade),
 		DeletionProtection:         passthroughBoolPtr(config.DeletionProtection),
-		// TODO: Figure out how to make this configurable.
-		// Right now we need this to be immediate because users need to take down deletion protection fast prior to cleanup
-		ApplyImmediately: boolPtr(true),
+		DBParameterGroupName:       optionalStringPtr(config.ParameterGroupName),
+
+		EnableIAMDatabaseAuthentication: passthroughBoolPtr(config.IAMDatabaseAuthenticationEnabled),
 	}
 
-	result, err := rdsClient.ModifyDBInstance(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("failed to modify RDS instance: %w", err)
+	// Replicas and restored instances take storage and engine version from their source unless set explicitly
+	if config.isReplica() || config.isRestore() {
+		desired.AllocatedStorage = passthroughPositiveInt32Ptr(&config.AllocatedStorage)
+		desired.EngineVersion = optionalStringPtr(config.EngineVersion)
 	}
 
-	log.Info("RDS instance modification initiated successfully")
+	return desired
+}
 
-	return result.DBInstance, nil
+// currentSettings returns the modifiable settings of the instance. A modification pending for the
+// maintenance window counts as made, so it is neither sent nor planned again.
+func currentSettings(instance *types.DBInstance) *rds.ModifyDBInstanceInput {
+	current := &rds.ModifyDBInstanceInput{
+		DBInstanceClass:            instance.DBInstanceClass,
+		AllocatedStorage:           instance.AllocatedStorage,
+		MaxAllocatedStorage:        maxAllocatedStorage(instance),
+		StorageType:                instance.StorageType,
+		Iops:                       instance.Iops,
+		StorageThroughput:          instance.StorageThroughput,
+		EngineVersion:              instance.EngineVersion,
+		BackupRetentionPeriod:      instance.BackupRetentionPeriod,
+		MultiAZ:                    instance.MultiAZ,
+		PreferredBackupWindow:      instance.PreferredBackupWindow,
+		PreferredMaintenanceWindow: instance.PreferredMaintenanceWindow,
+		AutoMinorVersionUpgrade:    instance.AutoMinorVersionUpgrade,
+		DeletionProtection:         instance.DeletionProtection,
+
+		EnableIAMDatabaseAuthentication: instance.IAMDatabaseAuthenticationEnabled,
+	}
+	if group := parameterGroup(instance); group != nil {
+		current.DBParameterGroupName = group.DBParameterGroupName
+	}
+
+	if pending := instance.PendingModifiedValues; pending != nil {
+		current.DBInstanceClass = firstSet(pending.DBInstanceClass, current.DBInstanceClass)
+		current.AllocatedStorage = firstSet(pending.AllocatedStorage, current.AllocatedStorage)
+		current.StorageType = firstSet(pending.StorageType, current.StorageType)
+		current.Iops = firstSet(pending.Iops, current.Iops)
+		current.StorageThroughput = firstSet(pending.StorageThroughput, current.StorageThroughput)
+		current.EngineVersion = firstSet(pending.EngineVersion, current.EngineVersion)
+		current.BackupRetentionPeriod = firstSet(pending.BackupRetentionPeriod, current.BackupRetentionPeriod)
+		current.MultiAZ = firstSet(pending.MultiAZ, current.MultiAZ)
+		current.EnableIAMDatabaseAuthentication = firstSet(pending.IAMDatabaseAuthenticationEnabled, current.EnableIAMDatabaseAuthentication)
+	}
+	return current
+}
+
+// hasModifications reports whether a request changes any setting
+func hasModifications(input *rds.ModifyDBInstanceInput) bool {
+	return input.DBInstanceClass != nil || input.AllocatedStorage != nil || input.EngineVersion != nil ||
+		input.StorageType != nil || input.Iops != nil || input.StorageThroughput != nil ||
+		input.MaxAllocatedStorage != nil || input.MultiAZ != nil || input.BackupRetentionPeriod != nil || input.PreferredBackupWindow != nil ||
+		input.PreferredMaintenanceWindow != nil || input.AutoMinorVersionUpgrade != nil ||
+		input.DeletionProtection != nil || input.DBParameterGroupName != nil ||
+		input.EnableIAMDatabaseAuthentication != nil || input.CloudwatchLogsExportConfiguration != nil
+}
+
+// pendingModifications lists the modifications waiting for the maintenance window by setting name
+func pendingModifications(instance *types.DBInstance) map[string]string {
+	pending := instance.PendingModifiedValues
+	if pending == nil {
+		return nil
+	}
+
+	values := map[string]*string{
+		"instanceClass":                    pending.DBInstanceClass,
+		"engineVersion":                    pending.EngineVersion,
+		"storageType":                      pending.StorageType,
+		"allocatedStorage":                 formatSet(pending.AllocatedStorage),
+		"iops":                             formatSet(pending.Iops),
+		"storageThroughput":                formatSet(pending.StorageThroughput),
+		"backupRetentionPeriod":            formatSet(pending.BackupRetentionPeriod),
+		"port":                             formatSet(pending.Port),
+		"multiAZ":                          formatSet(pending.MultiAZ),
+		"iamDatabaseAuthenticationEnabled": formatSet(pending.IAMDatabaseAuthenticationEnabled),
+	}
+
+	result := make(map[string]string)
+	for setting, value := range values {
+		if value != nil {
+			result[setting] = *value
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
 }
 
 // deleteInstance deletes an RDS instance
-func deleteInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance, error) {
+func deleteInstance(ctx context.Context, client API, config *RdsConfig) (*types.DBInstance, error) {
 	instanceID := config.InstanceID
 
 	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
@@ -209,7 +420,7 @@ func deleteInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 		DBInstanceIdentifier: stringPtr(instanceID),
 	}
 
-	if boolValue(config.SkipFinalSnapshot) {
+	if skipFinalSnapshot(config) {
 		deleteInput.SkipFinalSnapshot = boolPtr(true)
 	} else {
 		deleteInput.SkipFinalSnapshot = boolPtr(false)
@@ -218,7 +429,7 @@ func deleteInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 
 	log.Info("Deleting RDS instance", "skipFinalSnapshot", boolValue(deleteInput.SkipFinalSnapshot))
 
-	result, err := rdsClient.DeleteDBInstance(ctx, deleteInput)
+	result, err := client.DeleteDBInstance(ctx, deleteInput)
 	if err != nil {
 		if isInstanceNotFoundError(err) {
 			log.Info("RDS instance already deleted")
@@ -239,6 +450,12 @@ func deleteInstance(ctx context.Context, config *RdsConfig) (*types.DBInstance,
 	return result.DBInstance, nil
 }
 
+// skipFinalSnapshot reports whether instances are deleted without a final snapshot.
+// Read replicas have no snapshots of their own.
+func skipFinalSnapshot(config *RdsConfig) bool {
+	return boolValue(config.SkipFinalSnapshot) || (config.isReplica() && !config.PromoteReplica)
+}
+
 // updateStatusFromInstance updates RdsStatus fields from AWS DBInstance data
 func updateStatusFromInstance(status *RdsStatus, instance *types.DBInstance) {
 	if instance == nil {
@@ -257,6 +474,31 @@ func updateStatusFromInstance(status *RdsStatus, instance *types.DBInstance) {
 		status.MasterUserSecretArn = *instance.MasterUserSecret.SecretArn
 	}
 	// If not present in response but already in status, keep existing value (ARN doesn't change)
+
+	status.PendingModifications = pendingModifications(instance)
+
+	status.ParameterGroupName, status.ParameterApplyStatus = "", ""
+	if group := parameterGroup(instance); group != nil {
+		status.ParameterGroupName = stringValue(group.DBParameterGroupName)
+		status.ParameterApplyStatus = stringValue(group.ParameterApplyStatus)
+	}
+
+	status.ReplicaSource = replicaSource(instance)
+	status.ReplicationState = ""
+	if info := replicationStatus(instance); info != nil && status.ReplicaSource != "" {
+		status.ReplicationState = stringValue(info.Status)
+	}
+	if status.ReplicaSource == "" {
+		status.ReplicaLagSeconds = nil
+	}
+}
+
+// parameterGroup returns the DB parameter group of the instance, or nil if RDS reports none
+func parameterGroup(instance *types.DBInstance) *types.DBParameterGroupStatus {
+	if len(instance.DBParameterGroups) == 0 {
+		return nil
+	}
+	return &instance.DBParameterGroups[0]
 }
 
 // isInstanceNotFoundError checks if the error indicates the RDS instance was not found
@@ -287,6 +529,7 @@ func isInstanceAlreadyBeingDeletedError(err error) bool {
 }
 
 // rdsErrorClassifier wraps the AWS SDK retry logic for use with result builder utilities.
+// Register replaces it when an embedding program supplies WithErrorClassifier.
 var rdsErrorClassifier = controller.ErrorClassifier(isRetryable)
 
 // isRetryable determines if an error is retryable using AWS SDK's built-in error classification.
@@ -303,5 +546,59 @@ func isRetryable(err error) bool {
 		}
 	}
 
+	// Connection Secret writes race with other writers and the API server's load
+	if apierrors.IsConflict(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) {
+		return true
+	}
+
 	return false
 }
+
+// tagInstance adds tags to an existing instance
+func tagInstance(ctx context.Context, client API, instanceArn string, tags map[string]string) error {
+	_, err := client.AddTagsToResource(ctx, &rds.AddTagsToResourceInput{
+		ResourceName: stringPtr(instanceArn),
+		Tags:         toRDSTags(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag RDS instance: %w", err)
+	}
+	return nil
+}
+
+// untagInstance removes tags from an instance
+func untagInstance(ctx context.Context, client API, instanceArn string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := client.RemoveTagsFromResource(ctx, &rds.RemoveTagsFromResourceInput{
+		ResourceName: stringPtr(instanceArn),
+		TagKeys:      keys,
+	})
+	if err != nil && !isInstanceNotFoundError(err) {
+		return fmt.Errorf("failed to untag RDS instance: %w", err)
+	}
+	return nil
+}
+
+// toRDSTags converts a map to an RDS tag slice
+func toRDSTags(tags map[string]string) []types.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	result := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		result = append(result, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return result
+}
+
+// fromRDSTags converts an RDS tag slice to a map
+func fromRDSTags(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
@@ -0,0 +1,411 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/functional"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Upgrade strategies for major engine version upgrades
+const (
+	UpgradeInPlace   = "InPlace"
+	UpgradeBlueGreen = "BlueGreen"
+)
+
+// Stages of a blue/green upgrade
+const (
+	BlueGreenProvisioning  = "Provisioning"  // creating the green environment and waiting for it to sync
+	BlueGreenSwitchingOver = "SwitchingOver" // moving the instance identifier and endpoint to green
+	BlueGreenRetiring      = "Retiring"      // deleting the old blue instance
+)
+
+// Statuses of a blue/green deployment
+const (
+	blueGreenProvisioning         = "PROVISIONING"
+	blueGreenAvailable            = "AVAILABLE"
+	blueGreenSwitchoverInProgress = "SWITCHOVER_IN_PROGRESS"
+	blueGreenSwitchoverCompleted  = "SWITCHOVER_COMPLETED"
+	blueGreenDeleting             = "DELETING"
+)
+
+// blueGreenSwitchoverTimeout is the time in seconds RDS may take to switch over before it rolls back
+const blueGreenSwitchoverTimeout = 300
+
+// maxBlueGreenNameLength is the longest blue/green deployment name RDS accepts
+const maxBlueGreenNameLength = 60
+
+// resolveUpgradeStrategy validates an upgradeStrategy setting, defaulting to UpgradeInPlace
+func resolveUpgradeStrategy(config *RdsConfig) (string, error) {
+	switch config.UpgradeStrategy {
+	case "":
+		return UpgradeInPlace, nil
+	case UpgradeInPlace:
+		return config.UpgradeStrategy, nil
+	case UpgradeBlueGreen:
+		if config.isReplica() && !config.PromoteReplica {
+			return "", fmt.Errorf("upgradeStrategy %s does not apply to read replicas, which upgrade with their source", UpgradeBlueGreen)
+		}
+		return config.UpgradeStrategy, nil
+	default:
+		return "", fmt.Errorf("invalid upgradeStrategy %q: must be one of %s, %s",
+			config.UpgradeStrategy, UpgradeInPlace, UpgradeBlueGreen)
+	}
+}
+
+// majorVersion returns the major version of an engine version. Postgres counts majors in the
+// first number since version 10; MySQL, MariaDB and older Postgres use the first two.
+func majorVersion(engine, version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if first, err := strconv.Atoi(parts[0]); err == nil && engine == "postgres" && first >= 10 {
+		return parts[0]
+	}
+	if len(parts) >= 2 {
+		return parts[0] + "." + parts[1]
+	}
+	return parts[0]
+}
+
+// isMajorUpgrade reports whether moving the instance from one engine version to another changes
+// the major version, which RDS only does with AllowMajorVersionUpgrade
+func isMajorUpgrade(engine string, current, desired *string) bool {
+	if current == nil || desired == nil {
+		return false
+	}
+	return majorVersion(engine, *current) != majorVersion(engine, *desired)
+}
+
+// blueGreenTarget returns the engine version a blue/green deployment upgrades the instance to,
+// or "" if the config does not ask for a major upgrade with the BlueGreen strategy
+func blueGreenTarget(config *RdsConfig, instance *rdstypes.DBInstance) string {
+	if config.UpgradeStrategy != UpgradeBlueGreen || replicaSource(instance) != "" {
+		return ""
+	}
+	target := changed(currentSettings(instance).EngineVersion, desiredSettings(config).EngineVersion)
+	if !isMajorUpgrade(stringValue(instance.Engine), instance.EngineVersion, target) {
+		return ""
+	}
+	return *target
+}
+
+// blueGreenDeploymentName names the deployment that upgrades the instance to the target version
+func blueGreenDeploymentName(instanceID, target string) string {
+	suffix := "-to-" + strings.ReplaceAll(target, ".", "-")
+	if len(instanceID)+len(suffix) > maxBlueGreenNameLength {
+		instanceID = strings.TrimRight(instanceID[:maxBlueGreenNameLength-len(suffix)], "-")
+	}
+	return instanceID + suffix
+}
+
+// upgradeBlueGreen handles an apply that upgrades the major engine version with the BlueGreen
+// strategy. It creates a blue/green deployment with the new version, and checkApplied drives the
+// switchover and the cleanup of the old instance.
+func upgradeBlueGreen(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec RdsConfig,
+	status RdsStatus,
+	instance *rdstypes.DBInstance,
+	target string) (*functional.ActionResult[RdsStatus], error) {
+
+	instanceID := spec.InstanceID
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+
+	deploymentName := blueGreenDeploymentName(instanceID, target)
+	log.Info("Creating RDS blue/green deployment", "deployment", deploymentName, "engineVersion", target)
+
+	result, err := client.CreateBlueGreenDeployment(ctx, &rds.CreateBlueGreenDeploymentInput{
+		BlueGreenDeploymentName: stringPtr(deploymentName),
+		Source:                  instance.DBInstanceArn,
+		TargetEngineVersion:     stringPtr(target),
+		// A new major version needs a parameter group of its family; without one RDS takes the default
+		TargetDBParameterGroupName: optionalStringPtr(spec.ParameterGroupName),
+		Tags:                       toRDSTags(rdsOwnership.Tags(name)),
+	})
+	if err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to create blue/green deployment %s: %w", deploymentName, err))
+	}
+
+	status.BlueGreen = &BlueGreenUpgrade{
+		Stage:                BlueGreenProvisioning,
+		DeploymentIdentifier: stringValue(result.BlueGreenDeployment.BlueGreenDeploymentIdentifier),
+		EngineVersion:        target,
+	}
+
+	rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Upgrading RDS instance %s to engine version %s with blue/green deployment %s",
+		instanceID, target, deploymentName)
+
+	details := fmt.Sprintf("Upgrading RDS instance %s to engine version %s with blue/green deployment %s", instanceID, target, deploymentName)
+	return functional.ActionSuccess(status, details)
+}
+
+// checkBlueGreen drives a blue/green upgrade through its stages. Once the green environment is
+// provisioned and in sync, it switches over, which moves the instance identifier and endpoint to the
+// upgraded instance. The old blue instance is recorded in status before the deployment, which names
+// it, is deleted; the old instance then follows, with a final snapshot unless the config skips it.
+// Settings the deployment did not take are applied like after a restore.
+func checkBlueGreen(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec RdsConfig,
+	status RdsStatus) (*functional.CheckResult[RdsStatus], error) {
+
+	instanceID := spec.InstanceID
+	upgrade := status.BlueGreen
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID, "stage", upgrade.Stage)
+
+	switch upgrade.Stage {
+	case BlueGreenProvisioning:
+		deployment, err := getBlueGreenDeployment(ctx, client, upgrade.DeploymentIdentifier)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+
+		switch stringValue(deployment.Status) {
+		case blueGreenAvailable:
+		case blueGreenProvisioning:
+			details := fmt.Sprintf("Upgrading instance %s: provisioning green environment of %s",
+				instanceID, stringValue(deployment.BlueGreenDeploymentName))
+			return functional.CheckInProgress(status, details)
+		default:
+			return checkError(ctx, name, status, blueGreenFailure(deployment))
+		}
+
+		log.Info("Switching over RDS blue/green deployment", "deployment", upgrade.DeploymentIdentifier)
+		_, err = client.SwitchoverBlueGreenDeployment(ctx, &rds.SwitchoverBlueGreenDeploymentInput{
+			BlueGreenDeploymentIdentifier: stringPtr(upgrade.DeploymentIdentifier),
+			SwitchoverTimeout:             int32Ptr(blueGreenSwitchoverTimeout),
+		})
+		if err != nil {
+			return checkError(ctx, name, status, fmt.Errorf("failed to switch over blue/green deployment %s: %w",
+				upgrade.DeploymentIdentifier, err))
+		}
+		upgrade.Stage = BlueGreenSwitchingOver
+
+		details := fmt.Sprintf("Upgrading instance %s: switching over to engine version %s", instanceID, upgrade.EngineVersion)
+		return functional.CheckInProgress(status, details)
+
+	case BlueGreenSwitchingOver:
+		deployment, err := getBlueGreenDeployment(ctx, client, upgrade.DeploymentIdentifier)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+
+		switch stringValue(deployment.Status) {
+		case blueGreenSwitchoverCompleted:
+		case blueGreenSwitchoverInProgress:
+			details := fmt.Sprintf("Upgrading instance %s: switching over to engine version %s", instanceID, upgrade.EngineVersion)
+			return functional.CheckInProgress(status, details)
+		default:
+			return checkError(ctx, name, status, blueGreenFailure(deployment))
+		}
+
+		// After the switchover the deployment source is the old blue instance under its new identifier.
+		// The deployment is the only other record of it, so it is kept until status has the identifier.
+		blue := instanceIDFromArn(stringValue(deployment.Source))
+		upgrade.Stage = BlueGreenRetiring
+		upgrade.RetiredIdentifier = blue
+
+		rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Switched RDS instance %s over to engine version %s",
+			instanceID, upgrade.EngineVersion)
+
+		details := fmt.Sprintf("Upgrading instance %s: switched over, retiring %s", instanceID, blue)
+		return functional.CheckInProgress(status, details)
+
+	case BlueGreenRetiring:
+		// The old blue instance is deleted once the deployment is gone
+		deployment, err := findBlueGreenDeployment(ctx, client, upgrade.DeploymentIdentifier)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if deployment != nil {
+			if stringValue(deployment.Status) != blueGreenDeleting {
+				if err := deleteBlueGreenDeployment(ctx, client, upgrade.DeploymentIdentifier, false); err != nil {
+					return checkError(ctx, name, status, err)
+				}
+			}
+			details := fmt.Sprintf("Upgrading instance %s: switched over, deleting blue/green deployment %s",
+				instanceID, stringValue(deployment.BlueGreenDeploymentName))
+			return functional.CheckInProgress(status, details)
+		}
+
+		retired, err := getInstanceData(ctx, client, upgrade.RetiredIdentifier)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if retired != nil && RDSInstanceStatus(stringValue(retired.DBInstanceStatus)) == StatusAvailable &&
+			boolValue(retired.DeletionProtection) {
+			if err := unprotectInstance(ctx, client, upgrade.RetiredIdentifier); err != nil {
+				return checkError(ctx, name, status, err)
+			}
+		}
+		if retired != nil && (RDSInstanceStatus(stringValue(retired.DBInstanceStatus)) != StatusAvailable ||
+			boolValue(retired.DeletionProtection)) {
+			details := fmt.Sprintf("Upgrading instance %s: waiting for %s to lose its deletion protection",
+				instanceID, upgrade.RetiredIdentifier)
+			return functional.CheckInProgress(status, details)
+		}
+		if retired != nil {
+			if err := deleteRetiredInstance(ctx, client, &spec, upgrade.RetiredIdentifier); err != nil {
+				return checkError(ctx, name, status, err)
+			}
+		}
+
+		instance, err := getInstanceData(ctx, client, instanceID)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if instance == nil {
+			return checkError(ctx, name, status, fmt.Errorf("RDS instance %s not found after blue/green switchover", instanceID))
+		}
+
+		// The green environment only took the engine version and parameter group, the rest follows now
+		updateStatusFromInstance(&status, instance)
+		status.BlueGreen = nil
+		status.RestoreModifyPending = true
+		instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+		log.Info("RDS instance upgraded", "engineVersion", upgrade.EngineVersion)
+		rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Upgraded RDS instance %s to engine version %s, deleting %s",
+			instanceID, upgrade.EngineVersion, upgrade.RetiredIdentifier)
+
+		details := fmt.Sprintf("Upgraded instance %s to engine version %s, deleting %s", instanceID, upgrade.EngineVersion, upgrade.RetiredIdentifier)
+		return functional.CheckInProgress(status, details)
+
+	default:
+		return checkError(ctx, name, status, fmt.Errorf("unknown blue/green upgrade stage %q", upgrade.Stage))
+	}
+}
+
+// abandonBlueGreen cleans up a blue/green upgrade of an instance that is being deleted. A green
+// environment that has not taken over is deleted with its deployment. After the switchover the
+// deployment is deleted, and then the old blue instance like in checkBlueGreen. A switchover in
+// progress is left to complete.
+func abandonBlueGreen(ctx context.Context, client API, config *RdsConfig, upgrade *BlueGreenUpgrade) error {
+	switch upgrade.Stage {
+	case BlueGreenProvisioning:
+		return deleteBlueGreenDeployment(ctx, client, upgrade.DeploymentIdentifier, true)
+	case BlueGreenRetiring:
+		deployment, err := findBlueGreenDeployment(ctx, client, upgrade.DeploymentIdentifier)
+		if err != nil {
+			return err
+		}
+		if deployment != nil {
+			if stringValue(deployment.Status) != blueGreenDeleting {
+				if err := deleteBlueGreenDeployment(ctx, client, upgrade.DeploymentIdentifier, false); err != nil {
+					return err
+				}
+			}
+			return fmt.Errorf("blue/green deployment %s is being deleted, retry once it is gone", upgrade.DeploymentIdentifier)
+		}
+
+		retired, err := getInstanceData(ctx, client, upgrade.RetiredIdentifier)
+		if err != nil || retired == nil {
+			return err
+		}
+		if boolValue(retired.DeletionProtection) {
+			if err := unprotectInstance(ctx, client, upgrade.RetiredIdentifier); err != nil {
+				return err
+			}
+			return fmt.Errorf("RDS instance %s is losing its deletion protection, retry once it did", upgrade.RetiredIdentifier)
+		}
+		return deleteRetiredInstance(ctx, client, config, upgrade.RetiredIdentifier)
+	default:
+		return fmt.Errorf("blue/green deployment %s is switching over, retry once it completed", upgrade.DeploymentIdentifier)
+	}
+}
+
+// getBlueGreenDeployment describes a blue/green deployment that must exist
+func getBlueGreenDeployment(ctx context.Context, client API, deploymentID string) (*rdstypes.BlueGreenDeployment, error) {
+	deployment, err := findBlueGreenDeployment(ctx, client, deploymentID)
+	if err == nil && deployment == nil {
+		err = fmt.Errorf("blue/green deployment %s not found", deploymentID)
+	}
+	return deployment, err
+}
+
+// findBlueGreenDeployment describes a blue/green deployment, or returns nil if it is gone
+func findBlueGreenDeployment(ctx context.Context, client API, deploymentID string) (*rdstypes.BlueGreenDeployment, error) {
+	result, err := client.DescribeBlueGreenDeployments(ctx, &rds.DescribeBlueGreenDeploymentsInput{
+		BlueGreenDeploymentIdentifier: stringPtr(deploymentID),
+	})
+	if err != nil {
+		var notFound *rdstypes.BlueGreenDeploymentNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe blue/green deployment %s: %w", deploymentID, err)
+	}
+	if len(result.BlueGreenDeployments) == 0 {
+		return nil, nil
+	}
+	return &result.BlueGreenDeployments[0], nil
+}
+
+// blueGreenFailure describes a blue/green deployment that cannot go on
+func blueGreenFailure(deployment *rdstypes.BlueGreenDeployment) error {
+	err := fmt.Errorf("blue/green deployment %s is %s", stringValue(deployment.BlueGreenDeploymentName), stringValue(deployment.Status))
+	if details := stringValue(deployment.StatusDetails); details != "" {
+		err = fmt.Errorf("%w: %s", err, details)
+	}
+	return err
+}
+
+// deleteBlueGreenDeployment deletes a blue/green deployment, and its green environment if asked to.
+// It is skipped if the deployment is already gone.
+func deleteBlueGreenDeployment(ctx context.Context, client API, deploymentID string, deleteTarget bool) error {
+	log := logf.FromContext(ctx).WithValues("deployment", deploymentID)
+
+	_, err := client.DeleteBlueGreenDeployment(ctx, &rds.DeleteBlueGreenDeploymentInput{
+		BlueGreenDeploymentIdentifier: stringPtr(deploymentID),
+		DeleteTarget:                  boolPtr(deleteTarget),
+	})
+	if err != nil {
+		var notFound *rdstypes.BlueGreenDeploymentNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete blue/green deployment %s: %w", deploymentID, err)
+	}
+
+	log.Info("RDS blue/green deployment deletion initiated successfully", "deleteTarget", deleteTarget)
+	return nil
+}
+
+// unprotectInstance turns off the deletion protection of an instance about to be deleted
+func unprotectInstance(ctx context.Context, client API, instanceID string) error {
+	instance, err := getInstanceData(ctx, client, instanceID)
+	if err != nil || instance == nil || !boolValue(instance.DeletionProtection) {
+		return err
+	}
+
+	_, err = client.ModifyDBInstance(ctx, &rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: stringPtr(instanceID),
+		DeletionProtection:   boolPtr(false),
+		ApplyImmediately:     boolPtr(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to turn off deletion protection of RDS instance %s: %w", instanceID, err)
+	}
+	return nil
+}
+
+// instanceIDFromArn returns the identifier of an instance ARN (arn:aws:rds:<region>:<account>:db:<id>)
+func instanceIDFromArn(arn string) string {
+	return arn[strings.LastIndex(arn, ":")+1:]
+}
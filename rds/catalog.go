@@ -0,0 +1,323 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
+
+// catalogTTL is how long the engine versions and instance options RDS offers in a region are cached
+const catalogTTL = 6 * time.Hour
+
+// maxAlternatives is the most valid alternatives listed when a setting is not offered
+const maxAlternatives = 10
+
+// offeringError reports a setting RDS does not offer. Retrying does not help, so the apply fails for good.
+type offeringError struct {
+	message string
+}
+
+func (e *offeringError) Error() string {
+	return e.message
+}
+
+// catalogKey selects cached offerings: the versions of an engine, or the instance options of one of them.
+// The role is part of the key, as what RDS offers depends on the account the role gives access to.
+type catalogKey struct {
+	region, role, engine, version string
+}
+
+// newCatalogKey returns the key of an offering read with the access settings
+func newCatalogKey(access awsclient.AccessConfig, engine, version string) catalogKey {
+	return catalogKey{region: access.Region, role: access.AssumeRoleArn, engine: engine, version: version}
+}
+
+// catalogEntry is a cached offering with the time it was read
+type catalogEntry[T any] struct {
+	value   T
+	fetched time.Time
+}
+
+// catalog caches what RDS offers per region and role. The offering rarely changes, and every apply of a
+// new or changed instance checks against it.
+type catalog struct {
+	mu       sync.Mutex
+	versions map[catalogKey]catalogEntry[[]string]
+	options  map[catalogKey]catalogEntry[[]types.OrderableDBInstanceOption]
+}
+
+func newCatalog() *catalog {
+	return &catalog{
+		versions: make(map[catalogKey]catalogEntry[[]string]),
+		options:  make(map[catalogKey]catalogEntry[[]types.OrderableDBInstanceOption]),
+	}
+}
+
+// Package-level catalog shared by all Components
+var rdsCatalog = newCatalog()
+
+// cached returns the entry under the key while it is fresh, reading it with fetch otherwise
+func cached[T any](c *catalog, entries map[catalogKey]catalogEntry[T], key catalogKey, fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	entry, ok := entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetched) < catalogTTL {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.mu.Lock()
+	entries[key] = catalogEntry[T]{value: value, fetched: time.Now()}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// engineVersions returns the versions of the engine RDS offers to the access settings, oldest first
+func (c *catalog) engineVersions(ctx context.Context, client API, access awsclient.AccessConfig, engine string) ([]string, error) {
+	return cached(c, c.versions, newCatalogKey(access, engine, ""), func() ([]string, error) {
+		var versions []string
+		input := &rds.DescribeDBEngineVersionsInput{Engine: stringPtr(engine)}
+		for {
+			result, err := client.DescribeDBEngineVersions(ctx, input)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe %s engine versions: %w", engine, err)
+			}
+			for _, version := range result.DBEngineVersions {
+				versions = append(versions, stringValue(version.EngineVersion))
+			}
+			if result.Marker == nil {
+				break
+			}
+			input.Marker = result.Marker
+		}
+		slices.SortFunc(versions, compareVersions)
+		return versions, nil
+	})
+}
+
+// instanceOptions returns the instance options RDS offers to the access settings for the engine version,
+// or for every version of the engine if version is ""
+func (c *catalog) instanceOptions(ctx context.Context, client API, access awsclient.AccessConfig, engine, version string) ([]types.OrderableDBInstanceOption, error) {
+	return cached(c, c.options, newCatalogKey(access, engine, version), func() ([]types.OrderableDBInstanceOption, error) {
+		var options []types.OrderableDBInstanceOption
+		input := &rds.DescribeOrderableDBInstanceOptionsInput{
+			Engine:        stringPtr(engine),
+			EngineVersion: optionalStringPtr(version),
+		}
+		for {
+			result, err := client.DescribeOrderableDBInstanceOptions(ctx, input)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe orderable instance options of %s: %w", engine, err)
+			}
+			options = append(options, result.OrderableDBInstanceOptions...)
+			if result.Marker == nil {
+				break
+			}
+			input.Marker = result.Marker
+		}
+		return options, nil
+	})
+}
+
+// resolveOffering checks the engine version, instance class, storage type and Multi-AZ setting
+// against what RDS offers in the region of the access settings, before they fail deep inside CreateDBInstance or
+// ModifyDBInstance. A partial engine version like 16 resolves to the latest 16.x, or to the version
+// of the instance if it is already on 16.x. Settings the instance already has are not checked,
+// so an instance on a class RDS no longer offers keeps working. Settings RDS does not offer return
+// an offeringError listing valid alternatives.
+func resolveOffering(ctx context.Context, client API, access awsclient.AccessConfig, config *RdsConfig, instance *types.DBInstance) error {
+	region := access.Region
+	current := &rds.ModifyDBInstanceInput{}
+	engine := config.DatabaseEngine
+	if instance != nil {
+		current = currentSettings(instance)
+		engine = cmp.Or(engine, stringValue(instance.Engine))
+	}
+	// Replicas and restored instances take the engine of their source
+	if engine == "" {
+		return nil
+	}
+
+	versionChanged := config.EngineVersion != "" && config.EngineVersion != stringValue(current.EngineVersion)
+	if versionChanged {
+		versions, err := rdsCatalog.engineVersions(ctx, client, access, engine)
+		if err != nil {
+			return err
+		}
+		version, err := resolveEngineVersion(engine, config.EngineVersion, versions, stringValue(current.EngineVersion), region)
+		if err != nil {
+			return err
+		}
+		config.EngineVersion = version
+		versionChanged = version != stringValue(current.EngineVersion)
+	}
+
+	class := cmp.Or(config.InstanceClass, stringValue(current.DBInstanceClass))
+	storageType := cmp.Or(config.StorageType, stringValue(current.StorageType))
+	if instance == nil && !config.isReplica() && !config.isRestore() {
+		storageType = createStorageType(config)
+	}
+	multiAZ := boolValue(config.MultiAZ) && !boolValue(current.MultiAZ)
+
+	changes := instance == nil || versionChanged || class != stringValue(current.DBInstanceClass) ||
+		storageType != stringValue(current.StorageType) || multiAZ
+	if !changes || class == "" {
+		return nil
+	}
+
+	version := cmp.Or(config.EngineVersion, stringValue(current.EngineVersion))
+	options, err := rdsCatalog.instanceOptions(ctx, client, access, engine, version)
+	if err != nil {
+		return err
+	}
+	return checkInstanceOptions(options, describeEngine(engine, version), region, class, storageType, boolValue(config.MultiAZ))
+}
+
+// pinEngineVersion keeps the version of the instance for a partial engine version it is already on,
+// like 16 for an instance on 16.4, so that checks comparing the config with the instance see no change
+func pinEngineVersion(config *RdsConfig, instance *types.DBInstance) {
+	current := stringValue(currentSettings(instance).EngineVersion)
+	if config.EngineVersion != "" && strings.HasPrefix(current, config.EngineVersion+".") {
+		config.EngineVersion = current
+	}
+}
+
+// resolveEngineVersion resolves a requested engine version against the offered versions, oldest first
+func resolveEngineVersion(engine, requested string, offered []string, current, region string) (string, error) {
+	if slices.Contains(offered, requested) {
+		return requested, nil
+	}
+	if strings.HasPrefix(current, requested+".") {
+		return current, nil
+	}
+
+	var matching []string
+	for _, version := range offered {
+		if strings.HasPrefix(version, requested+".") {
+			matching = append(matching, version)
+		}
+	}
+	if len(matching) > 0 {
+		return matching[len(matching)-1], nil
+	}
+
+	// Suggest the versions of the same major version, or the latest of every major version
+	var alternatives []string
+	for _, version := range offered {
+		if majorVersion(engine, version) == majorVersion(engine, requested) {
+			alternatives = append(alternatives, version)
+		}
+	}
+	if len(alternatives) == 0 {
+		for i, version := range offered {
+			if i == len(offered)-1 || majorVersion(engine, offered[i+1]) != majorVersion(engine, version) {
+				alternatives = append(alternatives, version)
+			}
+		}
+	}
+	return "", &offeringError{fmt.Sprintf("engine version %s of %s is not offered in %s; valid versions: %s",
+		requested, engine, region, listAlternatives(alternatives))}
+}
+
+// checkInstanceOptions checks that an instance class is offered with the storage type, and with
+// Multi-AZ if asked for
+func checkInstanceOptions(options []types.OrderableDBInstanceOption, engine, region, class, storageType string, multiAZ bool) error {
+	var classes []string
+	var forClass []types.OrderableDBInstanceOption
+	for _, option := range options {
+		classes = append(classes, stringValue(option.DBInstanceClass))
+		if stringValue(option.DBInstanceClass) == class {
+			forClass = append(forClass, option)
+		}
+	}
+
+	if len(forClass) == 0 {
+		// Suggest the classes of the same family, like db.t3.* for db.t3.micor, or all of them
+		family := class[:strings.LastIndex(class, ".")+1]
+		alternatives := slices.DeleteFunc(slices.Clone(classes), func(c string) bool {
+			return family == "" || !strings.HasPrefix(c, family)
+		})
+		if len(alternatives) == 0 {
+			alternatives = classes
+		}
+		return &offeringError{fmt.Sprintf("instance class %s is not offered for %s in %s; valid classes: %s",
+			class, engine, region, listAlternatives(sortedUnique(alternatives)))}
+	}
+
+	if storageType != "" {
+		var storageTypes []string
+		for _, option := range forClass {
+			storageTypes = append(storageTypes, stringValue(option.StorageType))
+		}
+		if !slices.Contains(storageTypes, storageType) {
+			return &offeringError{fmt.Sprintf("storage type %s is not offered for instance class %s of %s in %s; valid storage types: %s",
+				storageType, class, engine, region, listAlternatives(sortedUnique(storageTypes)))}
+		}
+		forClass = slices.DeleteFunc(forClass, func(option types.OrderableDBInstanceOption) bool {
+			return stringValue(option.StorageType) != storageType
+		})
+	}
+
+	if multiAZ && !slices.ContainsFunc(forClass, func(option types.OrderableDBInstanceOption) bool {
+		return boolValue(option.MultiAZCapable)
+	}) {
+		return &offeringError{fmt.Sprintf("instance class %s of %s does not support Multi-AZ in %s", class, engine, region)}
+	}
+
+	return nil
+}
+
+// describeEngine names an engine version for messages, like "postgres 16.4"
+func describeEngine(engine, version string) string {
+	return strings.TrimSpace(engine + " " + version)
+}
+
+// listAlternatives joins valid alternatives, keeping the last maxAlternatives of a long list
+func listAlternatives(alternatives []string) string {
+	if len(alternatives) > maxAlternatives {
+		alternatives = alternatives[len(alternatives)-maxAlternatives:]
+	}
+	return strings.Join(alternatives, ", ")
+}
+
+// sortedUnique returns the sorted distinct values
+func sortedUnique(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
+
+// compareVersions orders engine versions by their numeric components, like 16.10 after 16.9
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		var c int
+		if aErr == nil && bErr == nil {
+			c = cmp.Compare(an, bn)
+		} else {
+			c = strings.Compare(as[i], bs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
@@ -5,11 +5,16 @@ package rds
 
 import (
 	"fmt"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
 )
 
 // RdsConfig represents the configuration structure for RDS components
 // that gets unmarshaled from Component.Spec.Config
 type RdsConfig struct {
+	// AccessConfig optionally selects the AWS region and a role to assume in another account
+	awsclient.AccessConfig
+
 	// Instance Configuration - Required
 	InstanceID string `json:"instanceID"`
 
@@ -19,11 +24,16 @@ type RdsConfig struct {
 	InstanceClass  string `json:"instanceClass"`
 	DatabaseName   string `json:"databaseName"`
 
-	// Storage Configuration
-	AllocatedStorage int32  `json:"allocatedStorage"`
-	StorageType      string `json:"storageType,omitempty"`
-	StorageEncrypted *bool  `json:"storageEncrypted,omitempty"`
-	KmsKeyId         string `json:"kmsKeyId,omitempty"`
+	// Storage Configuration - storageType defaults to gp3 for new instances. MaxAllocatedStorage
+	// turns on storage autoscaling up to that size; equal to allocatedStorage it turns it off.
+	// Iops and storageThroughput provision gp3, io1 or io2 volumes beyond their baseline.
+	AllocatedStorage    int32  `json:"allocatedStorage"`
+	MaxAllocatedStorage *int32 `json:"maxAllocatedStorage,omitempty"`
+	StorageType         string `json:"storageType,omitempty"`
+	Iops                *int32 `json:"iops,omitempty"`
+	StorageThroughput   *int32 `json:"storageThroughput,omitempty"`
+	StorageEncrypted    *bool  `json:"storageEncrypted,omitempty"`
+	KmsKeyId            string `json:"kmsKeyId,omitempty"`
 
 	// Database Credentials
 	MasterUsername           string `json:"masterUsername"`
@@ -36,14 +46,31 @@ type RdsConfig struct {
 	PubliclyAccessible  *bool    `json:"publiclyAccessible,omitempty"`
 	Port                *int32   `json:"port,omitempty"`
 
+	// Security and Logging Configuration - IAM database authentication, and the engine logs exported
+	// to CloudWatch Logs: postgresql and upgrade for Postgres; audit, error, general and slowquery for
+	// MySQL and MariaDB. An empty list turns all exports off; leaving it unset keeps them as they are.
+	IAMDatabaseAuthenticationEnabled *bool    `json:"iamDatabaseAuthenticationEnabled,omitempty"`
+	EnableCloudwatchLogsExports      []string `json:"enableCloudwatchLogsExports,omitempty"`
+
+	// Parameter Group Configuration - the DB parameter group of the instance, e.g. one managed by an
+	// rds-parameter-group Component. Instances without one use the engine's default group.
+	ParameterGroupName string `json:"parameterGroupName,omitempty"`
+
 	// Backup Configuration
 	BackupRetentionPeriod *int32 `json:"backupRetentionPeriod,omitempty"`
 	PreferredBackupWindow string `json:"preferredBackupWindow,omitempty"`
 
+	// Manual Snapshots - taken on a cron schedule and on request with the SnapshotAnnotation, and kept
+	// beyond the backup retention period. They are kept when the instance is deleted.
+	Snapshots *Snapshots `json:"snapshots,omitempty"`
+
 	// Maintenance Configuration
 	PreferredMaintenanceWindow string `json:"preferredMaintenanceWindow,omitempty"`
 	AutoMinorVersionUpgrade    *bool  `json:"autoMinorVersionUpgrade,omitempty"`
 
+	// Modification Timing - when changes to an existing instance take effect, per change class
+	ApplyChanges ApplyChanges `json:"applyChanges,omitempty"`
+
 	// Performance Configuration
 	MultiAZ                    *bool  `json:"multiAZ,omitempty"`
 	PerformanceInsightsEnabled *bool  `json:"performanceInsightsEnabled,omitempty"`
@@ -53,6 +80,101 @@ type RdsConfig struct {
 	DeletionProtection        *bool  `json:"deletionProtection,omitempty"`
 	SkipFinalSnapshot         *bool  `json:"skipFinalSnapshot,omitempty"`
 	FinalDBSnapshotIdentifier string `json:"finalDBSnapshotIdentifier,omitempty"`
+
+	// Read Replica Configuration - the source instance identifier, or its ARN for a cross-region replica.
+	// Engine, storage and credentials come from the source. PromoteReplica turns the replica into a
+	// standalone instance, which cannot be undone.
+	ReplicaSourceIdentifier string `json:"replicaSourceIdentifier,omitempty"`
+	PromoteReplica          bool   `json:"promoteReplica,omitempty"`
+	MaxReplicaLagSeconds    *int32 `json:"maxReplicaLagSeconds,omitempty"`
+
+	// Restore Configuration - create the instance from a snapshot or a point in time instead of empty.
+	// Engine, storage and credentials come from the restored data.
+	RestoreFrom *RestoreFrom `json:"restoreFrom,omitempty"`
+
+	// Replacement - Never (default) fails the apply when a setting RDS cannot modify changes, like
+	// databaseName or subnetGroupName; SnapshotRestore replaces the instance with one restored from
+	// a snapshot of it
+	ReplacementPolicy string `json:"replacementPolicy,omitempty"`
+
+	// Upgrade strategy for major engine version upgrades - InPlace (default) modifies the instance,
+	// with downtime for the length of the upgrade; BlueGreen upgrades a synced copy in a blue/green
+	// deployment and switches over to it
+	UpgradeStrategy string `json:"upgradeStrategy,omitempty"`
+
+	// Connection Secret - publishes the connection details to a Kubernetes Secret in the Component's
+	// namespace, optionally with the master password from the RDS-managed Secrets Manager secret
+	ConnectionSecret *ConnectionSecret `json:"connectionSecret,omitempty"`
+
+	// Tags are applied to the instance and copied to its snapshots. Tags removed from the config
+	// are removed from the instance; tags set outside the config are left alone.
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Ownership - Never (default), IfUntagged or Always take over an existing instance
+	// that is not tagged as owned by this Component
+	AdoptionPolicy string `json:"adoptionPolicy,omitempty"`
+
+	// Delete (default) or Retain, which keeps the instance on Component deletion
+	// and removes its ownership tags
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// Modification timings of a change class
+const (
+	ApplyImmediately             = "Immediately"
+	ApplyDuringMaintenanceWindow = "MaintenanceWindow"
+)
+
+// ApplyChanges selects when modifications of each change class take effect: Immediately (default),
+// or MaintenanceWindow to leave them pending until the next maintenance window. Changes to other
+// settings, like the backup retention or deletion protection, always apply immediately.
+type ApplyChanges struct {
+	// InstanceClass covers instanceClass changes
+	InstanceClass string `json:"instanceClass,omitempty"`
+
+	// Storage covers allocatedStorage, storageType, iops and storageThroughput changes
+	Storage string `json:"storage,omitempty"`
+
+	// EngineVersion covers engineVersion upgrades
+	EngineVersion string `json:"engineVersion,omitempty"`
+
+	// MultiAZ covers multiAZ changes
+	MultiAZ string `json:"multiAZ,omitempty"`
+}
+
+// ConnectionSecret selects the Kubernetes Secret the connection details of the instance are written to.
+// The Secret holds the host, port, dbname and username keys, and password if IncludePassword is set.
+type ConnectionSecret struct {
+	// Name of the Secret, defaults to "<instanceID>-connection"
+	Name string `json:"name,omitempty"`
+
+	// IncludePassword adds the master password, kept in sync when RDS rotates it
+	IncludePassword bool `json:"includePassword,omitempty"`
+}
+
+// Snapshots selects the manual snapshots taken of the instance. Each is tagged as owned by the
+// Component; the oldest ones of a kind are deleted once more than Retain exist.
+type Snapshots struct {
+	// Schedule is a cron expression in UTC (minute hour day-of-month month day-of-week, or @daily,
+	// @weekly, ...) for scheduled snapshots, e.g. "0 3 * * 0" for Sundays at 03:00. Without it
+	// snapshots are only taken on request.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Retain is how many snapshots of each kind, Scheduled and OnDemand, are kept (default 7)
+	Retain *int32 `json:"retain,omitempty"`
+}
+
+// RestoreFrom selects the data a new instance is restored from: a DB snapshot, or a point in time
+// of another instance's automated backups. It only applies when the instance is created.
+type RestoreFrom struct {
+	// SnapshotIdentifier is the identifier or ARN of a DB snapshot
+	SnapshotIdentifier string `json:"snapshotIdentifier,omitempty"`
+
+	// SourceInstanceIdentifier is the instance to restore to a point in time,
+	// given by RestoreTime (RFC 3339) or UseLatestRestorableTime
+	SourceInstanceIdentifier string `json:"sourceInstanceIdentifier,omitempty"`
+	RestoreTime              string `json:"restoreTime,omitempty"`
+	UseLatestRestorableTime  bool   `json:"useLatestRestorableTime,omitempty"`
 }
 
 // RdsStatus contains handler-specific status data for RDS deployments.
@@ -69,6 +191,98 @@ type RdsStatus struct {
 
 	// Credentials information
 	MasterUserSecretArn string `json:"masterUserSecretArn,omitempty"`
+
+	// Replication information - set while the instance is a read replica.
+	// The lag is the latest CloudWatch ReplicaLag when the apply last completed.
+	ReplicaSource     string `json:"replicaSource,omitempty"`
+	ReplicationState  string `json:"replicationState,omitempty"`
+	ReplicaLagSeconds *int64 `json:"replicaLagSeconds,omitempty"`
+
+	// Modifications waiting for the maintenance window, by setting
+	PendingModifications map[string]string `json:"pendingModifications,omitempty"`
+
+	// Parameter group information - the apply status is pending-reboot while
+	// parameter changes wait for the instance to reboot
+	ParameterGroupName   string `json:"parameterGroupName,omitempty"`
+	ParameterApplyStatus string `json:"parameterApplyStatus,omitempty"`
+
+	// Restore information - the source of a restored instance, and whether the settings
+	// the restore could not set still have to be applied with a modification
+	RestoredFrom         string `json:"restoredFrom,omitempty"`
+	RestoreModifyPending bool   `json:"restoreModifyPending,omitempty"`
+
+	// Replacement information - progress of a replacement that applies changes to settings
+	// RDS cannot modify; it is cleared once the replacement took over the instance identifier
+	Replacement *Replacement `json:"replacement,omitempty"`
+
+	// Blue/green upgrade information - progress of a major version upgrade with the BlueGreen
+	// strategy; it is cleared once the old instance is deleted
+	BlueGreen *BlueGreenUpgrade `json:"blueGreen,omitempty"`
+
+	// Name of the connection Secret written for the instance
+	ConnectionSecretName string `json:"connectionSecretName,omitempty"`
+
+	// Manual snapshots taken for the snapshots config, newest first, as of when the apply last completed
+	Snapshots []ManualSnapshot `json:"snapshots,omitempty"`
+
+	// Keys of the config tags last applied to the instance - the tags the provider removes
+	// from the instance once they are removed from the config
+	TagKeys []string `json:"tagKeys,omitempty"`
+
+	// AWS target the instance was created in
+	Access *awsclient.AccessConfig `json:"access,omitempty"`
+
+	// Plan lists the changes a dry-run apply would make; it is cleared by a real apply
+	Plan *awsclient.Plan `json:"plan,omitempty"`
+}
+
+// Replacement tracks the snapshot-restore replacement of an instance through its stages
+type Replacement struct {
+	// Stage is the step in progress: Snapshotting, Copying, Restoring, Renaming or Swapping,
+	// or RollingBack when a Component with the Retain deletion policy is deleted during the replacement
+	Stage string `json:"stage"`
+
+	// Changes lists the settings the replacement changes
+	Changes []string `json:"changes"`
+
+	// SnapshotIdentifier is the snapshot the replacement is restored from. It is kept afterwards.
+	SnapshotIdentifier string `json:"snapshotIdentifier"`
+
+	// ReplacementIdentifier is the identifier of the new instance until it takes over the instance
+	// identifier; RetiredIdentifier is the one the old instance is renamed to before its deletion
+	ReplacementIdentifier string `json:"replacementIdentifier"`
+	RetiredIdentifier     string `json:"retiredIdentifier"`
+}
+
+// ManualSnapshot is a manual DB snapshot the provider took of the instance
+type ManualSnapshot struct {
+	// Identifier of the DB snapshot
+	Identifier string `json:"identifier"`
+
+	// Kind is Scheduled or OnDemand
+	Kind string `json:"kind"`
+
+	// Request is the SnapshotAnnotation value an OnDemand snapshot was taken for
+	Request string `json:"request,omitempty"`
+
+	// Time the snapshot was taken (RFC 3339) and its RDS status
+	Time   string `json:"time"`
+	Status string `json:"status"`
+}
+
+// BlueGreenUpgrade tracks a blue/green major version upgrade of an instance through its stages
+type BlueGreenUpgrade struct {
+	// Stage is the step in progress: Provisioning, SwitchingOver or Retiring
+	Stage string `json:"stage"`
+
+	// DeploymentIdentifier is the blue/green deployment doing the upgrade
+	DeploymentIdentifier string `json:"deploymentIdentifier"`
+
+	// EngineVersion is the engine version the instance is upgraded to
+	EngineVersion string `json:"engineVersion"`
+
+	// RetiredIdentifier is the identifier of the old blue instance after the switchover, until its deletion
+	RetiredIdentifier string `json:"retiredIdentifier,omitempty"`
 }
 
 // resolveSpec validates config and applies defaults
@@ -77,6 +291,47 @@ func resolveSpec(config *RdsConfig) error {
 	if config.InstanceID == "" {
 		return fmt.Errorf("InstanceID is required and cannot be empty")
 	}
+	if err := config.AccessConfig.Validate(); err != nil {
+		return err
+	}
+
+	policy, err := awsclient.ResolveAdoptionPolicy(config.AdoptionPolicy)
+	if err != nil {
+		return err
+	}
+	config.AdoptionPolicy = policy
+
+	if config.DeletionPolicy, err = awsclient.ResolveDeletionPolicy(config.DeletionPolicy); err != nil {
+		return err
+	}
+
+	if err := validateReplica(config); err != nil {
+		return err
+	}
+	if err := validateRestore(config); err != nil {
+		return err
+	}
+	if err := validateStorage(config); err != nil {
+		return err
+	}
+	if err := validateTags(config.Tags); err != nil {
+		return err
+	}
+	if err := validateLogExports(config); err != nil {
+		return err
+	}
+	if err := validateSnapshots(config); err != nil {
+		return err
+	}
+	if err := config.ApplyChanges.validate(); err != nil {
+		return err
+	}
+	if config.ReplacementPolicy, err = resolveReplacementPolicy(config.ReplacementPolicy); err != nil {
+		return err
+	}
+	if config.UpgradeStrategy, err = resolveUpgradeStrategy(config); err != nil {
+		return err
+	}
 
 	// Apply defaults
 	if err := applyDefaults(config); err != nil {
@@ -86,10 +341,28 @@ func resolveSpec(config *RdsConfig) error {
 	return nil
 }
 
+// resolveAccess returns the AWS target the instance was created in.
+// Falls back to the config for instances that have not been created yet.
+func resolveAccess(spec RdsConfig, status RdsStatus) awsclient.AccessConfig {
+	if status.Access != nil {
+		return *status.Access
+	}
+	return rdsClients.Resolve(spec.AccessConfig)
+}
+
+// adoptionPolicy returns the adoption policy for an existing instance. An instance already recorded
+// in status was managed before ownership tags were introduced, so it is tagged instead of refused.
+func adoptionPolicy(spec RdsConfig, status RdsStatus, instanceArn string) string {
+	if spec.AdoptionPolicy == awsclient.AdoptionNever && status.InstanceARN == instanceArn {
+		return awsclient.AdoptionIfUntagged
+	}
+	return spec.AdoptionPolicy
+}
+
 // applyDefaults sets sensible defaults for optional RDS configuration fields
 func applyDefaults(config *RdsConfig) error {
-	// Validate required credentials fields
-	if config.MasterUsername == "" {
+	// Validate required credentials fields; replicas and restored instances keep the credentials of their source
+	if config.MasterUsername == "" && !config.isReplica() && !config.isRestore() {
 		return fmt.Errorf("masterUsername is required and cannot be empty")
 	}
 
@@ -102,14 +375,8 @@ func applyDefaults(config *RdsConfig) error {
 		return fmt.Errorf("manageMasterUserPassword must be true - explicit password management is not supported. AWS RDS will generate secure passwords automatically")
 	}
 
-	// Storage defaults
-	if config.StorageType == "" {
-		config.StorageType = "gp2" // General Purpose SSD
-	}
-	if config.StorageEncrypted == nil {
-		defaultEncrypted := true
-		config.StorageEncrypted = &defaultEncrypted
-	}
+	// Storage defaults - the storage type and encryption default at creation only, so existing
+	// instances keep theirs and an unset storageEncrypted is never an immutable change
 
 	// Network defaults
 	if config.PubliclyAccessible == nil {
@@ -117,8 +384,8 @@ func applyDefaults(config *RdsConfig) error {
 		config.PubliclyAccessible = &defaultPublicAccess
 	}
 
-	// Backup defaults
-	if config.BackupRetentionPeriod == nil {
+	// Backup defaults - replicas of some engines cannot keep backups until promoted
+	if config.BackupRetentionPeriod == nil && (!config.isReplica() || config.PromoteReplica) {
 		defaultRetention := int32(7) // 7 days
 		config.BackupRetentionPeriod = &defaultRetention
 	}
@@ -153,5 +420,30 @@ func applyDefaults(config *RdsConfig) error {
 		config.SkipFinalSnapshot = &defaultSkipSnapshot
 	}
 
+	// Replication defaults
+	if config.MaxReplicaLagSeconds == nil && config.isReplica() {
+		defaultMaxLag := int32(300) // 5 minutes
+		config.MaxReplicaLagSeconds = &defaultMaxLag
+	}
+
+	return nil
+}
+
+// validate checks that every change class has a known timing
+func (a *ApplyChanges) validate() error {
+	timings := []struct{ class, timing string }{
+		{"instanceClass", a.InstanceClass},
+		{"storage", a.Storage},
+		{"engineVersion", a.EngineVersion},
+		{"multiAZ", a.MultiAZ},
+	}
+	for _, t := range timings {
+		switch t.timing {
+		case "", ApplyImmediately, ApplyDuringMaintenanceWindow:
+		default:
+			return fmt.Errorf("invalid applyChanges.%s %q: must be %s or %s",
+				t.class, t.timing, ApplyImmediately, ApplyDuringMaintenanceWindow)
+		}
+	}
 	return nil
 }
@@ -5,8 +5,11 @@ package rds
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -109,6 +112,42 @@ var _ = Describe("RDS Config Parsing", func() {
 			Expect(err.Error()).To(ContainSubstring("manageMasterUserPassword must be true"))
 		})
 
+		It("should parse cross-account access settings", func() {
+			rawConfig := json.RawMessage(`{
+				"instanceID": "test-db",
+				"region": "eu-west-1",
+				"assumeRoleArn": "arn:aws:iam::210987654321:role/provisioner",
+				"externalId": "staging",
+				"masterUsername": "admin"
+			}`)
+
+			var config RdsConfig
+			err := json.Unmarshal(rawConfig, &config)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = resolveSpec(&config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.Region).To(Equal("eu-west-1"))
+			Expect(config.AssumeRoleArn).To(Equal("arn:aws:iam::210987654321:role/provisioner"))
+			Expect(config.ExternalID).To(Equal("staging"))
+		})
+
+		It("should fail on externalId without assumeRoleArn", func() {
+			rawConfig := json.RawMessage(`{
+				"instanceID": "test-db",
+				"externalId": "staging",
+				"masterUsername": "admin"
+			}`)
+
+			var config RdsConfig
+			err := json.Unmarshal(rawConfig, &config)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = resolveSpec(&config)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("externalId requires assumeRoleArn"))
+		})
+
 		It("should fail on missing masterUsername", func() {
 			rawConfig := json.RawMessage(`{
 				"instanceID": "test-db",
@@ -128,5 +167,176 @@ var _ = Describe("RDS Config Parsing", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("masterUsername is required"))
 		})
+
+		It("should parse a cross-region read replica without credentials", func() {
+			rawConfig := json.RawMessage(`{
+				"instanceID": "reports",
+				"instanceClass": "db.t3.micro",
+				"region": "eu-west-1",
+				"replicaSourceIdentifier": "arn:aws:rds:us-east-1:123456789012:db:orders"
+			}`)
+
+			var config RdsConfig
+			err := json.Unmarshal(rawConfig, &config)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = resolveSpec(&config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sourceRegion(config.ReplicaSourceIdentifier)).To(Equal("us-east-1"))
+			Expect(*config.MaxReplicaLagSeconds).To(Equal(int32(300)))
+			Expect(config.BackupRetentionPeriod).To(BeNil())
+		})
+
+		It("should fail on invalid replica settings", func() {
+			for raw, message := range map[string]string{
+				`{"instanceID": "reports", "replicaSourceIdentifier": "arn:aws:s3:::bucket"}`:               "invalid replicaSourceIdentifier",
+				`{"instanceID": "reports", "replicaSourceIdentifier": "orders", "maxReplicaLagSeconds": 0}`: "maxReplicaLagSeconds must be positive",
+				`{"instanceID": "reports", "masterUsername": "admin", "promoteReplica": true}`:              "promoteReplica requires replicaSourceIdentifier",
+				`{"instanceID": "reports", "masterUsername": "admin", "maxReplicaLagSeconds": 60}`:          "maxReplicaLagSeconds requires replicaSourceIdentifier",
+			} {
+				var config RdsConfig
+				Expect(json.Unmarshal([]byte(raw), &config)).To(Succeed())
+				Expect(resolveSpec(&config)).To(MatchError(ContainSubstring(message)), raw)
+			}
+		})
+
+		It("should parse a point-in-time restore without credentials", func() {
+			rawConfig := json.RawMessage(`{
+				"instanceID": "orders-copy",
+				"instanceClass": "db.t3.micro",
+				"restoreFrom": {"sourceInstanceIdentifier": "orders", "restoreTime": "2025-03-01T12:00:00Z"}
+			}`)
+
+			var config RdsConfig
+			err := json.Unmarshal(rawConfig, &config)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = resolveSpec(&config)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.RestoreFrom.String()).To(Equal("instance orders at 2025-03-01T12:00:00Z"))
+			Expect(*config.BackupRetentionPeriod).To(Equal(int32(7)))
+		})
+
+		It("should fail on invalid restore settings", func() {
+			for raw, message := range map[string]string{
+				`{"instanceID": "copy", "restoreFrom": {}}`:                                                                 "restoreFrom requires snapshotIdentifier or sourceInstanceIdentifier",
+				`{"instanceID": "copy", "restoreFrom": {"snapshotIdentifier": "s", "sourceInstanceIdentifier": "orders"}}`:  "only one of snapshotIdentifier and sourceInstanceIdentifier",
+				`{"instanceID": "copy", "restoreFrom": {"snapshotIdentifier": "s", "useLatestRestorableTime": true}}`:       "apply only to sourceInstanceIdentifier",
+				`{"instanceID": "copy", "restoreFrom": {"sourceInstanceIdentifier": "orders"}}`:                             "exactly one of restoreTime and useLatestRestorableTime",
+				`{"instanceID": "copy", "restoreFrom": {"sourceInstanceIdentifier": "orders", "restoreTime": "yesterday"}}`: "must be an RFC 3339 timestamp",
+				`{"instanceID": "copy", "replicaSourceIdentifier": "orders", "restoreFrom": {"snapshotIdentifier": "s"}}`:   "cannot be combined with replicaSourceIdentifier",
+			} {
+				var config RdsConfig
+				Expect(json.Unmarshal([]byte(raw), &config)).To(Succeed())
+				Expect(resolveSpec(&config)).To(MatchError(ContainSubstring(message)), raw)
+			}
+		})
+
+		It("should fail on an unknown modification timing", func() {
+			var config RdsConfig
+			raw := `{"instanceID": "orders", "masterUsername": "admin", "applyChanges": {"instanceClass": "Tonight"}}`
+			Expect(json.Unmarshal([]byte(raw), &config)).To(Succeed())
+			Expect(resolveSpec(&config)).To(MatchError(ContainSubstring(`invalid applyChanges.instanceClass "Tonight"`)))
+		})
+
+		It("should validate the upgrade strategy and tell major versions apart", func() {
+			config := RdsConfig{InstanceID: "orders", MasterUsername: "admin"}
+			Expect(resolveSpec(&config)).To(Succeed())
+			Expect(config.UpgradeStrategy).To(Equal(UpgradeInPlace))
+
+			for raw, message := range map[string]string{
+				`{"instanceID": "orders", "masterUsername": "admin", "upgradeStrategy": "Rolling"}`:              `invalid upgradeStrategy "Rolling"`,
+				`{"instanceID": "reports", "replicaSourceIdentifier": "orders", "upgradeStrategy": "BlueGreen"}`: "does not apply to read replicas",
+			} {
+				var config RdsConfig
+				Expect(json.Unmarshal([]byte(raw), &config)).To(Succeed())
+				Expect(resolveSpec(&config)).To(MatchError(ContainSubstring(message)), raw)
+			}
+
+			Expect(majorVersion("postgres", "16.3")).To(Equal("16"))
+			Expect(majorVersion("postgres", "9.6.24")).To(Equal("9.6"))
+			Expect(majorVersion("mysql", "8.0.35")).To(Equal("8.0"))
+			Expect(isMajorUpgrade("mysql", aws.String("8.0.35"), aws.String("8.4.3"))).To(BeTrue())
+			Expect(isMajorUpgrade("postgres", aws.String("16.3"), aws.String("16.4"))).To(BeFalse())
+			Expect(blueGreenDeploymentName(strings.Repeat("a", 63), "17.2")).To(HaveLen(maxBlueGreenNameLength))
+		})
+
+		It("should fail on reserved tag keys", func() {
+			for key, message := range map[string]string{
+				"aws:createdBy":           `tag key "aws:createdBy" uses the reserved prefix aws:`,
+				"componator.io/component": `tag key "componator.io/component" uses the reserved prefix componator.io/`,
+			} {
+				config := RdsConfig{InstanceID: "orders", MasterUsername: "admin", Tags: map[string]string{key: "x"}}
+				Expect(resolveSpec(&config)).To(MatchError(ContainSubstring(message)), key)
+			}
+		})
+
+		It("should validate log exports against the engine", func() {
+			for raw, message := range map[string]string{
+				`{"instanceID": "orders", "masterUsername": "admin", "databaseEngine": "postgres", "enableCloudwatchLogsExports": ["slowquery"]}`:   `invalid enableCloudwatchLogsExports entry "slowquery" for engine postgres: must be one of postgresql, upgrade`,
+				`{"instanceID": "orders", "masterUsername": "admin", "databaseEngine": "mysql", "enableCloudwatchLogsExports": ["postgresql"]}`:     `invalid enableCloudwatchLogsExports entry "postgresql" for engine mysql`,
+				`{"instanceID": "orders", "masterUsername": "admin", "databaseEngine": "mysql", "enableCloudwatchLogsExports": ["error", "error"]}`: `enableCloudwatchLogsExports lists "error" twice`,
+			} {
+				var config RdsConfig
+				Expect(json.Unmarshal([]byte(raw), &config)).To(Succeed())
+				Expect(resolveSpec(&config)).To(MatchError(ContainSubstring(message)), raw)
+			}
+		})
+
+		It("should validate snapshot schedules and find their next time", func() {
+			for raw, message := range map[string]string{
+				`{"instanceID": "orders", "masterUsername": "admin", "snapshots": {"schedule": "0 3 * *"}}`:                     "expected 5 fields",
+				`{"instanceID": "orders", "masterUsername": "admin", "snapshots": {"schedule": "0 25 * * *"}}`:                  `invalid value "25" in hour field: must be 0-23`,
+				`{"instanceID": "orders", "masterUsername": "admin", "snapshots": {"schedule": "*/0 * * * *"}}`:                 `invalid step "0" in minute field`,
+				`{"instanceID": "orders", "masterUsername": "admin", "snapshots": {"schedule": "0 0 * * fri-mon"}}`:             `invalid range "fri-mon" in day of week field`,
+				`{"instanceID": "orders", "masterUsername": "admin", "snapshots": {"schedule": "@daily", "retain": 0}}`:         "snapshots.retain must be at least 1",
+				`{"instanceID": "orders", "masterUsername": "admin", "snapshots": {"schedule": "0 0 * smarch *", "retain": 3}}`: `invalid value "smarch" in month field`,
+			} {
+				var config RdsConfig
+				Expect(json.Unmarshal([]byte(raw), &config)).To(Succeed())
+				Expect(resolveSpec(&config)).To(MatchError(ContainSubstring(message)), raw)
+			}
+
+			// Friday, 10:07
+			from := time.Date(2026, 10, 16, 10, 7, 30, 0, time.UTC)
+			for expr, next := range map[string]time.Time{
+				"0 3 * * 0":              time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC),
+				"0 3 * * 7":              time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC),
+				"*/15 * * * *":           time.Date(2026, 10, 16, 10, 15, 0, 0, time.UTC),
+				"7 10 * * *":             time.Date(2026, 10, 17, 10, 7, 0, 0, time.UTC),
+				"@monthly":               time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				"0 0 13 * fri":           time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC),
+				"30 9 * jan-mar mon-fri": time.Date(2027, 1, 1, 9, 30, 0, 0, time.UTC),
+				"0 0 30 2 *":             {},
+			} {
+				sched, err := parseSchedule(expr)
+				Expect(err).NotTo(HaveOccurred(), expr)
+				Expect(sched.next(from)).To(Equal(next), expr)
+			}
+		})
+
+		It("should validate storage settings against the storage type", func() {
+			var valid RdsConfig
+			raw := `{"instanceID": "orders", "masterUsername": "admin", "databaseEngine": "postgres",
+				"allocatedStorage": 400, "maxAllocatedStorage": 1000, "iops": 12000, "storageThroughput": 500}`
+			Expect(json.Unmarshal([]byte(raw), &valid)).To(Succeed())
+			Expect(resolveSpec(&valid)).To(Succeed())
+			Expect(valid.StorageType).To(BeEmpty())
+
+			for raw, message := range map[string]string{
+				`{"instanceID": "orders", "masterUsername": "admin", "allocatedStorage": 100, "maxAllocatedStorage": 105}`:             "exceed it by at least 10%",
+				`{"instanceID": "orders", "masterUsername": "admin", "allocatedStorage": 100, "maxAllocatedStorage": 50}`:              "exceed it by at least 10%",
+				`{"instanceID": "orders", "masterUsername": "admin", "storageType": "gp2", "iops": 3000}`:                              "storageType gp2 does not take iops",
+				`{"instanceID": "orders", "masterUsername": "admin", "databaseEngine": "mysql", "allocatedStorage": 20, "iops": 4000}`: "at least 400 GiB",
+				`{"instanceID": "orders", "masterUsername": "admin", "storageType": "io1", "allocatedStorage": 100}`:                   "requires iops of at least 1000",
+				`{"instanceID": "orders", "masterUsername": "admin", "storageType": "io1", "allocatedStorage": 100, "iops": 6000}`:     "at most 50 iops per GiB",
+				`{"instanceID": "orders", "masterUsername": "admin", "storageType": "io2", "iops": 3000, "storageThroughput": 250}`:    "storageType io2 does not take storageThroughput",
+				`{"instanceID": "orders", "masterUsername": "admin", "storageType": "magnetic"}`:                                       `invalid storageType "magnetic"`,
+			} {
+				var config RdsConfig
+				Expect(json.Unmarshal([]byte(raw), &config)).To(Succeed())
+				Expect(resolveSpec(&config)).To(MatchError(ContainSubstring(message)), raw)
+			}
+		})
 	})
 })
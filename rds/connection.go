@@ -0,0 +1,249 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Keys of the connection Secret
+const (
+	ConnectionHost     = "host"
+	ConnectionPort     = "port"
+	ConnectionDBName   = "dbname"
+	ConnectionUsername = "username"
+	ConnectionPassword = "password"
+)
+
+// connectionSecretAnnotation marks a connection Secret with the Component that writes it.
+// Secrets without it, or written for another Component, are never changed or deleted.
+const connectionSecretAnnotation = awsclient.TagComponent
+
+// SecretsAPI reads the RDS-managed master user secret for the password of a connection Secret.
+// It is satisfied by *secretsmanager.Client and by awsfake.SecretsManager in unit tests.
+type SecretsAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// KubeClient writes the connection Secrets in the Components' namespaces.
+// It is satisfied by splitKubeClient and by awsfake.KubeSecrets in unit tests.
+type KubeClient interface {
+	Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error
+	Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error
+	Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error
+	Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error
+}
+
+// splitKubeClient reads Secrets with the manager's uncached API reader and writes them with its client.
+// Reading through the cached client would start an informer on every Secret in the cluster.
+type splitKubeClient struct {
+	client.Reader
+	client.Writer
+}
+
+// Package-level clients for connection Secrets initialized during registration
+var (
+	rdsSecrets *awsclient.Cache[SecretsAPI]
+	rdsKube    KubeClient
+)
+
+// connectionSecretName returns the name of the connection Secret the config asks for, or "" for none
+func connectionSecretName(spec *RdsConfig) string {
+	switch {
+	case spec.ConnectionSecret == nil:
+		return ""
+	case spec.ConnectionSecret.Name != "":
+		return spec.ConnectionSecret.Name
+	default:
+		return spec.InstanceID + "-connection"
+	}
+}
+
+// reconcileConnectionSecret writes the connection details of an available instance to the Secret the
+// config asks for. A Secret written before under another name, or when the config no longer asks
+// for one, is deleted. The Secret is only rewritten when its data changed, e.g. after a password rotation.
+// In dry-run mode nothing is written; the change is recorded in the plan of the status instead.
+func reconcileConnectionSecret(
+	ctx context.Context,
+	name types.NamespacedName,
+	spec *RdsConfig,
+	status *RdsStatus,
+	instance *rdstypes.DBInstance) error {
+
+	// Writing and deleting Secrets changes the cluster
+	if dryRun, err := rdsDryRun.Enabled(ctx, name); err != nil || dryRun {
+		if err != nil {
+			return err
+		}
+		if status.Plan == nil {
+			status.Plan = awsclient.NewPlan(spec.InstanceID, true)
+		}
+		planConnectionSecret(status.Plan, spec, *status)
+		return nil
+	}
+
+	secretName := connectionSecretName(spec)
+	if status.ConnectionSecretName != "" && status.ConnectionSecretName != secretName {
+		if err := deleteConnectionSecret(ctx, name, status.ConnectionSecretName); err != nil {
+			return err
+		}
+		status.ConnectionSecretName = ""
+	}
+	if secretName == "" {
+		return nil
+	}
+
+	data, err := connectionSecretData(ctx, spec, *status, instance)
+	if err != nil {
+		return err
+	}
+
+	written, err := writeConnectionSecret(ctx, name, secretName, data)
+	if err != nil {
+		return err
+	}
+	status.ConnectionSecretName = secretName
+
+	if written {
+		rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Wrote connection Secret %s for RDS instance %s",
+			secretName, spec.InstanceID)
+	}
+	return nil
+}
+
+// planConnectionSecret records a change of the connection Secret reconcileConnectionSecret would make
+func planConnectionSecret(plan *awsclient.Plan, spec *RdsConfig, status RdsStatus) {
+	secretName := connectionSecretName(spec)
+	switch {
+	case secretName == status.ConnectionSecretName:
+		return
+	case secretName == "":
+		plan.Remove("connectionSecret", status.ConnectionSecretName)
+	default:
+		awsclient.Diff(plan, "connectionSecret", optionalStringPtr(status.ConnectionSecretName), &secretName)
+	}
+}
+
+// connectionSecretData returns the connection details of the instance, with the master password
+// read from its RDS-managed secret if the config asks for it
+func connectionSecretData(ctx context.Context, spec *RdsConfig, status RdsStatus, instance *rdstypes.DBInstance) (map[string][]byte, error) {
+	data := map[string][]byte{
+		ConnectionDBName:   []byte(stringValue(instance.DBName)),
+		ConnectionUsername: []byte(stringValue(instance.MasterUsername)),
+	}
+	if endpoint := instance.Endpoint; endpoint != nil {
+		data[ConnectionHost] = []byte(stringValue(endpoint.Address))
+		data[ConnectionPort] = []byte(strconv.Itoa(int(int32Value(endpoint.Port))))
+	}
+
+	if !spec.ConnectionSecret.IncludePassword {
+		return data, nil
+	}
+
+	if instance.MasterUserSecret == nil || instance.MasterUserSecret.SecretArn == nil {
+		return nil, fmt.Errorf("RDS instance %s has no RDS-managed master user secret to read the password from", spec.InstanceID)
+	}
+	password, err := masterPassword(ctx, rdsSecrets.Get(resolveAccess(*spec, status)), *instance.MasterUserSecret.SecretArn)
+	if err != nil {
+		return nil, err
+	}
+	data[ConnectionPassword] = []byte(password)
+
+	return data, nil
+}
+
+// masterPassword reads the password from the JSON value of an RDS-managed master user secret
+func masterPassword(ctx context.Context, client SecretsAPI, secretArn string) (string, error) {
+	result, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: stringPtr(secretArn)})
+	if err != nil {
+		return "", fmt.Errorf("failed to read master user secret %s: %w", secretArn, err)
+	}
+
+	var value struct {
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal([]byte(stringValue(result.SecretString)), &value); err != nil {
+		return "", fmt.Errorf("failed to parse master user secret %s: %w", secretArn, err)
+	}
+	return value.Password, nil
+}
+
+// writeConnectionSecret creates or updates the connection Secret in the Component's namespace.
+// It returns whether the Secret changed.
+func writeConnectionSecret(ctx context.Context, name types.NamespacedName, secretName string, data map[string][]byte) (bool, error) {
+	log := logf.FromContext(ctx).WithValues("secret", secretName)
+
+	secret := &corev1.Secret{}
+	err := rdsKube.Get(ctx, types.NamespacedName{Namespace: name.Namespace, Name: secretName}, secret)
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{Type: corev1.SecretTypeOpaque, Data: data}
+		secret.Namespace = name.Namespace
+		secret.Name = secretName
+		secret.Annotations = map[string]string{connectionSecretAnnotation: name.String()}
+
+		log.Info("Creating connection Secret")
+		if err := rdsKube.Create(ctx, secret); err != nil {
+			return false, fmt.Errorf("failed to create connection Secret %s: %w", secretName, err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read connection Secret %s: %w", secretName, err)
+	}
+
+	if owner := secret.Annotations[connectionSecretAnnotation]; owner != name.String() {
+		return false, fmt.Errorf("secret %s/%s exists and is not managed by Component %s", name.Namespace, secretName, name)
+	}
+	if maps.EqualFunc(secret.Data, data, bytes.Equal) {
+		return false, nil
+	}
+
+	log.Info("Updating connection Secret")
+	secret.Data = data
+	if err := rdsKube.Update(ctx, secret); err != nil {
+		return false, fmt.Errorf("failed to update connection Secret %s: %w", secretName, err)
+	}
+	return true, nil
+}
+
+// deleteConnectionSecret deletes the connection Secret written for the Component, if any
+func deleteConnectionSecret(ctx context.Context, name types.NamespacedName, secretName string) error {
+	if secretName == "" {
+		return nil
+	}
+
+	secret := &corev1.Secret{}
+	err := rdsKube.Get(ctx, types.NamespacedName{Namespace: name.Namespace, Name: secretName}, secret)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read connection Secret %s: %w", secretName, err)
+	}
+
+	// A Secret taken over by someone else since is left alone
+	if secret.Annotations[connectionSecretAnnotation] != name.String() {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Deleting connection Secret", "secret", secretName)
+	if err := rdsKube.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete connection Secret %s: %w", secretName, err)
+	}
+	return nil
+}
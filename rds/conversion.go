@@ -8,6 +8,8 @@
 package rds
 
 import (
+	"fmt"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rds/types"
 )
@@ -112,3 +114,27 @@ func endpointPort(endpoint *types.Endpoint) int32 {
 	}
 	return *endpoint.Port
 }
+
+// changed returns desired if it is set and differs from current, nil otherwise
+func changed[T comparable](current, desired *T) *T {
+	if desired == nil || (current != nil && *current == *desired) {
+		return nil
+	}
+	return desired
+}
+
+// firstSet returns the first pointer that is not nil
+func firstSet[T any](p, fallback *T) *T {
+	if p != nil {
+		return p
+	}
+	return fallback
+}
+
+// formatSet formats a set value for status, or returns nil for an unset one
+func formatSet[T any](p *T) *string {
+	if p == nil {
+		return nil
+	}
+	return stringPtr(fmt.Sprint(*p))
+}
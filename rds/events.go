@@ -0,0 +1,27 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/functional"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Package-level event recorder initialized during registration; nil discards events
+var rdsEvents *awsclient.EventRecorder
+
+// actionError records a Warning event for AWS errors that need attention and builds the action result
+func actionError(ctx context.Context, name types.NamespacedName, status RdsStatus, err error) (*functional.ActionResult[RdsStatus], error) {
+	rdsEvents.AWSError(ctx, name, err)
+	return functional.ActionResultForError(status, err, rdsErrorClassifier)
+}
+
+// checkError records a Warning event for AWS errors that need attention and builds the check result
+func checkError(ctx context.Context, name types.NamespacedName, status RdsStatus, err error) (*functional.CheckResult[RdsStatus], error) {
+	rdsEvents.AWSError(ctx, name, err)
+	return functional.CheckResultForError(status, err, rdsErrorClassifier)
+}
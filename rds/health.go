@@ -7,19 +7,28 @@ import (
 	"context"
 	"fmt"
 
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/controller"
 	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// parameterApplyPendingReboot is the parameter apply status of an instance whose
+// parameter group has changes that only take effect after a reboot
+const parameterApplyPendingReboot = "pending-reboot"
+
 // checkHealth performs runtime health monitoring of Ready RDS instances.
 // This is called periodically while the Component is in Ready state.
 //
 // Health evaluation focuses on operational status that affects database availability:
 //   - Healthy: instance is operational and accepting connections
-//   - Degraded: instance has operational issues (storage full, maintenance, stopped)
+//   - Degraded: instance has operational issues (storage full, maintenance, stopped),
+//     waits for a reboot to apply parameter group changes, or nears its storage limit
 //
 // Health checks do not trigger phase transitions - they only update the Degraded condition.
+// They do change AWS for operational instances, outside dry-run mode: due and requested manual
+// snapshots are taken and snapshots beyond the retention are deleted.
 func checkHealth(
 	ctx context.Context,
 	name types.NamespacedName,
@@ -29,16 +38,25 @@ func checkHealth(
 	instanceID := spec.InstanceID
 	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
 
+	// A dry-run Component does not manage the instance yet
+	if status.Plan != nil {
+		return controller.HealthCheckHealthy("Dry run: " + status.Plan.String())
+	}
+
+	client := rdsClients.Get(resolveAccess(spec, status))
+
 	// Query current RDS instance status
-	instance, err := getInstanceData(ctx, instanceID)
+	instance, err := getInstanceData(ctx, client, instanceID)
 	if err != nil {
 		// Classify error to determine if retryable or degraded
+		rdsEvents.AWSError(ctx, name, err)
 		return controller.HealthCheckResultForError(err, rdsErrorClassifier, "APIError")
 	}
 
 	// Instance not found - external deletion detected
 	if instance == nil {
 		log.Info("RDS instance not found during health check - may have been deleted externally")
+		instancesByStatus.Delete(name.String())
 		return controller.HealthCheckDegraded(
 			"InstanceDeleted",
 			fmt.Sprintf("RDS instance %s not found in AWS", instanceID))
@@ -46,6 +64,7 @@ func checkHealth(
 
 	instanceStatus := stringValue(instance.DBInstanceStatus)
 	log.V(1).Info("Checking RDS instance health", "status", instanceStatus)
+	instancesByStatus.Set(name.String(), instanceStatus)
 
 	// Evaluate health based on instance status
 	switch RDSInstanceStatus(instanceStatus) {
@@ -55,11 +74,38 @@ func checkHealth(
 		// Instance is operational and accepting connections
 		// These states don't prevent normal database operations
 		// - modifying: most changes don't cause downtime
+		// - pending-reboot: parameter changes wait for the next reboot
+		// Keep the connection Secret in sync with password rotations
+		if err := reconcileConnectionSecret(ctx, name, &spec, &status, instance); err != nil {
+			rdsEvents.AWSError(ctx, name, err)
+			return controller.HealthCheckResultForError(err, rdsErrorClassifier, "ConnectionSecretFailed")
+		}
+		// Take scheduled and requested snapshots and prune old ones. A health check cannot write
+		// status, so the list is left to the next apply check.
+		if _, err := reconcileSnapshots(ctx, name, client, &spec, instance); err != nil {
+			rdsEvents.AWSError(ctx, name, err)
+			return controller.HealthCheckResultForError(err, rdsErrorClassifier, "SnapshotFailed")
+		}
+		// Reported after the reconcilers above, which a pending reboot does not hold back
+		group := parameterGroup(instance)
+		if group != nil && stringValue(group.ParameterApplyStatus) == parameterApplyPendingReboot {
+			return controller.HealthCheckDegraded(
+				"PendingReboot",
+				fmt.Sprintf("Instance %s needs a reboot to apply parameter group %s",
+					instanceID, stringValue(group.DBParameterGroupName)))
+		}
+		if degraded, err := checkStorageHealth(ctx, name, spec, status, instance); degraded != nil || err != nil {
+			return degraded, err
+		}
+		if replicaSource(instance) != "" {
+			return checkReplicaHealth(ctx, name, spec, status, instance)
+		}
 		return controller.HealthCheckHealthy(
 			fmt.Sprintf("Instance %s is operational (status: %s)", instanceID, instanceStatus))
 
 	case StatusStorageFull:
 		// Instance storage capacity exhausted - connections may fail
+		rdsEvents.Warning(ctx, name, "StorageFull", "RDS instance %s storage capacity exhausted", instanceID)
 		return controller.HealthCheckDegraded(
 			"StorageFull",
 			fmt.Sprintf("Instance %s storage capacity exhausted", instanceID))
@@ -81,6 +127,7 @@ func checkHealth(
 		StatusIncompatibleParameters, StatusIncompatibleRestore,
 		StatusInsufficientCapacity:
 		// Instance in error state - not operational
+		rdsEvents.Warning(ctx, name, awsclient.ReasonFailed, "RDS instance %s in error state: %s", instanceID, instanceStatus)
 		return controller.HealthCheckDegraded(
 			"Failed",
 			fmt.Sprintf("Instance %s in error state: %s", instanceID, instanceStatus))
@@ -93,3 +140,75 @@ func checkHealth(
 			fmt.Sprintf("Instance %s in unknown state: %s", instanceID, instanceStatus))
 	}
 }
+
+// checkStorageHealth degrades an operational instance whose used storage nears the most it can
+// grow to, before it runs full. It returns nil while there is room or the usage is unknown.
+func checkStorageHealth(
+	ctx context.Context,
+	name types.NamespacedName,
+	spec RdsConfig,
+	status RdsStatus,
+	instance *rdstypes.DBInstance) (*controller.HealthCheckResult, error) {
+
+	instanceID := spec.InstanceID
+
+	used, ceiling, ok, err := storageUsage(ctx, resolveAccess(spec, status), instance)
+	if err != nil {
+		rdsEvents.AWSError(ctx, name, err)
+		return controller.HealthCheckResultForError(err, rdsErrorClassifier, "APIError")
+	}
+	if !ok || used < storageNearLimitRatio*float64(ceiling) {
+		return nil, nil
+	}
+
+	rdsEvents.Warning(ctx, name, "StorageNearLimit", "RDS instance %s uses %.0f of %d GiB storage", instanceID, used, ceiling)
+	return controller.HealthCheckDegraded(
+		"StorageNearLimit",
+		fmt.Sprintf("Instance %s uses %.0f GiB of its %d GiB storage limit", instanceID, used, ceiling))
+}
+
+// checkReplicaHealth checks that an operational read replica is replicating and keeps up with its source.
+// The replica degrades when replication broke or the lag exceeds maxReplicaLagSeconds.
+func checkReplicaHealth(
+	ctx context.Context,
+	name types.NamespacedName,
+	spec RdsConfig,
+	status RdsStatus,
+	instance *rdstypes.DBInstance) (*controller.HealthCheckResult, error) {
+
+	instanceID := spec.InstanceID
+	source := replicaSource(instance)
+
+	if err := resolveSpec(&spec); err != nil {
+		return controller.HealthCheckDegraded("InvalidConfig", fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	if info := replicationStatus(instance); replicationBroken(info) {
+		rdsEvents.Warning(ctx, name, "ReplicationBroken", "RDS read replica %s replication %s: %s",
+			instanceID, stringValue(info.Status), stringValue(info.Message))
+		return controller.HealthCheckDegraded(
+			"ReplicationBroken",
+			fmt.Sprintf("Replica %s replication from %s is %s", instanceID, source, stringValue(info.Status)))
+	}
+
+	lag, err := replicaLag(ctx, resolveAccess(spec, status), instanceID)
+	if err != nil {
+		rdsEvents.AWSError(ctx, name, err)
+		return controller.HealthCheckResultForError(err, rdsErrorClassifier, "APIError")
+	}
+	if lag == nil {
+		return controller.HealthCheckHealthy(
+			fmt.Sprintf("Replica %s of %s is operational (lag unknown)", instanceID, source))
+	}
+
+	maxLag := int64(int32Value(spec.MaxReplicaLagSeconds))
+	if *lag > maxLag {
+		rdsEvents.Warning(ctx, name, "ReplicationLag", "RDS read replica %s is %ds behind %s", instanceID, *lag, source)
+		return controller.HealthCheckDegraded(
+			"ReplicationLag",
+			fmt.Sprintf("Replica %s lag %ds exceeds %ds", instanceID, *lag, maxLag))
+	}
+
+	return controller.HealthCheckHealthy(
+		fmt.Sprintf("Replica %s of %s is operational (lag: %ds)", instanceID, source, *lag))
+}
@@ -0,0 +1,87 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// logTypesByEngine lists the CloudWatch log exports each engine supports.
+// Engines not listed are left to RDS to validate.
+var logTypesByEngine = map[string][]string{
+	"postgres": {"postgresql", "upgrade"},
+	"mysql":    {"audit", "error", "general", "slowquery"},
+	"mariadb":  {"audit", "error", "general", "slowquery"},
+}
+
+// validateLogExports checks the log exports against the engine. Replicas and restored
+// instances without an engine take the one of their source and are left to RDS.
+func validateLogExports(config *RdsConfig) error {
+	supported, known := logTypesByEngine[config.DatabaseEngine]
+
+	seen := make(map[string]bool, len(config.EnableCloudwatchLogsExports))
+	for _, logType := range config.EnableCloudwatchLogsExports {
+		if seen[logType] {
+			return fmt.Errorf("enableCloudwatchLogsExports lists %q twice", logType)
+		}
+		seen[logType] = true
+
+		if known && !slices.Contains(supported, logType) {
+			return fmt.Errorf("invalid enableCloudwatchLogsExports entry %q for engine %s: must be one of %s",
+				logType, config.DatabaseEngine, strings.Join(supported, ", "))
+		}
+	}
+	return nil
+}
+
+// enabledLogExports returns the log exports of the instance, counting pending changes as made
+func enabledLogExports(instance *types.DBInstance) []string {
+	enabled := slices.Clone(instance.EnabledCloudwatchLogsExports)
+
+	if instance.PendingModifiedValues != nil && instance.PendingModifiedValues.PendingCloudwatchLogsExports != nil {
+		pending := instance.PendingModifiedValues.PendingCloudwatchLogsExports
+		enabled = slices.DeleteFunc(enabled, func(logType string) bool {
+			return slices.Contains(pending.LogTypesToDisable, logType)
+		})
+		for _, logType := range pending.LogTypesToEnable {
+			if !slices.Contains(enabled, logType) {
+				enabled = append(enabled, logType)
+			}
+		}
+	}
+
+	slices.Sort(enabled)
+	return enabled
+}
+
+// logExportChanges returns the log exports to enable and disable on the instance, or nil if they
+// match the config. An unset enableCloudwatchLogsExports leaves the exports alone; an empty one
+// turns them all off.
+func logExportChanges(config *RdsConfig, instance *types.DBInstance) *types.CloudwatchLogsExportConfiguration {
+	if config.EnableCloudwatchLogsExports == nil {
+		return nil
+	}
+
+	enabled := enabledLogExports(instance)
+	changes := &types.CloudwatchLogsExportConfiguration{}
+	for _, logType := range config.EnableCloudwatchLogsExports {
+		if !slices.Contains(enabled, logType) {
+			changes.EnableLogTypes = append(changes.EnableLogTypes, logType)
+		}
+	}
+	for _, logType := range enabled {
+		if !slices.Contains(config.EnableCloudwatchLogsExports, logType) {
+			changes.DisableLogTypes = append(changes.DisableLogTypes, logType)
+		}
+	}
+
+	if len(changes.EnableLogTypes) == 0 && len(changes.DisableLogTypes) == 0 {
+		return nil
+	}
+	return changes
+}
@@ -0,0 +1,285 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	v1beta1 "github.com/rinswind/componator/api/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Kinds of manual snapshots the provider takes
+const (
+	SnapshotScheduled = "Scheduled"
+	SnapshotOnDemand  = "OnDemand"
+)
+
+// SnapshotAnnotation set on a Component requests an on-demand snapshot of its instance, e.g. before a
+// risky release. One snapshot is taken per distinct value, so a new value requests a new snapshot.
+const SnapshotAnnotation = "aws.componator.io/snapshot"
+
+// defaultSnapshotRetain is how many snapshots of each kind are kept when snapshots.retain is not set
+const defaultSnapshotRetain = 7
+
+// Tags marking the manual snapshots the provider manages, besides the ownership tags
+const (
+	tagSnapshotKind    = "componator.io/snapshot-kind"
+	tagSnapshotTime    = "componator.io/snapshot-time"
+	tagSnapshotRequest = "componator.io/snapshot-request"
+)
+
+// snapshotRequestPattern matches the annotation values RDS accepts as tag values
+var snapshotRequestPattern = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]{1,256}$`)
+
+// Package-level reader of the snapshot annotation initialized during registration with the
+// manager's cache, which already holds the Components. A nil reader ignores on-demand requests.
+var rdsComponents awsclient.ComponentReader
+
+// snapshotNow returns the time schedules are evaluated at; tests replace it to move through a schedule
+var snapshotNow = time.Now
+
+// validateSnapshots checks the schedule and the retention of the snapshots config
+func validateSnapshots(config *RdsConfig) error {
+	snapshots := config.Snapshots
+	if snapshots == nil {
+		return nil
+	}
+	if snapshots.Schedule != "" {
+		if _, err := parseSchedule(snapshots.Schedule); err != nil {
+			return fmt.Errorf("snapshots: %w", err)
+		}
+	}
+	if snapshots.Retain != nil && *snapshots.Retain < 1 {
+		return fmt.Errorf("snapshots.retain must be at least 1, got %d", *snapshots.Retain)
+	}
+	return nil
+}
+
+// reconcileSnapshots takes the manual snapshots the config asks for, deletes the ones beyond the
+// retention and returns the rest for status. Snapshots are only taken of an available instance, one
+// per call: an on-demand request first, then a due scheduled snapshot. A schedule that matched several
+// times while the instance was busy gets one snapshot, late. A Component in dry-run mode only lists its
+// snapshots. Without a snapshots config existing snapshots are left alone.
+//
+// Both the apply check and the health check call it, so that schedules are kept while the Component
+// is Ready; only the apply check can record the result in status.
+func reconcileSnapshots(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec *RdsConfig,
+	instance *rdstypes.DBInstance) ([]ManualSnapshot, error) {
+
+	if spec.Snapshots == nil {
+		return nil, nil
+	}
+
+	snapshots, err := managedSnapshots(ctx, name, client, spec.InstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Taking and pruning snapshots changes AWS
+	if dryRun, err := rdsDryRun.Enabled(ctx, name); err != nil || dryRun {
+		return snapshots, err
+	}
+
+	if RDSInstanceStatus(stringValue(instance.DBInstanceStatus)) == StatusAvailable {
+		now := snapshotNow().UTC()
+
+		request, err := snapshotRequest(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		requested := request != "" && !slices.ContainsFunc(snapshots, func(s ManualSnapshot) bool {
+			return s.Kind == SnapshotOnDemand && s.Request == request
+		})
+
+		var taken *ManualSnapshot
+		switch {
+		case requested:
+			taken, err = takeManualSnapshot(ctx, name, client, spec, SnapshotOnDemand, request, now)
+		case scheduleDue(spec.Snapshots.Schedule, snapshots, instance, now):
+			taken, err = takeManualSnapshot(ctx, name, client, spec, SnapshotScheduled, "", now)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if taken != nil {
+			snapshots = slices.Insert(snapshots, 0, *taken)
+		}
+	}
+
+	retain := int(cmp.Or(int32Value(spec.Snapshots.Retain), defaultSnapshotRetain))
+	return pruneSnapshots(ctx, name, client, spec.InstanceID, snapshots, retain)
+}
+
+// managedSnapshots returns the manual snapshots of the instance taken for the Component, newest first
+func managedSnapshots(ctx context.Context, name types.NamespacedName, client API, instanceID string) ([]ManualSnapshot, error) {
+	described, err := listManualSnapshots(ctx, client, instanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []ManualSnapshot
+	for _, snapshot := range described {
+		tags := fromRDSTags(snapshot.TagList)
+		kind := tags[tagSnapshotKind]
+		if (kind != SnapshotScheduled && kind != SnapshotOnDemand) || !rdsOwnership.Owns(name, tags) {
+			continue
+		}
+
+		taken := tags[tagSnapshotTime]
+		if _, err := time.Parse(time.RFC3339, taken); err != nil && snapshot.SnapshotCreateTime != nil {
+			taken = snapshot.SnapshotCreateTime.UTC().Format(time.RFC3339)
+		}
+		snapshots = append(snapshots, ManualSnapshot{
+			Identifier: stringValue(snapshot.DBSnapshotIdentifier),
+			Kind:       kind,
+			Request:    tags[tagSnapshotRequest],
+			Time:       taken,
+			Status:     stringValue(snapshot.Status),
+		})
+	}
+
+	// RFC 3339 times in UTC sort as strings
+	slices.SortFunc(snapshots, func(a, b ManualSnapshot) int {
+		return cmp.Or(cmp.Compare(b.Time, a.Time), cmp.Compare(b.Identifier, a.Identifier))
+	})
+	return snapshots, nil
+}
+
+// snapshotRequest returns the on-demand snapshot requested with the SnapshotAnnotation, or "" for none.
+// An invalid request is reported and ignored.
+func snapshotRequest(ctx context.Context, name types.NamespacedName) (string, error) {
+	if rdsComponents == nil {
+		return "", nil
+	}
+
+	component := &v1beta1.Component{}
+	if err := rdsComponents.Get(ctx, name, component); err != nil {
+		return "", fmt.Errorf("failed to read %s annotation: %w", SnapshotAnnotation, err)
+	}
+
+	request := component.Annotations[SnapshotAnnotation]
+	if request != "" && !snapshotRequestPattern.MatchString(request) {
+		rdsEvents.Warning(ctx, name, "InvalidSnapshotRequest",
+			"Ignoring %s annotation %q: use up to 256 letters, digits, spaces and _.:/=+-@", SnapshotAnnotation, request)
+		return "", nil
+	}
+	return request, nil
+}
+
+// scheduleDue reports whether the schedule matched since the latest scheduled snapshot, or since the
+// instance was created if there is none yet
+func scheduleDue(expr string, snapshots []ManualSnapshot, instance *rdstypes.DBInstance, now time.Time) bool {
+	if expr == "" {
+		return false
+	}
+	sched, err := parseSchedule(expr)
+	if err != nil {
+		return false
+	}
+
+	var last time.Time
+	if instance.InstanceCreateTime != nil {
+		last = *instance.InstanceCreateTime
+	}
+	if i := slices.IndexFunc(snapshots, func(s ManualSnapshot) bool { return s.Kind == SnapshotScheduled }); i >= 0 {
+		if taken, err := time.Parse(time.RFC3339, snapshots[i].Time); err == nil {
+			last = taken
+		}
+	}
+
+	next := sched.next(last)
+	return !next.IsZero() && !next.After(now)
+}
+
+// takeManualSnapshot starts a snapshot of the instance tagged with its kind and time, and with the
+// config and ownership tags
+func takeManualSnapshot(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec *RdsConfig,
+	kind, request string,
+	now time.Time) (*ManualSnapshot, error) {
+
+	suffix := "scheduled"
+	if kind == SnapshotOnDemand {
+		suffix = "ondemand"
+	}
+	snapshotID := fmt.Sprintf("%s-%s-%s", spec.InstanceID, suffix, now.Format("20060102-150405"))
+
+	tags := maps.Clone(spec.Tags)
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	maps.Copy(tags, rdsOwnership.Tags(name))
+	tags[tagSnapshotKind] = kind
+	tags[tagSnapshotTime] = now.Format(time.RFC3339)
+	if request != "" {
+		tags[tagSnapshotRequest] = request
+	}
+
+	snapshot, err := createSnapshot(ctx, client, spec.InstanceID, snapshotID, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if request != "" {
+		rdsEvents.Normal(ctx, name, awsclient.ReasonCreated, "Creating RDS snapshot %s of instance %s for request %q",
+			snapshotID, spec.InstanceID, request)
+	} else {
+		rdsEvents.Normal(ctx, name, awsclient.ReasonCreated, "Creating scheduled RDS snapshot %s of instance %s",
+			snapshotID, spec.InstanceID)
+	}
+
+	return &ManualSnapshot{
+		Identifier: snapshotID,
+		Kind:       kind,
+		Request:    request,
+		Time:       tags[tagSnapshotTime],
+		Status:     stringValue(snapshot.Status),
+	}, nil
+}
+
+// pruneSnapshots deletes the available snapshots of each kind beyond the newest retain ones and
+// returns the others. Snapshots still being created are deleted once they are available.
+func pruneSnapshots(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	instanceID string,
+	snapshots []ManualSnapshot,
+	retain int) ([]ManualSnapshot, error) {
+
+	var kept []ManualSnapshot
+	counts := make(map[string]int)
+	for _, snapshot := range snapshots {
+		counts[snapshot.Kind]++
+		if counts[snapshot.Kind] <= retain || snapshot.Status != snapshotAvailable {
+			kept = append(kept, snapshot)
+			continue
+		}
+
+		logf.FromContext(ctx).Info("Pruning RDS snapshot", "snapshotId", snapshot.Identifier, "kind", snapshot.Kind, "retain", retain)
+		if err := deleteSnapshot(ctx, client, snapshot.Identifier); err != nil {
+			return nil, err
+		}
+		rdsEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleted RDS snapshot %s of instance %s, keeping the newest %d",
+			snapshot.Identifier, instanceID, retain)
+	}
+	return kept, nil
+}
@@ -0,0 +1,17 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// instancesByStatus counts managed RDS instances by their last observed InstanceStatus
+var instancesByStatus = awsclient.NewStateGauge(
+	"rds_instances", "RDS instances managed by Components, by instance status.", "status")
+
+func init() {
+	metrics.Registry.MustRegister(instancesByStatus)
+}
@@ -5,8 +5,11 @@ package rds
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/functional"
 	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -24,39 +27,186 @@ func applyAction(
 		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
 	}
 
+	// The instance cannot follow a region or account change
+	access := rdsClients.Resolve(spec.AccessConfig)
+	if err := access.CheckUnchanged(status.Access); err != nil {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	dryRun, err := rdsDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+
 	instanceID := spec.InstanceID
 
 	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
-	log.Info("Starting RDS deployment")
+	log.Info("Starting RDS deployment", "region", access.Region, "dryRun", dryRun)
+
+	client := rdsClients.Get(access)
+
+	// A replacement in progress is driven by checkApplied, and the instance identifier may be free meanwhile
+	if status.Replacement != nil {
+		details := fmt.Sprintf("Replacing RDS instance %s (%s)", instanceID, status.Replacement.Stage)
+		return functional.ActionSuccess(status, details)
+	}
+	if status.BlueGreen != nil {
+		details := fmt.Sprintf("Upgrading RDS instance %s with blue/green deployment (%s)", instanceID, status.BlueGreen.Stage)
+		return functional.ActionSuccess(status, details)
+	}
 
 	// Check if the instance exists
-	instance, err := getInstanceData(ctx, instanceID)
+	instance, err := getInstanceData(ctx, client, instanceID)
 	if err != nil {
-		return functional.ActionResultForError(status, fmt.Errorf("failed to check RDS instance existence: %w", err), rdsErrorClassifier)
+		return actionError(ctx, name, status, fmt.Errorf("failed to check RDS instance existence: %w", err))
+	}
+
+	// Only modify an existing instance owned by this Component, or one the adoption policy allows taking over
+	adopt := false
+	if instance != nil {
+		instanceArn := stringValue(instance.DBInstanceArn)
+		adopt, err = rdsOwnership.CheckAdoption(name, fromRDSTags(instance.TagList), adoptionPolicy(spec, status, instanceArn))
+		if err != nil {
+			rdsEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused, "Not managing RDS instance %s: %v", instanceID, err)
+			return functional.ActionFailure(status, fmt.Sprintf("cannot manage RDS instance %s: %v", instanceID, err))
+		}
+		if err := checkReplication(&spec, instance); err != nil {
+			return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+		}
+	}
+
+	// Catch settings RDS does not offer before they fail halfway through creating or modifying the instance
+	if err := resolveOffering(ctx, client, access, &spec, instance); err != nil {
+		var offering *offeringError
+		if errors.As(err, &offering) {
+			return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+		}
+		return actionError(ctx, name, status, err)
+	}
+
+	// In dry-run mode only record what would change
+	if dryRun {
+		status.Plan = planInstance(&spec, instance)
+		if adopt {
+			status.Plan.Add("ownership", name.String())
+		}
+		planTags(status.Plan, instance, spec.Tags, status.TagKeys)
+		planConnectionSecret(status.Plan, &spec, status)
+		log.Info("Dry run, not applying changes", "plan", status.Plan.String())
+		return functional.ActionSuccess(status, "Dry run: "+status.Plan.String())
 	}
+	status.Plan = nil
 
 	if instance != nil {
 		log.Info("RDS instance exists, modifying existing instance")
-		instance, err = modifyInstance(ctx, &spec)
+
+		// Settings fixed at creation can only change by replacing the instance
+		if changes := immutableChanges(&spec, instance); len(changes) > 0 {
+			return replaceInstance(ctx, name, client, spec, status, changes)
+		}
+
+		if adopt {
+			if err := tagInstance(ctx, client, stringValue(instance.DBInstanceArn), rdsOwnership.Tags(name)); err != nil {
+				return actionError(ctx, name, status, err)
+			}
+			rdsEvents.Normal(ctx, name, awsclient.ReasonAdopted, "Adopted RDS instance %s", instanceID)
+		}
+
+		retagged, err := reconcileTags(ctx, client, instance, spec.Tags, status.TagKeys)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		status.TagKeys = tagKeys(spec.Tags)
+
+		// A major upgrade with the BlueGreen strategy goes through a blue/green deployment instead
+		if target := blueGreenTarget(&spec, instance); target != "" {
+			return upgradeBlueGreen(ctx, name, client, spec, status, instance, target)
+		}
+
+		inputs := buildModifyInputs(&spec, instance)
+		if len(inputs) == 0 {
+			updateStatusFromInstance(&status, instance)
+			status.Access = &access
+			instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+			if retagged {
+				rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Updated tags of RDS instance %s", instanceID)
+				return functional.ActionSuccess(status, fmt.Sprintf("Updated tags of RDS instance %s", instanceID))
+			}
+
+			log.Info("RDS instance matches config, nothing to modify")
+			return functional.ActionSuccess(status, fmt.Sprintf("RDS instance %s up to date", instanceID))
+		}
+
+		instance, err = modifyInstance(ctx, client, inputs)
 		if err != nil {
-			return functional.ActionResultForError(status, err, rdsErrorClassifier)
+			return actionError(ctx, name, status, err)
 		}
 
 		// Update status with modification information
 		updateStatusFromInstance(&status, instance)
+		status.Access = &access
+		instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+		rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Modifying RDS instance %s (%s)", instanceID, spec.InstanceClass)
 
 		details := fmt.Sprintf("Modifying RDS instance %s (%s)", instanceID, spec.InstanceClass)
+		if len(status.PendingModifications) > 0 {
+			details += ", some changes pending for the maintenance window"
+		}
+		return functional.ActionSuccess(status, details)
+	}
+
+	if spec.isReplica() {
+		log.Info("RDS instance does not exist, creating read replica")
+		instance, err = createReplica(ctx, client, &spec, access.Region, rdsOwnership.WithTags(name, spec.Tags))
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+
+		updateStatusFromInstance(&status, instance)
+		status.Access = &access
+		status.TagKeys = tagKeys(spec.Tags)
+		instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+		rdsEvents.Normal(ctx, name, awsclient.ReasonCreated, "Creating RDS read replica %s of %s (%s)",
+			instanceID, spec.ReplicaSourceIdentifier, spec.InstanceClass)
+
+		details := fmt.Sprintf("Creating RDS read replica %s of %s (%s)", instanceID, spec.ReplicaSourceIdentifier, spec.InstanceClass)
+		return functional.ActionSuccess(status, details)
+	}
+
+	if spec.isRestore() {
+		log.Info("RDS instance does not exist, restoring it", "source", spec.RestoreFrom.String())
+		instance, err = restoreInstance(ctx, client, &spec, rdsOwnership.WithTags(name, spec.Tags))
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+
+		updateStatusFromInstance(&status, instance)
+		status.Access = &access
+		status.TagKeys = tagKeys(spec.Tags)
+		status.RestoredFrom = spec.RestoreFrom.String()
+		status.RestoreModifyPending = true
+		instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+		rdsEvents.Normal(ctx, name, awsclient.ReasonCreated, "Restoring RDS instance %s from %s", instanceID, status.RestoredFrom)
+
+		details := fmt.Sprintf("Restoring RDS instance %s from %s", instanceID, status.RestoredFrom)
 		return functional.ActionSuccess(status, details)
 	}
 
 	log.Info("RDS instance does not exist, creating new instance")
-	instance, err = createInstance(ctx, &spec)
+	instance, err = createInstance(ctx, client, &spec, rdsOwnership.WithTags(name, spec.Tags))
 	if err != nil {
-		return functional.ActionResultForError(status, err, rdsErrorClassifier)
+		return actionError(ctx, name, status, err)
 	}
 
 	// Update status with deployment information
 	updateStatusFromInstance(&status, instance)
+	status.Access = &access
+	status.TagKeys = tagKeys(spec.Tags)
+	instancesByStatus.Set(name.String(), status.InstanceStatus)
 
 	// Capture managed password secret ARN from RDS response
 	// AWS RDS guarantees this is present when ManageMasterUserPassword=true
@@ -65,6 +215,8 @@ func applyAction(
 		log.Info("Captured RDS managed password secret ARN", "secretArn", status.MasterUserSecretArn)
 	}
 
+	rdsEvents.Normal(ctx, name, awsclient.ReasonCreated, "Creating RDS instance %s (%s)", instanceID, spec.InstanceClass)
+
 	details := fmt.Sprintf("Creating RDS instance %s (%s)", instanceID, spec.InstanceClass)
 	return functional.ActionSuccess(status, details)
 }
@@ -81,19 +233,36 @@ func checkApplied(
 	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
 	log.Info("Checking RDS deployment status")
 
+	// A dry-run apply changed nothing. A resource that was never created is not ready.
+	if status.Plan != nil {
+		if status.Plan.Action == awsclient.PlanCreate {
+			return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+		}
+		return functional.CheckComplete(status, "Dry run: "+status.Plan.String())
+	}
+
+	client := rdsClients.Get(resolveAccess(spec, status))
+
+	if status.Replacement != nil {
+		return checkReplacement(ctx, name, client, spec, status)
+	}
+	if status.BlueGreen != nil {
+		return checkBlueGreen(ctx, name, client, spec, status)
+	}
+
 	// Query RDS instance status
-	instance, err := getInstanceData(ctx, instanceID)
+	instance, err := getInstanceData(ctx, client, instanceID)
 	if err != nil {
-		return functional.CheckResultForError(status, fmt.Errorf("failed to describe RDS instance: %w", err), rdsErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("failed to describe RDS instance: %w", err))
 	}
 
 	if instance == nil {
-		return functional.CheckResultForError(status,
-			fmt.Errorf("RDS instance %s not found during deployment check", instanceID), rdsErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("RDS instance %s not found during deployment check", instanceID))
 	}
 
 	// Update status with current instance information
 	updateStatusFromInstance(&status, instance)
+	instancesByStatus.Set(name.String(), status.InstanceStatus)
 
 	// Check if deployment is complete
 	log = log.WithValues("status", status.InstanceStatus)
@@ -107,6 +276,19 @@ func checkApplied(
 		// - storage-optimization: post-creation optimization, DB fully functional
 		// - backing-up: automated backups don't block connections
 		// - configuring-*: enabling features doesn't require downtime
+		if err := reconcileConnectionSecret(ctx, name, &spec, &status, instance); err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if status.Snapshots, err = reconcileSnapshots(ctx, name, client, &spec, instance); err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if status.ReplicaSource != "" {
+			return checkReplicaApplied(ctx, name, client, spec, status)
+		}
+		if status.RestoreModifyPending {
+			return checkRestoreApplied(ctx, name, client, spec, status, instance)
+		}
+
 		log.Info("RDS instance deployment completed successfully",
 			"endpoint", status.Endpoint,
 			"port", status.Port)
@@ -141,8 +323,8 @@ func checkApplied(
 		// Failed states or problematic states during deployment
 		// stopped/stopping should not occur during normal deployment
 		// storage-full during deployment indicates provisioning issue
-		return functional.CheckResultForError(status,
-			fmt.Errorf("RDS instance deployment failed with status: %s", status.InstanceStatus), rdsErrorClassifier)
+		rdsEvents.Warning(ctx, name, awsclient.ReasonFailed, "RDS instance %s is %s", instanceID, status.InstanceStatus)
+		return checkError(ctx, name, status, fmt.Errorf("RDS instance deployment failed with status: %s", status.InstanceStatus))
 
 	default:
 		// Unknown status - continue checking to be safe
@@ -163,24 +345,137 @@ func deleteAction(
 
 	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
 
-	instance, err := deleteInstance(ctx, &spec)
+	retain := spec.DeletionPolicy == awsclient.DeletionPolicyRetain
+
+	dryRun, err := rdsDryRun.Enabled(ctx, name)
 	if err != nil {
-		return functional.ActionResultForError(status, err, rdsErrorClassifier)
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+	if dryRun {
+		status.Plan = awsclient.NewDeletePlan(instanceID)
+		if retain {
+			status.Plan = awsclient.NewRetainPlan(instanceID)
+		}
+		log.Info("Dry run, holding back deletion of instance", "plan", status.Plan.String())
+		return functional.ActionResultForError(status, awsclient.DeletionHeld(status.Plan), awsclient.RetryDryRun)
+	}
+
+	client := rdsClients.Get(resolveAccess(spec, status))
+
+	// The connection Secret goes with the Component, even if the instance is retained
+	if err := deleteConnectionSecret(ctx, name, status.ConnectionSecretName); err != nil {
+		return actionError(ctx, name, status, err)
+	}
+	status.ConnectionSecretName = ""
+
+	if status.BlueGreen != nil {
+		if err := abandonBlueGreen(ctx, client, &spec, status.BlueGreen); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		status.BlueGreen = nil
+	}
+
+	if retain {
+		if status.Replacement != nil {
+			return rollBackReplacement(ctx, name, client, spec, status)
+		}
+		return retainInstance(ctx, name, client, instanceID, status)
+	}
+
+	// An unfinished replacement leaves instances under its temporary identifiers
+	if replacement := status.Replacement; replacement != nil {
+		for _, id := range []string{replacement.ReplacementIdentifier, replacement.RetiredIdentifier} {
+			if err := deleteRetiredInstance(ctx, client, &spec, id); err != nil {
+				return actionError(ctx, name, status, err)
+			}
+		}
+	}
+
+	// Only delete the instance this Component owns or recorded, never one it refused to adopt
+	instance, err := getInstanceData(ctx, client, instanceID)
+	if err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to check RDS instance existence: %w", err))
+	}
+	if instance != nil && !ownsInstance(name, status, instance) {
+		log.Info("RDS instance is not owned by this Component, leaving it in place")
+		rdsEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused, "Not deleting RDS instance %s: it is not owned by this Component", instanceID)
+		instancesByStatus.Delete(name.String())
+		details := fmt.Sprintf("RDS instance %s is not owned by this Component, left in place", instanceID)
+		return functional.ActionSuccess(status, details)
+	}
+
+	if instance != nil {
+		instance, err = deleteInstance(ctx, client, &spec)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
 	}
 
 	if instance == nil {
 		// Already deleted
 		log.Info("RDS instance already deleted")
+		instancesByStatus.Delete(name.String())
 		return functional.ActionSuccess(status, "RDS instance already deleted")
 	}
 
 	// Update status with AWS response data
 	updateStatusFromInstance(&status, instance)
+	instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+	rdsEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleting RDS instance %s", instanceID)
 
 	details := fmt.Sprintf("Deleting RDS instance %s", instanceID)
 	return functional.ActionSuccess(status, details)
 }
 
+// ownsInstance reports whether the instance carries the ownership tags of the Component,
+// or is the one recorded in its status
+func ownsInstance(name types.NamespacedName, status RdsStatus, instance *rdstypes.DBInstance) bool {
+	if rdsOwnership.Owns(name, fromRDSTags(instance.TagList)) {
+		return true
+	}
+	return status.InstanceARN != "" && status.InstanceARN == stringValue(instance.DBInstanceArn)
+}
+
+// retainInstance keeps the instance in AWS and removes the ownership tags,
+// so that another Component can adopt it
+func retainInstance(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	instanceID string,
+	status RdsStatus) (*functional.ActionResult[RdsStatus], error) {
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+
+	instance, err := getInstanceData(ctx, client, instanceID)
+	if err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to check RDS instance existence: %w", err))
+	}
+
+	if err := releaseInstance(ctx, name, client, instance); err != nil {
+		return actionError(ctx, name, status, err)
+	}
+
+	log.Info("DeletionPolicy is Retain, leaving RDS instance in place")
+	rdsEvents.Normal(ctx, name, awsclient.ReasonRetained, "Retained RDS instance %s", instanceID)
+
+	details := fmt.Sprintf("RDS instance %s retained (Retain policy)", instanceID)
+	return functional.ActionSuccess(status, details)
+}
+
+// releaseInstance removes the ownership tags of a retained instance. Tags of an instance adopted by
+// another Component since are left alone.
+func releaseInstance(ctx context.Context, name types.NamespacedName, client API, instance *rdstypes.DBInstance) error {
+	if instance != nil && rdsOwnership.Owns(name, fromRDSTags(instance.TagList)) {
+		if err := untagInstance(ctx, client, stringValue(instance.DBInstanceArn), rdsOwnership.TagKeys()); err != nil {
+			return err
+		}
+	}
+	instancesByStatus.Delete(name.String())
+	return nil
+}
+
 // checkDeleted verifies the current deletion status
 func checkDeleted(
 	ctx context.Context,
@@ -193,25 +488,46 @@ func checkDeleted(
 	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
 	log.Info("Checking RDS deleted")
 
+	// A dry-run delete is held back until dry run is turned off
+	if status.Plan != nil && (status.Plan.Action == awsclient.PlanDelete || status.Plan.Action == awsclient.PlanRetain) {
+		return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+	}
+
+	// A retained instance stays in AWS, once an unfinished replacement is resolved
+	if spec.DeletionPolicy == awsclient.DeletionPolicyRetain {
+		if status.Replacement != nil {
+			return checkRetainedReplacement(ctx, name, rdsClients.Get(resolveAccess(spec, status)), spec, status)
+		}
+		return functional.CheckComplete(status, fmt.Sprintf("Instance %s retained", instanceID))
+	}
+
+	client := rdsClients.Get(resolveAccess(spec, status))
+
 	// Query RDS instance existence
-	instance, err := getInstanceData(ctx, instanceID)
+	instance, err := getInstanceData(ctx, client, instanceID)
 	if err != nil {
-		return functional.CheckResultForError(status,
-			fmt.Errorf("failed to describe RDS instance during deletion check: %w", err), rdsErrorClassifier)
+		return checkError(ctx, name, status, fmt.Errorf("failed to describe RDS instance during deletion check: %w", err))
 	}
 
 	if instance == nil {
 		log.Info("RDS instance successfully deleted")
+		instancesByStatus.Delete(name.String())
 		details := fmt.Sprintf("Instance %s deleted", instanceID)
 		return functional.CheckComplete(status, details)
 	}
 
+	// An instance the Component does not own was left in place by deleteAction
+	if !ownsInstance(name, status, instance) {
+		return functional.CheckComplete(status, fmt.Sprintf("Instance %s left in place", instanceID))
+	}
+
 	instanceStatus := stringValue(instance.DBInstanceStatus)
 
 	log = log.WithValues("status", instanceStatus)
 
 	// Update status with current instance information
 	status.InstanceStatus = instanceStatus
+	instancesByStatus.Set(name.String(), instanceStatus)
 
 	log.Info("RDS instance still exists, checking deletion status")
 
@@ -233,3 +549,89 @@ func checkDeleted(
 		return functional.CheckInProgress(status, "")
 	}
 }
+
+// checkReplicaApplied completes the deployment of an available read replica.
+// A replica configured for promotion is promoted first; otherwise its replication lag is recorded.
+func checkReplicaApplied(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec RdsConfig,
+	status RdsStatus) (*functional.CheckResult[RdsStatus], error) {
+
+	instanceID := spec.InstanceID
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+
+	if err := resolveSpec(&spec); err != nil {
+		return checkError(ctx, name, status, fmt.Errorf("config validation failed: %w", err))
+	}
+
+	if spec.PromoteReplica {
+		instance, err := promoteReplica(ctx, client, &spec)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		source := status.ReplicaSource
+		updateStatusFromInstance(&status, instance)
+		instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+		rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Promoting RDS read replica %s (source %s)", instanceID, source)
+
+		details := fmt.Sprintf("Promoting replica %s (source %s)", instanceID, source)
+		return functional.CheckInProgress(status, details)
+	}
+
+	// Lag is informational here; the health check compares it with the threshold
+	lag, err := replicaLag(ctx, resolveAccess(spec, status), instanceID)
+	if err != nil {
+		log.Error(err, "Failed to read replica lag")
+	}
+	status.ReplicaLagSeconds = lag
+
+	log.Info("RDS read replica deployment completed successfully",
+		"endpoint", status.Endpoint,
+		"port", status.Port,
+		"source", status.ReplicaSource)
+
+	details := fmt.Sprintf("Replica %s of %s available at %s:%d", instanceID, status.ReplicaSource, status.Endpoint, status.Port)
+	return functional.CheckComplete(status, details)
+}
+
+// checkRestoreApplied applies the settings the restore APIs do not accept,
+// like the backup retention, once the restored instance is available
+func checkRestoreApplied(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec RdsConfig,
+	status RdsStatus,
+	instance *rdstypes.DBInstance) (*functional.CheckResult[RdsStatus], error) {
+
+	instanceID := spec.InstanceID
+
+	if err := resolveSpec(&spec); err != nil {
+		return checkError(ctx, name, status, fmt.Errorf("config validation failed: %w", err))
+	}
+	pinEngineVersion(&spec, instance)
+
+	inputs := buildModifyInputs(&spec, instance)
+	if len(inputs) == 0 {
+		status.RestoreModifyPending = false
+		details := fmt.Sprintf("Instance %s available at %s:%d", instanceID, status.Endpoint, status.Port)
+		return functional.CheckComplete(status, details)
+	}
+
+	instance, err := modifyInstance(ctx, client, inputs)
+	if err != nil {
+		return checkError(ctx, name, status, err)
+	}
+	updateStatusFromInstance(&status, instance)
+	status.RestoreModifyPending = false
+	instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+	rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Configuring RDS instance %s restored from %s", instanceID, status.RestoredFrom)
+
+	details := fmt.Sprintf("Configuring instance %s restored from %s", instanceID, status.RestoredFrom)
+	return functional.CheckInProgress(status, details)
+}
@@ -0,0 +1,1233 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/rinswind/componator/componentkit/controller"
+	"github.com/rinswind/componator/componentkit/functional"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator-aws-providers/awsfake"
+)
+
+var _ = Describe("RDS Operations", func() {
+	var (
+		ctx     context.Context
+		fake    *awsfake.RDS
+		metrics *awsfake.CloudWatch
+		name    types.NamespacedName
+		spec    RdsConfig
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = awsfake.NewRDS()
+		rdsClients = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceRDS, func(aws.Config) API { return fake })
+		metrics = awsfake.NewCloudWatch()
+		rdsCatalog = newCatalog()
+		rdsMetrics = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceCloudWatch,
+			func(aws.Config) MetricsAPI { return metrics })
+
+		name = types.NamespacedName{Namespace: "default", Name: "test-db"}
+		spec = RdsConfig{
+			InstanceID:         "test-db",
+			DatabaseEngine:     "postgres",
+			EngineVersion:      "16.3",
+			InstanceClass:      "db.t3.micro",
+			DatabaseName:       "app",
+			AllocatedStorage:   20,
+			MasterUsername:     "admin",
+			DeletionProtection: aws.Bool(false),
+			SkipFinalSnapshot:  aws.Bool(true),
+		}
+	})
+
+	// appliedStatus is the status checkApplied derives from the fake's current instance
+	appliedStatus := func() RdsStatus {
+		var status RdsStatus
+		updateStatusFromInstance(&status, fake.Instance("test-db"))
+		return status
+	}
+
+	It("should create, modify and delete an instance", func() {
+		By("creating the instance")
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		instance := fake.Instance("test-db")
+		Expect(instance).NotTo(BeNil())
+		Expect(aws.ToString(instance.DBInstanceStatus)).To(Equal(string(StatusCreating)))
+		Expect(aws.ToString(instance.StorageType)).To(Equal("gp3"))
+		Expect(instance.MasterUserSecret).NotTo(BeNil())
+
+		By("waiting for the instance to become available")
+		checked, err := checkApplied(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		inProgress, _ := functional.CheckInProgress(appliedStatus(), "Instance test-db status: creating")
+		Expect(checked).To(Equal(inProgress))
+
+		fake.Advance()
+		checked, err = checkApplied(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		status := appliedStatus()
+		Expect(status.InstanceStatus).To(Equal(string(StatusAvailable)))
+		complete, _ := functional.CheckComplete(status,
+			fmt.Sprintf("Instance test-db available at %s:%d", status.Endpoint, status.Port))
+		Expect(checked).To(Equal(complete))
+
+		By("modifying the existing instance")
+		spec.InstanceClass = "db.t3.small"
+		_, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("CreateDBInstance")).To(Equal(1))
+		Expect(fake.CallCount("ModifyDBInstance")).To(Equal(1))
+
+		instance = fake.Instance("test-db")
+		Expect(aws.ToString(instance.DBInstanceClass)).To(Equal("db.t3.small"))
+		Expect(aws.ToString(instance.DBInstanceStatus)).To(Equal(string(StatusModifying)))
+		fake.Advance()
+
+		By("deleting the instance")
+		_, err = deleteAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(aws.ToString(fake.Instance("test-db").DBInstanceStatus)).To(Equal(string(StatusDeleting)))
+
+		checked, err = checkDeleted(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		inProgress, _ = functional.CheckInProgress(
+			RdsStatus{InstanceStatus: string(StatusDeleting)}, "Waiting for instance test-db deletion")
+		Expect(checked).To(Equal(inProgress))
+
+		fake.Advance()
+		Expect(fake.Instance("test-db")).To(BeNil())
+		checked, err = checkDeleted(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		complete, _ = functional.CheckComplete(RdsStatus{}, "Instance test-db deleted")
+		Expect(checked).To(Equal(complete))
+	})
+
+	It("should only send changed settings and defer them to the maintenance window when asked", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		By("leaving an unchanged instance alone")
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db up to date"))
+		Expect(fake.CallCount("ModifyDBInstance")).To(BeZero())
+
+		By("deferring the instance class change")
+		spec.InstanceClass = "db.t3.large"
+		spec.BackupRetentionPeriod = aws.Int32(14)
+		spec.ApplyChanges.InstanceClass = ApplyDuringMaintenanceWindow
+		result, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("ModifyDBInstance")).To(Equal(2))
+		Expect(result.Status.PendingModifications).To(Equal(map[string]string{"instanceClass": "db.t3.large"}))
+		Expect(result.Details).To(HaveSuffix(", some changes pending for the maintenance window"))
+
+		fake.Advance()
+		instance := fake.Instance("test-db")
+		Expect(aws.ToString(instance.DBInstanceClass)).To(Equal("db.t3.micro"))
+		Expect(aws.ToInt32(instance.BackupRetentionPeriod)).To(Equal(int32(14)))
+
+		By("not sending the pending change again")
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("ModifyDBInstance")).To(Equal(2))
+		Expect(result.Status.PendingModifications).To(HaveKeyWithValue("instanceClass", "db.t3.large"))
+		Expect(planInstance(&spec, fake.Instance("test-db")).Changes).To(BeEmpty())
+
+		By("clearing the pending change once the maintenance window applied it")
+		fake.ApplyPendingModifications("test-db")
+		Expect(appliedStatus().PendingModifications).To(BeNil())
+		Expect(aws.ToString(fake.Instance("test-db").DBInstanceClass)).To(Equal("db.t3.large"))
+	})
+
+	It("should not replace an unencrypted instance when storageEncrypted is unset", func() {
+		spec.StorageEncrypted = aws.Bool(false)
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		Expect(fake.Instance("test-db").StorageEncrypted).To(HaveValue(BeFalse()))
+
+		spec.StorageEncrypted = nil
+		spec.ReplacementPolicy = ReplacementSnapshotRestore
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db up to date"))
+		Expect(result.Status.Replacement).To(BeNil())
+		Expect(fake.CallCount("CreateDBSnapshot")).To(BeZero())
+		Expect(planInstance(&spec, fake.Instance("test-db")).Changes).NotTo(ContainElement(HaveField("Field", "storageEncrypted")))
+	})
+
+	It("should refuse immutable changes unless the instance may be replaced", func() {
+		spec.SubnetGroupName = "app-a"
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		By("failing on changes ModifyDBInstance cannot make")
+		spec.SubnetGroupName = "app-b"
+		spec.DatabaseName = "billing"
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring(`cannot change databaseName ("app" to "billing"), subnetGroupName ("app-a" to "app-b")`))
+		Expect(result.Details).To(ContainSubstring("set replacementPolicy to SnapshotRestore"))
+		Expect(fake.CallCount("ModifyDBInstance")).To(BeZero())
+
+		By("planning them in dry-run mode")
+		plan := planInstance(&spec, fake.Instance("test-db"))
+		Expect(plan.Changes).To(ContainElement(awsclient.Change{
+			Field: "subnetGroupName", Action: awsclient.ChangeModify, Current: "app-a", Desired: "app-b"}))
+
+		By("failing on changes a replacement cannot make either")
+		spec.ReplacementPolicy = ReplacementSnapshotRestore
+		result, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring(`cannot change databaseName ("app" to "billing") of existing RDS instance test-db, not even`))
+		Expect(fake.CallCount("CreateDBSnapshot")).To(BeZero())
+
+		By("replacing the instance from a snapshot")
+		clock := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		snapshotNow = func() time.Time { return clock }
+		DeferCleanup(func() { snapshotNow = time.Now })
+		spec.DatabaseName = "app"
+		spec.SkipFinalSnapshot = aws.Bool(false)
+		spec.FinalDBSnapshotIdentifier = "test-db-final"
+		result, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Replacement.Changes).To(Equal([]string{"subnetGroupName"}))
+		Expect(fake.CallCount("CreateDBSnapshot")).To(Equal(1))
+		snapshotID := result.Status.Replacement.SnapshotIdentifier
+
+		var stages []string
+		checked, err := checkApplied(ctx, name, spec, result.Status)
+		for i := 0; i < 10 && err == nil && checked.Status.Replacement != nil; i++ {
+			stages = append(stages, checked.Status.Replacement.Stage)
+			fake.Advance()
+			checked, err = checkApplied(ctx, name, spec, checked.Status)
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(slices.Compact(stages)).To(Equal([]string{
+			ReplacementSnapshotting, ReplacementRestoring, ReplacementRenaming, ReplacementSwapping}))
+		Expect(checked.Details).To(Equal("Replaced instance test-db, deleting test-db-retired"))
+
+		By("applying the settings the restore cannot set")
+		fake.Advance()
+		checked, err = checkApplied(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Status.RestoredFrom).To(Equal("snapshot " + snapshotID))
+		fake.Advance()
+		checked, err = checkApplied(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeTrue())
+
+		instance := fake.Instance("test-db")
+		Expect(aws.ToString(instance.DBSubnetGroup.DBSubnetGroupName)).To(Equal("app-b"))
+		Expect(aws.ToInt32(instance.BackupRetentionPeriod)).To(Equal(int32(7)))
+		Expect(fake.Instance("test-db-retired")).To(BeNil())
+		Expect(fake.Instance("test-db-replacement")).To(BeNil())
+		Expect(fake.Snapshot(snapshotID)).NotTo(BeNil())
+
+		By("keeping the writes made on the retired instance in a final snapshot")
+		Expect(fake.Snapshot("test-db-final-retired-20250301120000")).NotTo(BeNil())
+
+		result, err = applyAction(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db up to date"))
+	})
+
+	It("should reconcile config tags and leave other tags alone", func() {
+		spec.Tags = map[string]string{"team": "orders", "cost-center": "42"}
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.TagKeys).To(Equal([]string{"cost-center", "team"}))
+		fake.Advance()
+
+		instance := fake.Instance("test-db")
+		Expect(aws.ToBool(instance.CopyTagsToSnapshot)).To(BeTrue())
+		Expect(fromRDSTags(instance.TagList)).To(HaveKeyWithValue("team", "orders"))
+		Expect(fromRDSTags(instance.TagList)).To(HaveKeyWithValue(awsclient.TagComponent, name.String()))
+
+		By("updating and removing config tags, keeping tags set by others")
+		_, err = fake.AddTagsToResource(ctx, &rds.AddTagsToResourceInput{
+			ResourceName: instance.DBInstanceArn,
+			Tags:         toRDSTags(map[string]string{"backup": "daily"}),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		spec.Tags = map[string]string{"team": "payments"}
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Updated tags of RDS instance test-db"))
+		Expect(result.Status.TagKeys).To(Equal([]string{"team"}))
+
+		tags := fromRDSTags(fake.Instance("test-db").TagList)
+		Expect(tags).To(HaveKeyWithValue("team", "payments"))
+		Expect(tags).To(HaveKeyWithValue("backup", "daily"))
+		Expect(tags).NotTo(HaveKey("cost-center"))
+		Expect(tags).To(HaveKeyWithValue(awsclient.TagComponent, name.String()))
+
+		tagged := fake.CallCount("AddTagsToResource")
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db up to date"))
+		Expect(fake.CallCount("AddTagsToResource")).To(Equal(tagged))
+	})
+
+	It("should enable IAM authentication and send only changed log exports", func() {
+		spec.IAMDatabaseAuthenticationEnabled = aws.Bool(true)
+		spec.EnableCloudwatchLogsExports = []string{"postgresql"}
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		instance := fake.Instance("test-db")
+		Expect(aws.ToBool(instance.IAMDatabaseAuthenticationEnabled)).To(BeTrue())
+		Expect(instance.EnabledCloudwatchLogsExports).To(Equal([]string{"postgresql"}))
+
+		By("planning and switching the exported logs")
+		spec.EnableCloudwatchLogsExports = []string{"upgrade", "postgresql"}
+		Expect(planInstance(&spec, instance).Changes).To(Equal([]awsclient.Change{{
+			Field: "enableCloudwatchLogsExports", Action: awsclient.ChangeModify, Current: "postgresql", Desired: "postgresql,upgrade"}}))
+		Expect(logExportChanges(&spec, instance).EnableLogTypes).To(Equal([]string{"upgrade"}))
+
+		spec.EnableCloudwatchLogsExports = []string{"upgrade"}
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		Expect(fake.Instance("test-db").EnabledCloudwatchLogsExports).To(Equal([]string{"upgrade"}))
+
+		By("leaving matching and unmanaged exports alone")
+		modifications := fake.CallCount("ModifyDBInstance")
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db up to date"))
+
+		spec.EnableCloudwatchLogsExports = nil
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db up to date"))
+		Expect(fake.CallCount("ModifyDBInstance")).To(Equal(modifications))
+	})
+
+	It("should upgrade the major version with a blue/green deployment", func() {
+		spec.UpgradeStrategy = UpgradeBlueGreen
+		spec.DeletionProtection = aws.Bool(true)
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		By("planning the deployment")
+		spec.EngineVersion = "17.2"
+		Expect(planInstance(&spec, fake.Instance("test-db")).Changes).To(ContainElement(awsclient.Change{
+			Field: "blueGreenDeployment", Action: awsclient.ChangeAdd, Desired: "test-db-to-17-2"}))
+
+		By("creating the deployment instead of modifying the instance")
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Upgrading RDS instance test-db to engine version 17.2 with blue/green deployment test-db-to-17-2"))
+		Expect(result.Status.BlueGreen.Stage).To(Equal(BlueGreenProvisioning))
+		Expect(fake.CallCount("ModifyDBInstance")).To(BeZero())
+
+		By("switching over once the green environment is in sync")
+		clock := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		snapshotNow = func() time.Time { return clock }
+		DeferCleanup(func() { snapshotNow = time.Now })
+		spec.SkipFinalSnapshot = aws.Bool(false)
+		spec.FinalDBSnapshotIdentifier = "test-db-final"
+		checked, err := checkApplied(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Details).To(Equal("Upgrading instance test-db: provisioning green environment of test-db-to-17-2"))
+
+		var stages []string
+		for i := 0; i < 10 && err == nil && checked.Status.BlueGreen != nil; i++ {
+			stages = append(stages, checked.Status.BlueGreen.Stage)
+			if checked.Status.BlueGreen.Stage == BlueGreenRetiring {
+				Expect(checked.Status.BlueGreen.RetiredIdentifier).To(Equal("test-db-old1"))
+			}
+			fake.Advance()
+			checked, err = checkApplied(ctx, name, spec, checked.Status)
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(slices.Compact(stages)).To(Equal([]string{BlueGreenProvisioning, BlueGreenSwitchingOver, BlueGreenRetiring}))
+		Expect(checked.Details).To(Equal("Upgraded instance test-db to engine version 17.2, deleting test-db-old1"))
+
+		instance := fake.Instance("test-db")
+		Expect(aws.ToString(instance.EngineVersion)).To(Equal("17.2"))
+		Expect(aws.ToString(instance.Endpoint.Address)).To(Equal(checked.Status.Endpoint))
+		Expect(aws.ToString(fake.Instance("test-db-old1").DBInstanceStatus)).To(Equal(string(StatusDeleting)))
+		Expect(fake.Snapshot("test-db-final-old1-20250301120000")).NotTo(BeNil())
+		Expect(fake.BlueGreenDeployment(result.Status.BlueGreen.DeploymentIdentifier)).To(BeNil())
+
+		checked, err = checkApplied(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeTrue())
+
+		By("upgrading in place with the InPlace strategy")
+		spec.UpgradeStrategy = UpgradeInPlace
+		spec.EngineVersion = "18.1"
+		inputs := buildModifyInputs(&spec, fake.Instance("test-db"))
+		Expect(inputs).To(HaveLen(1))
+		Expect(aws.ToBool(inputs[0].AllowMajorVersionUpgrade)).To(BeTrue())
+
+		_, err = applyAction(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		Expect(aws.ToString(fake.Instance("test-db").EngineVersion)).To(Equal("18.1"))
+	})
+
+	It("should check settings against what RDS offers and resolve partial engine versions", func() {
+		By("failing before creating an instance on a version RDS does not offer")
+		spec.EngineVersion = "16.99"
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("engine version 16.99 of postgres is not offered in " + awsfake.Region + "; valid versions: 16.3, 16.4"))
+		Expect(fake.CallCount("CreateDBInstance")).To(BeZero())
+
+		By("creating the instance on the latest version of a partial engine version")
+		spec.EngineVersion = "16"
+		result, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		Expect(aws.ToString(fake.Instance("test-db").EngineVersion)).To(Equal("16.4"))
+
+		By("keeping the instance on its version while it matches")
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db up to date"))
+		Expect(fake.CallCount("ModifyDBInstance")).To(BeZero())
+
+		By("listing the classes of the same family for a mistyped class")
+		spec.InstanceClass = "db.t3.micor"
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("instance class db.t3.micor is not offered for postgres 16.4 in " + awsfake.Region +
+			"; valid classes: db.t3.large, db.t3.medium, db.t3.micro, db.t3.small"))
+
+		By("refusing Multi-AZ for a class that does not support it")
+		fake.DisableMultiAZ("postgres", "db.t3.micro")
+		rdsCatalog = newCatalog()
+		spec.InstanceClass = "db.t3.micro"
+		spec.MultiAZ = aws.Bool(true)
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("instance class db.t3.micro of postgres 16.4 does not support Multi-AZ in " + awsfake.Region))
+		Expect(fake.CallCount("ModifyDBInstance")).To(BeZero())
+
+		By("reading the offering once per cache period")
+		Expect(fake.CallCount("DescribeDBEngineVersions")).To(Equal(2))
+		Expect(fake.CallCount("DescribeOrderableDBInstanceOptions")).To(Equal(2))
+
+		By("reading it again for another role")
+		rdsCatalog = newCatalog()
+		access := awsclient.AccessConfig{Region: awsfake.Region}
+		for _, role := range []string{"", "arn:aws:iam::123456789012:role/rds", ""} {
+			access.AssumeRoleArn = role
+			_, err := rdsCatalog.engineVersions(ctx, fake, access, "postgres")
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(fake.CallCount("DescribeDBEngineVersions")).To(Equal(4))
+	})
+
+	It("should take scheduled and requested snapshots and keep the newest ones", func() {
+		components := awsfake.NewComponents(name)
+		rdsComponents = components
+		clock := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+		snapshotNow = func() time.Time { return clock }
+		DeferCleanup(func() { rdsComponents, snapshotNow = nil, time.Now })
+
+		spec.Tags = map[string]string{"team": "payments"}
+		spec.Snapshots = &Snapshots{Schedule: "0 3 * * *", Retain: aws.Int32(2)}
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		By("taking the first scheduled snapshot once the instance is available")
+		checked, err := checkApplied(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeTrue())
+		Expect(checked.Status.Snapshots).To(Equal([]ManualSnapshot{{
+			Identifier: "test-db-scheduled-20261016-100000",
+			Kind:       SnapshotScheduled,
+			Time:       "2026-10-16T10:00:00Z",
+			Status:     "creating",
+		}}))
+		tags := fromRDSTags(fake.Snapshot("test-db-scheduled-20261016-100000").TagList)
+		Expect(tags).To(HaveKeyWithValue("team", "payments"))
+		Expect(tags).To(HaveKeyWithValue(tagSnapshotKind, SnapshotScheduled))
+
+		By("waiting for the next slot of the schedule")
+		clock = clock.Add(time.Hour)
+		_, err = checkHealth(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("CreateDBSnapshot")).To(Equal(1))
+
+		By("taking one snapshot per annotation value")
+		fake.Advance()
+		components.Annotate(name, SnapshotAnnotation, "release-42")
+		for range 2 {
+			_, err = checkHealth(ctx, name, spec, checked.Status)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(fake.CallCount("CreateDBSnapshot")).To(Equal(2))
+		Expect(fromRDSTags(fake.Snapshot("test-db-ondemand-20261016-110000").TagList)).To(
+			HaveKeyWithValue(tagSnapshotRequest, "release-42"))
+
+		By("pruning scheduled snapshots beyond the retention")
+		for _, day := range []int{17, 18} {
+			fake.Advance()
+			clock = time.Date(2026, 10, day, 3, 0, 0, 0, time.UTC)
+			_, err = checkHealth(ctx, name, spec, checked.Status)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(fake.Snapshot("test-db-scheduled-20261016-100000")).To(BeNil())
+
+		By("leaving snapshots alone in dry-run mode")
+		rdsDryRun = awsclient.NewDryRun(components, false)
+		DeferCleanup(func() { rdsDryRun = nil })
+		components.Annotate(name, awsclient.DryRunAnnotation, "true")
+		fake.Advance()
+		clock = time.Date(2026, 10, 19, 3, 0, 0, 0, time.UTC)
+		_, err = checkHealth(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("CreateDBSnapshot")).To(Equal(4))
+		Expect(fake.CallCount("DeleteDBSnapshot")).To(Equal(1))
+		rdsDryRun = nil
+
+		By("listing them in status on the next apply check")
+		Expect(checked.Status.Snapshots).To(HaveLen(1))
+		checked, err = checkApplied(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		var identifiers []string
+		for _, snapshot := range checked.Status.Snapshots {
+			identifiers = append(identifiers, snapshot.Identifier)
+		}
+		Expect(identifiers).To(Equal([]string{
+			"test-db-scheduled-20261018-030000", "test-db-scheduled-20261017-030000", "test-db-ondemand-20261016-110000"}))
+
+		By("keeping the snapshots once the config drops them")
+		spec.Snapshots = nil
+		checked, err = checkApplied(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Status.Snapshots).To(BeEmpty())
+		Expect(fake.CallCount("DeleteDBSnapshot")).To(Equal(1))
+		Expect(fake.Snapshot("test-db-ondemand-20261016-110000")).NotTo(BeNil())
+	})
+
+	It("should publish connection details to a Secret and keep the password in sync", func() {
+		kube := awsfake.NewKubeSecrets()
+		secrets := awsfake.NewSecretsManager()
+		rdsKube = kube
+		rdsSecrets = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceSecretsManager,
+			func(aws.Config) SecretsAPI { return secrets })
+		DeferCleanup(func() { rdsKube, rdsSecrets = nil, nil })
+
+		// The master user secret RDS creates for the instance
+		_, err := secrets.CreateSecret(ctx, &secretsmanager.CreateSecretInput{
+			Name:         aws.String("rds!db-test-db"),
+			SecretString: aws.String(`{"username":"admin","password":"first"}`),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		spec.ConnectionSecret = &ConnectionSecret{IncludePassword: true}
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kube.Secret("default", "test-db-connection")).To(BeNil())
+
+		By("writing the Secret once the instance is available")
+		fake.Advance()
+		checked, err := checkApplied(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeTrue())
+		Expect(checked.Status.ConnectionSecretName).To(Equal("test-db-connection"))
+
+		secret := kube.Secret("default", "test-db-connection")
+		Expect(secret).NotTo(BeNil())
+		Expect(secret.Annotations).To(HaveKeyWithValue(awsclient.TagComponent, name.String()))
+		Expect(secret.Data).To(Equal(map[string][]byte{
+			ConnectionHost:     []byte(checked.Status.Endpoint),
+			ConnectionPort:     []byte(fmt.Sprint(checked.Status.Port)),
+			ConnectionDBName:   []byte("app"),
+			ConnectionUsername: []byte("admin"),
+			ConnectionPassword: []byte("first"),
+		}))
+
+		By("following a password rotation in the health check")
+		secrets.PutValue("rds!db-test-db", `{"username":"admin","password":"second"}`)
+		health, err := checkHealth(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Instance test-db is operational (status: available)")
+		Expect(health).To(Equal(healthy))
+		Expect(kube.Secret("default", "test-db-connection").Data).To(HaveKeyWithValue(ConnectionPassword, []byte("second")))
+
+		By("only planning the Secret in dry-run mode")
+		components := awsfake.NewComponents(name)
+		components.Annotate(name, awsclient.DryRunAnnotation, "true")
+		rdsDryRun = awsclient.NewDryRun(components, false)
+		DeferCleanup(func() { rdsDryRun = nil })
+		spec.ConnectionSecret.Name = "app-db"
+		planned, err := checkApplied(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(planned.Status.Plan.Changes).To(ContainElement(awsclient.Change{
+			Field: "connectionSecret", Action: awsclient.ChangeModify, Current: "test-db-connection", Desired: "app-db"}))
+		_, err = checkHealth(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kube.Secret("default", "app-db")).To(BeNil())
+		Expect(kube.Secret("default", "test-db-connection")).NotTo(BeNil())
+		rdsDryRun = nil
+		spec.ConnectionSecret.Name = ""
+
+		By("refusing to overwrite a Secret written by someone else")
+		foreign := &corev1.Secret{Data: map[string][]byte{"key": []byte("value")}}
+		foreign.Namespace, foreign.Name = "default", "shared"
+		Expect(kube.Create(ctx, foreign)).To(Succeed())
+		_, err = writeConnectionSecret(ctx, name, "shared", secret.Data)
+		Expect(err).To(MatchError(ContainSubstring("secret default/shared exists and is not managed by Component default/test-db")))
+		Expect(kube.Secret("default", "shared").Data).To(Equal(foreign.Data))
+
+		By("removing the Secret with the Component")
+		_, err = deleteAction(ctx, name, spec, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kube.Secret("default", "test-db-connection")).To(BeNil())
+		Expect(kube.Secret("default", "shared")).NotTo(BeNil())
+	})
+
+	It("should record events for changes and failures", func() {
+		recorder := record.NewFakeRecorder(10)
+		rdsEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { rdsEvents = nil })
+
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Creating RDS instance test-db (db.t3.micro)")))
+		fake.Advance()
+
+		fake.SetInstanceStatus("test-db", string(StatusStorageFull))
+		_, err = checkHealth(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Warning StorageFull RDS instance test-db storage capacity exhausted")))
+
+		fake.FailNext("DescribeDBInstances", &smithy.GenericAPIError{Code: "ThrottlingException"})
+		_, _ = checkHealth(ctx, name, spec, RdsStatus{})
+		Expect(recorder.Events).To(Receive(HavePrefix("Warning Throttled ")))
+
+		fake.SetInstanceStatus("test-db", string(StatusAvailable))
+		_, err = deleteAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Normal Deleted Deleting RDS instance test-db")))
+	})
+
+	It("should plan changes without calling mutating APIs in dry-run mode", func() {
+		components := awsfake.NewComponents(name)
+		components.Annotate(name, awsclient.DryRunAnnotation, "true")
+		rdsDryRun = awsclient.NewDryRun(components, false)
+		DeferCleanup(func() { rdsDryRun = nil })
+
+		By("planning the creation")
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Instance("test-db")).To(BeNil())
+		plan := result.Status.Plan
+		Expect(plan.Action).To(Equal(awsclient.PlanCreate))
+		Expect(plan.Changes).To(ContainElement(awsclient.Change{Field: "instanceClass", Action: awsclient.ChangeAdd, Desired: "db.t3.micro"}))
+
+		checked, err := checkApplied(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		inProgress, _ := functional.CheckInProgress(result.Status, "Dry run: would create test-db (9 changes)")
+		Expect(checked).To(Equal(inProgress))
+
+		By("planning a modification of an existing instance")
+		components.Annotate(name, awsclient.DryRunAnnotation, "false")
+		result, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan).To(BeNil())
+		fake.Advance()
+
+		components.Annotate(name, awsclient.DryRunAnnotation, "true")
+		spec.InstanceClass = "db.t3.large"
+		spec.AllocatedStorage = 50
+		result, err = applyAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("ModifyDBInstance")).To(BeZero())
+		Expect(result.Status.Plan.Changes).To(Equal([]awsclient.Change{
+			{Field: "instanceClass", Action: awsclient.ChangeModify, Current: "db.t3.micro", Desired: "db.t3.large"},
+			{Field: "allocatedStorage", Action: awsclient.ChangeModify, Current: "20", Desired: "50"},
+		}))
+
+		By("holding back the deletion until dry run is turned off")
+		_, err = deleteAction(ctx, name, spec, result.Status)
+		Expect(err).To(MatchError("dry run: would delete test-db; deletion waits until dry run is turned off"))
+		Expect(fake.CallCount("DeleteDBInstance")).To(BeZero())
+
+		components.Annotate(name, awsclient.DryRunAnnotation, "false")
+		_, err = deleteAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("DeleteDBInstance")).To(Equal(1))
+	})
+
+	It("should count the instance under its latest status", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instancesByStatus.Count(string(StatusCreating))).To(Equal(1))
+
+		fake.Advance()
+		_, err = checkApplied(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instancesByStatus.Count(string(StatusCreating))).To(Equal(0))
+		Expect(instancesByStatus.Count(string(StatusAvailable))).To(Equal(1))
+
+		_, err = deleteAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		_, err = checkDeleted(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instancesByStatus.Count(string(StatusAvailable))).To(Equal(0))
+		Expect(instancesByStatus.Count(string(StatusDeleting))).To(Equal(0))
+	})
+
+	It("should treat repeated deletion as complete", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		instance, err := deleteInstance(ctx, fake, &spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance).NotTo(BeNil())
+
+		instance, err = deleteInstance(ctx, fake, &spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance).To(BeNil())
+
+		fake.Advance()
+		instance, err = deleteInstance(ctx, fake, &spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance).To(BeNil())
+	})
+
+	It("should refuse to move an existing instance to another region", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		status := RdsStatus{Access: &awsclient.AccessConfig{Region: awsfake.Region}}
+		spec.Region = "eu-west-1"
+		_, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("ModifyDBInstance")).To(BeZero())
+		Expect(resolveAccess(spec, status).Region).To(Equal(awsfake.Region))
+	})
+
+	It("should not modify an instance owned by another Component", func() {
+		rdsOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { rdsOwnership = nil })
+
+		other := types.NamespacedName{Namespace: "team", Name: "test-db"}
+		_, err := applyAction(ctx, other, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		Expect(fromRDSTags(fake.Instance("test-db").TagList)).To(HaveKeyWithValue(awsclient.TagComponent, "team/test-db"))
+
+		spec.InstanceClass = "db.t3.large"
+		spec.AdoptionPolicy = awsclient.AdoptionIfUntagged
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("owned by Component team/test-db"))
+		Expect(fake.CallCount("ModifyDBInstance")).To(BeZero())
+
+		By("planning the takeover in dry-run mode")
+		rdsDryRun = awsclient.NewDryRun(awsfake.NewComponents(name), true)
+		DeferCleanup(func() { rdsDryRun = nil })
+		spec.AdoptionPolicy = awsclient.AdoptionAlways
+		result, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan.Changes).To(ContainElement(awsclient.Change{
+			Field: "ownership", Action: awsclient.ChangeAdd, Desired: "default/test-db",
+		}))
+
+		By("taking it over with Always")
+		rdsDryRun = nil
+		_, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fromRDSTags(fake.Instance("test-db").TagList)).To(HaveKeyWithValue(awsclient.TagComponent, "default/test-db"))
+		Expect(aws.ToString(fake.Instance("test-db").DBInstanceClass)).To(Equal("db.t3.large"))
+	})
+
+	It("should leave an instance it refused to adopt in place on delete", func() {
+		rdsOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { rdsOwnership = nil })
+
+		other := types.NamespacedName{Namespace: "team", Name: "test-db"}
+		_, err := applyAction(ctx, other, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("owned by Component team/test-db"))
+
+		deleted, err := deleteAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted.Details).To(Equal("RDS instance test-db is not owned by this Component, left in place"))
+		Expect(fake.CallCount("DeleteDBInstance")).To(BeZero())
+
+		checked, err := checkDeleted(ctx, name, spec, deleted.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeTrue())
+		Expect(aws.ToString(fake.Instance("test-db").DBInstanceStatus)).To(Equal(string(StatusAvailable)))
+	})
+
+	It("should keep a retained instance and remove its ownership tags", func() {
+		rdsOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { rdsOwnership = nil })
+
+		spec.DeletionProtection = aws.Bool(true)
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		spec.DeletionPolicy = awsclient.DeletionPolicyRetain
+		result, err := deleteAction(ctx, name, spec, appliedStatus())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db retained (Retain policy)"))
+		Expect(fake.Instance("test-db")).NotTo(BeNil())
+		Expect(fromRDSTags(fake.Instance("test-db").TagList)).NotTo(HaveKey(awsclient.TagComponent))
+		Expect(fake.CallCount("DeleteDBInstance")).To(BeZero())
+		Expect(instancesByStatus.Count(string(StatusAvailable))).To(Equal(0))
+
+		check, err := checkDeleted(ctx, name, spec, appliedStatus())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Instance test-db retained"))
+	})
+
+	It("should roll back an unfinished replacement before retaining the instance", func() {
+		spec.SubnetGroupName = "app-a"
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		By("starting a replacement up to the rename of the old instance")
+		spec.SubnetGroupName = "app-b"
+		spec.ReplacementPolicy = ReplacementSnapshotRestore
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		status := result.Status
+		for i := 0; i < 10 && status.Replacement.Stage != ReplacementRenaming; i++ {
+			fake.Advance()
+			checked, err := checkApplied(ctx, name, spec, status)
+			Expect(err).NotTo(HaveOccurred())
+			status = checked.Status
+		}
+		Expect(status.Replacement.Stage).To(Equal(ReplacementRenaming))
+		Expect(fake.Instance("test-db-retired")).NotTo(BeNil())
+
+		By("deleting the replacement and renaming the old instance back")
+		spec.DeletionPolicy = awsclient.DeletionPolicyRetain
+		deleted, err := deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted.Details).To(Equal("Rolling back the replacement of instance test-db before retaining it"))
+		Expect(deleted.Status.Replacement.Stage).To(Equal(ReplacementRollingBack))
+
+		status = deleted.Status
+		var check *functional.CheckResult[RdsStatus]
+		for i := 0; i < 10 && status.Replacement != nil; i++ {
+			fake.Advance()
+			check, err = checkDeleted(ctx, name, spec, status)
+			Expect(err).NotTo(HaveOccurred())
+			status = check.Status
+		}
+		Expect(check.Complete).To(BeTrue())
+		Expect(check.Details).To(Equal("Instance test-db retained"))
+
+		instance := fake.Instance("test-db")
+		Expect(aws.ToString(instance.DBSubnetGroup.DBSubnetGroupName)).To(Equal("app-a"))
+		Expect(fake.Instance("test-db-retired")).To(BeNil())
+		Expect(fake.Instance("test-db-replacement")).To(BeNil())
+		Expect(fake.CallCount("DeleteDBInstance")).To(Equal(1))
+	})
+
+	It("should create, monitor and promote a read replica", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		replicaName := types.NamespacedName{Namespace: "default", Name: "reports"}
+		replica := RdsConfig{
+			InstanceID:              "reports",
+			InstanceClass:           "db.t3.small",
+			ReplicaSourceIdentifier: "test-db",
+			MaxReplicaLagSeconds:    aws.Int32(60),
+			DeletionProtection:      aws.Bool(false),
+		}
+		lag := func(seconds float64) {
+			metrics.SetMetric("AWS/RDS", "ReplicaLag", map[string]string{"DBInstanceIdentifier": "reports"}, seconds)
+		}
+
+		By("creating the replica from the source")
+		result, err := applyAction(ctx, replicaName, replica, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Creating RDS read replica reports of test-db (db.t3.small)"))
+		Expect(fake.CallCount("CreateDBInstance")).To(Equal(1))
+		Expect(aws.ToInt32(fake.Instance("reports").AllocatedStorage)).To(Equal(int32(20)))
+
+		fake.Advance()
+		lag(4)
+		checked, err := checkApplied(ctx, replicaName, replica, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Details).To(HavePrefix("Replica reports of test-db available at reports.fake.us-east-1.rds.amazonaws.com:"))
+		Expect(checked.Status.ReplicaSource).To(Equal("test-db"))
+		Expect(checked.Status.ReplicationState).To(Equal(ReplicationReplicating))
+		Expect(checked.Status.ReplicaLagSeconds).To(Equal(aws.Int64(4)))
+
+		By("degrading on replication lag and broken replication")
+		health, err := checkHealth(ctx, replicaName, replica, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Replica reports of test-db is operational (lag: 4s)")
+		Expect(health).To(Equal(healthy))
+
+		lag(125.4)
+		health, err = checkHealth(ctx, replicaName, replica, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("ReplicationLag", "Replica reports lag 125s exceeds 60s")
+		Expect(health).To(Equal(degraded))
+
+		fake.SetReplicationStatus("reports", ReplicationError, false)
+		health, err = checkHealth(ctx, replicaName, replica, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ = controller.HealthCheckDegraded("ReplicationBroken", "Replica reports replication from test-db is error")
+		Expect(health).To(Equal(degraded))
+		fake.SetReplicationStatus("reports", ReplicationReplicating, true)
+
+		By("promoting the replica once it is available")
+		replica.PromoteReplica = true
+		result, err = applyAction(ctx, replicaName, replica, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		checked, err = checkApplied(ctx, replicaName, replica, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Details).To(Equal("Promoting replica reports (source test-db)"))
+		Expect(fake.CallCount("PromoteReadReplica")).To(Equal(1))
+		Expect(aws.ToInt32(fake.Instance("reports").BackupRetentionPeriod)).To(Equal(int32(7)))
+
+		fake.Advance()
+		checked, err = checkApplied(ctx, replicaName, replica, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeTrue())
+		Expect(checked.Status.ReplicaSource).To(BeEmpty())
+		Expect(checked.Status.ReplicaLagSeconds).To(BeNil())
+		Expect(fake.Instance("test-db").ReadReplicaDBInstanceIdentifiers).To(BeEmpty())
+
+		By("refusing to demote the promoted instance")
+		replica.PromoteReplica = false
+		result, err = applyAction(ctx, replicaName, replica, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("a promoted replica cannot be demoted"))
+	})
+
+	It("should plan a replica and delete it without a final snapshot", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		replicaName := types.NamespacedName{Namespace: "default", Name: "reports"}
+		replica := RdsConfig{
+			InstanceID:              "reports",
+			InstanceClass:           "db.t3.micro",
+			ReplicaSourceIdentifier: "test-db",
+			DeletionProtection:      aws.Bool(false),
+		}
+
+		rdsDryRun = awsclient.NewDryRun(awsfake.NewComponents(replicaName), true)
+		result, err := applyAction(ctx, replicaName, replica, RdsStatus{})
+		rdsDryRun = nil
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan.Changes).To(ContainElement(awsclient.Change{
+			Field: "replicaSourceIdentifier", Action: awsclient.ChangeAdd, Desired: "test-db",
+		}))
+		Expect(fake.CallCount("CreateDBInstanceReadReplica")).To(BeZero())
+
+		_, err = applyAction(ctx, replicaName, replica, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		By("refusing to manage the replica as a standalone instance")
+		standalone := replica
+		standalone.ReplicaSourceIdentifier = ""
+		standalone.MasterUsername = "admin"
+		result, err = applyAction(ctx, replicaName, standalone, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("RDS instance reports is a read replica of test-db"))
+
+		_, err = deleteAction(ctx, replicaName, replica, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		Expect(fake.Instance("reports")).To(BeNil())
+		Expect(fake.Instance("test-db").ReadReplicaDBInstanceIdentifiers).To(BeEmpty())
+	})
+
+	It("should restore an instance from a snapshot and then apply its settings", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		_, err = fake.CreateDBSnapshot(ctx, &rds.CreateDBSnapshotInput{
+			DBInstanceIdentifier: aws.String("test-db"),
+			DBSnapshotIdentifier: aws.String("test-db-nightly"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		snapshotArn := aws.ToString(fake.Snapshot("test-db-nightly").DBSnapshotArn)
+
+		copyName := types.NamespacedName{Namespace: "default", Name: "test-db-copy"}
+		restored := RdsConfig{
+			InstanceID:            "test-db-copy",
+			InstanceClass:         "db.t3.small",
+			BackupRetentionPeriod: aws.Int32(14),
+			DeletionProtection:    aws.Bool(false),
+			SkipFinalSnapshot:     aws.Bool(true),
+			RestoreFrom:           &RestoreFrom{SnapshotIdentifier: snapshotArn},
+		}
+
+		By("restoring the instance")
+		result, err := applyAction(ctx, copyName, restored, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Restoring RDS instance test-db-copy from snapshot " + snapshotArn))
+		Expect(result.Status.RestoreModifyPending).To(BeTrue())
+		Expect(fake.CallCount("RestoreDBInstanceFromDBSnapshot")).To(Equal(1))
+		Expect(fake.CallCount("CreateDBInstance")).To(Equal(1))
+
+		instance := fake.Instance("test-db-copy")
+		Expect(aws.ToString(instance.Engine)).To(Equal("postgres"))
+		Expect(aws.ToString(instance.MasterUsername)).To(Equal("admin"))
+		Expect(aws.ToString(instance.DBInstanceClass)).To(Equal("db.t3.small"))
+
+		By("applying the settings the restore cannot set once available")
+		fake.Advance()
+		checked, err := checkApplied(ctx, copyName, restored, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeFalse())
+		Expect(checked.Details).To(HavePrefix("Configuring instance test-db-copy restored from snapshot "))
+		Expect(checked.Status.RestoreModifyPending).To(BeFalse())
+		Expect(aws.ToInt32(fake.Instance("test-db-copy").BackupRetentionPeriod)).To(Equal(int32(14)))
+
+		fake.Advance()
+		checked, err = checkApplied(ctx, copyName, restored, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeTrue())
+		Expect(fake.CallCount("ModifyDBInstance")).To(Equal(1))
+
+		By("modifying the restored instance on later reconciles")
+		restored.InstanceClass = "db.t3.medium"
+		result, err = applyAction(ctx, copyName, restored, checked.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Modifying RDS instance test-db-copy (db.t3.medium)"))
+		Expect(fake.CallCount("RestoreDBInstanceFromDBSnapshot")).To(Equal(1))
+	})
+
+	It("should plan and restore an instance to a point in time", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		copyName := types.NamespacedName{Namespace: "default", Name: "test-db-pitr"}
+		restored := RdsConfig{
+			InstanceID:         "test-db-pitr",
+			InstanceClass:      "db.t3.micro",
+			DeletionProtection: aws.Bool(false),
+			RestoreFrom:        &RestoreFrom{SourceInstanceIdentifier: "test-db", UseLatestRestorableTime: true},
+		}
+
+		rdsDryRun = awsclient.NewDryRun(awsfake.NewComponents(copyName), true)
+		result, err := applyAction(ctx, copyName, restored, RdsStatus{})
+		rdsDryRun = nil
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan.Changes).To(ContainElement(awsclient.Change{
+			Field: "restoreFrom", Action: awsclient.ChangeAdd, Desired: "instance test-db at the latest restorable time",
+		}))
+		Expect(fake.CallCount("RestoreDBInstanceToPointInTime")).To(BeZero())
+
+		result, err = applyAction(ctx, copyName, restored, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.RestoredFrom).To(Equal("instance test-db at the latest restorable time"))
+		Expect(aws.ToString(fake.Instance("test-db-pitr").DBName)).To(Equal("app"))
+
+		By("surfacing a failed restore")
+		fake.FailNext("RestoreDBInstanceToPointInTime", errors.New("boom"))
+		restored.InstanceID = "test-db-pitr2"
+		_, err = restoreInstance(ctx, fake, &restored, nil)
+		Expect(err).To(MatchError(ContainSubstring("failed to restore RDS instance to point in time: boom")))
+	})
+
+	It("should return nil for a missing instance", func() {
+		instance, err := getInstanceData(ctx, fake, "missing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instance).To(BeNil())
+	})
+
+	It("should surface AWS errors from create", func() {
+		fake.FailNext("CreateDBInstance", errors.New("boom"))
+
+		_, err := createInstance(ctx, fake, &spec, nil)
+		Expect(err).To(MatchError(ContainSubstring("boom")))
+		Expect(fake.Instance("test-db")).To(BeNil())
+	})
+
+	It("should refuse to delete a protected instance", func() {
+		spec.DeletionProtection = aws.Bool(true)
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		_, err = deleteInstance(ctx, fake, &spec)
+		Expect(err).To(HaveOccurred())
+		Expect(fake.Instance("test-db")).NotTo(BeNil())
+	})
+
+	It("should report degraded health for a full instance", func() {
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		result, err := checkHealth(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Instance test-db is operational (status: available)")
+		Expect(result).To(Equal(healthy))
+
+		fake.SetInstanceStatus("test-db", string(StatusStorageFull))
+		result, err = checkHealth(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("StorageFull", "Instance test-db storage capacity exhausted")
+		Expect(result).To(Equal(degraded))
+	})
+
+	It("should manage storage autoscaling and provisioned IOPS and degrade near the storage limit", func() {
+		spec.AllocatedStorage = 400
+		spec.MaxAllocatedStorage = aws.Int32(1000)
+		spec.Iops = aws.Int32(12000)
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		instance := fake.Instance("test-db")
+		Expect(aws.ToString(instance.StorageType)).To(Equal("gp3"))
+		Expect(aws.ToInt32(instance.MaxAllocatedStorage)).To(Equal(int32(1000)))
+		Expect(aws.ToInt32(instance.Iops)).To(Equal(int32(12000)))
+
+		By("leaving storage grown by autoscaling alone")
+		_, err = fake.ModifyDBInstance(ctx, &rds.ModifyDBInstanceInput{
+			DBInstanceIdentifier: aws.String("test-db"),
+			AllocatedStorage:     aws.Int32(500),
+			ApplyImmediately:     aws.Bool(true),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		modifications := fake.CallCount("ModifyDBInstance")
+
+		result, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS instance test-db up to date"))
+		Expect(fake.CallCount("ModifyDBInstance")).To(Equal(modifications))
+
+		By("switching to io2 and turning autoscaling off")
+		spec.StorageType = StorageIO2
+		spec.MaxAllocatedStorage = aws.Int32(400)
+		_, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		instance = fake.Instance("test-db")
+		Expect(aws.ToString(instance.StorageType)).To(Equal(StorageIO2))
+		Expect(aws.ToInt32(instance.AllocatedStorage)).To(Equal(int32(500)))
+		Expect(instance.MaxAllocatedStorage).To(BeNil())
+
+		By("degrading when the used storage nears the limit")
+		dimensions := map[string]string{"DBInstanceIdentifier": "test-db"}
+		metrics.SetMetric("AWS/RDS", "FreeStorageSpace", dimensions, 200*(1<<30))
+		health, err := checkHealth(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Instance test-db is operational (status: available)")
+		Expect(health).To(Equal(healthy))
+
+		metrics.SetMetric("AWS/RDS", "FreeStorageSpace", dimensions, 20*(1<<30))
+		health, err = checkHealth(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("StorageNearLimit",
+			"Instance test-db uses 480 GiB of its 500 GiB storage limit")
+		Expect(health).To(Equal(degraded))
+	})
+
+	It("should use the configured parameter group and degrade while a reboot is pending", func() {
+		for _, group := range []string{"app-postgres", "app-postgres-tuned"} {
+			_, err := fake.CreateDBParameterGroup(ctx, &rds.CreateDBParameterGroupInput{
+				DBParameterGroupName:   aws.String(group),
+				DBParameterGroupFamily: aws.String("postgres16"),
+				Description:            aws.String(group),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		spec.ParameterGroupName = "app-postgres"
+		_, err := applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		status := appliedStatus()
+		Expect(status.ParameterGroupName).To(Equal("app-postgres"))
+		Expect(status.ParameterApplyStatus).To(Equal("in-sync"))
+
+		By("planning a switch to another group")
+		spec.ParameterGroupName = "app-postgres-tuned"
+		plan := planInstance(&spec, fake.Instance("test-db"))
+		Expect(plan.Changes).To(ContainElement(awsclient.Change{
+			Field: "parameterGroupName", Action: awsclient.ChangeModify, Current: "app-postgres", Desired: "app-postgres-tuned"}))
+
+		By("switching groups, which waits for a reboot")
+		_, err = applyAction(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		Expect(appliedStatus().ParameterApplyStatus).To(Equal(parameterApplyPendingReboot))
+
+		By("still taking due snapshots while degraded")
+		snapshotNow = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		DeferCleanup(func() { snapshotNow = time.Now })
+		spec.Snapshots = &Snapshots{Schedule: "@hourly"}
+		result, err := checkHealth(ctx, name, spec, RdsStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("PendingReboot",
+			"Instance test-db needs a reboot to apply parameter group app-postgres-tuned")
+		Expect(result).To(Equal(degraded))
+		Expect(fake.CallCount("CreateDBSnapshot")).To(Equal(1))
+	})
+})
+
+var _ = Describe("ReadinessProbe", func() {
+	It("should make one cheap read with the controller's own credentials", func() {
+		fake := awsfake.NewRDS()
+		rdsClients = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceRDS,
+			func(aws.Config) API { return fake })
+
+		Expect(ReadinessProbe(context.Background())).To(Succeed())
+		Expect(fake.Calls()).To(Equal([]string{"DescribeDBInstances"}))
+
+		fake.FailNext("DescribeDBInstances", errors.New("AccessDenied"))
+		Expect(ReadinessProbe(context.Background())).To(MatchError("AccessDenied"))
+	})
+})
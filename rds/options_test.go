@@ -0,0 +1,69 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator-aws-providers/awsfake"
+)
+
+var _ = Describe("Options", func() {
+	apply := func(opts ...Option) options {
+		o := defaultOptions()
+		for _, opt := range opts {
+			opt(&o)
+		}
+		return o
+	}
+
+	It("should keep today's defaults without options", func() {
+		o := apply()
+		Expect(o.ProviderName).To(Equal(DefaultProviderName))
+		Expect(o.AWSConfig).To(BeNil())
+		Expect(o.client).To(BeNil())
+		Expect(o.HealthCheckInterval).To(Equal(time.Minute))
+		Expect(o.ErrorRequeue).To(Equal(15 * time.Second))
+		Expect(o.DefaultRequeue).To(Equal(30 * time.Second))
+		Expect(o.StatusCheckRequeue).To(Equal(30 * time.Second))
+		Expect(o.ErrorClassifier(errors.New("validation failed"))).To(BeFalse())
+	})
+
+	It("should keep the default name when given an empty one", func() {
+		Expect(apply(WithProviderName("")).providerName).To(Equal(DefaultProviderName))
+		Expect(apply(WithProviderName("wordpress-rds")).providerName).To(Equal("wordpress-rds"))
+	})
+
+	It("should apply overrides", func() {
+		fake := awsfake.NewRDS()
+		metrics := awsfake.NewCloudWatch()
+		endpoints := awsclient.Endpoints{URL: "http://localstack:4566"}
+		o := apply(
+			WithAWSConfig(aws.Config{Region: "eu-west-1"}),
+			WithEndpoints(endpoints),
+			WithClient(fake),
+			WithMetricsClient(metrics),
+			WithErrorClassifier(func(error) bool { return true }),
+			WithHealthCheckInterval(5*time.Minute),
+			WithErrorRequeue(time.Second),
+			WithDefaultRequeue(2*time.Second),
+			WithStatusCheckRequeue(3*time.Second),
+		)
+		Expect(o.AWSConfig.Region).To(Equal("eu-west-1"))
+		Expect(o.Endpoints).To(Equal(endpoints))
+		Expect(o.client).To(BeIdenticalTo(fake))
+		Expect(o.metrics).To(BeIdenticalTo(metrics))
+		Expect(o.ErrorClassifier(errors.New("validation failed"))).To(BeTrue())
+		Expect(o.HealthCheckInterval).To(Equal(5 * time.Minute))
+		Expect(o.ErrorRequeue).To(Equal(time.Second))
+		Expect(o.DefaultRequeue).To(Equal(2 * time.Second))
+		Expect(o.StatusCheckRequeue).To(Equal(3 * time.Second))
+	})
+})
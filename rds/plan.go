@@ -0,0 +1,86 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
+
+// Package-level dry-run switch initialized during registration; nil never plans
+var rdsDryRun *awsclient.DryRun
+
+// planInstance computes what applyAction would change on the instance.
+// A nil instance plans its creation.
+func planInstance(config *RdsConfig, instance *types.DBInstance) *awsclient.Plan {
+	plan := awsclient.NewPlan(config.InstanceID, instance != nil)
+
+	current := instance
+	switch {
+	case current == nil && config.isReplica():
+		current = &types.DBInstance{}
+		plan.Add("replicaSourceIdentifier", config.ReplicaSourceIdentifier)
+		awsclient.Diff(plan, "storageType", nil, optionalStringPtr(config.StorageType))
+	case current == nil && config.isRestore():
+		current = &types.DBInstance{}
+		plan.Add("restoreFrom", config.RestoreFrom.String())
+		awsclient.Diff(plan, "storageType", nil, optionalStringPtr(config.StorageType))
+	case current == nil:
+		current = &types.DBInstance{}
+		awsclient.Diff(plan, "databaseEngine", nil, stringPtr(config.DatabaseEngine))
+		awsclient.Diff(plan, "storageType", nil, stringPtr(createStorageType(config)))
+	case config.PromoteReplica && replicaSource(current) != "":
+		// Promotion detaches the replica from its source
+		plan.Remove("replicaSourceIdentifier", replicaSource(current))
+	}
+
+	// Settings fixed at creation fail the apply or replace the instance
+	if instance != nil {
+		for _, c := range immutableChanges(config, instance) {
+			plan.Modify(c.field, c.current, c.desired)
+		}
+	}
+
+	// Compare the same settings ModifyDBInstance would send
+	desired := desiredSettings(config)
+	settings := currentSettings(current)
+	awsclient.Diff(plan, "instanceClass", settings.DBInstanceClass, desired.DBInstanceClass)
+	awsclient.Diff(plan, "allocatedStorage", settings.AllocatedStorage, storageGrowth(settings.AllocatedStorage, desired.AllocatedStorage))
+	awsclient.Diff(plan, "maxAllocatedStorage", settings.MaxAllocatedStorage, maxStorageChange(settings, desired))
+	if instance != nil {
+		// New instances plan their storage type above
+		awsclient.Diff(plan, "storageType", settings.StorageType, desired.StorageType)
+	}
+	awsclient.Diff(plan, "iops", settings.Iops, desired.Iops)
+	awsclient.Diff(plan, "storageThroughput", settings.StorageThroughput, desired.StorageThroughput)
+	awsclient.Diff(plan, "engineVersion", settings.EngineVersion, desired.EngineVersion)
+	if instance != nil {
+		if target := blueGreenTarget(config, instance); target != "" {
+			plan.Add("blueGreenDeployment", blueGreenDeploymentName(config.InstanceID, target))
+		}
+	}
+	awsclient.Diff(plan, "backupRetentionPeriod", settings.BackupRetentionPeriod, desired.BackupRetentionPeriod)
+	awsclient.Diff(plan, "multiAZ", settings.MultiAZ, desired.MultiAZ)
+	awsclient.Diff(plan, "preferredBackupWindow", settings.PreferredBackupWindow, desired.PreferredBackupWindow)
+	awsclient.Diff(plan, "preferredMaintenanceWindow", settings.PreferredMaintenanceWindow, desired.PreferredMaintenanceWindow)
+	awsclient.Diff(plan, "autoMinorVersionUpgrade", settings.AutoMinorVersionUpgrade, desired.AutoMinorVersionUpgrade)
+	awsclient.Diff(plan, "deletionProtection", settings.DeletionProtection, desired.DeletionProtection)
+	awsclient.Diff(plan, "parameterGroupName", settings.DBParameterGroupName, desired.DBParameterGroupName)
+	awsclient.Diff(plan, "iamDatabaseAuthenticationEnabled",
+		settings.EnableIAMDatabaseAuthentication, desired.EnableIAMDatabaseAuthentication)
+
+	if logExportChanges(config, current) != nil {
+		var enabled *string
+		if instance != nil {
+			enabled = stringPtr(strings.Join(enabledLogExports(instance), ","))
+		}
+		exports := strings.Join(slices.Sorted(slices.Values(config.EnableCloudwatchLogsExports)), ",")
+		awsclient.Diff(plan, "enableCloudwatchLogsExports", enabled, &exports)
+	}
+
+	return plan
+}
@@ -5,11 +5,11 @@ package rds
 
 import (
 	"context"
-	"fmt"
-	"time"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	v1beta1 "github.com/rinswind/componator/api/v1beta1"
 	"github.com/rinswind/componator/componentkit/functional"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -21,18 +21,28 @@ const (
 	DefaultProviderName = "rds"
 )
 
+// rdsRegistration refuses a second Register, which would take over the package state of the first
+var rdsRegistration awsclient.Registration
+
 // Register registers the rds Component provider with the controller manager.
 //
-// The providerName parameter specifies the unique name used for Component claiming.
-// Pass empty string to use the default "rds". For setkit embedding, use a
-// prefixed name (e.g., "wordpress-rds") to avoid conflicts with other providers.
+// Without options the provider is claimed as "rds" and initializes AWS RDS clients
+// using the default credential chain (environment variables, EC2 instance metadata, etc.).
+// Components may override the region and assume a role in another account; one client
+// is cached per target. Embedding programs customize the name, AWS config or client,
+// timings and error classification through options.
 //
 // Provider names must be unique across all providers in the cluster. Multiple providers
 // with the same name will conflict during Component claiming.
 //
-// Initializes AWS RDS client using the default credential chain
-// (environment variables, EC2 instance metadata, etc.).
-func Register(mgr ctrl.Manager, providerName string) error {
+// The provider keeps its clients, ownership and dry-run settings in package state, so a package
+// can only be registered once per process; a second Register fails.
+func Register(mgr ctrl.Manager, opts ...Option) error {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// Ensure required schemes are registered (safe to call multiple times)
 	if err := clientgoscheme.AddToScheme(mgr.GetScheme()); err != nil {
 		return err
@@ -41,39 +51,63 @@ func Register(mgr ctrl.Manager, providerName string) error {
 		return err
 	}
 
-	// Use default provider name if not specified
-	if providerName == "" {
-		providerName = DefaultProviderName
+	// An injected client needs no credentials of its own
+	cfg := aws.Config{}
+	if o.client == nil || o.AWSConfig != nil {
+		var err error
+		if cfg, err = awsclient.BaseConfig(context.Background(), o.AWSConfig, o.Endpoints); err != nil {
+			return err
+		}
 	}
 
-	// Load AWS config with default chain (uses AWS_REGION, EC2 metadata, etc.)
-	// Disable retries - controller handles requeue
-	// Use WithEC2IMDSRegion to auto-detect region from EC2 metadata when in EKS
-	cfg, err := awsconfig.LoadDefaultConfig(
-		context.Background(),
-		awsconfig.WithRetryMaxAttempts(1),
-		awsconfig.WithEC2IMDSRegion(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to load AWS configuration: %w", err)
+	if err := rdsRegistration.Claim(o.ProviderName); err != nil {
+		return err
 	}
 
-	rdsClient = rds.NewFromConfig(cfg)
+	rdsClients = awsclient.NewCache(cfg, o.Endpoints, awsclient.ServiceRDS, func(cfg aws.Config) API {
+		if o.client != nil {
+			return o.client
+		}
+		return rds.NewFromConfig(cfg)
+	})
+	rdsMetrics = awsclient.NewCache(cfg, o.Endpoints, awsclient.ServiceCloudWatch, func(cfg aws.Config) MetricsAPI {
+		if o.metrics != nil {
+			return o.metrics
+		}
+		return awsclient.NewCloudWatch(cfg)
+	})
+	rdsSecrets = awsclient.NewCache(cfg, o.Endpoints, awsclient.ServiceSecretsManager, func(cfg aws.Config) SecretsAPI {
+		if o.secrets != nil {
+			return o.secrets
+		}
+		return secretsmanager.NewFromConfig(cfg)
+	})
+	rdsKube = splitKubeClient{Reader: mgr.GetAPIReader(), Writer: mgr.GetClient()}
+	rdsComponents = mgr.GetCache()
+	rdsErrorClassifier = o.ErrorClassifier
+	rdsEvents = awsclient.NewEventRecorder(mgr.GetClient(), mgr.GetEventRecorderFor(o.ProviderName))
+	rdsDryRun = awsclient.NewDryRun(mgr.GetClient(), o.DryRun)
+	rdsOwnership = awsclient.NewOwnership(o.ClusterID, o.ProviderName)
 
 	// Log client initialization
 	log := logf.Log.WithName("rds")
-	log.Info("Initialized AWS RDS client", "region", cfg.Region)
+	log.Info("Initialized AWS RDS client cache",
+		"defaultRegion", cfg.Region, "endpointOverride", o.Endpoints.IsSet(), "injectedClient", o.client != nil)
 
 	// Register with functional API using custom timeouts for RDS operations
-	return functional.NewBuilder[RdsConfig, RdsStatus](providerName).
+	if err := functional.NewBuilder[RdsConfig, RdsStatus](o.ProviderName).
 		WithApply(applyAction).
 		WithApplyCheck(checkApplied).
 		WithDelete(deleteAction).
 		WithDeleteCheck(checkDeleted).
 		WithHealthCheck(checkHealth).
-		WithHealthCheckInterval(1 * time.Minute).
-		WithErrorRequeue(15 * time.Second).
-		WithDefaultRequeue(30 * time.Second).
-		WithStatusCheckRequeue(30 * time.Second).
-		Register(mgr)
+		WithHealthCheckInterval(o.HealthCheckInterval).
+		WithErrorRequeue(o.ErrorRequeue).
+		WithDefaultRequeue(o.DefaultRequeue).
+		WithStatusCheckRequeue(o.StatusCheckRequeue).
+		Register(mgr); err != nil {
+		rdsRegistration.Release()
+		return err
+	}
+	return nil
 }
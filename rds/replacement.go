@@ -0,0 +1,502 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/functional"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Replacement policies for changes to settings RDS cannot modify
+const (
+	ReplacementNever           = "Never"
+	ReplacementSnapshotRestore = "SnapshotRestore"
+)
+
+// Stages of a snapshot-restore replacement
+const (
+	ReplacementSnapshotting = "Snapshotting" // taking a snapshot of the instance
+	ReplacementCopying      = "Copying"      // copying the snapshot to change its encryption
+	ReplacementRestoring    = "Restoring"    // restoring the replacement from the snapshot
+	ReplacementRenaming     = "Renaming"     // moving the instance to its retired identifier
+	ReplacementSwapping     = "Swapping"     // moving the replacement to the instance identifier
+	ReplacementRollingBack  = "RollingBack"  // moving the instance back to its identifier to retain it
+)
+
+// defaultKmsKey is the AWS managed key RDS encrypts with when no kmsKeyId is given
+const defaultKmsKey = "alias/aws/rds"
+
+// resolveReplacementPolicy validates a replacementPolicy setting, defaulting to ReplacementNever
+func resolveReplacementPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return ReplacementNever, nil
+	case ReplacementNever, ReplacementSnapshotRestore:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid replacementPolicy %q: must be one of %s, %s",
+			policy, ReplacementNever, ReplacementSnapshotRestore)
+	}
+}
+
+// immutableChange is a difference in a setting ModifyDBInstance cannot change
+type immutableChange struct {
+	field, current, desired string
+
+	// replaceable reports whether an instance restored from a snapshot can have the desired value
+	replaceable bool
+}
+
+// immutableChanges compares the settings fixed at creation with the instance.
+// Settings the config leaves unset, or the instance does not report, are not compared.
+func immutableChanges(config *RdsConfig, instance *rdstypes.DBInstance) []immutableChange {
+	var changes []immutableChange
+	compare := func(field string, current *string, desired string, replaceable bool) {
+		if desired != "" && current != nil && *current != desired {
+			changes = append(changes, immutableChange{field, *current, desired, replaceable})
+		}
+	}
+
+	compare("databaseEngine", instance.Engine, config.DatabaseEngine, false)
+	compare("databaseName", instance.DBName, config.DatabaseName, false)
+	compare("masterUsername", instance.MasterUsername, config.MasterUsername, false)
+	if instance.DBSubnetGroup != nil {
+		compare("subnetGroupName", instance.DBSubnetGroup.DBSubnetGroupName, config.SubnetGroupName, true)
+	}
+
+	// Replicas and restored instances take their encryption from the source.
+	// A snapshot copy can be encrypted, but not decrypted.
+	if !config.isReplica() && !config.isRestore() && config.StorageEncrypted != nil && instance.StorageEncrypted != nil &&
+		*config.StorageEncrypted != *instance.StorageEncrypted {
+		changes = append(changes, immutableChange{"storageEncrypted",
+			strconv.FormatBool(*instance.StorageEncrypted), strconv.FormatBool(*config.StorageEncrypted), *config.StorageEncrypted})
+	}
+	if current := stringValue(instance.KmsKeyId); current != "" && !sameKmsKey(current, config.KmsKeyId) {
+		changes = append(changes, immutableChange{"kmsKeyId", current, config.KmsKeyId, true})
+	}
+
+	return changes
+}
+
+// sameKmsKey reports whether the configured KMS key is the current one. RDS reports key ARNs,
+// while the config may give a key ID. Aliases cannot be resolved here and are not compared.
+func sameKmsKey(current, desired string) bool {
+	if desired == "" || strings.HasPrefix(desired, "alias/") || strings.Contains(desired, ":alias/") {
+		return true
+	}
+	return current == desired || strings.HasSuffix(current, "/"+desired)
+}
+
+// describeChanges lists immutable changes for failure details
+func describeChanges(changes []immutableChange) string {
+	descriptions := make([]string, 0, len(changes))
+	for _, c := range changes {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%q to %q)", c.field, c.current, c.desired))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+// replaceInstance handles an apply that changes settings RDS cannot modify. Unless the replacement
+// policy allows replacing the instance, the apply fails listing the changes. Otherwise it takes a
+// snapshot of the instance, and checkApplied drives the remaining stages of the replacement.
+func replaceInstance(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec RdsConfig,
+	status RdsStatus,
+	changes []immutableChange) (*functional.ActionResult[RdsStatus], error) {
+
+	instanceID := spec.InstanceID
+
+	if spec.ReplacementPolicy != ReplacementSnapshotRestore {
+		return functional.ActionFailure(status, fmt.Sprintf(
+			"config validation failed: cannot change %s of existing RDS instance %s; set replacementPolicy to %s to replace the instance",
+			describeChanges(changes), instanceID, ReplacementSnapshotRestore))
+	}
+
+	var fixed []immutableChange
+	for _, c := range changes {
+		if !c.replaceable {
+			fixed = append(fixed, c)
+		}
+	}
+	if len(fixed) > 0 {
+		return functional.ActionFailure(status, fmt.Sprintf(
+			"config validation failed: cannot change %s of existing RDS instance %s, not even by restoring a replacement from a snapshot",
+			describeChanges(fixed), instanceID))
+	}
+
+	replacement := &Replacement{
+		Stage:                 ReplacementSnapshotting,
+		SnapshotIdentifier:    fmt.Sprintf("%s-replacement-%s", instanceID, time.Now().UTC().Format("20060102150405")),
+		ReplacementIdentifier: instanceID + "-replacement",
+		RetiredIdentifier:     instanceID + "-retired",
+	}
+	for _, c := range changes {
+		replacement.Changes = append(replacement.Changes, c.field)
+	}
+
+	if _, err := createSnapshot(ctx, client, instanceID, replacement.SnapshotIdentifier, rdsOwnership.Tags(name)); err != nil {
+		return actionError(ctx, name, status, err)
+	}
+	status.Replacement = replacement
+
+	rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Replacing RDS instance %s to change %s",
+		instanceID, strings.Join(replacement.Changes, ", "))
+
+	details := fmt.Sprintf("Replacing RDS instance %s: taking snapshot %s", instanceID, replacement.SnapshotIdentifier)
+	return functional.ActionSuccess(status, details)
+}
+
+// checkReplacement drives a replacement through its stages. Once the snapshot is available, and copied
+// if the encryption changes, the replacement is restored from it under a temporary identifier. The old
+// instance is then renamed out of the way, the replacement takes over the instance identifier, and the
+// old instance is deleted with a final snapshot. The replacement snapshot is kept.
+func checkReplacement(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec RdsConfig,
+	status RdsStatus) (*functional.CheckResult[RdsStatus], error) {
+
+	instanceID := spec.InstanceID
+	replacement := status.Replacement
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID, "stage", replacement.Stage)
+
+	if err := resolveSpec(&spec); err != nil {
+		return checkError(ctx, name, status, fmt.Errorf("config validation failed: %w", err))
+	}
+
+	switch replacement.Stage {
+	case ReplacementSnapshotting, ReplacementCopying:
+		snapshot, err := getSnapshot(ctx, client, replacement.SnapshotIdentifier)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if snapshot == nil {
+			return checkError(ctx, name, status, fmt.Errorf("replacement snapshot %s not found", replacement.SnapshotIdentifier))
+		}
+
+		switch stringValue(snapshot.Status) {
+		case snapshotAvailable:
+		case snapshotCreating:
+			details := fmt.Sprintf("Replacing instance %s: waiting for snapshot %s", instanceID, replacement.SnapshotIdentifier)
+			return functional.CheckInProgress(status, details)
+		default:
+			return checkError(ctx, name, status, fmt.Errorf("replacement snapshot %s is %s",
+				replacement.SnapshotIdentifier, stringValue(snapshot.Status)))
+		}
+
+		// Restoring keeps the encryption of the snapshot, so a new key needs an encrypted copy first
+		if replacement.Stage == ReplacementSnapshotting {
+			if key := replacementKmsKey(&spec, replacement); key != "" {
+				target := replacement.SnapshotIdentifier + "-encrypted"
+				if _, err := copySnapshot(ctx, client, replacement.SnapshotIdentifier, target, key); err != nil {
+					return checkError(ctx, name, status, err)
+				}
+				replacement.SnapshotIdentifier = target
+				replacement.Stage = ReplacementCopying
+
+				details := fmt.Sprintf("Replacing instance %s: encrypting snapshot copy %s", instanceID, target)
+				return functional.CheckInProgress(status, details)
+			}
+		}
+
+		restore := spec
+		restore.InstanceID = replacement.ReplacementIdentifier
+		restore.RestoreFrom = &RestoreFrom{SnapshotIdentifier: replacement.SnapshotIdentifier}
+		if _, err := restoreInstance(ctx, client, &restore, rdsOwnership.WithTags(name, spec.Tags)); err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		replacement.Stage = ReplacementRestoring
+		status.TagKeys = tagKeys(spec.Tags)
+
+		details := fmt.Sprintf("Replacing instance %s: restoring %s", instanceID, replacement.ReplacementIdentifier)
+		return functional.CheckInProgress(status, details)
+
+	case ReplacementRestoring:
+		restored, err := getInstanceData(ctx, client, replacement.ReplacementIdentifier)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if restored == nil {
+			return checkError(ctx, name, status, fmt.Errorf("replacement instance %s not found", replacement.ReplacementIdentifier))
+		}
+		if RDSInstanceStatus(stringValue(restored.DBInstanceStatus)) != StatusAvailable {
+			details := fmt.Sprintf("Replacing instance %s: %s is %s", instanceID,
+				replacement.ReplacementIdentifier, stringValue(restored.DBInstanceStatus))
+			return functional.CheckInProgress(status, details)
+		}
+
+		// The old instance is deleted once the replacement took over, so it loses its protection
+		if err := renameInstance(ctx, client, instanceID, replacement.RetiredIdentifier, boolPtr(false)); err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		replacement.Stage = ReplacementRenaming
+
+		details := fmt.Sprintf("Replacing instance %s: renaming it to %s", instanceID, replacement.RetiredIdentifier)
+		return functional.CheckInProgress(status, details)
+
+	case ReplacementRenaming:
+		// The instance identifier is free once the rename completed
+		retired, err := getInstanceData(ctx, client, replacement.RetiredIdentifier)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if retired == nil || RDSInstanceStatus(stringValue(retired.DBInstanceStatus)) != StatusAvailable {
+			details := fmt.Sprintf("Replacing instance %s: waiting for the rename to %s", instanceID, replacement.RetiredIdentifier)
+			return functional.CheckInProgress(status, details)
+		}
+
+		if err := renameInstance(ctx, client, replacement.ReplacementIdentifier, instanceID, nil); err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		replacement.Stage = ReplacementSwapping
+
+		details := fmt.Sprintf("Replacing instance %s: renaming %s to it", instanceID, replacement.ReplacementIdentifier)
+		return functional.CheckInProgress(status, details)
+
+	case ReplacementSwapping:
+		instance, err := getInstanceData(ctx, client, instanceID)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if instance == nil || RDSInstanceStatus(stringValue(instance.DBInstanceStatus)) != StatusAvailable {
+			details := fmt.Sprintf("Replacing instance %s: waiting for the rename of %s", instanceID, replacement.ReplacementIdentifier)
+			return functional.CheckInProgress(status, details)
+		}
+
+		if err := deleteRetiredInstance(ctx, client, &spec, replacement.RetiredIdentifier); err != nil {
+			return checkError(ctx, name, status, err)
+		}
+
+		// Like any restored instance, the replacement still needs the settings the restore does not take
+		updateStatusFromInstance(&status, instance)
+		status.Replacement = nil
+		status.RestoredFrom = "snapshot " + replacement.SnapshotIdentifier
+		status.RestoreModifyPending = true
+		instancesByStatus.Set(name.String(), status.InstanceStatus)
+
+		log.Info("RDS instance replaced", "snapshotId", replacement.SnapshotIdentifier)
+		rdsEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Replaced RDS instance %s, deleting %s",
+			instanceID, replacement.RetiredIdentifier)
+
+		details := fmt.Sprintf("Replaced instance %s, deleting %s", instanceID, replacement.RetiredIdentifier)
+		return functional.CheckInProgress(status, details)
+
+	default:
+		return checkError(ctx, name, status, fmt.Errorf("unknown replacement stage %q", replacement.Stage))
+	}
+}
+
+// rollBackReplacement resolves an unfinished replacement before a Component with the Retain deletion
+// policy lets go of its instance, so that the instance is retained under its own identifier and nothing
+// is left under the temporary ones. Until the replacement takes over the instance identifier, the
+// replacement is deleted and the old instance, if already renamed, is renamed back by
+// checkRetainedReplacement. Once the replacement is taking over, it is finished instead: the retired
+// instance is deleted like at the end of any replacement, and the replacement is retained.
+func rollBackReplacement(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec RdsConfig,
+	status RdsStatus) (*functional.ActionResult[RdsStatus], error) {
+
+	instanceID := spec.InstanceID
+	replacement := status.Replacement
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID, "stage", replacement.Stage)
+
+	if replacement.Stage == ReplacementSwapping {
+		if err := deleteRetiredInstance(ctx, client, &spec, replacement.RetiredIdentifier); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+
+		log.Info("Finishing the replacement before retaining the instance")
+		details := fmt.Sprintf("Finishing the replacement of instance %s before retaining it", instanceID)
+		return functional.ActionSuccess(status, details)
+	}
+
+	if err := deleteReplacementInstance(ctx, client, replacement.ReplacementIdentifier); err != nil {
+		return actionError(ctx, name, status, err)
+	}
+
+	// The old instance is only renamed from the Renaming stage on
+	if replacement.Stage != ReplacementRenaming && replacement.Stage != ReplacementRollingBack {
+		status.Replacement = nil
+		return retainInstance(ctx, name, client, instanceID, status)
+	}
+	replacement.Stage = ReplacementRollingBack
+
+	log.Info("Rolling back the replacement before retaining the instance")
+	details := fmt.Sprintf("Rolling back the replacement of instance %s before retaining it", instanceID)
+	return functional.ActionSuccess(status, details)
+}
+
+// checkRetainedReplacement waits for the instance of a retained Component to be back under its own
+// identifier after rollBackReplacement, renaming the old instance back if needed, and retains it
+func checkRetainedReplacement(
+	ctx context.Context,
+	name types.NamespacedName,
+	client API,
+	spec RdsConfig,
+	status RdsStatus) (*functional.CheckResult[RdsStatus], error) {
+
+	instanceID := spec.InstanceID
+	replacement := status.Replacement
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID, "stage", replacement.Stage)
+
+	if err := resolveSpec(&spec); err != nil {
+		return checkError(ctx, name, status, fmt.Errorf("config validation failed: %w", err))
+	}
+
+	switch replacement.Stage {
+	case ReplacementSwapping:
+	case ReplacementRollingBack:
+		retired, err := getInstanceData(ctx, client, replacement.RetiredIdentifier)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if retired != nil {
+			if RDSInstanceStatus(stringValue(retired.DBInstanceStatus)) != StatusAvailable {
+				details := fmt.Sprintf("Rolling back the replacement of instance %s: waiting for %s", instanceID, replacement.RetiredIdentifier)
+				return functional.CheckInProgress(status, details)
+			}
+
+			// The rename to the retired identifier dropped the deletion protection
+			if err := renameInstance(ctx, client, replacement.RetiredIdentifier, instanceID, passthroughBoolPtr(spec.DeletionProtection)); err != nil {
+				return checkError(ctx, name, status, err)
+			}
+
+			details := fmt.Sprintf("Rolling back the replacement of instance %s: renaming %s back to it", instanceID, replacement.RetiredIdentifier)
+			return functional.CheckInProgress(status, details)
+		}
+	default:
+		return checkError(ctx, name, status, fmt.Errorf("unknown replacement stage %q", replacement.Stage))
+	}
+
+	instance, err := getInstanceData(ctx, client, instanceID)
+	if err != nil {
+		return checkError(ctx, name, status, err)
+	}
+	if instance == nil || RDSInstanceStatus(stringValue(instance.DBInstanceStatus)) != StatusAvailable {
+		details := fmt.Sprintf("Waiting for instance %s to take back its identifier before retaining it", instanceID)
+		return functional.CheckInProgress(status, details)
+	}
+
+	if err := releaseInstance(ctx, name, client, instance); err != nil {
+		return checkError(ctx, name, status, err)
+	}
+	status.Replacement = nil
+
+	log.Info("DeletionPolicy is Retain, leaving RDS instance in place")
+	rdsEvents.Normal(ctx, name, awsclient.ReasonRetained, "Retained RDS instance %s", instanceID)
+
+	return functional.CheckComplete(status, fmt.Sprintf("Instance %s retained", instanceID))
+}
+
+// deleteReplacementInstance deletes the instance an unfinished replacement restored, skipping the final
+// snapshot: it never took writes under its temporary identifier, and its snapshot is kept anyway
+func deleteReplacementInstance(ctx context.Context, client API, instanceID string) error {
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+
+	_, err := client.DeleteDBInstance(ctx, &rds.DeleteDBInstanceInput{
+		DBInstanceIdentifier: stringPtr(instanceID),
+		SkipFinalSnapshot:    boolPtr(true),
+	})
+	if err != nil {
+		if isInstanceNotFoundError(err) || isInstanceAlreadyBeingDeletedError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete replacement RDS instance %s: %w", instanceID, err)
+	}
+
+	log.Info("Replacement RDS instance deletion initiated successfully")
+	return nil
+}
+
+// replacementKmsKey returns the KMS key the replacement snapshot has to be copied with to change
+// the encryption, or "" if the replacement keeps the encryption of the instance
+func replacementKmsKey(config *RdsConfig, replacement *Replacement) string {
+	if !slices.Contains(replacement.Changes, "storageEncrypted") && !slices.Contains(replacement.Changes, "kmsKeyId") {
+		return ""
+	}
+	if config.KmsKeyId != "" {
+		return config.KmsKeyId
+	}
+	return defaultKmsKey
+}
+
+// renameInstance changes the identifier of an instance, optionally changing its deletion protection
+func renameInstance(ctx context.Context, client API, instanceID, newID string, deletionProtection *bool) error {
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+
+	log.Info("Renaming RDS instance", "newInstanceId", newID)
+
+	_, err := client.ModifyDBInstance(ctx, &rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier:    stringPtr(instanceID),
+		NewDBInstanceIdentifier: stringPtr(newID),
+		DeletionProtection:      deletionProtection,
+		ApplyImmediately:        boolPtr(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to rename RDS instance %s to %s: %w", instanceID, newID, err)
+	}
+
+	log.Info("RDS instance rename initiated successfully")
+	return nil
+}
+
+// deleteRetiredInstance deletes an instance left behind by a replacement or a blue/green upgrade.
+// It is skipped if the instance is already gone. The old instance keeps taking writes until the new
+// one takes over its identifier, so writes made after the replacement snapshot or during the
+// switchover are only on it: unless the config skips final snapshots, it gets a final snapshot named
+// by retiredSnapshotID.
+func deleteRetiredInstance(ctx context.Context, client API, config *RdsConfig, instanceID string) error {
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+
+	input := &rds.DeleteDBInstanceInput{
+		DBInstanceIdentifier: stringPtr(instanceID),
+		SkipFinalSnapshot:    boolPtr(skipFinalSnapshot(config)),
+	}
+	if !skipFinalSnapshot(config) {
+		input.FinalDBSnapshotIdentifier = stringPtr(retiredSnapshotID(config, instanceID, snapshotNow()))
+	}
+
+	_, err := client.DeleteDBInstance(ctx, input)
+	if err != nil {
+		if isInstanceNotFoundError(err) || isInstanceAlreadyBeingDeletedError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete retired RDS instance %s: %w", instanceID, err)
+	}
+
+	log.Info("Retired RDS instance deletion initiated successfully", "finalSnapshotId", stringValue(input.FinalDBSnapshotIdentifier))
+	return nil
+}
+
+// retiredSnapshotID names the final snapshot of a retired instance after finalDBSnapshotIdentifier,
+// or after the retired identifier, with a timestamp so that it never collides with the final
+// snapshot of the instance itself or of an earlier replacement
+func retiredSnapshotID(config *RdsConfig, retiredID string, now time.Time) string {
+	stamp := now.UTC().Format("20060102150405")
+	if config.FinalDBSnapshotIdentifier != "" {
+		return fmt.Sprintf("%s-%s-%s", config.FinalDBSnapshotIdentifier, strings.TrimPrefix(retiredID, config.InstanceID+"-"), stamp)
+	}
+	return fmt.Sprintf("%s-final-%s", retiredID, stamp)
+}
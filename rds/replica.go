@@ -0,0 +1,223 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Read replication states reported in the instance status infos
+const (
+	replicationStatusType = "read replication"
+
+	ReplicationReplicating = "replicating"
+	ReplicationError       = "error"
+	ReplicationStopped     = "stopped"
+	ReplicationTerminated  = "terminated"
+)
+
+// isReplica reports whether the config describes a read replica, promoted or not
+func (c *RdsConfig) isReplica() bool {
+	return c.ReplicaSourceIdentifier != ""
+}
+
+// validateReplica checks the read replica settings
+func validateReplica(config *RdsConfig) error {
+	source := config.ReplicaSourceIdentifier
+	if source == "" {
+		if config.PromoteReplica {
+			return fmt.Errorf("promoteReplica requires replicaSourceIdentifier")
+		}
+		if config.MaxReplicaLagSeconds != nil {
+			return fmt.Errorf("maxReplicaLagSeconds requires replicaSourceIdentifier")
+		}
+		return nil
+	}
+
+	if strings.HasPrefix(source, "arn:") && sourceRegion(source) == "" {
+		return fmt.Errorf("invalid replicaSourceIdentifier %q: must be an instance identifier or an RDS instance ARN", source)
+	}
+	if config.MaxReplicaLagSeconds != nil && *config.MaxReplicaLagSeconds <= 0 {
+		return fmt.Errorf("maxReplicaLagSeconds must be positive, got %d", *config.MaxReplicaLagSeconds)
+	}
+	return nil
+}
+
+// sourceRegion returns the region of a source instance ARN (arn:aws:rds:<region>:<account>:db:<id>),
+// or "" for a plain instance identifier
+func sourceRegion(source string) string {
+	parts := strings.Split(source, ":")
+	if len(parts) != 7 || parts[0] != "arn" || parts[2] != "rds" || parts[3] == "" || parts[5] != "db" || parts[6] == "" {
+		return ""
+	}
+	return parts[3]
+}
+
+// sourceInstanceID returns the instance identifier of a source given as identifier or ARN
+func sourceInstanceID(source string) string {
+	if i := strings.LastIndex(source, ":"); i >= 0 {
+		return source[i+1:]
+	}
+	return source
+}
+
+// replicaSource returns the source the instance replicates from, or "" if it is not a replica
+func replicaSource(instance *types.DBInstance) string {
+	return stringValue(instance.ReadReplicaSourceDBInstanceIdentifier)
+}
+
+// checkReplication verifies that an existing instance matches the replica settings of the config.
+// A standalone instance cannot become a replica, and a promoted replica cannot be demoted.
+func checkReplication(config *RdsConfig, instance *types.DBInstance) error {
+	source := replicaSource(instance)
+	switch {
+	case !config.isReplica() && source != "":
+		return fmt.Errorf("RDS instance %s is a read replica of %s; set replicaSourceIdentifier to manage it",
+			config.InstanceID, source)
+	case config.isReplica() && source == "" && !config.PromoteReplica:
+		return fmt.Errorf("RDS instance %s is not a read replica of %s; a promoted replica cannot be demoted",
+			config.InstanceID, config.ReplicaSourceIdentifier)
+	case config.isReplica() && source != "" && sourceInstanceID(source) != sourceInstanceID(config.ReplicaSourceIdentifier):
+		return fmt.Errorf("RDS instance %s replicates from %s, not %s; the source cannot be changed",
+			config.InstanceID, source, config.ReplicaSourceIdentifier)
+	}
+	return nil
+}
+
+// createReplica creates a read replica of the configured source instance in the given region
+func createReplica(
+	ctx context.Context,
+	client API,
+	config *RdsConfig,
+	region string,
+	tags map[string]string) (*types.DBInstance, error) {
+
+	instanceID := config.InstanceID
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+
+	log.Info("Creating RDS read replica",
+		"source", config.ReplicaSourceIdentifier,
+		"instanceClass", config.InstanceClass)
+
+	input := &rds.CreateDBInstanceReadReplicaInput{
+		DBInstanceIdentifier:       stringPtr(instanceID),
+		SourceDBInstanceIdentifier: stringPtr(config.ReplicaSourceIdentifier),
+		DBInstanceClass:            stringPtr(config.InstanceClass),
+
+		// Optional storage configuration; a cross-region replica of an encrypted source needs a KMS key in its region
+		AllocatedStorage:    passthroughPositiveInt32Ptr(&config.AllocatedStorage),
+		MaxAllocatedStorage: createMaxAllocatedStorage(config),
+		StorageType:         optionalStringPtr(config.StorageType),
+		Iops:                passthroughPositiveInt32Ptr(config.Iops),
+		StorageThroughput:   passthroughPositiveInt32Ptr(config.StorageThroughput),
+		KmsKeyId:            optionalStringPtr(config.KmsKeyId),
+
+		// Optional networking configuration
+		VpcSecurityGroupIds: config.VpcSecurityGroupIds,
+		DBSubnetGroupName:   optionalStringPtr(config.SubnetGroupName),
+		PubliclyAccessible:  passthroughBoolPtr(config.PubliclyAccessible),
+		Port:                passthroughInt32Ptr(config.Port),
+
+		// Optional security and logging configuration
+		EnableIAMDatabaseAuthentication: passthroughBoolPtr(config.IAMDatabaseAuthenticationEnabled),
+		EnableCloudwatchLogsExports:     config.EnableCloudwatchLogsExports,
+
+		// Optional parameter group
+		DBParameterGroupName: optionalStringPtr(config.ParameterGroupName),
+
+		// Optional maintenance and performance configuration
+		AutoMinorVersionUpgrade:   passthroughBoolPtr(config.AutoMinorVersionUpgrade),
+		MultiAZ:                   passthroughBoolPtr(config.MultiAZ),
+		EnablePerformanceInsights: passthroughBoolPtr(config.PerformanceInsightsEnabled),
+		MonitoringInterval:        passthroughPositiveInt32Ptr(config.MonitoringInterval),
+
+		DeletionProtection: passthroughBoolPtr(config.DeletionProtection),
+
+		Tags:               toRDSTags(tags),
+		CopyTagsToSnapshot: boolPtr(true),
+	}
+
+	// The SDK presigns the request in the source region for cross-region replicas
+	if source := sourceRegion(config.ReplicaSourceIdentifier); source != "" && source != region {
+		input.SourceRegion = stringPtr(source)
+	}
+
+	result, err := client.CreateDBInstanceReadReplica(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RDS read replica: %w", err)
+	}
+
+	log.Info("RDS read replica creation initiated successfully")
+
+	return result.DBInstance, nil
+}
+
+// promoteReplica turns a read replica into a standalone instance
+func promoteReplica(ctx context.Context, client API, config *RdsConfig) (*types.DBInstance, error) {
+	log := logf.FromContext(ctx).WithValues("instanceId", config.InstanceID)
+
+	log.Info("Promoting RDS read replica")
+
+	result, err := client.PromoteReadReplica(ctx, &rds.PromoteReadReplicaInput{
+		DBInstanceIdentifier:  stringPtr(config.InstanceID),
+		BackupRetentionPeriod: passthroughInt32Ptr(config.BackupRetentionPeriod),
+		PreferredBackupWindow: optionalStringPtr(config.PreferredBackupWindow),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to promote RDS read replica: %w", err)
+	}
+
+	log.Info("RDS read replica promotion initiated successfully")
+
+	return result.DBInstance, nil
+}
+
+// replicationStatus returns the read replication status info of a replica, or nil if there is none
+func replicationStatus(instance *types.DBInstance) *types.DBInstanceStatusInfo {
+	for i := range instance.StatusInfos {
+		if stringValue(instance.StatusInfos[i].StatusType) == replicationStatusType {
+			return &instance.StatusInfos[i]
+		}
+	}
+	return nil
+}
+
+// replicationBroken reports whether replication to the replica has failed or stopped
+func replicationBroken(info *types.DBInstanceStatusInfo) bool {
+	if info == nil {
+		return false
+	}
+	switch stringValue(info.Status) {
+	case ReplicationError, ReplicationStopped, ReplicationTerminated:
+		return true
+	default:
+		return !boolValue(info.Normal)
+	}
+}
+
+// replicaLag reads the latest ReplicaLag metric of the instance in whole seconds.
+// It returns nil if CloudWatch has no recent datapoint, e.g. right after creation,
+// or reports -1 because replication is not running.
+func replicaLag(ctx context.Context, access awsclient.AccessConfig, instanceID string) (*int64, error) {
+	lag, ok, err := rdsMetrics.Get(access).LatestMaximum(ctx, "AWS/RDS", "ReplicaLag",
+		map[string]string{"DBInstanceIdentifier": instanceID}, time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replica lag: %w", err)
+	}
+	if !ok || lag < 0 {
+		return nil, nil
+	}
+	seconds := int64(math.Round(lag))
+	return &seconds, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// String describes the restore source for events and plans
+func (r *RestoreFrom) String() string {
+	switch {
+	case r.SnapshotIdentifier != "":
+		return "snapshot " + r.SnapshotIdentifier
+	case r.UseLatestRestorableTime:
+		return fmt.Sprintf("instance %s at the latest restorable time", r.SourceInstanceIdentifier)
+	default:
+		return fmt.Sprintf("instance %s at %s", r.SourceInstanceIdentifier, r.RestoreTime)
+	}
+}
+
+// isRestore reports whether the config restores the instance instead of creating an empty one
+func (c *RdsConfig) isRestore() bool {
+	return c.RestoreFrom != nil
+}
+
+// validateRestore checks the restore settings
+func validateRestore(config *RdsConfig) error {
+	restore := config.RestoreFrom
+	if restore == nil {
+		return nil
+	}
+
+	if config.isReplica() {
+		return fmt.Errorf("restoreFrom cannot be combined with replicaSourceIdentifier")
+	}
+
+	switch {
+	case restore.SnapshotIdentifier != "" && restore.SourceInstanceIdentifier != "":
+		return fmt.Errorf("restoreFrom must set only one of snapshotIdentifier and sourceInstanceIdentifier")
+
+	case restore.SnapshotIdentifier != "":
+		if restore.RestoreTime != "" || restore.UseLatestRestorableTime {
+			return fmt.Errorf("restoreFrom.restoreTime and useLatestRestorableTime apply only to sourceInstanceIdentifier")
+		}
+
+	case restore.SourceInstanceIdentifier != "":
+		if (restore.RestoreTime != "") == restore.UseLatestRestorableTime {
+			return fmt.Errorf("restoreFrom.sourceInstanceIdentifier requires exactly one of restoreTime and useLatestRestorableTime")
+		}
+		if restore.RestoreTime != "" {
+			if _, err := time.Parse(time.RFC3339, restore.RestoreTime); err != nil {
+				return fmt.Errorf("invalid restoreFrom.restoreTime %q: must be an RFC 3339 timestamp", restore.RestoreTime)
+			}
+		}
+
+	default:
+		return fmt.Errorf("restoreFrom requires snapshotIdentifier or sourceInstanceIdentifier")
+	}
+
+	return nil
+}
+
+// restoreInstance creates the instance from the configured snapshot or point in time with the given tags.
+// The restore APIs do not accept the backup retention and the backup and maintenance windows;
+// checkApplied applies them with a modification once the instance is available.
+func restoreInstance(ctx context.Context, client API, config *RdsConfig, tags map[string]string) (*types.DBInstance, error) {
+	instanceID := config.InstanceID
+	restore := config.RestoreFrom
+
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID)
+
+	log.Info("Restoring RDS instance",
+		"source", restore.String(),
+		"instanceClass", config.InstanceClass)
+
+	if restore.SnapshotIdentifier != "" {
+		input := &rds.RestoreDBInstanceFromDBSnapshotInput{
+			DBInstanceIdentifier: stringPtr(instanceID),
+			DBSnapshotIdentifier: stringPtr(restore.SnapshotIdentifier),
+			DBInstanceClass:      stringPtr(config.InstanceClass),
+			Engine:               optionalStringPtr(config.DatabaseEngine),
+
+			// Managed password configuration
+			ManageMasterUserPassword: passthroughBoolPtr(config.ManageMasterUserPassword),
+
+			// Optional storage configuration; encryption follows the snapshot.
+			// The restore cannot turn on autoscaling, the modification after it does.
+			AllocatedStorage:  passthroughPositiveInt32Ptr(&config.AllocatedStorage),
+			StorageType:       optionalStringPtr(config.StorageType),
+			Iops:              passthroughPositiveInt32Ptr(config.Iops),
+			StorageThroughput: passthroughPositiveInt32Ptr(config.StorageThroughput),
+
+			// Optional networking configuration
+			VpcSecurityGroupIds: config.VpcSecurityGroupIds,
+			DBSubnetGroupName:   optionalStringPtr(config.SubnetGroupName),
+			PubliclyAccessible:  passthroughBoolPtr(config.PubliclyAccessible),
+			Port:                passthroughInt32Ptr(config.Port),
+
+			// Optional security and logging configuration
+			EnableIAMDatabaseAuthentication: passthroughBoolPtr(config.IAMDatabaseAuthenticationEnabled),
+			EnableCloudwatchLogsExports:     config.EnableCloudwatchLogsExports,
+
+			// Optional parameter group
+			DBParameterGroupName: optionalStringPtr(config.ParameterGroupName),
+
+			// Optional maintenance and availability configuration
+			AutoMinorVersionUpgrade: passthroughBoolPtr(config.AutoMinorVersionUpgrade),
+			MultiAZ:                 passthroughBoolPtr(config.MultiAZ),
+
+			DeletionProtection: passthroughBoolPtr(config.DeletionProtection),
+
+			Tags:               toRDSTags(tags),
+			CopyTagsToSnapshot: boolPtr(true),
+		}
+		if config.MasterUserSecretKmsKeyId != "" {
+			input.MasterUserSecretKmsKeyId = stringPtr(config.MasterUserSecretKmsKeyId)
+		}
+
+		result, err := client.RestoreDBInstanceFromDBSnapshot(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore RDS instance from snapshot: %w", err)
+		}
+
+		log.Info("RDS instance restore initiated successfully")
+
+		return result.DBInstance, nil
+	}
+
+	input := &rds.RestoreDBInstanceToPointInTimeInput{
+		TargetDBInstanceIdentifier: stringPtr(instanceID),
+		SourceDBInstanceIdentifier: stringPtr(restore.SourceInstanceIdentifier),
+		DBInstanceClass:            stringPtr(config.InstanceClass),
+		Engine:                     optionalStringPtr(config.DatabaseEngine),
+
+		// Managed password configuration
+		ManageMasterUserPassword: passthroughBoolPtr(config.ManageMasterUserPassword),
+
+		// Optional storage configuration; encryption follows the source
+		AllocatedStorage:    passthroughPositiveInt32Ptr(&config.AllocatedStorage),
+		MaxAllocatedStorage: createMaxAllocatedStorage(config),
+		StorageType:         optionalStringPtr(config.StorageType),
+		Iops:                passthroughPositiveInt32Ptr(config.Iops),
+		StorageThroughput:   passthroughPositiveInt32Ptr(config.StorageThroughput),
+
+		// Optional networking configuration
+		VpcSecurityGroupIds: config.VpcSecurityGroupIds,
+		DBSubnetGroupName:   optionalStringPtr(config.SubnetGroupName),
+		PubliclyAccessible:  passthroughBoolPtr(config.PubliclyAccessible),
+		Port:                passthroughInt32Ptr(config.Port),
+
+		// Optional security and logging configuration
+		EnableIAMDatabaseAuthentication: passthroughBoolPtr(config.IAMDatabaseAuthenticationEnabled),
+		EnableCloudwatchLogsExports:     config.EnableCloudwatchLogsExports,
+
+		// Optional parameter group
+		DBParameterGroupName: optionalStringPtr(config.ParameterGroupName),
+
+		// Optional maintenance and availability configuration
+		AutoMinorVersionUpgrade: passthroughBoolPtr(config.AutoMinorVersionUpgrade),
+		MultiAZ:                 passthroughBoolPtr(config.MultiAZ),
+
+		DeletionProtection: passthroughBoolPtr(config.DeletionProtection),
+
+		Tags:               toRDSTags(tags),
+		CopyTagsToSnapshot: boolPtr(true),
+	}
+	if config.MasterUserSecretKmsKeyId != "" {
+		input.MasterUserSecretKmsKeyId = stringPtr(config.MasterUserSecretKmsKeyId)
+	}
+	if restore.UseLatestRestorableTime {
+		input.UseLatestRestorableTime = boolPtr(true)
+	} else {
+		// Validated by resolveSpec
+		restoreTime, _ := time.Parse(time.RFC3339, restore.RestoreTime)
+		input.RestoreTime = &restoreTime
+	}
+
+	result, err := client.RestoreDBInstanceToPointInTime(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore RDS instance to point in time: %w", err)
+	}
+
+	log.Info("RDS instance restore initiated successfully")
+
+	return result.DBInstance, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Snapshot states reported by DescribeDBSnapshots
+const (
+	snapshotCreating  = "creating"
+	snapshotAvailable = "available"
+)
+
+// createSnapshot starts a manual snapshot of the instance with the given tags
+func createSnapshot(ctx context.Context, client API, instanceID, snapshotID string, tags map[string]string) (*types.DBSnapshot, error) {
+	log := logf.FromContext(ctx).WithValues("instanceId", instanceID, "snapshotId", snapshotID)
+
+	log.Info("Creating RDS snapshot")
+
+	result, err := client.CreateDBSnapshot(ctx, &rds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: stringPtr(instanceID),
+		DBSnapshotIdentifier: stringPtr(snapshotID),
+		Tags:                 toRDSTags(tags),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RDS snapshot %s: %w", snapshotID, err)
+	}
+
+	log.Info("RDS snapshot creation initiated successfully")
+
+	return result.DBSnapshot, nil
+}
+
+// copySnapshot starts a copy of an available snapshot encrypted with the given KMS key
+func copySnapshot(ctx context.Context, client API, sourceID, targetID, kmsKeyID string) (*types.DBSnapshot, error) {
+	log := logf.FromContext(ctx).WithValues("snapshotId", sourceID, "targetSnapshotId", targetID)
+
+	log.Info("Copying RDS snapshot", "kmsKeyId", kmsKeyID)
+
+	result, err := client.CopyDBSnapshot(ctx, &rds.CopyDBSnapshotInput{
+		SourceDBSnapshotIdentifier: stringPtr(sourceID),
+		TargetDBSnapshotIdentifier: stringPtr(targetID),
+		KmsKeyId:                   stringPtr(kmsKeyID),
+		CopyTags:                   boolPtr(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy RDS snapshot %s: %w", sourceID, err)
+	}
+
+	log.Info("RDS snapshot copy initiated successfully")
+
+	return result.DBSnapshot, nil
+}
+
+// getSnapshot retrieves a DB snapshot, or nil if it does not exist
+func getSnapshot(ctx context.Context, client API, snapshotID string) (*types.DBSnapshot, error) {
+	result, err := client.DescribeDBSnapshots(ctx, &rds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: stringPtr(snapshotID),
+	})
+	if err != nil {
+		var notFound *types.DBSnapshotNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe RDS snapshot %s: %w", snapshotID, err)
+	}
+
+	if len(result.DBSnapshots) == 0 {
+		return nil, nil
+	}
+	return &result.DBSnapshots[0], nil
+}
+
+// deleteSnapshot deletes a manual DB snapshot; a snapshot already gone counts as deleted
+func deleteSnapshot(ctx context.Context, client API, snapshotID string) error {
+	logf.FromContext(ctx).Info("Deleting RDS snapshot", "snapshotId", snapshotID)
+
+	_, err := client.DeleteDBSnapshot(ctx, &rds.DeleteDBSnapshotInput{DBSnapshotIdentifier: stringPtr(snapshotID)})
+	if err != nil {
+		var notFound *types.DBSnapshotNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete RDS snapshot %s: %w", snapshotID, err)
+	}
+	return nil
+}
+
+// listManualSnapshots returns the manual snapshots of the instance
+func listManualSnapshots(ctx context.Context, client API, instanceID string) ([]types.DBSnapshot, error) {
+	var snapshots []types.DBSnapshot
+	input := &rds.DescribeDBSnapshotsInput{
+		DBInstanceIdentifier: stringPtr(instanceID),
+		SnapshotType:         stringPtr("manual"),
+	}
+	for {
+		result, err := client.DescribeDBSnapshots(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list RDS snapshots of %s: %w", instanceID, err)
+		}
+		snapshots = append(snapshots, result.DBSnapshots...)
+		if result.Marker == nil {
+			return snapshots, nil
+		}
+		input.Marker = result.Marker
+	}
+}
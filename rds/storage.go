@@ -0,0 +1,176 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
+
+// Storage types of RDS instances
+const (
+	StorageStandard = "standard"
+	StorageGP2      = "gp2"
+	StorageGP3      = "gp3"
+	StorageIO1      = "io1"
+	StorageIO2      = "io2"
+)
+
+// defaultStorageType is the storage type of new instances that do not set one
+const defaultStorageType = StorageGP3
+
+// gp3MinProvisionedStorage is the allocated storage in GiB from which gp3 volumes of the open
+// source engines take provisioned IOPS and throughput; smaller volumes have a fixed baseline
+const gp3MinProvisionedStorage = 400
+
+// Provisioned IOPS limits of io1 and io2 volumes: the minimum IOPS and the maximum IOPS per GiB
+const (
+	minProvisionedIops = 1000
+	maxIo1IopsPerGiB   = 50
+	maxIo2IopsPerGiB   = 500
+)
+
+// storageNearLimitRatio is the share of the storage ceiling in use from which the health check degrades
+const storageNearLimitRatio = 0.9
+
+// validateStorage checks the storage settings against the storage type. Replicas and restored
+// instances without a storage type take the one of their source, so only the generic limits apply.
+func validateStorage(config *RdsConfig) error {
+	storage := config.AllocatedStorage
+	iops := int32Value(config.Iops)
+	throughput := int32Value(config.StorageThroughput)
+
+	if maxStorage := config.MaxAllocatedStorage; maxStorage != nil && storage > 0 {
+		// Autoscaling needs headroom of at least 10%; the allocated storage itself turns it off
+		if *maxStorage < storage || (*maxStorage > storage && *maxStorage*10 < storage*11) {
+			return fmt.Errorf("maxAllocatedStorage %d must equal allocatedStorage %d to turn off autoscaling, or exceed it by at least 10%%",
+				*maxStorage, storage)
+		}
+	}
+	if iops < 0 || throughput < 0 {
+		return fmt.Errorf("iops and storageThroughput cannot be negative")
+	}
+
+	storageType := config.StorageType
+	if storageType == "" && !config.isReplica() && !config.isRestore() {
+		storageType = defaultStorageType
+	}
+
+	switch storageType {
+	case "":
+		return nil
+
+	case StorageStandard, StorageGP2:
+		if iops > 0 || throughput > 0 {
+			return fmt.Errorf("storageType %s does not take iops or storageThroughput", storageType)
+		}
+
+	case StorageGP3:
+		provisioned := iops > 0 || throughput > 0
+		openSource := slices.Contains([]string{"postgres", "mysql", "mariadb"}, config.DatabaseEngine)
+		if provisioned && openSource && storage > 0 && storage < gp3MinProvisionedStorage {
+			return fmt.Errorf("storageType gp3 takes iops and storageThroughput only with allocatedStorage of at least %d GiB, got %d",
+				gp3MinProvisionedStorage, storage)
+		}
+
+	case StorageIO1, StorageIO2:
+		if throughput > 0 {
+			return fmt.Errorf("storageType %s does not take storageThroughput", storageType)
+		}
+		if iops < minProvisionedIops {
+			return fmt.Errorf("storageType %s requires iops of at least %d", storageType, minProvisionedIops)
+		}
+		maxPerGiB := int32(maxIo1IopsPerGiB)
+		if storageType == StorageIO2 {
+			maxPerGiB = maxIo2IopsPerGiB
+		}
+		if storage > 0 && iops > storage*maxPerGiB {
+			return fmt.Errorf("storageType %s allows at most %d iops per GiB, got %d iops for %d GiB",
+				storageType, maxPerGiB, iops, storage)
+		}
+
+	default:
+		return fmt.Errorf("invalid storageType %q: must be one of %s, %s, %s, %s, %s",
+			storageType, StorageStandard, StorageGP2, StorageGP3, StorageIO1, StorageIO2)
+	}
+
+	return nil
+}
+
+// createStorageType returns the storage type a new instance is created with
+func createStorageType(config *RdsConfig) string {
+	if config.StorageType != "" {
+		return config.StorageType
+	}
+	return defaultStorageType
+}
+
+// createStorageEncrypted returns whether a new instance is encrypted, which it is unless the config says otherwise
+func createStorageEncrypted(config *RdsConfig) *bool {
+	return boolPtr(config.StorageEncrypted == nil || *config.StorageEncrypted)
+}
+
+// createMaxAllocatedStorage returns the autoscaling maximum a new instance is created with, or nil
+// to create it without autoscaling
+func createMaxAllocatedStorage(config *RdsConfig) *int32 {
+	if config.MaxAllocatedStorage == nil || *config.MaxAllocatedStorage <= config.AllocatedStorage {
+		return nil
+	}
+	return passthroughInt32Ptr(config.MaxAllocatedStorage)
+}
+
+// storageGrowth returns the desired allocated storage if it grows the current one, nil otherwise.
+// RDS cannot shrink storage, and autoscaling may have grown it beyond the config.
+func storageGrowth(current, desired *int32) *int32 {
+	if desired == nil || (current != nil && *desired <= *current) {
+		return nil
+	}
+	return desired
+}
+
+// maxAllocatedStorage returns the autoscaling maximum of the instance. RDS reports none while
+// autoscaling is off, which is the same as a maximum equal to the allocated storage.
+func maxAllocatedStorage(instance *types.DBInstance) *int32 {
+	return firstSet(instance.MaxAllocatedStorage, instance.AllocatedStorage)
+}
+
+// maxStorageChange returns the autoscaling maximum to send, or nil if it is already in place.
+// Turning autoscaling off takes the current allocated storage as maximum, since autoscaling may
+// have grown it beyond the config.
+func maxStorageChange(current, desired *rds.ModifyDBInstanceInput) *int32 {
+	if desired.MaxAllocatedStorage != nil && desired.AllocatedStorage != nil &&
+		*desired.MaxAllocatedStorage <= *desired.AllocatedStorage {
+		return changed(current.MaxAllocatedStorage, current.AllocatedStorage)
+	}
+	return changed(current.MaxAllocatedStorage, desired.MaxAllocatedStorage)
+}
+
+// storageUsage returns the storage in use by the instance in GiB and the ceiling it can grow to:
+// the autoscaling maximum, or the allocated storage without autoscaling. It returns false if
+// CloudWatch has no recent FreeStorageSpace datapoint.
+func storageUsage(ctx context.Context, access awsclient.AccessConfig, instance *types.DBInstance) (float64, int32, bool, error) {
+	allocated := int32Value(instance.AllocatedStorage)
+	ceiling := max(int32Value(instance.MaxAllocatedStorage), allocated)
+	if ceiling == 0 {
+		return 0, 0, false, nil
+	}
+
+	free, ok, err := rdsMetrics.Get(access).LatestMaximum(ctx, "AWS/RDS", "FreeStorageSpace",
+		map[string]string{"DBInstanceIdentifier": stringValue(instance.DBInstanceIdentifier)}, 5*time.Minute)
+	if err != nil {
+		return 0, 0, false, fmt.Errorf("failed to read free storage space: %w", err)
+	}
+	if !ok {
+		return 0, 0, false, nil
+	}
+
+	const bytesPerGiB = 1 << 30
+	return float64(allocated) - free/bytesPerGiB, ceiling, true, nil
+}
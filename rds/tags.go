@@ -0,0 +1,113 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rds
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Tag key prefixes the config cannot use: AWS reserves aws: and ownership tags use componator.io/
+var reservedTagPrefixes = []string{"aws:", "componator.io/"}
+
+// validateTags checks that the config tags leave the reserved keys alone
+func validateTags(tags map[string]string) error {
+	for key := range tags {
+		if key == "" {
+			return fmt.Errorf("tags cannot have an empty key")
+		}
+		for _, prefix := range reservedTagPrefixes {
+			if strings.HasPrefix(key, prefix) {
+				return fmt.Errorf("tag key %q uses the reserved prefix %s", key, prefix)
+			}
+		}
+	}
+	return nil
+}
+
+// tagKeys returns the sorted keys of the config tags, recorded in status as the keys the provider owns
+func tagKeys(tags map[string]string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(tags))
+}
+
+// tagChanges compares the config tags with the instance tags. It returns the tags to add or update,
+// and the keys to remove: those the provider set before that are no longer in the config. Tags set
+// by others, like AWS Backup or cost allocation tooling, are never removed.
+func tagChanges(instance *types.DBInstance, desired map[string]string, ownedKeys []string) (map[string]string, []string) {
+	current := fromRDSTags(instance.TagList)
+
+	add := make(map[string]string)
+	for key, value := range desired {
+		if existing, ok := current[key]; !ok || existing != value {
+			add[key] = value
+		}
+	}
+
+	var remove []string
+	for _, key := range ownedKeys {
+		if _, keep := desired[key]; keep {
+			continue
+		}
+		if _, ok := current[key]; ok {
+			remove = append(remove, key)
+		}
+	}
+	return add, remove
+}
+
+// reconcileTags brings the config tags of an existing instance in line with the config.
+// It returns whether any tag changed.
+func reconcileTags(ctx context.Context, client API, instance *types.DBInstance, desired map[string]string, ownedKeys []string) (bool, error) {
+	add, remove := tagChanges(instance, desired, ownedKeys)
+	if len(add) == 0 && len(remove) == 0 {
+		return false, nil
+	}
+
+	log := logf.FromContext(ctx).WithValues("instanceId", stringValue(instance.DBInstanceIdentifier))
+	log.Info("Updating RDS instance tags", "add", tagKeys(add), "remove", remove)
+
+	instanceArn := stringValue(instance.DBInstanceArn)
+	if len(add) > 0 {
+		if err := tagInstance(ctx, client, instanceArn, add); err != nil {
+			return false, err
+		}
+	}
+	if err := untagInstance(ctx, client, instanceArn, remove); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// planTags records the tag changes reconcileTags would make
+func planTags(plan *awsclient.Plan, instance *types.DBInstance, desired map[string]string, ownedKeys []string) {
+	if instance == nil {
+		for _, key := range tagKeys(desired) {
+			plan.Add("tags."+key, desired[key])
+		}
+		return
+	}
+
+	current := fromRDSTags(instance.TagList)
+	add, remove := tagChanges(instance, desired, ownedKeys)
+	for _, key := range tagKeys(add) {
+		if existing, ok := current[key]; ok {
+			plan.Modify("tags."+key, existing, add[key])
+		} else {
+			plan.Add("tags."+key, add[key])
+		}
+	}
+	for _, key := range remove {
+		plan.Remove("tags."+key, current[key])
+	}
+}
@@ -0,0 +1,509 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// API is the subset of the AWS RDS API used by this provider.
+// It is satisfied by *rds.Client and by awsfake.RDS in unit tests.
+// Embedding programs may supply their own implementation with WithClient.
+type API interface {
+	DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error)
+	CreateDBCluster(ctx context.Context, params *rds.CreateDBClusterInput, optFns ...func(*rds.Options)) (*rds.CreateDBClusterOutput, error)
+	ModifyDBCluster(ctx context.Context, params *rds.ModifyDBClusterInput, optFns ...func(*rds.Options)) (*rds.ModifyDBClusterOutput, error)
+	DeleteDBCluster(ctx context.Context, params *rds.DeleteDBClusterInput, optFns ...func(*rds.Options)) (*rds.DeleteDBClusterOutput, error)
+	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	CreateDBInstance(ctx context.Context, params *rds.CreateDBInstanceInput, optFns ...func(*rds.Options)) (*rds.CreateDBInstanceOutput, error)
+	ModifyDBInstance(ctx context.Context, params *rds.ModifyDBInstanceInput, optFns ...func(*rds.Options)) (*rds.ModifyDBInstanceOutput, error)
+	DeleteDBInstance(ctx context.Context, params *rds.DeleteDBInstanceInput, optFns ...func(*rds.Options)) (*rds.DeleteDBInstanceOutput, error)
+	AddTagsToResource(ctx context.Context, params *rds.AddTagsToResourceInput, optFns ...func(*rds.Options)) (*rds.AddTagsToResourceOutput, error)
+	RemoveTagsFromResource(ctx context.Context, params *rds.RemoveTagsFromResourceInput, optFns ...func(*rds.Options)) (*rds.RemoveTagsFromResourceOutput, error)
+}
+
+// Package-level singletons initialized during registration
+var (
+	clusterClients   *awsclient.Cache[API]
+	clusterOwnership *awsclient.Ownership
+)
+
+// ReadinessProbe checks that the RDS API is reachable with the controller's own credentials
+func ReadinessProbe(ctx context.Context) error {
+	_, err := clusterClients.Get(awsclient.AccessConfig{}).DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
+		MaxRecords: aws.Int32(20), // smallest page RDS accepts
+	})
+	return err
+}
+
+// RDSClusterStatus represents AWS Aurora DB cluster status values.
+// Each lifecycle function (checkApplied, checkHealth, checkDeleted) interprets
+// these states according to its specific responsibilities.
+type RDSClusterStatus string
+
+// Deployment and modification states - operations that change cluster configuration
+const (
+	StatusCreating                   RDSClusterStatus = "creating"
+	StatusModifying                  RDSClusterStatus = "modifying"
+	StatusUpgrading                  RDSClusterStatus = "upgrading"
+	StatusRenaming                   RDSClusterStatus = "renaming"
+	StatusResettingMasterCredentials RDSClusterStatus = "resetting-master-credentials"
+)
+
+// Operational healthy states - cluster is available and accepting connections
+const (
+	StatusAvailable    RDSClusterStatus = "available"
+	StatusBackingUp    RDSClusterStatus = "backing-up"
+	StatusBacktracking RDSClusterStatus = "backtracking"
+)
+
+// Operational degraded states - cluster has operational issues
+const (
+	StatusMaintenance RDSClusterStatus = "maintenance"
+	StatusFailingOver RDSClusterStatus = "failing-over"
+	StatusStarting    RDSClusterStatus = "starting"
+	StatusStopped     RDSClusterStatus = "stopped"
+	StatusStopping    RDSClusterStatus = "stopping"
+)
+
+// Failed states - deployment or operational failures
+const (
+	StatusInaccessibleEncryptionCredentials RDSClusterStatus = "inaccessible-encryption-credentials"
+	StatusMigrationFailed                   RDSClusterStatus = "migration-failed"
+	StatusCloningFailed                     RDSClusterStatus = "cloning-failed"
+)
+
+// Deletion states
+const (
+	StatusDeleting RDSClusterStatus = "deleting"
+)
+
+// Instance states that matter for cluster members
+const (
+	instanceAvailable = "available"
+	instanceDeleting  = "deleting"
+)
+
+// getClusterData retrieves cluster data, handling not-found cases consistently
+func getClusterData(ctx context.Context, client API, clusterID string) (*types.DBCluster, error) {
+	result, err := client.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(clusterID),
+	})
+	if err != nil {
+		if isClusterNotFoundError(err) {
+			return nil, nil // Cluster not found - return nil without error
+		}
+		return nil, fmt.Errorf("failed to describe RDS cluster: %w", err)
+	}
+
+	if len(result.DBClusters) == 0 {
+		return nil, nil // No clusters returned - treat as not found
+	}
+
+	return &result.DBClusters[0], nil
+}
+
+// listClusterInstances returns the DB instances that are members of the cluster
+func listClusterInstances(ctx context.Context, client API, clusterID string) ([]types.DBInstance, error) {
+	input := &rds.DescribeDBInstancesInput{
+		Filters: []types.Filter{{Name: aws.String("db-cluster-id"), Values: []string{clusterID}}},
+	}
+
+	var instances []types.DBInstance
+	paginator := rds.NewDescribeDBInstancesPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list RDS cluster instances: %w", err)
+		}
+		instances = append(instances, page.DBInstances...)
+	}
+	return instances, nil
+}
+
+// createCluster creates an Aurora cluster with the given tags. Its instances are
+// created once the cluster is available.
+func createCluster(ctx context.Context, client API, config *RdsClusterConfig, tags map[string]string) (*types.DBCluster, error) {
+	log := logf.FromContext(ctx).WithValues("clusterId", config.ClusterID)
+
+	log.Info("Creating RDS cluster",
+		"databaseName", config.DatabaseName,
+		"databaseEngine", config.DatabaseEngine,
+		"engineVersion", config.EngineVersion)
+
+	createInput := &rds.CreateDBClusterInput{
+		DBClusterIdentifier: aws.String(config.ClusterID),
+		Engine:              aws.String(config.DatabaseEngine),
+		EngineVersion:       optionalString(config.EngineVersion),
+		DatabaseName:        optionalString(config.DatabaseName),
+		MasterUsername:      aws.String(config.MasterUsername),
+
+		// Managed password configuration
+		ManageMasterUserPassword: config.ManageMasterUserPassword,
+
+		// Storage configuration
+		StorageEncrypted: config.StorageEncrypted,
+		KmsKeyId:         optionalString(config.KmsKeyId),
+
+		// Networking configuration
+		VpcSecurityGroupIds: config.VpcSecurityGroupIds,
+		DBSubnetGroupName:   optionalString(config.SubnetGroupName),
+		Port:                config.Port,
+
+		// Backup and maintenance configuration
+		BackupRetentionPeriod:      config.BackupRetentionPeriod,
+		PreferredBackupWindow:      optionalString(config.PreferredBackupWindow),
+		PreferredMaintenanceWindow: optionalString(config.PreferredMaintenanceWindow),
+
+		ServerlessV2ScalingConfiguration: scalingConfiguration(config.ServerlessV2Scaling),
+
+		DeletionProtection: config.DeletionProtection,
+
+		Tags: toRDSTags(tags),
+	}
+
+	// AWS doesn't ignore a nil KMS ID for this arg, so we must set it only if provided
+	if config.MasterUserSecretKmsKeyId != "" {
+		createInput.MasterUserSecretKmsKeyId = aws.String(config.MasterUserSecretKmsKeyId)
+	}
+
+	result, err := client.CreateDBCluster(ctx, createInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RDS cluster: %w", err)
+	}
+
+	log.Info("RDS cluster creation initiated successfully")
+
+	return result.DBCluster, nil
+}
+
+// modifyCluster sends the ModifyDBCluster request built by buildModifyInput
+func modifyCluster(ctx context.Context, client API, input *rds.ModifyDBClusterInput) (*types.DBCluster, error) {
+	log := logf.FromContext(ctx).WithValues("clusterId", aws.ToString(input.DBClusterIdentifier))
+
+	log.Info("Modifying RDS cluster", "applyImmediately", aws.ToBool(input.ApplyImmediately))
+
+	result, err := client.ModifyDBCluster(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to modify RDS cluster: %w", err)
+	}
+
+	log.Info("RDS cluster modification initiated successfully")
+
+	return result.DBCluster, nil
+}
+
+// buildModifyInput builds the ModifyDBCluster request for the settings that differ between the
+// config and the cluster, or returns nil if the cluster matches its config. Plans compare the same
+// request against the current cluster.
+//
+// An engine upgrade waits for the maintenance window when applyChanges.engineVersion says so, and
+// is not sent again while it is pending. The other cluster settings take effect right away whatever
+// ApplyImmediately says, so deletion protection can still be lifted before a cleanup.
+func buildModifyInput(config *RdsClusterConfig, cluster *types.DBCluster) *rds.ModifyDBClusterInput {
+	input := &rds.ModifyDBClusterInput{
+		DBClusterIdentifier: aws.String(config.ClusterID),
+		ApplyImmediately:    aws.Bool(config.ApplyChanges.EngineVersion != ApplyDuringMaintenanceWindow),
+	}
+
+	if !pendingEngineVersion(cluster, config.EngineVersion) {
+		input.EngineVersion = changed(cluster.EngineVersion, optionalString(config.EngineVersion))
+	}
+	input.BackupRetentionPeriod = changed(cluster.BackupRetentionPeriod, config.BackupRetentionPeriod)
+	input.PreferredBackupWindow = changed(cluster.PreferredBackupWindow, optionalString(config.PreferredBackupWindow))
+	input.PreferredMaintenanceWindow = changed(cluster.PreferredMaintenanceWindow, optionalString(config.PreferredMaintenanceWindow))
+	input.DeletionProtection = changed(cluster.DeletionProtection, config.DeletionProtection)
+	if scalingChanged(cluster.ServerlessV2ScalingConfiguration, config.ServerlessV2Scaling) {
+		input.ServerlessV2ScalingConfiguration = scalingConfiguration(config.ServerlessV2Scaling)
+	}
+
+	if input.EngineVersion == nil && input.BackupRetentionPeriod == nil && input.PreferredBackupWindow == nil &&
+		input.PreferredMaintenanceWindow == nil && input.DeletionProtection == nil &&
+		input.ServerlessV2ScalingConfiguration == nil {
+		return nil
+	}
+	return input
+}
+
+// changed returns desired if it is set and differs from current, and nil otherwise
+func changed[T comparable](current, desired *T) *T {
+	if desired == nil || (current != nil && *current == *desired) {
+		return nil
+	}
+	return desired
+}
+
+// pendingEngineVersion reports whether an upgrade to version is already waiting for the maintenance window
+func pendingEngineVersion(cluster *types.DBCluster, version string) bool {
+	return version != "" && cluster.PendingModifiedValues != nil &&
+		aws.ToString(cluster.PendingModifiedValues.EngineVersion) == version
+}
+
+// scalingChanged reports whether the configured Serverless v2 capacity range differs from the cluster's
+func scalingChanged(current *types.ServerlessV2ScalingConfigurationInfo, desired *ServerlessV2Scaling) bool {
+	if desired == nil {
+		return false
+	}
+	if current == nil {
+		return true
+	}
+	return aws.ToFloat64(current.MinCapacity) != desired.MinCapacity || aws.ToFloat64(current.MaxCapacity) != desired.MaxCapacity
+}
+
+// deleteCluster deletes the cluster once all its instances are being deleted
+func deleteCluster(ctx context.Context, client API, config *RdsClusterConfig) (*types.DBCluster, error) {
+	log := logf.FromContext(ctx).WithValues("clusterId", config.ClusterID)
+
+	deleteInput := &rds.DeleteDBClusterInput{
+		DBClusterIdentifier: aws.String(config.ClusterID),
+		SkipFinalSnapshot:   aws.Bool(aws.ToBool(config.SkipFinalSnapshot)),
+	}
+	if !aws.ToBool(config.SkipFinalSnapshot) {
+		deleteInput.FinalDBSnapshotIdentifier = aws.String(config.FinalDBSnapshotIdentifier)
+	}
+
+	log.Info("Deleting RDS cluster", "skipFinalSnapshot", aws.ToBool(deleteInput.SkipFinalSnapshot))
+
+	result, err := client.DeleteDBCluster(ctx, deleteInput)
+	if err != nil {
+		if isClusterNotFoundError(err) {
+			log.Info("RDS cluster already deleted")
+			return nil, nil
+		}
+		if isAlreadyBeingDeletedError(err) {
+			log.Info("RDS cluster is already being deleted")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("delete RDS cluster call failed: %w", err)
+	}
+
+	log.Info("RDS cluster deletion initiated successfully")
+
+	return result.DBCluster, nil
+}
+
+// createClusterInstance adds a DB instance to the cluster
+func createClusterInstance(
+	ctx context.Context, client API, config *RdsClusterConfig, instanceID string, tags map[string]string) error {
+
+	log := logf.FromContext(ctx).WithValues("clusterId", config.ClusterID, "instanceId", instanceID)
+
+	_, err := client.CreateDBInstance(ctx, &rds.CreateDBInstanceInput{
+		DBInstanceIdentifier:      aws.String(instanceID),
+		DBClusterIdentifier:       aws.String(config.ClusterID),
+		DBInstanceClass:           aws.String(config.InstanceClass),
+		Engine:                    aws.String(config.DatabaseEngine),
+		PubliclyAccessible:        config.PubliclyAccessible,
+		AutoMinorVersionUpgrade:   config.AutoMinorVersionUpgrade,
+		EnablePerformanceInsights: config.PerformanceInsightsEnabled,
+		Tags:                      toRDSTags(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create RDS cluster instance %s: %w", instanceID, err)
+	}
+
+	log.Info("RDS cluster instance creation initiated successfully", "instanceClass", config.InstanceClass)
+	return nil
+}
+
+// modifyClusterInstanceClass changes the instance class of a cluster member, right away or in the
+// maintenance window as applyChanges.instanceClass says
+func modifyClusterInstanceClass(ctx context.Context, client API, config *RdsClusterConfig, instanceID string) error {
+	immediately := config.ApplyChanges.InstanceClass != ApplyDuringMaintenanceWindow
+	_, err := client.ModifyDBInstance(ctx, &rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(instanceID),
+		DBInstanceClass:      aws.String(config.InstanceClass),
+		ApplyImmediately:     aws.Bool(immediately),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to modify RDS cluster instance %s: %w", instanceID, err)
+	}
+
+	logf.FromContext(ctx).Info("RDS cluster instance modification initiated successfully",
+		"instanceId", instanceID, "instanceClass", config.InstanceClass, "applyImmediately", immediately)
+	return nil
+}
+
+// deleteClusterInstance removes a DB instance from its cluster.
+// Snapshots are taken of the whole cluster, so members are deleted without one.
+func deleteClusterInstance(ctx context.Context, client API, instanceID string) error {
+	_, err := client.DeleteDBInstance(ctx, &rds.DeleteDBInstanceInput{
+		DBInstanceIdentifier: aws.String(instanceID),
+	})
+	if err != nil {
+		if isInstanceNotFoundError(err) || isAlreadyBeingDeletedError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete RDS cluster instance %s: %w", instanceID, err)
+	}
+
+	logf.FromContext(ctx).Info("RDS cluster instance deletion initiated successfully", "instanceId", instanceID)
+	return nil
+}
+
+// updateStatusFromCluster updates RdsClusterStatus fields from AWS DBCluster data
+func updateStatusFromCluster(status *RdsClusterStatus, cluster *types.DBCluster) {
+	if cluster == nil {
+		return
+	}
+
+	status.ClusterStatus = aws.ToString(cluster.Status)
+	status.ClusterARN = aws.ToString(cluster.DBClusterArn)
+	status.Endpoint = aws.ToString(cluster.Endpoint)
+	status.ReaderEndpoint = aws.ToString(cluster.ReaderEndpoint)
+	status.Port = aws.ToInt32(cluster.Port)
+
+	status.WriterInstance = ""
+	status.ReaderInstances = nil
+	for _, member := range cluster.DBClusterMembers {
+		if aws.ToBool(member.IsClusterWriter) {
+			status.WriterInstance = aws.ToString(member.DBInstanceIdentifier)
+		} else {
+			status.ReaderInstances = append(status.ReaderInstances, aws.ToString(member.DBInstanceIdentifier))
+		}
+	}
+	slices.Sort(status.ReaderInstances)
+
+	// The ARN is immutable once created; keep the existing value if the response omits it
+	if cluster.MasterUserSecret != nil && cluster.MasterUserSecret.SecretArn != nil {
+		status.MasterUserSecretArn = *cluster.MasterUserSecret.SecretArn
+	}
+}
+
+// writerInstance returns the identifier of the cluster's writer, or "" if it has none
+func writerInstance(cluster *types.DBCluster) string {
+	for _, member := range cluster.DBClusterMembers {
+		if aws.ToBool(member.IsClusterWriter) {
+			return aws.ToString(member.DBInstanceIdentifier)
+		}
+	}
+	return ""
+}
+
+// scalingConfiguration converts the Serverless v2 scaling config for AWS requests
+func scalingConfiguration(scaling *ServerlessV2Scaling) *types.ServerlessV2ScalingConfiguration {
+	if scaling == nil {
+		return nil
+	}
+	return &types.ServerlessV2ScalingConfiguration{
+		MinCapacity: aws.Float64(scaling.MinCapacity),
+		MaxCapacity: aws.Float64(scaling.MaxCapacity),
+	}
+}
+
+// isClusterNotFoundError checks if the error indicates the cluster was not found
+func isClusterNotFoundError(err error) bool {
+	var notFoundErr *types.DBClusterNotFoundFault
+	return errors.As(err, &notFoundErr)
+}
+
+// isInstanceNotFoundError checks if the error indicates the DB instance was not found
+func isInstanceNotFoundError(err error) bool {
+	var notFoundErr *types.DBInstanceNotFoundFault
+	return errors.As(err, &notFoundErr)
+}
+
+// isAlreadyBeingDeletedError checks if the error indicates the cluster or instance is already being deleted
+func isAlreadyBeingDeletedError(err error) bool {
+	var clusterStateErr *types.InvalidDBClusterStateFault
+	if errors.As(err, &clusterStateErr) {
+		return strings.Contains(strings.ToLower(clusterStateErr.ErrorMessage()), "already being deleted")
+	}
+
+	var instanceStateErr *types.InvalidDBInstanceStateFault
+	if errors.As(err, &instanceStateErr) {
+		return strings.Contains(strings.ToLower(instanceStateErr.ErrorMessage()), "already being deleted")
+	}
+
+	return false
+}
+
+// clusterErrorClassifier wraps the AWS SDK retry logic for use with result builder utilities.
+// Register replaces it when an embedding program supplies WithErrorClassifier.
+var clusterErrorClassifier = controller.ErrorClassifier(isRetryable)
+
+// isRetryable determines if an error is retryable using AWS SDK's built-in error classification.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, checker := range retry.DefaultRetryables {
+		if checker.IsErrorRetryable(err) == aws.TrueTernary {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tagResource adds tags to an existing cluster or instance
+func tagResource(ctx context.Context, client API, arn string, tags map[string]string) error {
+	_, err := client.AddTagsToResource(ctx, &rds.AddTagsToResourceInput{
+		ResourceName: aws.String(arn),
+		Tags:         toRDSTags(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag %s: %w", arn, err)
+	}
+	return nil
+}
+
+// untagResource removes tags from a cluster or instance
+func untagResource(ctx context.Context, client API, arn string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := client.RemoveTagsFromResource(ctx, &rds.RemoveTagsFromResourceInput{
+		ResourceName: aws.String(arn),
+		TagKeys:      keys,
+	})
+	if err != nil && !isClusterNotFoundError(err) && !isInstanceNotFoundError(err) {
+		return fmt.Errorf("failed to untag %s: %w", arn, err)
+	}
+	return nil
+}
+
+// toRDSTags converts a map to an RDS tag slice
+func toRDSTags(tags map[string]string) []types.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	result := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		result = append(result, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return result
+}
+
+// fromRDSTags converts an RDS tag slice to a map
+func fromRDSTags(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+// optionalString converts a string to *string, returning nil for empty strings
+// so that AWS applies its default
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
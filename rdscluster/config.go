@@ -0,0 +1,307 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
+
+// Supported Aurora engines
+const (
+	EngineAuroraPostgreSQL = "aurora-postgresql"
+	EngineAuroraMySQL      = "aurora-mysql"
+)
+
+// ServerlessInstanceClass makes cluster instances Aurora Serverless v2 instances
+const ServerlessInstanceClass = "db.serverless"
+
+// Serverless v2 capacity limits in Aurora capacity units (ACUs)
+const (
+	minServerlessCapacity = 0
+	maxServerlessCapacity = 256
+)
+
+// RdsClusterConfig represents the configuration structure for rds-cluster components
+// that gets unmarshaled from Component.Spec.Config
+type RdsClusterConfig struct {
+	// AccessConfig optionally selects the AWS region and a role to assume in another account
+	awsclient.AccessConfig
+
+	// Cluster Configuration - Required
+	ClusterID string `json:"clusterID"`
+
+	// Core Database Configuration
+	DatabaseEngine string `json:"databaseEngine"`
+	EngineVersion  string `json:"engineVersion,omitempty"`
+	DatabaseName   string `json:"databaseName,omitempty"`
+
+	// Instance Configuration - one writer plus readerCount readers, named <clusterID>-1, <clusterID>-2, ...
+	InstanceClass string `json:"instanceClass"`
+	ReaderCount   int32  `json:"readerCount,omitempty"`
+
+	// Serverless v2 scaling, required when instanceClass is db.serverless
+	ServerlessV2Scaling *ServerlessV2Scaling `json:"serverlessV2Scaling,omitempty"`
+
+	// Storage Configuration
+	StorageEncrypted *bool  `json:"storageEncrypted,omitempty"`
+	KmsKeyId         string `json:"kmsKeyId,omitempty"`
+
+	// Database Credentials
+	MasterUsername           string `json:"masterUsername"`
+	ManageMasterUserPassword *bool  `json:"manageMasterUserPassword,omitempty"`
+	MasterUserSecretKmsKeyId string `json:"masterUserSecretKmsKeyId,omitempty"`
+
+	// Networking Configuration
+	VpcSecurityGroupIds []string `json:"vpcSecurityGroupIds,omitempty"`
+	SubnetGroupName     string   `json:"subnetGroupName,omitempty"`
+	PubliclyAccessible  *bool    `json:"publiclyAccessible,omitempty"`
+	Port                *int32   `json:"port,omitempty"`
+
+	// Backup Configuration
+	BackupRetentionPeriod *int32 `json:"backupRetentionPeriod,omitempty"`
+	PreferredBackupWindow string `json:"preferredBackupWindow,omitempty"`
+
+	// Maintenance Configuration
+	PreferredMaintenanceWindow string `json:"preferredMaintenanceWindow,omitempty"`
+	AutoMinorVersionUpgrade    *bool  `json:"autoMinorVersionUpgrade,omitempty"`
+
+	// When engine upgrades and instance class changes take effect
+	ApplyChanges ApplyChanges `json:"applyChanges,omitempty"`
+
+	// Performance Configuration
+	PerformanceInsightsEnabled *bool `json:"performanceInsightsEnabled,omitempty"`
+
+	// Deletion Protection
+	DeletionProtection        *bool  `json:"deletionProtection,omitempty"`
+	SkipFinalSnapshot         *bool  `json:"skipFinalSnapshot,omitempty"`
+	FinalDBSnapshotIdentifier string `json:"finalDBSnapshotIdentifier,omitempty"`
+
+	// Ownership - Never (default), IfUntagged or Always take over an existing cluster
+	// that is not tagged as owned by this Component
+	AdoptionPolicy string `json:"adoptionPolicy,omitempty"`
+
+	// Delete (default) or Retain, which keeps the cluster and its instances on Component
+	// deletion and removes their ownership tags
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+// Modification timings of a change class
+const (
+	ApplyImmediately             = "Immediately"
+	ApplyDuringMaintenanceWindow = "MaintenanceWindow"
+)
+
+// ApplyChanges selects when modifications of each change class take effect: Immediately (default),
+// or MaintenanceWindow to leave them pending until the next maintenance window. Changes to other
+// cluster settings, like the backup retention, scaling or deletion protection, always apply immediately.
+type ApplyChanges struct {
+	// InstanceClass covers instanceClass changes of the cluster members
+	InstanceClass string `json:"instanceClass,omitempty"`
+
+	// EngineVersion covers engineVersion upgrades
+	EngineVersion string `json:"engineVersion,omitempty"`
+}
+
+// validate checks that every change class has a known timing
+func (a *ApplyChanges) validate() error {
+	timings := []struct{ class, timing string }{
+		{"instanceClass", a.InstanceClass},
+		{"engineVersion", a.EngineVersion},
+	}
+	for _, t := range timings {
+		switch t.timing {
+		case "", ApplyImmediately, ApplyDuringMaintenanceWindow:
+		default:
+			return fmt.Errorf("invalid applyChanges.%s %q: must be %s or %s",
+				t.class, t.timing, ApplyImmediately, ApplyDuringMaintenanceWindow)
+		}
+	}
+	return nil
+}
+
+// ServerlessV2Scaling is the capacity range of Serverless v2 instances in ACUs
+type ServerlessV2Scaling struct {
+	MinCapacity float64 `json:"minCapacity"`
+	MaxCapacity float64 `json:"maxCapacity"`
+}
+
+// RdsClusterStatus contains handler-specific status data for rds-cluster deployments.
+// This data is persisted across reconciliation loops in Component.Status.ProviderStatus.
+type RdsClusterStatus struct {
+	// Cluster identification and state
+	ClusterStatus string `json:"clusterStatus,omitempty"`
+	ClusterARN    string `json:"clusterARN,omitempty"`
+
+	// Network information - the writer (cluster) and reader endpoints
+	Endpoint       string `json:"endpoint,omitempty"`
+	ReaderEndpoint string `json:"readerEndpoint,omitempty"`
+	Port           int32  `json:"port,omitempty"`
+
+	// Cluster members by role
+	WriterInstance  string   `json:"writerInstance,omitempty"`
+	ReaderInstances []string `json:"readerInstances,omitempty"`
+
+	// Credentials information
+	MasterUserSecretArn string `json:"masterUserSecretArn,omitempty"`
+
+	// AWS target the cluster was created in
+	Access *awsclient.AccessConfig `json:"access,omitempty"`
+
+	// Plan lists the changes a dry-run apply would make; it is cleared by a real apply
+	Plan *awsclient.Plan `json:"plan,omitempty"`
+}
+
+// resolveSpec validates config and applies defaults
+func resolveSpec(config *RdsClusterConfig) error {
+	// Validate required fields
+	if config.ClusterID == "" {
+		return fmt.Errorf("clusterID is required and cannot be empty")
+	}
+	if err := config.AccessConfig.Validate(); err != nil {
+		return err
+	}
+
+	switch config.DatabaseEngine {
+	case EngineAuroraPostgreSQL, EngineAuroraMySQL:
+	default:
+		return fmt.Errorf("invalid databaseEngine %q: must be one of %s, %s",
+			config.DatabaseEngine, EngineAuroraPostgreSQL, EngineAuroraMySQL)
+	}
+
+	if config.InstanceClass == "" {
+		return fmt.Errorf("instanceClass is required and cannot be empty")
+	}
+	if config.ReaderCount < 0 || config.ReaderCount > 15 {
+		return fmt.Errorf("readerCount must be between 0 and 15, got %d", config.ReaderCount)
+	}
+	if err := validateScaling(config); err != nil {
+		return err
+	}
+	if err := config.ApplyChanges.validate(); err != nil {
+		return err
+	}
+
+	policy, err := awsclient.ResolveAdoptionPolicy(config.AdoptionPolicy)
+	if err != nil {
+		return err
+	}
+	config.AdoptionPolicy = policy
+
+	if config.DeletionPolicy, err = awsclient.ResolveDeletionPolicy(config.DeletionPolicy); err != nil {
+		return err
+	}
+
+	return applyDefaults(config)
+}
+
+// validateScaling checks the Serverless v2 capacity range. Capacities go in steps of 0.5 ACU.
+func validateScaling(config *RdsClusterConfig) error {
+	scaling := config.ServerlessV2Scaling
+	if scaling == nil {
+		if config.InstanceClass == ServerlessInstanceClass {
+			return fmt.Errorf("serverlessV2Scaling is required for instanceClass %s", ServerlessInstanceClass)
+		}
+		return nil
+	}
+
+	for _, capacity := range []float64{scaling.MinCapacity, scaling.MaxCapacity} {
+		if capacity < minServerlessCapacity || capacity > maxServerlessCapacity || math.Mod(capacity*2, 1) != 0 {
+			return fmt.Errorf("serverlessV2Scaling capacity %v must be between %d and %d ACUs in steps of 0.5",
+				capacity, minServerlessCapacity, maxServerlessCapacity)
+		}
+	}
+	if scaling.MaxCapacity < scaling.MinCapacity || scaling.MaxCapacity == 0 {
+		return fmt.Errorf("serverlessV2Scaling maxCapacity %v must be positive and at least minCapacity %v",
+			scaling.MaxCapacity, scaling.MinCapacity)
+	}
+	return nil
+}
+
+// resolveAccess returns the AWS target the cluster was created in.
+// Falls back to the config for clusters that have not been created yet.
+func resolveAccess(spec RdsClusterConfig, status RdsClusterStatus) awsclient.AccessConfig {
+	if status.Access != nil {
+		return *status.Access
+	}
+	return clusterClients.Resolve(spec.AccessConfig)
+}
+
+// adoptionPolicy returns the adoption policy for an existing cluster. A cluster already recorded
+// in status was created by this Component, so it is tagged instead of refused.
+func adoptionPolicy(spec RdsClusterConfig, status RdsClusterStatus, clusterArn string) string {
+	if spec.AdoptionPolicy == awsclient.AdoptionNever && status.ClusterARN == clusterArn {
+		return awsclient.AdoptionIfUntagged
+	}
+	return spec.AdoptionPolicy
+}
+
+// applyDefaults sets sensible defaults for optional cluster configuration fields
+func applyDefaults(config *RdsClusterConfig) error {
+	if config.MasterUsername == "" {
+		return fmt.Errorf("masterUsername is required and cannot be empty")
+	}
+
+	// Always use RDS-managed passwords - enforce this policy
+	if config.ManageMasterUserPassword == nil {
+		defaultManaged := true
+		config.ManageMasterUserPassword = &defaultManaged
+	}
+	if !*config.ManageMasterUserPassword {
+		return fmt.Errorf("manageMasterUserPassword must be true - explicit password management is not supported. AWS RDS will generate secure passwords automatically")
+	}
+
+	// Storage defaults
+	if config.StorageEncrypted == nil {
+		defaultEncrypted := true
+		config.StorageEncrypted = &defaultEncrypted
+	}
+
+	// Network defaults
+	if config.PubliclyAccessible == nil {
+		defaultPublicAccess := false
+		config.PubliclyAccessible = &defaultPublicAccess
+	}
+
+	// Backup defaults
+	if config.BackupRetentionPeriod == nil {
+		defaultRetention := int32(7) // 7 days
+		config.BackupRetentionPeriod = &defaultRetention
+	}
+
+	// Maintenance defaults
+	if config.AutoMinorVersionUpgrade == nil {
+		defaultAutoUpgrade := true
+		config.AutoMinorVersionUpgrade = &defaultAutoUpgrade
+	}
+
+	// Performance defaults
+	if config.PerformanceInsightsEnabled == nil {
+		defaultPerfInsights := false
+		config.PerformanceInsightsEnabled = &defaultPerfInsights
+	}
+
+	// Deletion defaults
+	if config.DeletionProtection == nil {
+		defaultDeletionProtection := true // Enable by default for safety
+		config.DeletionProtection = &defaultDeletionProtection
+	}
+	if config.SkipFinalSnapshot == nil {
+		defaultSkipSnapshot := false // Take final snapshot by default
+		config.SkipFinalSnapshot = &defaultSkipSnapshot
+	}
+
+	return nil
+}
+
+// instanceIDs returns the identifiers of the configured cluster instances
+func instanceIDs(config *RdsClusterConfig) []string {
+	ids := make([]string, 0, config.ReaderCount+1)
+	for n := int32(1); n <= config.ReaderCount+1; n++ {
+		ids = append(ids, fmt.Sprintf("%s-%d", config.ClusterID, n))
+	}
+	return ids
+}
@@ -0,0 +1,154 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkHealth performs runtime health monitoring of Ready Aurora clusters.
+// This is called periodically while the Component is in Ready state.
+//
+// Health evaluation covers the cluster and its configured instances:
+//   - Healthy: cluster is operational with a writer and all configured instances available
+//   - Degraded: cluster or instances have operational issues (no writer, missing or
+//     unavailable instances, maintenance, stopped)
+//
+// Health checks do not trigger phase transitions - they only update the Degraded condition.
+func checkHealth(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsClusterConfig,
+	status RdsClusterStatus) (*controller.HealthCheckResult, error) {
+
+	clusterID := spec.ClusterID
+	log := logf.FromContext(ctx).WithValues("clusterId", clusterID)
+
+	// A dry-run Component does not manage the cluster yet
+	if status.Plan != nil {
+		return controller.HealthCheckHealthy("Dry run: " + status.Plan.String())
+	}
+
+	if err := resolveSpec(&spec); err != nil {
+		return controller.HealthCheckDegraded("InvalidConfig", fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	client := clusterClients.Get(resolveAccess(spec, status))
+
+	cluster, err := getClusterData(ctx, client, clusterID)
+	if err != nil {
+		clusterEvents.AWSError(ctx, name, err)
+		return controller.HealthCheckResultForError(err, clusterErrorClassifier, "APIError")
+	}
+
+	// Cluster not found - external deletion detected
+	if cluster == nil {
+		log.Info("RDS cluster not found during health check - may have been deleted externally")
+		clustersByStatus.Delete(name.String())
+		return controller.HealthCheckDegraded(
+			"ClusterDeleted",
+			fmt.Sprintf("RDS cluster %s not found in AWS", clusterID))
+	}
+
+	clusterStatus := aws.ToString(cluster.Status)
+	log.V(1).Info("Checking RDS cluster health", "status", clusterStatus)
+	clustersByStatus.Set(name.String(), clusterStatus)
+
+	switch RDSClusterStatus(clusterStatus) {
+	case StatusAvailable, StatusBackingUp, StatusBacktracking, StatusModifying, StatusUpgrading:
+		// The cluster is operational; the instances decide whether it serves traffic
+		return checkInstancesHealth(ctx, name, client, &spec, clusterID, writerInstance(cluster))
+
+	case StatusMaintenance, StatusFailingOver:
+		// Cluster undergoing maintenance or switching writers - temporarily unavailable
+		return controller.HealthCheckDegraded(
+			"Maintenance",
+			fmt.Sprintf("Cluster %s undergoing maintenance (status: %s)", clusterID, clusterStatus))
+
+	case StatusStopped, StatusStopping, StatusStarting:
+		// Cluster not running or transitioning power state
+		return controller.HealthCheckDegraded(
+			"Stopped",
+			fmt.Sprintf("Cluster %s is not running (status: %s)", clusterID, clusterStatus))
+
+	case StatusInaccessibleEncryptionCredentials, StatusMigrationFailed, StatusCloningFailed:
+		// Cluster in error state - not operational
+		clusterEvents.Warning(ctx, name, awsclient.ReasonFailed, "RDS cluster %s in error state: %s", clusterID, clusterStatus)
+		return controller.HealthCheckDegraded(
+			"Failed",
+			fmt.Sprintf("Cluster %s in error state: %s", clusterID, clusterStatus))
+
+	default:
+		// Unknown status - treat as degraded to surface the issue
+		log.Info("RDS cluster in unknown status during health check", "status", clusterStatus)
+		return controller.HealthCheckDegraded(
+			"UnknownStatus",
+			fmt.Sprintf("Cluster %s in unknown state: %s", clusterID, clusterStatus))
+	}
+}
+
+// checkInstancesHealth checks that the cluster has a writer and that every configured instance is available
+func checkInstancesHealth(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	client API,
+	spec *RdsClusterConfig,
+	clusterID, writer string) (*controller.HealthCheckResult, error) {
+
+	if writer == "" {
+		return controller.HealthCheckDegraded(
+			"NoWriter",
+			fmt.Sprintf("Cluster %s has no writer instance", clusterID))
+	}
+
+	instances, err := listClusterInstances(ctx, client, clusterID)
+	if err != nil {
+		clusterEvents.AWSError(ctx, name, err)
+		return controller.HealthCheckResultForError(err, clusterErrorClassifier, "APIError")
+	}
+
+	var unavailable []string
+	for _, id := range instanceIDs(spec) {
+		i := slices.IndexFunc(instances, func(instance types.DBInstance) bool {
+			return aws.ToString(instance.DBInstanceIdentifier) == id
+		})
+		if i < 0 {
+			unavailable = append(unavailable, fmt.Sprintf("%s (missing)", id))
+			continue
+		}
+		if instanceStatus := aws.ToString(instances[i].DBInstanceStatus); !instanceOperational(instanceStatus) {
+			unavailable = append(unavailable, fmt.Sprintf("%s (%s)", id, instanceStatus))
+		}
+	}
+
+	if len(unavailable) > 0 {
+		return controller.HealthCheckDegraded(
+			"InstanceUnavailable",
+			fmt.Sprintf("Cluster %s instances not available: %s", clusterID, strings.Join(unavailable, ", ")))
+	}
+
+	return controller.HealthCheckHealthy(
+		fmt.Sprintf("Cluster %s is operational with writer %s and %d instances", clusterID, writer, len(instances)))
+}
+
+// instanceOperational reports whether a cluster member in the given state serves connections
+func instanceOperational(instanceStatus string) bool {
+	switch instanceStatus {
+	case instanceAvailable, "backing-up", "modifying", "storage-optimization",
+		"configuring-enhanced-monitoring", "configuring-iam-database-auth", "configuring-log-exports":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// instanceChanges are the cluster member changes needed to match the config
+type instanceChanges struct {
+	// create lists configured instances that do not exist yet
+	create []string
+	// resize lists configured instances whose instance class differs from the config
+	resize []types.DBInstance
+	// remove lists owned instances beyond readerCount; the writer is never removed
+	remove []string
+	// pending lists instances that are not settled yet, with their status
+	pending []string
+	// foreign lists configured instances that exist but are not owned by the Component; they are left alone
+	foreign []string
+}
+
+// diffInstances compares the cluster members with the configured writer and readers.
+// Members not owned by the Component, e.g. readers added by hand, are left alone, like deleteAction
+// does, even when they carry a configured name.
+func diffInstances(
+	name k8stypes.NamespacedName,
+	config *RdsClusterConfig,
+	cluster *types.DBCluster,
+	instances []types.DBInstance) instanceChanges {
+
+	var changes instanceChanges
+
+	desired := instanceIDs(config)
+	existing := make(map[string]types.DBInstance, len(instances))
+	for _, instance := range instances {
+		existing[aws.ToString(instance.DBInstanceIdentifier)] = instance
+	}
+
+	for _, id := range desired {
+		instance, ok := existing[id]
+		if !ok {
+			changes.create = append(changes.create, id)
+			continue
+		}
+		if !clusterOwnership.Owns(name, fromRDSTags(instance.TagList)) {
+			changes.foreign = append(changes.foreign, id)
+			continue
+		}
+
+		instanceStatus := aws.ToString(instance.DBInstanceStatus)
+		switch {
+		case instanceStatus == instanceDeleting:
+			// Recreated once the deletion completes
+			changes.pending = append(changes.pending, fmt.Sprintf("%s (%s)", id, instanceStatus))
+		case aws.ToString(instance.DBInstanceClass) != config.InstanceClass && !pendingClass(instance, config.InstanceClass):
+			changes.resize = append(changes.resize, instance)
+		case instanceStatus != instanceAvailable:
+			changes.pending = append(changes.pending, fmt.Sprintf("%s (%s)", id, instanceStatus))
+		}
+	}
+
+	writer := ""
+	if cluster != nil {
+		writer = writerInstance(cluster)
+	}
+
+	for _, instance := range instances {
+		id := aws.ToString(instance.DBInstanceIdentifier)
+		switch {
+		case slices.Contains(desired, id):
+			continue
+		case aws.ToString(instance.DBInstanceStatus) == instanceDeleting:
+			changes.pending = append(changes.pending, fmt.Sprintf("%s (%s)", id, instanceDeleting))
+		case id != writer && clusterOwnership.Owns(name, fromRDSTags(instance.TagList)):
+			changes.remove = append(changes.remove, id)
+		}
+	}
+
+	return changes
+}
+
+// pendingClass reports whether a class change to instanceClass is already pending on the instance
+func pendingClass(instance types.DBInstance, instanceClass string) bool {
+	return instance.PendingModifiedValues != nil &&
+		aws.ToString(instance.PendingModifiedValues.DBInstanceClass) == instanceClass
+}
+
+// reconcileInstances creates, resizes and removes cluster members to match the config.
+// It returns the instances that are still settling; the cluster has to be available.
+func reconcileInstances(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	client API,
+	config *RdsClusterConfig,
+	cluster *types.DBCluster,
+	instances []types.DBInstance) ([]string, error) {
+
+	changes := diffInstances(name, config, cluster, instances)
+	pending := changes.pending
+
+	for _, id := range changes.foreign {
+		clusterEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused,
+			"Not managing RDS cluster instance %s: it is not owned by this Component", id)
+	}
+
+	for _, id := range changes.create {
+		if err := createClusterInstance(ctx, client, config, id, clusterOwnership.Tags(name)); err != nil {
+			return nil, err
+		}
+		clusterEvents.Normal(ctx, name, awsclient.ReasonCreated, "Creating RDS cluster instance %s (%s)", id, config.InstanceClass)
+		pending = append(pending, fmt.Sprintf("%s (creating)", id))
+	}
+
+	for _, instance := range changes.resize {
+		id := aws.ToString(instance.DBInstanceIdentifier)
+		if err := modifyClusterInstanceClass(ctx, client, config, id); err != nil {
+			return nil, err
+		}
+		clusterEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Modifying RDS cluster instance %s (%s -> %s)",
+			id, aws.ToString(instance.DBInstanceClass), config.InstanceClass)
+		// A class change left for the maintenance window keeps the instance available
+		if config.ApplyChanges.InstanceClass != ApplyDuringMaintenanceWindow {
+			pending = append(pending, fmt.Sprintf("%s (modifying)", id))
+		}
+	}
+
+	for _, id := range changes.remove {
+		if err := deleteClusterInstance(ctx, client, id); err != nil {
+			return nil, err
+		}
+		clusterEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleting RDS cluster instance %s", id)
+		pending = append(pending, fmt.Sprintf("%s (deleting)", id))
+	}
+
+	return pending, nil
+}
+
+// adoptInstances tags the configured members of an adopted cluster as owned by the Component when the
+// adoption policy allows taking them over too. Other members stay foreign and are left alone.
+func adoptInstances(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	client API,
+	config *RdsClusterConfig,
+	instances []types.DBInstance) error {
+
+	desired := instanceIDs(config)
+	for _, instance := range instances {
+		id := aws.ToString(instance.DBInstanceIdentifier)
+		if !slices.Contains(desired, id) {
+			continue
+		}
+		adopt, err := clusterOwnership.CheckAdoption(name, fromRDSTags(instance.TagList), config.AdoptionPolicy)
+		if err != nil || !adopt {
+			continue
+		}
+		if err := tagResource(ctx, client, aws.ToString(instance.DBInstanceArn), clusterOwnership.Tags(name)); err != nil {
+			return err
+		}
+		clusterEvents.Normal(ctx, name, awsclient.ReasonAdopted, "Adopted RDS cluster instance %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// clustersByStatus counts managed Aurora clusters by their last observed ClusterStatus
+var clustersByStatus = awsclient.NewStateGauge(
+	"rds_clusters", "RDS Aurora clusters managed by Components, by cluster status.", "status")
+
+func init() {
+	metrics.Registry.MustRegister(clustersByStatus)
+}
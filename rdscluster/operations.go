@@ -0,0 +1,441 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/functional"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// applyAction creates or modifies the Aurora cluster.
+// Instances can only join an available cluster, so checkApplied reconciles them.
+func applyAction(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsClusterConfig,
+	status RdsClusterStatus) (*functional.ActionResult[RdsClusterStatus], error) {
+
+	// Validate and apply defaults to config
+	if err := resolveSpec(&spec); err != nil {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	// The cluster cannot follow a region or account change
+	access := clusterClients.Resolve(spec.AccessConfig)
+	if err := access.CheckUnchanged(status.Access); err != nil {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	dryRun, err := clusterDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+
+	clusterID := spec.ClusterID
+
+	log := logf.FromContext(ctx).WithValues("clusterId", clusterID)
+	log.Info("Starting RDS cluster deployment", "region", access.Region, "dryRun", dryRun)
+
+	client := clusterClients.Get(access)
+
+	cluster, err := getClusterData(ctx, client, clusterID)
+	if err != nil {
+		return actionError(ctx, name, status, fmt.Errorf("failed to check RDS cluster existence: %w", err))
+	}
+
+	// Only modify an existing cluster owned by this Component, or one the adoption policy allows taking over
+	adopt := false
+	var instances []types.DBInstance
+	if cluster != nil {
+		clusterArn := aws.ToString(cluster.DBClusterArn)
+		adopt, err = clusterOwnership.CheckAdoption(name, fromRDSTags(cluster.TagList), adoptionPolicy(spec, status, clusterArn))
+		if err != nil {
+			clusterEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused, "Not managing RDS cluster %s: %v", clusterID, err)
+			return functional.ActionFailure(status, fmt.Sprintf("cannot manage RDS cluster %s: %v", clusterID, err))
+		}
+
+		if instances, err = listClusterInstances(ctx, client, clusterID); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+	}
+
+	// In dry-run mode only record what would change
+	if dryRun {
+		status.Plan = planCluster(name, &spec, cluster, instances)
+		if adopt {
+			status.Plan.Add("ownership", name.String())
+		}
+		log.Info("Dry run, not applying changes", "plan", status.Plan.String())
+		return functional.ActionSuccess(status, "Dry run: "+status.Plan.String())
+	}
+	status.Plan = nil
+
+	if cluster != nil {
+		log.Info("RDS cluster exists, modifying existing cluster")
+
+		if adopt {
+			if err := tagResource(ctx, client, aws.ToString(cluster.DBClusterArn), clusterOwnership.Tags(name)); err != nil {
+				return actionError(ctx, name, status, err)
+			}
+			clusterEvents.Normal(ctx, name, awsclient.ReasonAdopted, "Adopted RDS cluster %s", clusterID)
+
+			if err := adoptInstances(ctx, name, client, &spec, instances); err != nil {
+				return actionError(ctx, name, status, err)
+			}
+		}
+
+		input := buildModifyInput(&spec, cluster)
+		if input == nil {
+			updateStatusFromCluster(&status, cluster)
+			status.Access = &access
+			clustersByStatus.Set(name.String(), status.ClusterStatus)
+
+			log.Info("RDS cluster matches config, nothing to modify")
+			return functional.ActionSuccess(status, fmt.Sprintf("RDS cluster %s up to date", clusterID))
+		}
+
+		cluster, err = modifyCluster(ctx, client, input)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+
+		updateStatusFromCluster(&status, cluster)
+		status.Access = &access
+		clustersByStatus.Set(name.String(), status.ClusterStatus)
+
+		clusterEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Modifying RDS cluster %s", clusterID)
+
+		details := fmt.Sprintf("Modifying RDS cluster %s", clusterID)
+		if !aws.ToBool(input.ApplyImmediately) && input.EngineVersion != nil {
+			details += fmt.Sprintf(", engine version %s pending for the maintenance window", aws.ToString(input.EngineVersion))
+		}
+		return functional.ActionSuccess(status, details)
+	}
+
+	log.Info("RDS cluster does not exist, creating new cluster")
+	cluster, err = createCluster(ctx, client, &spec, clusterOwnership.Tags(name))
+	if err != nil {
+		return actionError(ctx, name, status, err)
+	}
+
+	updateStatusFromCluster(&status, cluster)
+	status.Access = &access
+	clustersByStatus.Set(name.String(), status.ClusterStatus)
+
+	clusterEvents.Normal(ctx, name, awsclient.ReasonCreated, "Creating RDS cluster %s (%s)", clusterID, spec.DatabaseEngine)
+
+	details := fmt.Sprintf("Creating RDS cluster %s (%s)", clusterID, spec.DatabaseEngine)
+	return functional.ActionSuccess(status, details)
+}
+
+// checkApplied waits for the cluster to become available, then reconciles its
+// writer and reader instances and waits for them
+func checkApplied(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsClusterConfig,
+	status RdsClusterStatus) (*functional.CheckResult[RdsClusterStatus], error) {
+
+	clusterID := spec.ClusterID
+
+	log := logf.FromContext(ctx).WithValues("clusterId", clusterID)
+	log.Info("Checking RDS cluster deployment status")
+
+	// A dry-run apply changed nothing. A resource that was never created is not ready.
+	if status.Plan != nil {
+		if status.Plan.Action == awsclient.PlanCreate {
+			return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+		}
+		return functional.CheckComplete(status, "Dry run: "+status.Plan.String())
+	}
+
+	if err := resolveSpec(&spec); err != nil {
+		return checkError(ctx, name, status, fmt.Errorf("config validation failed: %w", err))
+	}
+
+	client := clusterClients.Get(resolveAccess(spec, status))
+
+	cluster, err := getClusterData(ctx, client, clusterID)
+	if err != nil {
+		return checkError(ctx, name, status, err)
+	}
+	if cluster == nil {
+		return checkError(ctx, name, status, fmt.Errorf("RDS cluster %s not found during deployment check", clusterID))
+	}
+
+	updateStatusFromCluster(&status, cluster)
+	clustersByStatus.Set(name.String(), status.ClusterStatus)
+
+	log = log.WithValues("status", status.ClusterStatus)
+
+	switch RDSClusterStatus(status.ClusterStatus) {
+	case StatusAvailable, StatusBackingUp:
+		// The cluster accepts new members; create, resize and remove instances as configured
+		instances, err := listClusterInstances(ctx, client, clusterID)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+
+		pending, err := reconcileInstances(ctx, name, client, &spec, cluster, instances)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if len(pending) > 0 {
+			log.Info("RDS cluster instances in progress", "pending", pending)
+			details := fmt.Sprintf("Cluster %s waiting for instances: %s", clusterID, strings.Join(pending, ", "))
+			return functional.CheckInProgress(status, details)
+		}
+
+		log.Info("RDS cluster deployment completed successfully",
+			"endpoint", status.Endpoint,
+			"readerEndpoint", status.ReaderEndpoint,
+			"port", status.Port)
+
+		details := fmt.Sprintf("Cluster %s available at %s:%d with writer %s and %d readers",
+			clusterID, status.Endpoint, status.Port, status.WriterInstance, len(status.ReaderInstances))
+		return functional.CheckComplete(status, details)
+
+	case StatusCreating, StatusModifying, StatusUpgrading, StatusRenaming, StatusResettingMasterCredentials,
+		StatusMaintenance, StatusFailingOver, StatusBacktracking, StatusStarting:
+		// Deployment in progress or a transition that completes on its own
+		log.Info("RDS cluster deployment in progress")
+		details := fmt.Sprintf("Cluster %s status: %s", clusterID, status.ClusterStatus)
+		return functional.CheckInProgress(status, details)
+
+	case StatusInaccessibleEncryptionCredentials, StatusMigrationFailed, StatusCloningFailed,
+		StatusStopped, StatusStopping:
+		// Failed states or a cluster stopped during deployment
+		clusterEvents.Warning(ctx, name, awsclient.ReasonFailed, "RDS cluster %s is %s", clusterID, status.ClusterStatus)
+		return checkError(ctx, name, status, fmt.Errorf("RDS cluster deployment failed with status: %s", status.ClusterStatus))
+
+	default:
+		// Unknown status - continue checking to be safe
+		log.Info("RDS cluster in unknown status, continuing to monitor")
+		return functional.CheckInProgress(status, "")
+	}
+}
+
+// deleteAction deletes the cluster instances and then the cluster
+func deleteAction(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsClusterConfig,
+	status RdsClusterStatus) (*functional.ActionResult[RdsClusterStatus], error) {
+
+	clusterID := spec.ClusterID
+
+	log := logf.FromContext(ctx).WithValues("clusterId", clusterID)
+
+	retain := spec.DeletionPolicy == awsclient.DeletionPolicyRetain
+
+	dryRun, err := clusterDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+	if dryRun {
+		status.Plan = awsclient.NewDeletePlan(clusterID)
+		if retain {
+			status.Plan = awsclient.NewRetainPlan(clusterID)
+		}
+		log.Info("Dry run, holding back deletion of cluster", "plan", status.Plan.String())
+		return functional.ActionResultForError(status, awsclient.DeletionHeld(status.Plan), awsclient.RetryDryRun)
+	}
+
+	client := clusterClients.Get(resolveAccess(spec, status))
+
+	cluster, err := getClusterData(ctx, client, clusterID)
+	if err != nil {
+		return actionError(ctx, name, status, err)
+	}
+	if cluster == nil {
+		log.Info("RDS cluster already deleted")
+		clustersByStatus.Delete(name.String())
+		return functional.ActionSuccess(status, "RDS cluster already deleted")
+	}
+
+	// A cluster this Component refused to adopt, or that another Component took over since, is left alone
+	if !ownsCluster(name, status, cluster) {
+		log.Info("RDS cluster is not owned by this Component, leaving it in place")
+		clusterEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused, "Not deleting RDS cluster %s: it is not owned by this Component", clusterID)
+		clustersByStatus.Delete(name.String())
+		details := fmt.Sprintf("RDS cluster %s is not owned by this Component, left in place", clusterID)
+		return functional.ActionSuccess(status, details)
+	}
+
+	instances, err := listClusterInstances(ctx, client, clusterID)
+	if err != nil {
+		return actionError(ctx, name, status, err)
+	}
+
+	if retain {
+		return retainCluster(ctx, name, client, cluster, instances, status)
+	}
+
+	// Deleting the instances of a protected cluster would leave it without a writer
+	if aws.ToBool(cluster.DeletionProtection) {
+		return functional.ActionFailure(status, fmt.Sprintf(
+			"RDS cluster %s has deletion protection enabled; set deletionProtection to false and apply before deleting", clusterID))
+	}
+
+	// Members added outside the Component are left alone, like diffInstances does
+	foreign := foreignInstances(name, instances)
+	for _, instance := range instances {
+		id := aws.ToString(instance.DBInstanceIdentifier)
+		if aws.ToString(instance.DBInstanceStatus) == instanceDeleting || slices.Contains(foreign, id) {
+			continue
+		}
+		if err := deleteClusterInstance(ctx, client, id); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+	}
+
+	// The cluster cannot be deleted while it has members, so it stays for the ones the Component does not own
+	if len(foreign) > 0 {
+		log.Info("RDS cluster has members not owned by this Component, leaving it in place", "instances", foreign)
+		clusterEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused,
+			"Not deleting RDS cluster %s: instances %s are not owned by this Component", clusterID, strings.Join(foreign, ", "))
+		clustersByStatus.Delete(name.String())
+		details := fmt.Sprintf("Deleting %d instances of RDS cluster %s; the cluster is left in place for instances %s",
+			len(instances)-len(foreign), clusterID, strings.Join(foreign, ", "))
+		return functional.ActionSuccess(status, details)
+	}
+
+	cluster, err = deleteCluster(ctx, client, &spec)
+	if err != nil {
+		return actionError(ctx, name, status, err)
+	}
+	if cluster != nil {
+		updateStatusFromCluster(&status, cluster)
+		clustersByStatus.Set(name.String(), status.ClusterStatus)
+	}
+
+	clusterEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleting RDS cluster %s and %d instances", clusterID, len(instances))
+
+	details := fmt.Sprintf("Deleting RDS cluster %s and %d instances", clusterID, len(instances))
+	return functional.ActionSuccess(status, details)
+}
+
+// ownsCluster reports whether the cluster carries the ownership tags of the Component,
+// or is the one recorded in its status
+func ownsCluster(name k8stypes.NamespacedName, status RdsClusterStatus, cluster *types.DBCluster) bool {
+	if clusterOwnership.Owns(name, fromRDSTags(cluster.TagList)) {
+		return true
+	}
+	return status.ClusterARN != "" && status.ClusterARN == aws.ToString(cluster.DBClusterArn)
+}
+
+// foreignInstances returns the identifiers of the cluster members the Component does not own
+func foreignInstances(name k8stypes.NamespacedName, instances []types.DBInstance) []string {
+	var foreign []string
+	for _, instance := range instances {
+		if !clusterOwnership.Owns(name, fromRDSTags(instance.TagList)) {
+			foreign = append(foreign, aws.ToString(instance.DBInstanceIdentifier))
+		}
+	}
+	return foreign
+}
+
+// retainCluster keeps the cluster and its instances in AWS and removes their ownership tags,
+// so that another Component can adopt them
+func retainCluster(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	client API,
+	cluster *types.DBCluster,
+	instances []types.DBInstance,
+	status RdsClusterStatus) (*functional.ActionResult[RdsClusterStatus], error) {
+
+	clusterID := aws.ToString(cluster.DBClusterIdentifier)
+	log := logf.FromContext(ctx).WithValues("clusterId", clusterID)
+
+	// Tags of a cluster adopted by another Component since are left alone
+	if clusterOwnership.Owns(name, fromRDSTags(cluster.TagList)) {
+		if err := untagResource(ctx, client, aws.ToString(cluster.DBClusterArn), clusterOwnership.TagKeys()); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+	}
+	for _, instance := range instances {
+		if clusterOwnership.Owns(name, fromRDSTags(instance.TagList)) {
+			if err := untagResource(ctx, client, aws.ToString(instance.DBInstanceArn), clusterOwnership.TagKeys()); err != nil {
+				return actionError(ctx, name, status, err)
+			}
+		}
+	}
+	clustersByStatus.Delete(name.String())
+
+	log.Info("DeletionPolicy is Retain, leaving RDS cluster in place")
+	clusterEvents.Normal(ctx, name, awsclient.ReasonRetained, "Retained RDS cluster %s", clusterID)
+
+	details := fmt.Sprintf("RDS cluster %s retained (Retain policy)", clusterID)
+	return functional.ActionSuccess(status, details)
+}
+
+// checkDeleted waits until the cluster is gone
+func checkDeleted(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsClusterConfig,
+	status RdsClusterStatus) (*functional.CheckResult[RdsClusterStatus], error) {
+
+	clusterID := spec.ClusterID
+
+	log := logf.FromContext(ctx).WithValues("clusterId", clusterID)
+	log.Info("Checking RDS cluster deleted")
+
+	// A dry-run delete is held back until dry run is turned off
+	if status.Plan != nil && (status.Plan.Action == awsclient.PlanDelete || status.Plan.Action == awsclient.PlanRetain) {
+		return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+	}
+
+	// A retained cluster stays in AWS
+	if spec.DeletionPolicy == awsclient.DeletionPolicyRetain {
+		return functional.CheckComplete(status, fmt.Sprintf("Cluster %s retained", clusterID))
+	}
+
+	client := clusterClients.Get(resolveAccess(spec, status))
+
+	cluster, err := getClusterData(ctx, client, clusterID)
+	if err != nil {
+		return checkError(ctx, name, status, fmt.Errorf("failed to describe RDS cluster during deletion check: %w", err))
+	}
+
+	if cluster == nil {
+		log.Info("RDS cluster successfully deleted")
+		clustersByStatus.Delete(name.String())
+		details := fmt.Sprintf("Cluster %s deleted", clusterID)
+		return functional.CheckComplete(status, details)
+	}
+
+	// deleteAction leaves a cluster the Component does not own, or one with members it does not own, in place
+	if !ownsCluster(name, status, cluster) {
+		return functional.CheckComplete(status, fmt.Sprintf("Cluster %s left in place", clusterID))
+	}
+	if RDSClusterStatus(aws.ToString(cluster.Status)) != StatusDeleting {
+		instances, err := listClusterInstances(ctx, client, clusterID)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		if foreign := foreignInstances(name, instances); len(foreign) > 0 {
+			details := fmt.Sprintf("Cluster %s left in place for instances %s", clusterID, strings.Join(foreign, ", "))
+			return functional.CheckComplete(status, details)
+		}
+	}
+
+	clusterStatus := aws.ToString(cluster.Status)
+	status.ClusterStatus = clusterStatus
+	clustersByStatus.Set(name.String(), clusterStatus)
+
+	log.Info("RDS cluster deletion in progress", "status", clusterStatus)
+	details := fmt.Sprintf("Waiting for cluster %s deletion (status: %s)", clusterID, clusterStatus)
+	return functional.CheckInProgress(status, details)
+}
@@ -0,0 +1,460 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator-aws-providers/awsfake"
+	"github.com/rinswind/componator/componentkit/controller"
+)
+
+func TestRdsCluster(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RDS Cluster Suite")
+}
+
+var _ = Describe("RDS Cluster Operations", func() {
+	var (
+		ctx  context.Context
+		fake *awsfake.RDS
+		name k8stypes.NamespacedName
+		spec RdsClusterConfig
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = awsfake.NewRDS()
+		clusterClients = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceRDS, func(aws.Config) API { return fake })
+
+		name = k8stypes.NamespacedName{Namespace: "default", Name: "orders"}
+		spec = RdsClusterConfig{
+			ClusterID:          "orders",
+			DatabaseEngine:     EngineAuroraPostgreSQL,
+			EngineVersion:      "16.4",
+			InstanceClass:      "db.r6g.large",
+			ReaderCount:        1,
+			DatabaseName:       "orders",
+			MasterUsername:     "admin",
+			DeletionProtection: aws.Bool(false),
+			SkipFinalSnapshot:  aws.Bool(true),
+		}
+	})
+
+	// deploy applies the spec and drives the checks until the cluster and its instances are available
+	deploy := func(status RdsClusterStatus) RdsClusterStatus {
+		result, err := applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		status = result.Status
+
+		for range 3 {
+			fake.Advance()
+			check, err := checkApplied(ctx, name, spec, status)
+			Expect(err).NotTo(HaveOccurred())
+			status = check.Status
+			if check.Complete {
+				return status
+			}
+		}
+		Fail("cluster did not become available")
+		return status
+	}
+
+	It("should create the cluster, then its writer and readers", func() {
+		By("creating the cluster")
+		result, err := applyAction(ctx, name, spec, RdsClusterStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Creating RDS cluster orders (aurora-postgresql)"))
+		Expect(fake.Cluster("orders")).NotTo(BeNil())
+		Expect(fake.CallCount("CreateDBInstance")).To(BeZero())
+		status := result.Status
+
+		check, err := checkApplied(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Cluster orders status: creating"))
+
+		By("adding the instances once the cluster is available")
+		fake.Advance()
+		check, err = checkApplied(ctx, name, spec, check.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Cluster orders waiting for instances: orders-1 (creating), orders-2 (creating)"))
+		Expect(aws.ToString(fake.Instance("orders-1").DBClusterIdentifier)).To(Equal("orders"))
+		Expect(aws.ToString(fake.Instance("orders-2").DBInstanceClass)).To(Equal("db.r6g.large"))
+
+		fake.Advance()
+		check, err = checkApplied(ctx, name, spec, check.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Cluster orders available at orders.cluster-fake.us-east-1.rds.amazonaws.com:5432 with writer orders-1 and 1 readers"))
+		Expect(check.Status.ReaderEndpoint).To(Equal("orders.cluster-ro-fake.us-east-1.rds.amazonaws.com"))
+		Expect(check.Status.WriterInstance).To(Equal("orders-1"))
+		Expect(check.Status.ReaderInstances).To(Equal([]string{"orders-2"}))
+		Expect(check.Status.MasterUserSecretArn).To(ContainSubstring("rds!cluster-orders"))
+		Expect(fake.CallCount("CreateDBInstance")).To(Equal(2))
+	})
+
+	It("should scale readers and resize instances", func() {
+		status := deploy(RdsClusterStatus{})
+		Expect(status.ReaderInstances).To(Equal([]string{"orders-2"}))
+
+		By("adding a reader and changing the instance class")
+		spec.ReaderCount = 2
+		spec.InstanceClass = "db.r6g.xlarge"
+		status = deploy(status)
+		Expect(status.ReaderInstances).To(Equal([]string{"orders-2", "orders-3"}))
+		Expect(fake.CallCount("ModifyDBInstance")).To(Equal(2))
+		for _, id := range []string{"orders-1", "orders-2", "orders-3"} {
+			Expect(aws.ToString(fake.Instance(id).DBInstanceClass)).To(Equal("db.r6g.xlarge"))
+		}
+
+		By("removing readers beyond readerCount")
+		spec.ReaderCount = 0
+		status = deploy(status)
+		Expect(status.WriterInstance).To(Equal("orders-1"))
+		Expect(status.ReaderInstances).To(BeEmpty())
+		Expect(fake.Instance("orders-3")).To(BeNil())
+	})
+
+	It("should only modify changed settings and leave deferred ones for the maintenance window", func() {
+		recorder := record.NewFakeRecorder(10)
+		clusterEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { clusterEvents = nil })
+
+		status := deploy(RdsClusterStatus{})
+		for range 3 {
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Created")))
+		}
+
+		By("leaving a cluster that matches its config alone")
+		result, err := applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS cluster orders up to date"))
+		Expect(fake.CallCount("ModifyDBCluster")).To(BeZero())
+		Expect(recorder.Events).To(BeEmpty())
+
+		By("sending only the changed settings")
+		spec.BackupRetentionPeriod = aws.Int32(14)
+		result, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Modifying RDS cluster orders"))
+		Expect(fake.Cluster("orders").BackupRetentionPeriod).To(HaveValue(Equal(int32(14))))
+		Expect(recorder.Events).To(Receive(Equal("Normal Updated Modifying RDS cluster orders")))
+		status = deploy(result.Status)
+
+		By("leaving an engine upgrade and a resize for the maintenance window")
+		spec.EngineVersion = "16.6"
+		spec.InstanceClass = "db.r6g.xlarge"
+		spec.ApplyChanges = ApplyChanges{EngineVersion: ApplyDuringMaintenanceWindow, InstanceClass: ApplyDuringMaintenanceWindow}
+		result, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Modifying RDS cluster orders, engine version 16.6 pending for the maintenance window"))
+		Expect(fake.Cluster("orders").EngineVersion).To(HaveValue(Equal("16.4")))
+		status = deploy(result.Status)
+		Expect(aws.ToString(fake.Instance("orders-1").DBInstanceClass)).To(Equal("db.r6g.large"))
+
+		modifications := fake.CallCount("ModifyDBCluster") + fake.CallCount("ModifyDBInstance")
+		result, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS cluster orders up to date"))
+		deploy(result.Status)
+		Expect(fake.CallCount("ModifyDBCluster") + fake.CallCount("ModifyDBInstance")).To(Equal(modifications))
+
+		fake.ApplyPendingModifications("orders")
+		Expect(fake.Cluster("orders").EngineVersion).To(HaveValue(Equal("16.6")))
+	})
+
+	It("should configure Serverless v2 scaling", func() {
+		spec.InstanceClass = ServerlessInstanceClass
+		result, err := applyAction(ctx, name, spec, RdsClusterStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("config validation failed: serverlessV2Scaling is required for instanceClass db.serverless"))
+
+		spec.ServerlessV2Scaling = &ServerlessV2Scaling{MinCapacity: 0.5, MaxCapacity: 8}
+		status := deploy(RdsClusterStatus{})
+		scaling := fake.Cluster("orders").ServerlessV2ScalingConfiguration
+		Expect(aws.ToFloat64(scaling.MinCapacity)).To(Equal(0.5))
+		Expect(aws.ToFloat64(scaling.MaxCapacity)).To(Equal(8.0))
+		Expect(aws.ToString(fake.Instance("orders-1").DBInstanceClass)).To(Equal(ServerlessInstanceClass))
+
+		spec.ServerlessV2Scaling.MaxCapacity = 16
+		_, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(aws.ToFloat64(fake.Cluster("orders").ServerlessV2ScalingConfiguration.MaxCapacity)).To(Equal(16.0))
+	})
+
+	It("should plan the cluster and its instances without calling mutating APIs in dry-run mode", func() {
+		clusterDryRun = awsclient.NewDryRun(awsfake.NewComponents(name), true)
+		DeferCleanup(func() { clusterDryRun = nil })
+
+		result, err := applyAction(ctx, name, spec, RdsClusterStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan.Action).To(Equal(awsclient.PlanCreate))
+		Expect(result.Status.Plan.Changes).To(ContainElements(
+			awsclient.Change{Field: "instances", Action: awsclient.ChangeAdd, Desired: "orders-1 (db.r6g.large)"},
+			awsclient.Change{Field: "instances", Action: awsclient.ChangeAdd, Desired: "orders-2 (db.r6g.large)"},
+		))
+		Expect(fake.Calls()).To(Equal([]string{"DescribeDBClusters"}))
+
+		check, err := checkApplied(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Complete).To(BeFalse())
+		Expect(check.Details).To(Equal("Dry run: would create orders (6 changes)"))
+	})
+
+	It("should refuse to delete a protected cluster before touching its instances", func() {
+		spec.DeletionProtection = aws.Bool(true)
+		status := deploy(RdsClusterStatus{})
+
+		result, err := deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("has deletion protection enabled"))
+		Expect(fake.CallCount("DeleteDBInstance")).To(BeZero())
+		Expect(fake.CallCount("DeleteDBCluster")).To(BeZero())
+	})
+
+	It("should delete the instances and the cluster", func() {
+		status := deploy(RdsClusterStatus{})
+
+		result, err := deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Deleting RDS cluster orders and 2 instances"))
+		Expect(fake.CallCount("DeleteDBInstance")).To(Equal(2))
+
+		check, err := checkDeleted(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Waiting for cluster orders deletion (status: deleting)"))
+
+		fake.Advance()
+		check, err = checkDeleted(ctx, name, spec, check.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Cluster orders deleted"))
+		Expect(fake.Instance("orders-1")).To(BeNil())
+
+		result, err = deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS cluster already deleted"))
+	})
+
+	It("should keep a retained cluster and remove the ownership tags of it and its instances", func() {
+		clusterOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { clusterOwnership = nil })
+
+		status := deploy(RdsClusterStatus{})
+		Expect(fromRDSTags(fake.Instance("orders-2").TagList)).To(HaveKeyWithValue(awsclient.TagComponent, "default/orders"))
+
+		spec.DeletionPolicy = awsclient.DeletionPolicyRetain
+		result, err := deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("RDS cluster orders retained (Retain policy)"))
+		Expect(fromRDSTags(fake.Cluster("orders").TagList)).To(BeEmpty())
+		Expect(fromRDSTags(fake.Instance("orders-1").TagList)).To(BeEmpty())
+		Expect(fake.CallCount("DeleteDBInstance")).To(BeZero())
+
+		check, err := checkDeleted(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Cluster orders retained"))
+	})
+
+	It("should leave cluster members it does not own alone", func() {
+		clusterOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { clusterOwnership = nil })
+
+		status := deploy(RdsClusterStatus{})
+
+		_, err := fake.CreateDBInstance(ctx, &rds.CreateDBInstanceInput{
+			DBInstanceIdentifier: aws.String("orders-analytics"),
+			DBClusterIdentifier:  aws.String("orders"),
+			DBInstanceClass:      aws.String("db.r6g.2xlarge"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		spec.ReaderCount = 0
+		status = deploy(status)
+		Expect(fake.Instance("orders-2")).To(BeNil())
+		Expect(fake.Instance("orders-analytics")).NotTo(BeNil())
+		Expect(status.ReaderInstances).To(Equal([]string{"orders-analytics"}))
+
+		By("deleting only its own members and keeping the cluster for the others")
+		result, err := deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Deleting 1 instances of RDS cluster orders; the cluster is left in place for instances orders-analytics"))
+		Expect(fake.CallCount("DeleteDBInstance")).To(Equal(2))
+		Expect(fake.CallCount("DeleteDBCluster")).To(BeZero())
+
+		fake.Advance()
+		check, err := checkDeleted(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Complete).To(BeTrue())
+		Expect(fake.Instance("orders-1")).To(BeNil())
+		Expect(fake.Instance("orders-analytics")).NotTo(BeNil())
+		Expect(fake.Cluster("orders")).NotTo(BeNil())
+	})
+
+	It("should not resize a member it does not own under a configured name", func() {
+		clusterOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { clusterOwnership = nil })
+
+		status := deploy(RdsClusterStatus{})
+
+		_, err := fake.CreateDBInstance(ctx, &rds.CreateDBInstanceInput{
+			DBInstanceIdentifier: aws.String("orders-3"),
+			DBClusterIdentifier:  aws.String("orders"),
+			DBInstanceClass:      aws.String("db.r6g.2xlarge"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		spec.ReaderCount = 2
+		spec.InstanceClass = "db.r6g.xlarge"
+		deploy(status)
+		Expect(fake.CallCount("ModifyDBInstance")).To(Equal(2))
+		Expect(fake.CallCount("CreateDBInstance")).To(Equal(3))
+		Expect(aws.ToString(fake.Instance("orders-1").DBInstanceClass)).To(Equal("db.r6g.xlarge"))
+		Expect(aws.ToString(fake.Instance("orders-3").DBInstanceClass)).To(Equal("db.r6g.2xlarge"))
+	})
+
+	It("should adopt the configured members of an adopted cluster", func() {
+		clusterOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { clusterOwnership = nil })
+
+		_, err := fake.CreateDBCluster(ctx, &rds.CreateDBClusterInput{
+			DBClusterIdentifier: aws.String("orders"),
+			Engine:              aws.String(EngineAuroraPostgreSQL),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+		for _, id := range []string{"orders-1", "orders-analytics"} {
+			_, err = fake.CreateDBInstance(ctx, &rds.CreateDBInstanceInput{
+				DBInstanceIdentifier: aws.String(id),
+				DBClusterIdentifier:  aws.String("orders"),
+				DBInstanceClass:      aws.String("db.r6g.large"),
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		fake.Advance()
+
+		spec.ReaderCount = 0
+		spec.InstanceClass = "db.r6g.xlarge"
+		spec.AdoptionPolicy = awsclient.AdoptionIfUntagged
+		deploy(RdsClusterStatus{})
+		Expect(clusterOwnership.Owns(name, fromRDSTags(fake.Instance("orders-1").TagList))).To(BeTrue())
+		Expect(aws.ToString(fake.Instance("orders-1").DBInstanceClass)).To(Equal("db.r6g.xlarge"))
+		Expect(fake.Instance("orders-analytics").TagList).To(BeEmpty())
+		Expect(aws.ToString(fake.Instance("orders-analytics").DBInstanceClass)).To(Equal("db.r6g.large"))
+	})
+
+	It("should refuse to modify a cluster owned by another Component", func() {
+		clusterOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { clusterOwnership = nil })
+
+		other := k8stypes.NamespacedName{Namespace: "team", Name: "orders"}
+		_, err := applyAction(ctx, other, spec, RdsClusterStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		result, err := applyAction(ctx, name, spec, RdsClusterStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(ContainSubstring("cannot manage RDS cluster orders: resource is owned by Component team/orders"))
+		Expect(fake.CallCount("ModifyDBCluster")).To(BeZero())
+
+		By("leaving it in place when the Component is deleted")
+		deleted, err := deleteAction(ctx, name, spec, RdsClusterStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted.Details).To(Equal("RDS cluster orders is not owned by this Component, left in place"))
+		Expect(fake.CallCount("DeleteDBInstance")).To(BeZero())
+		Expect(fake.CallCount("DeleteDBCluster")).To(BeZero())
+
+		check, err := checkDeleted(ctx, name, spec, deleted.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(check.Details).To(Equal("Cluster orders left in place"))
+	})
+
+	It("should report missing instances and a deleted cluster as degraded", func() {
+		status := deploy(RdsClusterStatus{})
+
+		result, err := checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Cluster orders is operational with writer orders-1 and 2 instances")
+		Expect(result).To(Equal(healthy))
+
+		By("detecting a reader deleted outside the provider")
+		_, err = fake.DeleteDBInstance(ctx, &rds.DeleteDBInstanceInput{DBInstanceIdentifier: aws.String("orders-2")})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		result, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("InstanceUnavailable", "Cluster orders instances not available: orders-2 (missing)")
+		Expect(result).To(Equal(degraded))
+
+		By("detecting a stopped cluster")
+		fake.SetClusterStatus("orders", "stopped")
+		result, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ = controller.HealthCheckDegraded("Stopped", "Cluster orders is not running (status: stopped)")
+		Expect(result).To(Equal(degraded))
+	})
+
+	It("should record events for cluster and instance changes", func() {
+		recorder := record.NewFakeRecorder(10)
+		clusterEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { clusterEvents = nil })
+
+		deploy(RdsClusterStatus{})
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Creating RDS cluster orders (aurora-postgresql)")))
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Creating RDS cluster instance orders-1 (db.r6g.large)")))
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Creating RDS cluster instance orders-2 (db.r6g.large)")))
+	})
+})
+
+var _ = Describe("RDS Cluster Config", func() {
+	var spec RdsClusterConfig
+
+	BeforeEach(func() {
+		spec = RdsClusterConfig{
+			ClusterID:      "orders",
+			DatabaseEngine: EngineAuroraMySQL,
+			InstanceClass:  "db.r6g.large",
+			MasterUsername: "admin",
+		}
+	})
+
+	It("should apply defaults", func() {
+		Expect(resolveSpec(&spec)).To(Succeed())
+		Expect(aws.ToBool(spec.ManageMasterUserPassword)).To(BeTrue())
+		Expect(aws.ToBool(spec.StorageEncrypted)).To(BeTrue())
+		Expect(aws.ToBool(spec.DeletionProtection)).To(BeTrue())
+		Expect(aws.ToInt32(spec.BackupRetentionPeriod)).To(Equal(int32(7)))
+		Expect(spec.AdoptionPolicy).To(Equal(awsclient.AdoptionNever))
+		Expect(spec.DeletionPolicy).To(Equal(awsclient.DeletionPolicyDelete))
+		Expect(instanceIDs(&spec)).To(Equal([]string{"orders-1"}))
+	})
+
+	DescribeTable("should reject invalid configs",
+		func(mutate func(*RdsClusterConfig), message string) {
+			mutate(&spec)
+			Expect(resolveSpec(&spec)).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("non-Aurora engine", func(c *RdsClusterConfig) { c.DatabaseEngine = "postgres" }, `invalid databaseEngine "postgres"`),
+		Entry("missing instance class", func(c *RdsClusterConfig) { c.InstanceClass = "" }, "instanceClass is required"),
+		Entry("too many readers", func(c *RdsClusterConfig) { c.ReaderCount = 16 }, "readerCount must be between 0 and 15"),
+		Entry("capacity step", func(c *RdsClusterConfig) {
+			c.ServerlessV2Scaling = &ServerlessV2Scaling{MinCapacity: 0.75, MaxCapacity: 4}
+		}, "capacity 0.75 must be between 0 and 256 ACUs in steps of 0.5"),
+		Entry("inverted capacity", func(c *RdsClusterConfig) {
+			c.ServerlessV2Scaling = &ServerlessV2Scaling{MinCapacity: 8, MaxCapacity: 4}
+		}, "maxCapacity 4 must be positive and at least minCapacity 8"),
+		Entry("unknown apply timing", func(c *RdsClusterConfig) { c.ApplyChanges.EngineVersion = "Later" }, `invalid applyChanges.engineVersion "Later"`),
+		Entry("unmanaged password", func(c *RdsClusterConfig) { c.ManageMasterUserPassword = aws.Bool(false) }, "manageMasterUserPassword must be true"),
+	)
+})
@@ -0,0 +1,97 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+)
+
+// Option customizes how the rds-cluster provider is registered
+type Option func(*options)
+
+type options struct {
+	awsclient.ProviderOptions
+
+	client API
+}
+
+// defaultOptions returns the settings used when no options are given.
+// Aurora operations take minutes, so requeues are slower than the framework defaults.
+func defaultOptions() options {
+	o := options{ProviderOptions: awsclient.NewProviderOptions(DefaultProviderName, controller.ErrorClassifier(isRetryable))}
+	o.HealthCheckInterval = 1 * time.Minute
+	o.ErrorRequeue = 15 * time.Second
+	o.DefaultRequeue = 30 * time.Second
+	o.StatusCheckRequeue = 30 * time.Second
+	return o
+}
+
+// WithProviderName sets the unique name used for Component claiming.
+// An empty name keeps the default "rds-cluster". For setkit embedding, use a
+// prefixed name (e.g., "orders-rds-cluster") to avoid conflicts with other providers.
+func WithProviderName(name string) Option {
+	return awsclient.WithProviderName[*options](name)
+}
+
+// WithAWSConfig uses a prebuilt AWS config instead of loading the default credential chain.
+// Per-Component region and role overrides, as well as endpoint overrides, are still applied on top of it.
+func WithAWSConfig(cfg aws.Config) Option {
+	return awsclient.WithAWSConfig[*options](cfg)
+}
+
+// WithEndpoints redirects AWS calls to a local emulator such as LocalStack
+func WithEndpoints(endpoints awsclient.Endpoints) Option {
+	return awsclient.WithEndpoints[*options](endpoints)
+}
+
+// WithClient uses the given client for all AWS calls.
+// The client is shared by every Component, so per-Component region and role
+// overrides are only recorded in status; the client decides where calls go.
+func WithClient(client API) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithDryRun plans every Component instead of applying it, as if all carried
+// the awsclient.DryRunAnnotation. No mutating RDS API is called.
+func WithDryRun(enabled bool) Option {
+	return awsclient.WithDryRun[*options](enabled)
+}
+
+// WithClusterID sets the cluster identity stamped into the ownership tags of created resources,
+// so that providers in different clusters never manage each other's resources (default "default")
+func WithClusterID(id string) Option {
+	return awsclient.WithClusterID[*options](id)
+}
+
+// WithErrorClassifier decides which AWS errors are retried (true) and which fail the Component (false).
+// The default retries the errors the AWS SDK considers retryable.
+func WithErrorClassifier(classifier controller.ErrorClassifier) Option {
+	return awsclient.WithErrorClassifier[*options](classifier)
+}
+
+// WithHealthCheckInterval sets how often Ready clusters are checked (default 1m)
+func WithHealthCheckInterval(d time.Duration) Option {
+	return awsclient.WithHealthCheckInterval[*options](d)
+}
+
+// WithErrorRequeue sets the requeue delay after retryable errors (default 15s)
+func WithErrorRequeue(d time.Duration) Option {
+	return awsclient.WithErrorRequeue[*options](d)
+}
+
+// WithDefaultRequeue sets the default requeue delay (default 30s)
+func WithDefaultRequeue(d time.Duration) Option {
+	return awsclient.WithDefaultRequeue[*options](d)
+}
+
+// WithStatusCheckRequeue sets the delay between creation and deletion progress checks (default 30s)
+func WithStatusCheckRequeue(d time.Duration) Option {
+	return awsclient.WithStatusCheckRequeue[*options](d)
+}
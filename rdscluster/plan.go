@@ -0,0 +1,68 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// Package-level dry-run switch initialized during registration; nil never plans
+var clusterDryRun *awsclient.DryRun
+
+// planCluster computes what applyAction and the following checks would change on the
+// cluster and its instances. A nil cluster plans its creation.
+func planCluster(
+	name k8stypes.NamespacedName,
+	config *RdsClusterConfig,
+	cluster *types.DBCluster,
+	instances []types.DBInstance) *awsclient.Plan {
+
+	plan := awsclient.NewPlan(config.ClusterID, cluster != nil)
+
+	current := cluster
+	if current == nil {
+		current = &types.DBCluster{}
+		awsclient.Diff(plan, "databaseEngine", nil, aws.String(config.DatabaseEngine))
+	}
+
+	// Compare exactly what ModifyDBCluster would send
+	input := buildModifyInput(config, current)
+	if input == nil {
+		input = &rds.ModifyDBClusterInput{}
+	}
+	awsclient.Diff(plan, "engineVersion", current.EngineVersion, input.EngineVersion)
+	awsclient.Diff(plan, "backupRetentionPeriod", current.BackupRetentionPeriod, input.BackupRetentionPeriod)
+	awsclient.Diff(plan, "preferredBackupWindow", current.PreferredBackupWindow, input.PreferredBackupWindow)
+	awsclient.Diff(plan, "preferredMaintenanceWindow", current.PreferredMaintenanceWindow, input.PreferredMaintenanceWindow)
+	awsclient.Diff(plan, "deletionProtection", current.DeletionProtection, input.DeletionProtection)
+
+	if scaling := input.ServerlessV2ScalingConfiguration; scaling != nil {
+		currentScaling := current.ServerlessV2ScalingConfiguration
+		if currentScaling == nil {
+			currentScaling = &types.ServerlessV2ScalingConfigurationInfo{}
+		}
+		awsclient.Diff(plan, "serverlessV2Scaling.minCapacity", currentScaling.MinCapacity, scaling.MinCapacity)
+		awsclient.Diff(plan, "serverlessV2Scaling.maxCapacity", currentScaling.MaxCapacity, scaling.MaxCapacity)
+	}
+
+	changes := diffInstances(name, config, cluster, instances)
+	for _, id := range changes.create {
+		plan.Add("instances", fmt.Sprintf("%s (%s)", id, config.InstanceClass))
+	}
+	for _, instance := range changes.resize {
+		field := fmt.Sprintf("instanceClass[%s]", aws.ToString(instance.DBInstanceIdentifier))
+		plan.Modify(field, aws.ToString(instance.DBInstanceClass), config.InstanceClass)
+	}
+	for _, id := range changes.remove {
+		plan.Remove("instances", id)
+	}
+
+	return plan
+}
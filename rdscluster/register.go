@@ -0,0 +1,98 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdscluster
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	v1beta1 "github.com/rinswind/componator/api/v1beta1"
+	"github.com/rinswind/componator/componentkit/functional"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	DefaultProviderName = "rds-cluster"
+)
+
+// clusterRegistration refuses a second Register, which would take over the package state of the first
+var clusterRegistration awsclient.Registration
+
+// Register registers the rds-cluster Component provider with the controller manager.
+//
+// Without options the provider is claimed as "rds-cluster" and initializes AWS RDS clients
+// using the default credential chain (environment variables, EC2 instance metadata, etc.).
+// Components may override the region and assume a role in another account; one client
+// is cached per target. Embedding programs customize the name, AWS config or client,
+// timings and error classification through options.
+//
+// Provider names must be unique across all providers in the cluster. Multiple providers
+// with the same name will conflict during Component claiming.
+//
+// The provider keeps its clients, ownership and dry-run settings in package state, so a package
+// can only be registered once per process; a second Register fails.
+func Register(mgr ctrl.Manager, opts ...Option) error {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Ensure required schemes are registered (safe to call multiple times)
+	if err := clientgoscheme.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	if err := v1beta1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+
+	// An injected client needs no credentials of its own
+	cfg := aws.Config{}
+	if o.client == nil || o.AWSConfig != nil {
+		var err error
+		if cfg, err = awsclient.BaseConfig(context.Background(), o.AWSConfig, o.Endpoints); err != nil {
+			return err
+		}
+	}
+
+	if err := clusterRegistration.Claim(o.ProviderName); err != nil {
+		return err
+	}
+
+	clusterClients = awsclient.NewCache(cfg, o.Endpoints, awsclient.ServiceRDS, func(cfg aws.Config) API {
+		if o.client != nil {
+			return o.client
+		}
+		return rds.NewFromConfig(cfg)
+	})
+	clusterErrorClassifier = o.ErrorClassifier
+	clusterEvents = awsclient.NewEventRecorder(mgr.GetClient(), mgr.GetEventRecorderFor(o.ProviderName))
+	clusterDryRun = awsclient.NewDryRun(mgr.GetClient(), o.DryRun)
+	clusterOwnership = awsclient.NewOwnership(o.ClusterID, o.ProviderName)
+
+	// Log client initialization
+	log := logf.Log.WithName("rds-cluster")
+	log.Info("Initialized AWS RDS cluster client cache",
+		"defaultRegion", cfg.Region, "endpointOverride", o.Endpoints.IsSet(), "injectedClient", o.client != nil)
+
+	// Register with functional API using custom timeouts for Aurora cluster operations
+	if err := functional.NewBuilder[RdsClusterConfig, RdsClusterStatus](o.ProviderName).
+		WithApply(applyAction).
+		WithApplyCheck(checkApplied).
+		WithDelete(deleteAction).
+		WithDeleteCheck(checkDeleted).
+		WithHealthCheck(checkHealth).
+		WithHealthCheckInterval(o.HealthCheckInterval).
+		WithErrorRequeue(o.ErrorRequeue).
+		WithDefaultRequeue(o.DefaultRequeue).
+		WithStatusCheckRequeue(o.StatusCheckRequeue).
+		Register(mgr); err != nil {
+		clusterRegistration.Release()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// maxParametersPerRequest is the number of parameters ModifyDBParameterGroup and ResetDBParameterGroup accept at once
+const maxParametersPerRequest = 20
+
+// Parameter apply statuses reported for the parameter group of an instance
+const (
+	parametersApplying      = "applying"
+	parametersPendingReboot = "pending-reboot"
+)
+
+// API is the subset of the AWS RDS API used by this provider.
+// It is satisfied by *rds.Client and by awsfake.RDS in unit tests.
+// Embedding programs may supply their own implementation with WithClient.
+type API interface {
+	DescribeDBParameterGroups(ctx context.Context, params *rds.DescribeDBParameterGroupsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParameterGroupsOutput, error)
+	CreateDBParameterGroup(ctx context.Context, params *rds.CreateDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.CreateDBParameterGroupOutput, error)
+	DeleteDBParameterGroup(ctx context.Context, params *rds.DeleteDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.DeleteDBParameterGroupOutput, error)
+	DescribeDBParameters(ctx context.Context, params *rds.DescribeDBParametersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBParametersOutput, error)
+	ModifyDBParameterGroup(ctx context.Context, params *rds.ModifyDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.ModifyDBParameterGroupOutput, error)
+	ResetDBParameterGroup(ctx context.Context, params *rds.ResetDBParameterGroupInput, optFns ...func(*rds.Options)) (*rds.ResetDBParameterGroupOutput, error)
+	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
+	ListTagsForResource(ctx context.Context, params *rds.ListTagsForResourceInput, optFns ...func(*rds.Options)) (*rds.ListTagsForResourceOutput, error)
+	AddTagsToResource(ctx context.Context, params *rds.AddTagsToResourceInput, optFns ...func(*rds.Options)) (*rds.AddTagsToResourceOutput, error)
+	RemoveTagsFromResource(ctx context.Context, params *rds.RemoveTagsFromResourceInput, optFns ...func(*rds.Options)) (*rds.RemoveTagsFromResourceOutput, error)
+}
+
+// Package-level singletons initialized during registration
+var (
+	groupClients   *awsclient.Cache[API]
+	groupOwnership *awsclient.Ownership
+)
+
+// ReadinessProbe checks that the RDS API is reachable with the controller's own credentials
+func ReadinessProbe(ctx context.Context) error {
+	_, err := groupClients.Get(awsclient.AccessConfig{}).DescribeDBParameterGroups(ctx, &rds.DescribeDBParameterGroupsInput{
+		MaxRecords: aws.Int32(20), // smallest page RDS accepts
+	})
+	return err
+}
+
+// parameterChange is one difference between the configured and the current parameters.
+// A reset returns the parameter to the engine default.
+type parameterChange struct {
+	name    string
+	current string
+	desired string
+	method  types.ApplyMethod
+	reset   bool
+}
+
+// getParameterGroup retrieves the group, returning nil if it does not exist
+func getParameterGroup(ctx context.Context, client API, groupName string) (*types.DBParameterGroup, error) {
+	result, err := client.DescribeDBParameterGroups(ctx, &rds.DescribeDBParameterGroupsInput{
+		DBParameterGroupName: aws.String(groupName),
+	})
+	if err != nil {
+		if isGroupNotFoundError(err) {
+			return nil, nil // Group not found - return nil without error
+		}
+		return nil, fmt.Errorf("failed to describe RDS parameter group: %w", err)
+	}
+
+	if len(result.DBParameterGroups) == 0 {
+		return nil, nil
+	}
+	return &result.DBParameterGroups[0], nil
+}
+
+// getGroupTags returns the tags of the group; DescribeDBParameterGroups does not include them
+func getGroupTags(ctx context.Context, client API, groupArn string) (map[string]string, error) {
+	result, err := client.ListTagsForResource(ctx, &rds.ListTagsForResourceInput{
+		ResourceName: aws.String(groupArn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RDS parameter group tags: %w", err)
+	}
+	return fromRDSTags(result.TagList), nil
+}
+
+// createParameterGroup creates an empty group with the given tags; parameters are set afterwards
+func createParameterGroup(
+	ctx context.Context, client API, config *RdsParameterGroupConfig, tags map[string]string) (*types.DBParameterGroup, error) {
+
+	log := logf.FromContext(ctx).WithValues("groupName", config.GroupName)
+	log.Info("Creating RDS parameter group", "family", config.Family)
+
+	result, err := client.CreateDBParameterGroup(ctx, &rds.CreateDBParameterGroupInput{
+		DBParameterGroupName:   aws.String(config.GroupName),
+		DBParameterGroupFamily: aws.String(config.Family),
+		Description:            aws.String(config.Description),
+		Tags:                   toRDSTags(tags),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RDS parameter group: %w", err)
+	}
+	return result.DBParameterGroup, nil
+}
+
+// describeParameters returns all parameters of the group with their current values and apply types
+func describeParameters(ctx context.Context, client API, groupName string) ([]types.Parameter, error) {
+	input := &rds.DescribeDBParametersInput{DBParameterGroupName: aws.String(groupName)}
+
+	var parameters []types.Parameter
+	paginator := rds.NewDescribeDBParametersPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe RDS parameters: %w", err)
+		}
+		parameters = append(parameters, page.Parameters...)
+	}
+	return parameters, nil
+}
+
+// diffParameters compares the configured parameters with the current ones. Parameters set on the
+// group that the config does not list are reset. It fails on parameters the family does not have,
+// parameters that cannot be modified, and static parameters configured to apply immediately.
+func diffParameters(config *RdsParameterGroupConfig, current []types.Parameter) ([]parameterChange, error) {
+	byName := make(map[string]types.Parameter, len(current))
+	for _, parameter := range current {
+		byName[aws.ToString(parameter.ParameterName)] = parameter
+	}
+
+	var changes []parameterChange
+	var unknown, fixed, static []string
+	for _, name := range sortedNames(config.Parameters) {
+		desired := config.Parameters[name]
+		parameter, ok := byName[name]
+		switch {
+		case !ok:
+			unknown = append(unknown, name)
+			continue
+		case parameter.IsModifiable != nil && !*parameter.IsModifiable:
+			fixed = append(fixed, name)
+			continue
+		}
+
+		method := defaultApplyMethod(parameter)
+		if desired.ApplyMethod != "" {
+			method = types.ApplyMethod(desired.ApplyMethod)
+		}
+		if isStatic(parameter) && method == types.ApplyMethodImmediate {
+			static = append(static, name)
+			continue
+		}
+
+		if currentValue := aws.ToString(parameter.ParameterValue); currentValue != desired.Value || !isUserSet(parameter) {
+			changes = append(changes, parameterChange{name: name, current: currentValue, desired: desired.Value, method: method})
+		}
+	}
+
+	switch {
+	case len(unknown) > 0:
+		return nil, fmt.Errorf("unknown parameters for family %s: %s", config.Family, strings.Join(unknown, ", "))
+	case len(fixed) > 0:
+		return nil, fmt.Errorf("parameters cannot be modified: %s", strings.Join(fixed, ", "))
+	case len(static) > 0:
+		return nil, fmt.Errorf("static parameters need applyMethod %s: %s", ApplyPendingReboot, strings.Join(static, ", "))
+	}
+
+	for _, parameter := range current {
+		name := aws.ToString(parameter.ParameterName)
+		if _, configured := config.Parameters[name]; configured || !isUserSet(parameter) {
+			continue
+		}
+		changes = append(changes, parameterChange{
+			name:    name,
+			current: aws.ToString(parameter.ParameterValue),
+			method:  defaultApplyMethod(parameter),
+			reset:   true,
+		})
+	}
+
+	return changes, nil
+}
+
+// applyParameters sends the changes in batches, first the modifications and then the resets
+func applyParameters(ctx context.Context, client API, groupName string, changes []parameterChange) error {
+	var modify, reset []types.Parameter
+	for _, change := range changes {
+		parameter := types.Parameter{ParameterName: aws.String(change.name), ApplyMethod: change.method}
+		if change.reset {
+			reset = append(reset, parameter)
+			continue
+		}
+		parameter.ParameterValue = aws.String(change.desired)
+		modify = append(modify, parameter)
+	}
+
+	for batch := range slices.Chunk(modify, maxParametersPerRequest) {
+		_, err := client.ModifyDBParameterGroup(ctx, &rds.ModifyDBParameterGroupInput{
+			DBParameterGroupName: aws.String(groupName),
+			Parameters:           batch,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to modify RDS parameter group: %w", err)
+		}
+	}
+	for batch := range slices.Chunk(reset, maxParametersPerRequest) {
+		_, err := client.ResetDBParameterGroup(ctx, &rds.ResetDBParameterGroupInput{
+			DBParameterGroupName: aws.String(groupName),
+			Parameters:           batch,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to reset RDS parameters: %w", err)
+		}
+	}
+	return nil
+}
+
+// deleteParameterGroup deletes the group. A missing group counts as deleted.
+func deleteParameterGroup(ctx context.Context, client API, groupName string) error {
+	_, err := client.DeleteDBParameterGroup(ctx, &rds.DeleteDBParameterGroupInput{
+		DBParameterGroupName: aws.String(groupName),
+	})
+	if err != nil && !isGroupNotFoundError(err) {
+		return fmt.Errorf("failed to delete RDS parameter group: %w", err)
+	}
+	return nil
+}
+
+// groupInstances returns the identifiers of the instances using the group by their parameter apply status.
+// DescribeDBInstances cannot filter by parameter group, so all instances are listed.
+func groupInstances(ctx context.Context, client API, groupName string) (map[string][]string, error) {
+	byStatus := make(map[string][]string)
+	paginator := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list RDS instances: %w", err)
+		}
+		for _, instance := range page.DBInstances {
+			for _, group := range instance.DBParameterGroups {
+				if aws.ToString(group.DBParameterGroupName) == groupName {
+					status := aws.ToString(group.ParameterApplyStatus)
+					byStatus[status] = append(byStatus[status], aws.ToString(instance.DBInstanceIdentifier))
+				}
+			}
+		}
+	}
+	return byStatus, nil
+}
+
+// defaultApplyMethod applies dynamic parameters immediately and static ones on reboot
+func defaultApplyMethod(parameter types.Parameter) types.ApplyMethod {
+	if isStatic(parameter) {
+		return types.ApplyMethodPendingReboot
+	}
+	return types.ApplyMethodImmediate
+}
+
+// isStatic reports whether a parameter only takes effect after a reboot
+func isStatic(parameter types.Parameter) bool {
+	return aws.ToString(parameter.ApplyType) == "static"
+}
+
+// isUserSet reports whether a parameter was set on the group rather than inherited from the engine
+func isUserSet(parameter types.Parameter) bool {
+	return aws.ToString(parameter.Source) == "user"
+}
+
+// isGroupNotFoundError checks if the error indicates the parameter group was not found
+func isGroupNotFoundError(err error) bool {
+	var notFoundErr *types.DBParameterGroupNotFoundFault
+	return errors.As(err, &notFoundErr)
+}
+
+// isGroupInUseError checks if the error indicates instances still use the parameter group
+func isGroupInUseError(err error) bool {
+	var stateErr *types.InvalidDBParameterGroupStateFault
+	return errors.As(err, &stateErr)
+}
+
+// groupErrorClassifier wraps the AWS SDK retry logic for use with result builder utilities.
+// Register replaces it when an embedding program supplies WithErrorClassifier.
+var groupErrorClassifier = controller.ErrorClassifier(isRetryable)
+
+// isRetryable determines if an error is retryable using AWS SDK's built-in error classification.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	for _, checker := range retry.DefaultRetryables {
+		if checker.IsErrorRetryable(err) == aws.TrueTernary {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tagGroup adds tags to an existing group
+func tagGroup(ctx context.Context, client API, groupArn string, tags map[string]string) error {
+	_, err := client.AddTagsToResource(ctx, &rds.AddTagsToResourceInput{
+		ResourceName: aws.String(groupArn),
+		Tags:         toRDSTags(tags),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag RDS parameter group: %w", err)
+	}
+	return nil
+}
+
+// untagGroup removes tags from a group
+func untagGroup(ctx context.Context, client API, groupArn string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := client.RemoveTagsFromResource(ctx, &rds.RemoveTagsFromResourceInput{
+		ResourceName: aws.String(groupArn),
+		TagKeys:      keys,
+	})
+	if err != nil && !isGroupNotFoundError(err) {
+		return fmt.Errorf("failed to untag RDS parameter group: %w", err)
+	}
+	return nil
+}
+
+// toRDSTags converts a map to an RDS tag slice
+func toRDSTags(tags map[string]string) []types.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	result := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		result = append(result, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return result
+}
+
+// fromRDSTags converts an RDS tag slice to a map
+func fromRDSTags(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
@@ -0,0 +1,150 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
+
+// Parameter apply methods. Dynamic parameters may apply immediately; static parameters
+// only take effect when the instances using the group reboot.
+const (
+	ApplyImmediate     = "immediate"
+	ApplyPendingReboot = "pending-reboot"
+)
+
+// RdsParameterGroupConfig represents the configuration structure for rds-parameter-group components
+// that gets unmarshaled from Component.Spec.Config
+type RdsParameterGroupConfig struct {
+	// AccessConfig optionally selects the AWS region and a role to assume in another account
+	awsclient.AccessConfig
+
+	// GroupName is the name of the DB parameter group - Required
+	GroupName string `json:"groupName"`
+
+	// Family is the engine family of the group, e.g. postgres16 or mysql8.0 - Required.
+	// It cannot be changed after creation.
+	Family string `json:"family"`
+
+	// Description is set when the group is created (defaults to "Parameter group <groupName>")
+	Description string `json:"description,omitempty"`
+
+	// Parameters maps parameter names to their values. Parameters set on the group but
+	// missing here are reset to the engine default.
+	Parameters map[string]Parameter `json:"parameters,omitempty"`
+
+	// AdoptionPolicy controls whether an existing group that is not tagged as owned by this Component
+	// is taken over: Never (default), IfUntagged or Always
+	AdoptionPolicy string `json:"adoptionPolicy,omitempty"`
+
+	// DeletionPolicy controls what happens to the group when the Component is deleted:
+	// Delete (default) or Retain, which keeps the group and removes its ownership tags
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// RemediateDrift makes health checks restore the parameters when they are changed outside the provider.
+	// By default drift is only reported as Degraded.
+	RemediateDrift bool `json:"remediateDrift,omitempty"`
+}
+
+// Parameter is the desired value of one parameter
+type Parameter struct {
+	Value string `json:"value"`
+
+	// ApplyMethod is immediate or pending-reboot. By default dynamic parameters apply immediately
+	// and static parameters on the next reboot of each instance using the group.
+	ApplyMethod string `json:"applyMethod,omitempty"`
+}
+
+// RdsParameterGroupStatus contains handler-specific status data for rds-parameter-group deployments.
+// This data is persisted across reconciliation loops in Component.Status.ProviderStatus.
+type RdsParameterGroupStatus struct {
+	GroupName string `json:"groupName,omitempty"`
+	GroupARN  string `json:"groupARN,omitempty"`
+	Family    string `json:"family,omitempty"`
+
+	// RebootPending reports that instances using the group need a reboot to pick up parameter changes;
+	// PendingRebootInstances lists them. Both reflect the last apply check.
+	RebootPending          bool     `json:"rebootPending,omitempty"`
+	PendingRebootInstances []string `json:"pendingRebootInstances,omitempty"`
+
+	// Access is the AWS target the group was created in
+	Access *awsclient.AccessConfig `json:"access,omitempty"`
+
+	// Plan lists the changes a dry-run apply would make; it is cleared by a real apply
+	Plan *awsclient.Plan `json:"plan,omitempty"`
+}
+
+// resolveSpec validates config and applies defaults
+func resolveSpec(config *RdsParameterGroupConfig) error {
+	// Validate required fields
+	if config.GroupName == "" {
+		return fmt.Errorf("groupName is required and cannot be empty")
+	}
+	if config.Family == "" {
+		return fmt.Errorf("family is required and cannot be empty")
+	}
+	if err := config.AccessConfig.Validate(); err != nil {
+		return err
+	}
+
+	for _, parameterName := range sortedNames(config.Parameters) {
+		if parameterName == "" {
+			return fmt.Errorf("parameter names cannot be empty")
+		}
+		switch method := config.Parameters[parameterName].ApplyMethod; method {
+		case "", ApplyImmediate, ApplyPendingReboot:
+		default:
+			return fmt.Errorf("invalid applyMethod %q for parameter %s: must be %s or %s",
+				method, parameterName, ApplyImmediate, ApplyPendingReboot)
+		}
+	}
+
+	policy, err := awsclient.ResolveAdoptionPolicy(config.AdoptionPolicy)
+	if err != nil {
+		return err
+	}
+	config.AdoptionPolicy = policy
+
+	if config.DeletionPolicy, err = awsclient.ResolveDeletionPolicy(config.DeletionPolicy); err != nil {
+		return err
+	}
+
+	// Apply defaults
+	if config.Description == "" {
+		config.Description = "Parameter group " + config.GroupName
+	}
+
+	return nil
+}
+
+// resolveAccess returns the AWS target the group was created in.
+// Falls back to the config for groups that have not been created yet.
+func resolveAccess(spec RdsParameterGroupConfig, status RdsParameterGroupStatus) awsclient.AccessConfig {
+	if status.Access != nil {
+		return *status.Access
+	}
+	return groupClients.Resolve(spec.AccessConfig)
+}
+
+// adoptionPolicy returns the adoption policy for an existing group. A group already recorded
+// in status was created by this Component, so it is tagged instead of refused.
+func adoptionPolicy(spec RdsParameterGroupConfig, status RdsParameterGroupStatus, groupArn string) string {
+	if spec.AdoptionPolicy == awsclient.AdoptionNever && status.GroupARN == groupArn {
+		return awsclient.AdoptionIfUntagged
+	}
+	return spec.AdoptionPolicy
+}
+
+// sortedNames returns the parameter names in a stable order for requests, plans and messages
+func sortedNames[V any](parameters map[string]V) []string {
+	names := make([]string, 0, len(parameters))
+	for name := range parameters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,34 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"context"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	"github.com/rinswind/componator/componentkit/functional"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// Package-level event recorder initialized during registration; nil discards events
+var groupEvents *awsclient.EventRecorder
+
+// actionError records a Warning event for AWS errors that need attention and builds the action result
+func actionError(ctx context.Context, name k8stypes.NamespacedName, status RdsParameterGroupStatus, err error) (*functional.ActionResult[RdsParameterGroupStatus], error) {
+	groupEvents.AWSError(ctx, name, err)
+	return functional.ActionResultForError(status, err, groupErrorClassifier)
+}
+
+// checkError records a Warning event for AWS errors that need attention and builds the check result
+func checkError(ctx context.Context, name k8stypes.NamespacedName, status RdsParameterGroupStatus, err error) (*functional.CheckResult[RdsParameterGroupStatus], error) {
+	groupEvents.AWSError(ctx, name, err)
+	return functional.CheckResultForError(status, err, groupErrorClassifier)
+}
+
+// healthError records a Warning event for AWS errors that need attention and builds the health check result
+func healthError(ctx context.Context, name k8stypes.NamespacedName, err error) (*controller.HealthCheckResult, error) {
+	groupEvents.AWSError(ctx, name, err)
+	return controller.HealthCheckResultForError(err, groupErrorClassifier, "APIError")
+}
@@ -0,0 +1,110 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkHealth detects out-of-band changes to Ready parameter groups.
+// This is called periodically while the Component is in Ready state.
+//
+// Drift is reported as Degraded:
+//   - ParameterGroupDeleted: the group no longer exists
+//   - ParameterDrift: parameter values differ from the config
+//
+// With remediateDrift set, the configured group is restored instead.
+// Instances waiting for a reboot do not make the group unhealthy; they are named in the message.
+func checkHealth(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsParameterGroupConfig,
+	status RdsParameterGroupStatus) (*controller.HealthCheckResult, error) {
+
+	// A dry-run Component does not manage the group yet
+	if status.Plan != nil {
+		return controller.HealthCheckHealthy("Dry run: " + status.Plan.String())
+	}
+
+	if err := resolveSpec(&spec); err != nil {
+		return controller.HealthCheckDegraded("InvalidConfig", fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	log := logf.FromContext(ctx).WithValues("groupName", status.GroupName)
+	client := groupClients.Get(resolveAccess(spec, status))
+	drift := driftReporter(ctx, name, spec.RemediateDrift)
+
+	group, err := getParameterGroup(ctx, client, status.GroupName)
+	if err != nil {
+		return healthError(ctx, name, err)
+	}
+
+	if group == nil {
+		return drift.Report(ctx, name, "ParameterGroupDeleted",
+			fmt.Sprintf("RDS parameter group %s not found in AWS", status.GroupName),
+			func() error {
+				if _, err := createParameterGroup(ctx, client, &spec, groupOwnership.WithTags(name, nil)); err != nil {
+					return err
+				}
+				changes, err := diffGroup(ctx, client, &spec)
+				if err != nil {
+					return err
+				}
+				return applyParameters(ctx, client, spec.GroupName, changes)
+			})
+	}
+
+	changes, err := diffGroup(ctx, client, &spec)
+	if err != nil {
+		var invalid *invalidParametersError
+		if errors.As(err, &invalid) {
+			return controller.HealthCheckDegraded("InvalidConfig", fmt.Sprintf("config validation failed: %v", invalid.err))
+		}
+		return healthError(ctx, name, err)
+	}
+
+	if len(changes) > 0 {
+		names := make([]string, 0, len(changes))
+		for _, change := range changes {
+			names = append(names, change.name)
+		}
+		return drift.Report(ctx, name, "ParameterDrift",
+			fmt.Sprintf("Parameters %s of RDS parameter group %s differ from config", strings.Join(names, ", "), status.GroupName),
+			func() error {
+				return applyParameters(ctx, client, spec.GroupName, changes)
+			})
+	}
+
+	instances, err := groupInstances(ctx, client, status.GroupName)
+	if err != nil {
+		return healthError(ctx, name, err)
+	}
+	recordPendingReboot(&status, instances)
+
+	log.V(1).Info("RDS parameter group matches config", "rebootPending", status.RebootPending)
+	if status.RebootPending {
+		return controller.HealthCheckHealthy(fmt.Sprintf("Parameter group %s matches config, reboot pending on %s",
+			status.GroupName, strings.Join(status.PendingRebootInstances, ", ")))
+	}
+	return controller.HealthCheckHealthy(fmt.Sprintf("Parameter group %s matches config", status.GroupName))
+}
+
+// driftReporter reports drift on the named Component. Remediation is disabled while
+// the Component is in dry-run mode, since it would change AWS.
+func driftReporter(ctx context.Context, name k8stypes.NamespacedName, remediate bool) *awsclient.DriftReporter {
+	if remediate {
+		if dryRun, err := groupDryRun.Enabled(ctx, name); err != nil || dryRun {
+			remediate = false
+		}
+	}
+	return awsclient.NewDriftReporter(groupEvents, remediate)
+}
@@ -0,0 +1,378 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/functional"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// applyAction creates the parameter group if needed and brings its parameters in line with the config
+func applyAction(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsParameterGroupConfig,
+	status RdsParameterGroupStatus) (*functional.ActionResult[RdsParameterGroupStatus], error) {
+
+	// Validate and apply defaults to config
+	if err := resolveSpec(&spec); err != nil {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	// The group cannot follow a region or account change
+	access := groupClients.Resolve(spec.AccessConfig)
+	if err := access.CheckUnchanged(status.Access); err != nil {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	dryRun, err := groupDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+
+	log := logf.FromContext(ctx).WithValues("groupName", spec.GroupName)
+	log.Info("Starting RDS parameter group deployment", "dryRun", dryRun)
+
+	client := groupClients.Get(access)
+
+	group, err := getParameterGroup(ctx, client, spec.GroupName)
+	if err != nil {
+		return actionError(ctx, name, status, err)
+	}
+
+	// Only manage an existing group owned by this Component, or one the adoption policy allows taking over
+	adopt := false
+	var changes []parameterChange
+	if group != nil {
+		groupArn := aws.ToString(group.DBParameterGroupArn)
+
+		tags, err := getGroupTags(ctx, client, groupArn)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		adopt, err = groupOwnership.CheckAdoption(name, tags, adoptionPolicy(spec, status, groupArn))
+		if err != nil {
+			groupEvents.Warning(ctx, name, awsclient.ReasonAdoptionRefused, "Not managing RDS parameter group %s: %v", groupArn, err)
+			return functional.ActionFailure(status, fmt.Sprintf("cannot manage parameter group %s: %v", groupArn, err))
+		}
+
+		if family := aws.ToString(group.DBParameterGroupFamily); family != spec.Family {
+			return functional.ActionFailure(status, fmt.Sprintf(
+				"config validation failed: family cannot be changed from %s to %s", family, spec.Family))
+		}
+
+		if changes, err = diffGroup(ctx, client, &spec); err != nil {
+			return parameterError(ctx, name, status, err)
+		}
+	}
+
+	// In dry-run mode only record what would change
+	if dryRun {
+		plan := planGroup(&spec, group, changes)
+		if adopt {
+			plan.Add("ownership", name.String())
+		}
+		status.Plan = plan
+		log.Info("Dry run, not applying changes", "plan", plan.String())
+		return functional.ActionSuccess(status, "Dry run: "+plan.String())
+	}
+	status.Plan = nil
+
+	if group == nil {
+		if group, err = createParameterGroup(ctx, client, &spec, groupOwnership.WithTags(name, nil)); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		groupEvents.Normal(ctx, name, awsclient.ReasonCreated,
+			"Created RDS parameter group %s", aws.ToString(group.DBParameterGroupArn))
+
+		// A new group starts from the engine defaults
+		if changes, err = diffGroup(ctx, client, &spec); err != nil {
+			return parameterError(ctx, name, status, err)
+		}
+	}
+
+	status.GroupName = aws.ToString(group.DBParameterGroupName)
+	status.GroupARN = aws.ToString(group.DBParameterGroupArn)
+	status.Family = aws.ToString(group.DBParameterGroupFamily)
+	status.Access = &access
+
+	if adopt {
+		if err := tagGroup(ctx, client, status.GroupARN, groupOwnership.Tags(name)); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		groupEvents.Normal(ctx, name, awsclient.ReasonAdopted, "Adopted RDS parameter group %s", status.GroupARN)
+	}
+
+	if len(changes) == 0 {
+		return functional.ActionSuccess(status, fmt.Sprintf("Parameter group %s up to date", status.GroupName))
+	}
+
+	if err := applyParameters(ctx, client, status.GroupName, changes); err != nil {
+		return actionError(ctx, name, status, err)
+	}
+
+	log.Info("Applied RDS parameter changes", "changes", len(changes))
+	groupEvents.Normal(ctx, name, awsclient.ReasonUpdated,
+		"Changed %d parameters of RDS parameter group %s", len(changes), status.GroupARN)
+
+	details := fmt.Sprintf("Changed %d parameters of parameter group %s", len(changes), status.GroupName)
+	return functional.ActionSuccess(status, details)
+}
+
+// diffGroup compares the configured parameters with those of the group
+func diffGroup(ctx context.Context, client API, spec *RdsParameterGroupConfig) ([]parameterChange, error) {
+	current, err := describeParameters(ctx, client, spec.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := diffParameters(spec, current)
+	if err != nil {
+		return nil, &invalidParametersError{err: err}
+	}
+	return changes, nil
+}
+
+// invalidParametersError marks parameter settings the family rejects, which no retry can fix
+type invalidParametersError struct {
+	err error
+}
+
+func (e *invalidParametersError) Error() string { return e.err.Error() }
+func (e *invalidParametersError) Unwrap() error { return e.err }
+
+// parameterError fails the Component on invalid parameters and handles AWS errors as usual
+func parameterError(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	status RdsParameterGroupStatus,
+	err error) (*functional.ActionResult[RdsParameterGroupStatus], error) {
+
+	var invalid *invalidParametersError
+	if errors.As(err, &invalid) {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", invalid.err))
+	}
+	return actionError(ctx, name, status, err)
+}
+
+// checkApplied waits until the instances using the group have applied its parameters
+// and records which of them need a reboot
+func checkApplied(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsParameterGroupConfig,
+	status RdsParameterGroupStatus) (*functional.CheckResult[RdsParameterGroupStatus], error) {
+
+	log := logf.FromContext(ctx).WithValues("groupName", spec.GroupName)
+
+	// A dry-run apply changed nothing. A resource that was never created is not ready.
+	if status.Plan != nil {
+		if status.Plan.Action == awsclient.PlanCreate {
+			return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+		}
+		return functional.CheckComplete(status, "Dry run: "+status.Plan.String())
+	}
+
+	// If we don't have a group name yet, deployment hasn't started
+	if status.GroupName == "" {
+		log.V(1).Info("No group name in status, deployment not started")
+		return functional.CheckInProgress(status, "")
+	}
+
+	client := groupClients.Get(resolveAccess(spec, status))
+
+	group, err := getParameterGroup(ctx, client, status.GroupName)
+	if err != nil {
+		return checkError(ctx, name, status, err)
+	}
+	if group == nil {
+		return checkError(ctx, name, status, fmt.Errorf("parameter group %s not found", status.GroupName))
+	}
+
+	instances, err := groupInstances(ctx, client, status.GroupName)
+	if err != nil {
+		return checkError(ctx, name, status, err)
+	}
+
+	if applying := instances[parametersApplying]; len(applying) > 0 {
+		log.V(1).Info("Instances still applying parameters", "instances", applying)
+		details := fmt.Sprintf("Waiting for instances %s to apply parameter group %s",
+			strings.Join(applying, ", "), status.GroupName)
+		return functional.CheckInProgress(status, details)
+	}
+
+	recordPendingReboot(&status, instances)
+	return functional.CheckComplete(status, appliedDetails(status))
+}
+
+// recordPendingReboot records the instances that need a reboot to pick up static parameter changes
+func recordPendingReboot(status *RdsParameterGroupStatus, instances map[string][]string) {
+	status.PendingRebootInstances = instances[parametersPendingReboot]
+	status.RebootPending = len(status.PendingRebootInstances) > 0
+}
+
+// appliedDetails describes an applied group, naming the instances waiting for a reboot
+func appliedDetails(status RdsParameterGroupStatus) string {
+	if status.RebootPending {
+		return fmt.Sprintf("Parameter group %s applied, reboot pending on %s",
+			status.GroupName, strings.Join(status.PendingRebootInstances, ", "))
+	}
+	return fmt.Sprintf("Parameter group %s applied", status.GroupName)
+}
+
+// deleteAction implements deletion operations for parameter groups.
+// RDS refuses to delete a group that instances still use; checkDeleted retries until they are gone.
+func deleteAction(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsParameterGroupConfig,
+	status RdsParameterGroupStatus) (*functional.ActionResult[RdsParameterGroupStatus], error) {
+
+	log := logf.FromContext(ctx).WithValues("groupName", status.GroupName)
+
+	// If no group name in status, nothing to delete
+	if status.GroupName == "" {
+		log.Info("No group name in status, nothing to delete")
+		return functional.ActionSuccess(status, "No parameter group to delete")
+	}
+
+	retain := spec.DeletionPolicy == awsclient.DeletionPolicyRetain
+
+	dryRun, err := groupDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+	if dryRun {
+		status.Plan = awsclient.NewDeletePlan(status.GroupARN)
+		if retain {
+			status.Plan = awsclient.NewRetainPlan(status.GroupARN)
+		}
+		log.Info("Dry run, holding back deletion of parameter group", "plan", status.Plan.String())
+		return functional.ActionResultForError(status, awsclient.DeletionHeld(status.Plan), awsclient.RetryDryRun)
+	}
+
+	client := groupClients.Get(resolveAccess(spec, status))
+
+	if retain {
+		return retainGroup(ctx, name, client, status)
+	}
+
+	log.Info("Starting RDS parameter group deletion")
+
+	if err := deleteParameterGroup(ctx, client, status.GroupName); err != nil {
+		if isGroupInUseError(err) {
+			log.Info("Parameter group still in use, waiting for instances to release it")
+			details := fmt.Sprintf("Parameter group %s still in use", status.GroupName)
+			return functional.ActionSuccess(status, details)
+		}
+		return actionError(ctx, name, status, err)
+	}
+
+	groupEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleted RDS parameter group %s", status.GroupARN)
+
+	details := fmt.Sprintf("Deleting parameter group %s", status.GroupName)
+	return functional.ActionSuccess(status, details)
+}
+
+// retainGroup keeps the group in AWS and removes the ownership tags,
+// so that another Component can adopt it
+func retainGroup(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	client API,
+	status RdsParameterGroupStatus) (*functional.ActionResult[RdsParameterGroupStatus], error) {
+
+	log := logf.FromContext(ctx).WithValues("groupName", status.GroupName)
+
+	group, err := getParameterGroup(ctx, client, status.GroupName)
+	if err != nil {
+		return actionError(ctx, name, status, err)
+	}
+
+	// Tags of a group adopted by another Component since are left alone
+	if group != nil {
+		tags, err := getGroupTags(ctx, client, status.GroupARN)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		if groupOwnership.Owns(name, tags) {
+			if err := untagGroup(ctx, client, status.GroupARN, groupOwnership.TagKeys()); err != nil {
+				return actionError(ctx, name, status, err)
+			}
+		}
+	}
+
+	log.Info("DeletionPolicy is Retain, leaving parameter group in place")
+	groupEvents.Normal(ctx, name, awsclient.ReasonRetained, "Retained RDS parameter group %s", status.GroupARN)
+
+	details := fmt.Sprintf("Parameter group %s retained (Retain policy)", status.GroupName)
+	return functional.ActionSuccess(status, details)
+}
+
+// checkDeleted verifies deletion is complete, retrying the deletion while instances still use the group
+func checkDeleted(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec RdsParameterGroupConfig,
+	status RdsParameterGroupStatus) (*functional.CheckResult[RdsParameterGroupStatus], error) {
+
+	log := logf.FromContext(ctx).WithValues("groupName", status.GroupName)
+
+	// A dry-run delete is held back until dry run is turned off
+	if status.Plan != nil && (status.Plan.Action == awsclient.PlanDelete || status.Plan.Action == awsclient.PlanRetain) {
+		return functional.CheckInProgress(status, "Dry run: "+status.Plan.String())
+	}
+
+	// A retained group stays in AWS
+	if spec.DeletionPolicy == awsclient.DeletionPolicyRetain {
+		return functional.CheckComplete(status, fmt.Sprintf("Parameter group %s retained", status.GroupName))
+	}
+
+	// If no group name in status, deletion is complete
+	if status.GroupName == "" {
+		log.V(1).Info("No group name in status, deletion complete")
+		return functional.CheckComplete(status, "")
+	}
+
+	client := groupClients.Get(resolveAccess(spec, status))
+
+	group, err := getParameterGroup(ctx, client, status.GroupName)
+	if err != nil {
+		return checkError(ctx, name, status, err)
+	}
+	if group == nil {
+		return functional.CheckComplete(status, fmt.Sprintf("Parameter group %s deleted", status.GroupName))
+	}
+
+	if err := deleteParameterGroup(ctx, client, status.GroupName); err != nil {
+		if !isGroupInUseError(err) {
+			return checkError(ctx, name, status, err)
+		}
+
+		instances, err := groupInstances(ctx, client, status.GroupName)
+		if err != nil {
+			return checkError(ctx, name, status, err)
+		}
+		var using []string
+		for _, ids := range instances {
+			using = append(using, ids...)
+		}
+		slices.Sort(using)
+		log.V(1).Info("Parameter group still in use", "instances", using)
+		details := fmt.Sprintf("Waiting for instances %s to stop using parameter group %s",
+			strings.Join(using, ", "), status.GroupName)
+		return functional.CheckInProgress(status, details)
+	}
+
+	groupEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleted RDS parameter group %s", status.GroupARN)
+	return functional.CheckComplete(status, fmt.Sprintf("Parameter group %s deleted", status.GroupName))
+}
@@ -0,0 +1,308 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator-aws-providers/awsfake"
+	"github.com/rinswind/componator/componentkit/controller"
+	"github.com/rinswind/componator/componentkit/functional"
+)
+
+func TestRdsParameterGroup(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RDS Parameter Group Suite")
+}
+
+var _ = Describe("RDS Parameter Group Operations", func() {
+	var (
+		ctx  context.Context
+		fake *awsfake.RDS
+		name k8stypes.NamespacedName
+		spec RdsParameterGroupConfig
+		arn  string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = awsfake.NewRDS()
+		groupClients = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceRDS, func(aws.Config) API { return fake })
+
+		name = k8stypes.NamespacedName{Namespace: "default", Name: "orders-postgres"}
+		spec = RdsParameterGroupConfig{
+			GroupName: "orders-postgres",
+			Family:    "postgres16",
+			Parameters: map[string]Parameter{
+				"work_mem": {Value: "8192"},
+			},
+		}
+		arn = "arn:aws:rds:" + awsfake.Region + ":" + awsfake.AccountID + ":pg:orders-postgres"
+	})
+
+	// userParameters returns the values set on the group
+	userParameters := func() map[string]string {
+		result, err := fake.DescribeDBParameters(ctx, &rds.DescribeDBParametersInput{
+			DBParameterGroupName: aws.String("orders-postgres"),
+			Source:               aws.String("user"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		values := make(map[string]string)
+		for _, parameter := range result.Parameters {
+			values[aws.ToString(parameter.ParameterName)] = aws.ToString(parameter.ParameterValue)
+		}
+		return values
+	}
+
+	// createInstance creates an available instance using the group
+	createInstance := func(id string) {
+		_, err := fake.CreateDBInstance(ctx, &rds.CreateDBInstanceInput{
+			DBInstanceIdentifier: aws.String(id),
+			DBInstanceClass:      aws.String("db.t3.micro"),
+			Engine:               aws.String("postgres"),
+			DBParameterGroupName: aws.String("orders-postgres"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+	}
+
+	It("should create a group, apply parameters and wait for the instances using it", func() {
+		By("creating the group with its parameters")
+		result, err := applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		status := result.Status
+		Expect(status.GroupARN).To(Equal(arn))
+		Expect(status.Family).To(Equal("postgres16"))
+		Expect(userParameters()).To(Equal(map[string]string{"work_mem": "8192"}))
+
+		checked, err := checkApplied(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		complete, _ := functional.CheckComplete(status, "Parameter group orders-postgres applied")
+		Expect(checked).To(Equal(complete))
+
+		By("waiting for instances to apply a dynamic parameter")
+		createInstance("orders-db")
+		spec.Parameters["work_mem"] = Parameter{Value: "16384"}
+		result, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("Changed 1 parameters of parameter group orders-postgres"))
+
+		checked, err = checkApplied(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		inProgress, _ := functional.CheckInProgress(status, "Waiting for instances orders-db to apply parameter group orders-postgres")
+		Expect(checked).To(Equal(inProgress))
+		fake.Advance()
+
+		By("reporting the instances that need a reboot for a static parameter")
+		spec.Parameters["max_connections"] = Parameter{Value: "500"}
+		_, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userParameters()).To(Equal(map[string]string{"work_mem": "16384", "max_connections": "500"}))
+
+		checked, err = checkApplied(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Complete).To(BeTrue())
+		Expect(checked.Status.RebootPending).To(BeTrue())
+		Expect(checked.Status.PendingRebootInstances).To(Equal([]string{"orders-db"}))
+		Expect(checked.Details).To(Equal("Parameter group orders-postgres applied, reboot pending on orders-db"))
+
+		By("resetting parameters removed from the config")
+		delete(spec.Parameters, "max_connections")
+		result, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userParameters()).To(Equal(map[string]string{"work_mem": "16384"}))
+		Expect(fake.CallCount("ResetDBParameterGroup")).To(Equal(1))
+
+		_, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.CallCount("ModifyDBParameterGroup")).To(Equal(3))
+		Expect(fake.CallCount("ResetDBParameterGroup")).To(Equal(1))
+
+		By("waiting for the instances to release the group on delete")
+		deleted, err := deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted.Details).To(Equal("Parameter group orders-postgres still in use"))
+
+		checked, err = checkDeleted(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		inProgress, _ = functional.CheckInProgress(status,
+			"Waiting for instances orders-db to stop using parameter group orders-postgres")
+		Expect(checked).To(Equal(inProgress))
+
+		_, err = fake.DeleteDBInstance(ctx, &rds.DeleteDBInstanceInput{
+			DBInstanceIdentifier: aws.String("orders-db"), SkipFinalSnapshot: aws.Bool(true)})
+		Expect(err).NotTo(HaveOccurred())
+		fake.Advance()
+
+		checked, err = checkDeleted(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		complete, _ = functional.CheckComplete(status, "Parameter group orders-postgres deleted")
+		Expect(checked).To(Equal(complete))
+		Expect(fake.CallCount("DeleteDBParameterGroup")).To(Equal(3))
+	})
+
+	DescribeTable("should reject parameters the group cannot take",
+		func(parameters map[string]Parameter, details string) {
+			spec.Parameters = parameters
+			result, err := applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Details).To(Equal(details))
+			Expect(fake.CallCount("ModifyDBParameterGroup")).To(BeZero())
+		},
+		Entry("unknown parameter",
+			map[string]Parameter{"wal_level": {Value: "logical"}},
+			"config validation failed: unknown parameters for family postgres16: wal_level"),
+		Entry("static parameter applied immediately",
+			map[string]Parameter{"shared_buffers": {Value: "32768", ApplyMethod: ApplyImmediate}},
+			"config validation failed: static parameters need applyMethod pending-reboot: shared_buffers"),
+		Entry("invalid apply method",
+			map[string]Parameter{"work_mem": {Value: "8192", ApplyMethod: "later"}},
+			"config validation failed: invalid applyMethod \"later\" for parameter work_mem: must be immediate or pending-reboot"),
+	)
+
+	It("should refuse to change the family of an existing group", func() {
+		_, err := applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		spec.Family = "postgres17"
+		result, err := applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(Equal("config validation failed: family cannot be changed from postgres16 to postgres17"))
+	})
+
+	It("should plan parameter changes without calling mutating APIs in dry-run mode", func() {
+		result, err := applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		status := result.Status
+
+		groupDryRun = awsclient.NewDryRun(awsfake.NewComponents(name), true)
+		DeferCleanup(func() { groupDryRun = nil })
+
+		spec.Parameters = map[string]Parameter{"log_min_duration_statement": {Value: "1000"}}
+		result, err = applyAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan.Changes).To(Equal([]awsclient.Change{
+			{Field: "parameters.log_min_duration_statement", Action: awsclient.ChangeModify, Current: "-1", Desired: "1000"},
+			{Field: "parameters.work_mem", Action: awsclient.ChangeRemove, Current: "8192"},
+		}))
+		Expect(fake.CallCount("ModifyDBParameterGroup")).To(Equal(1))
+		Expect(fake.CallCount("ResetDBParameterGroup")).To(BeZero())
+
+		checked, err := checkApplied(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(checked.Details).To(Equal("Dry run: would update " + arn + " (2 changes)"))
+	})
+
+	It("should report and remediate parameter drift", func() {
+		recorder := record.NewFakeRecorder(10)
+		groupEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { groupEvents = nil })
+
+		result, err := applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Created RDS parameter group " + arn)))
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal Updated ")))
+		status := result.Status
+
+		health, err := checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		healthy, _ := controller.HealthCheckHealthy("Parameter group orders-postgres matches config")
+		Expect(health).To(Equal(healthy))
+
+		By("detecting a parameter changed outside the provider")
+		_, err = fake.ModifyDBParameterGroup(ctx, &rds.ModifyDBParameterGroupInput{
+			DBParameterGroupName: aws.String("orders-postgres"),
+			Parameters: []types.Parameter{{
+				ParameterName: aws.String("work_mem"), ParameterValue: aws.String("1024"), ApplyMethod: types.ApplyMethodImmediate}},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		message := "Parameters work_mem of RDS parameter group orders-postgres differ from config"
+		health, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("ParameterDrift", message)
+		Expect(health).To(Equal(degraded))
+		Expect(recorder.Events).To(Receive(Equal("Warning DriftDetected " + message)))
+
+		By("restoring the configured value when remediation is enabled")
+		spec.RemediateDrift = true
+		health, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health.Healthy).To(BeTrue())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal DriftRemediated " + message)))
+		Expect(userParameters()).To(Equal(map[string]string{"work_mem": "8192"}))
+
+		By("recreating a deleted group")
+		Expect(deleteParameterGroup(ctx, fake, "orders-postgres")).To(Succeed())
+		health, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health.Healthy).To(BeTrue())
+		Expect(userParameters()).To(Equal(map[string]string{"work_mem": "8192"}))
+	})
+
+	It("should only take over a group it does not own when adoption allows it", func() {
+		groupOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { groupOwnership = nil })
+
+		_, err := createParameterGroup(ctx, fake, &RdsParameterGroupConfig{
+			GroupName: "orders-postgres", Family: "postgres16", Description: "legacy"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Details).To(HavePrefix("cannot manage parameter group " + arn + ": resource has no ownership tags"))
+		Expect(userParameters()).To(BeEmpty())
+
+		spec.AdoptionPolicy = awsclient.AdoptionIfUntagged
+		_, err = applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		tags, err := getGroupTags(ctx, fake, arn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tags).To(HaveKeyWithValue(awsclient.TagComponent, "default/orders-postgres"))
+		Expect(userParameters()).To(Equal(map[string]string{"work_mem": "8192"}))
+	})
+
+	It("should keep a retained group and remove its ownership tags", func() {
+		groupOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { groupOwnership = nil })
+
+		result, err := applyAction(ctx, name, spec, RdsParameterGroupStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		spec.DeletionPolicy = awsclient.DeletionPolicyRetain
+		deleted, err := deleteAction(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deleted.Details).To(Equal("Parameter group orders-postgres retained (Retain policy)"))
+		Expect(fake.CallCount("DeleteDBParameterGroup")).To(BeZero())
+
+		tags, err := getGroupTags(ctx, fake, arn)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tags).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ReadinessProbe", func() {
+	It("should make one cheap read with the controller's own credentials", func() {
+		fake := awsfake.NewRDS()
+		groupClients = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceRDS,
+			func(aws.Config) API { return fake })
+
+		Expect(ReadinessProbe(context.Background())).To(Succeed())
+		Expect(fake.Calls()).To(Equal([]string{"DescribeDBParameterGroups"}))
+
+		fake.FailNext("DescribeDBParameterGroups", errors.New("AccessDenied"))
+		Expect(ReadinessProbe(context.Background())).To(MatchError("AccessDenied"))
+	})
+})
@@ -0,0 +1,92 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+)
+
+// Option customizes how the rds-parameter-group provider is registered
+type Option func(*options)
+
+type options struct {
+	awsclient.ProviderOptions
+
+	client API
+}
+
+// defaultOptions returns the settings used when no options are given.
+// Zero timings keep the framework defaults.
+func defaultOptions() options {
+	return options{ProviderOptions: awsclient.NewProviderOptions(DefaultProviderName, controller.ErrorClassifier(isRetryable))}
+}
+
+// WithProviderName sets the unique name used for Component claiming.
+// An empty name keeps the default "rds-parameter-group". For setkit embedding, use a
+// prefixed name (e.g., "orders-rds-parameter-group") to avoid conflicts with other providers.
+func WithProviderName(name string) Option {
+	return awsclient.WithProviderName[*options](name)
+}
+
+// WithAWSConfig uses a prebuilt AWS config instead of loading the default credential chain.
+// Per-Component region and role overrides, as well as endpoint overrides, are still applied on top of it.
+func WithAWSConfig(cfg aws.Config) Option {
+	return awsclient.WithAWSConfig[*options](cfg)
+}
+
+// WithEndpoints redirects AWS calls to a local emulator such as LocalStack
+func WithEndpoints(endpoints awsclient.Endpoints) Option {
+	return awsclient.WithEndpoints[*options](endpoints)
+}
+
+// WithClient uses the given client for all AWS calls.
+// The client is shared by every Component, so per-Component region and role
+// overrides are only recorded in status; the client decides where calls go.
+func WithClient(client API) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithDryRun plans every Component instead of applying it, as if all carried
+// the awsclient.DryRunAnnotation. No mutating RDS API is called.
+func WithDryRun(enabled bool) Option {
+	return awsclient.WithDryRun[*options](enabled)
+}
+
+// WithClusterID sets the cluster identity stamped into the ownership tags of created resources,
+// so that providers in different clusters never manage each other's resources (default "default")
+func WithClusterID(id string) Option {
+	return awsclient.WithClusterID[*options](id)
+}
+
+// WithErrorClassifier decides which AWS errors are retried (true) and which fail the Component (false).
+// The default retries the errors the AWS SDK considers retryable.
+func WithErrorClassifier(classifier controller.ErrorClassifier) Option {
+	return awsclient.WithErrorClassifier[*options](classifier)
+}
+
+// WithHealthCheckInterval sets how often Ready parameter groups are checked for drift (default: framework default)
+func WithHealthCheckInterval(d time.Duration) Option {
+	return awsclient.WithHealthCheckInterval[*options](d)
+}
+
+// WithErrorRequeue sets the requeue delay after retryable errors (default: framework default)
+func WithErrorRequeue(d time.Duration) Option {
+	return awsclient.WithErrorRequeue[*options](d)
+}
+
+// WithDefaultRequeue sets the default requeue delay (default: framework default)
+func WithDefaultRequeue(d time.Duration) Option {
+	return awsclient.WithDefaultRequeue[*options](d)
+}
+
+// WithStatusCheckRequeue sets the delay between progress checks (default: framework default)
+func WithStatusCheckRequeue(d time.Duration) Option {
+	return awsclient.WithStatusCheckRequeue[*options](d)
+}
@@ -0,0 +1,36 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
+
+// Package-level dry-run switch initialized during registration; nil never plans
+var groupDryRun *awsclient.DryRun
+
+// planGroup computes what applyAction would change on the group.
+// A nil group plans its creation with all configured parameters.
+func planGroup(spec *RdsParameterGroupConfig, group *types.DBParameterGroup, changes []parameterChange) *awsclient.Plan {
+	if group == nil {
+		plan := awsclient.NewPlan(spec.GroupName, false)
+		plan.Add("family", spec.Family)
+		for _, parameterName := range sortedNames(spec.Parameters) {
+			plan.Add("parameters."+parameterName, spec.Parameters[parameterName].Value)
+		}
+		return plan
+	}
+
+	plan := awsclient.NewPlan(aws.ToString(group.DBParameterGroupArn), true)
+	for _, change := range changes {
+		if change.reset {
+			plan.Remove("parameters."+change.name, change.current)
+			continue
+		}
+		plan.Modify("parameters."+change.name, change.current, change.desired)
+	}
+	return plan
+}
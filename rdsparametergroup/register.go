@@ -0,0 +1,108 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package rdsparametergroup
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	v1beta1 "github.com/rinswind/componator/api/v1beta1"
+	"github.com/rinswind/componator/componentkit/functional"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	DefaultProviderName = "rds-parameter-group"
+)
+
+// groupRegistration refuses a second Register, which would take over the package state of the first
+var groupRegistration awsclient.Registration
+
+// Register registers the rds-parameter-group Component provider with the controller manager.
+//
+// Without options the provider is claimed as "rds-parameter-group" and initializes AWS RDS clients
+// using the default credential chain (environment variables, EC2 instance metadata, etc.).
+// Components may override the region and assume a role in another account; one client
+// is cached per target. Embedding programs customize the name, AWS config or client,
+// timings and error classification through options.
+//
+// Provider names must be unique across all providers in the cluster. Multiple providers
+// with the same name will conflict during Component claiming.
+//
+// The provider keeps its clients, ownership and dry-run settings in package state, so a package
+// can only be registered once per process; a second Register fails.
+func Register(mgr ctrl.Manager, opts ...Option) error {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// Ensure required schemes are registered (safe to call multiple times)
+	if err := clientgoscheme.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+	if err := v1beta1.AddToScheme(mgr.GetScheme()); err != nil {
+		return err
+	}
+
+	// An injected client needs no credentials of its own
+	cfg := aws.Config{}
+	if o.client == nil || o.AWSConfig != nil {
+		var err error
+		if cfg, err = awsclient.BaseConfig(context.Background(), o.AWSConfig, o.Endpoints); err != nil {
+			return err
+		}
+	}
+
+	if err := groupRegistration.Claim(o.ProviderName); err != nil {
+		return err
+	}
+
+	groupClients = awsclient.NewCache(cfg, o.Endpoints, awsclient.ServiceRDS, func(cfg aws.Config) API {
+		if o.client != nil {
+			return o.client
+		}
+		return rds.NewFromConfig(cfg)
+	})
+	groupErrorClassifier = o.ErrorClassifier
+	groupEvents = awsclient.NewEventRecorder(mgr.GetClient(), mgr.GetEventRecorderFor(o.ProviderName))
+	groupDryRun = awsclient.NewDryRun(mgr.GetClient(), o.DryRun)
+	groupOwnership = awsclient.NewOwnership(o.ClusterID, o.ProviderName)
+
+	// Log client initialization
+	log := logf.Log.WithName("rds-parameter-group")
+	log.Info("Initialized AWS RDS parameter group client cache",
+		"defaultRegion", cfg.Region, "endpointOverride", o.Endpoints.IsSet(), "injectedClient", o.client != nil)
+
+	// Register with functional API
+	builder := functional.NewBuilder[RdsParameterGroupConfig, RdsParameterGroupStatus](o.ProviderName).
+		WithApply(applyAction).
+		WithApplyCheck(checkApplied).
+		WithDelete(deleteAction).
+		WithDeleteCheck(checkDeleted).
+		WithHealthCheck(checkHealth)
+
+	if o.HealthCheckInterval > 0 {
+		builder = builder.WithHealthCheckInterval(o.HealthCheckInterval)
+	}
+	if o.ErrorRequeue > 0 {
+		builder = builder.WithErrorRequeue(o.ErrorRequeue)
+	}
+	if o.DefaultRequeue > 0 {
+		builder = builder.WithDefaultRequeue(o.DefaultRequeue)
+	}
+	if o.StatusCheckRequeue > 0 {
+		builder = builder.WithStatusCheckRequeue(o.StatusCheckRequeue)
+	}
+
+	if err := builder.Register(mgr); err != nil {
+		groupRegistration.Release()
+		return err
+	}
+	return nil
+}
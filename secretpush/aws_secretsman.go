@@ -8,25 +8,51 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/controller"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// API is the subset of the AWS Secrets Manager API used by this provider.
+// It is satisfied by *secretsmanager.Client and by awsfake.SecretsManager in unit tests.
+// Embedding programs may supply their own implementation with WithClient.
+type API interface {
+	DescribeSecret(ctx context.Context, params *secretsmanager.DescribeSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DescribeSecretOutput, error)
+	CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+	UpdateSecret(ctx context.Context, params *secretsmanager.UpdateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretOutput, error)
+	DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+	RestoreSecret(ctx context.Context, params *secretsmanager.RestoreSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.RestoreSecretOutput, error)
+	UpdateSecretVersionStage(ctx context.Context, params *secretsmanager.UpdateSecretVersionStageInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UpdateSecretVersionStageOutput, error)
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+	GetRandomPassword(ctx context.Context, params *secretsmanager.GetRandomPasswordInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetRandomPasswordOutput, error)
+	ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error)
+	UntagResource(ctx context.Context, params *secretsmanager.UntagResourceInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.UntagResourceOutput, error)
+}
+
 // Package-level singletons initialized during registration
 var (
-	awsConfig aws.Config
-	smClient  *secretsmanager.Client
+	smClients   *awsclient.Cache[API]
+	smOwnership *awsclient.Ownership
 )
 
+// ReadinessProbe checks that the Secrets Manager API is reachable with the controller's own credentials
+func ReadinessProbe(ctx context.Context) error {
+	_, err := smClients.Get(awsclient.AccessConfig{}).ListSecrets(ctx, &secretsmanager.ListSecretsInput{
+		MaxResults: aws.Int32(1),
+	})
+	return err
+}
+
 // findSecret checks if the secret exists in AWS Secrets Manager
 // Returns: arn, name (empty strings if not found), error
-func findSecret(ctx context.Context, id string) (string, string, error) {
-	describeOutput, err := smClient.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+func findSecret(ctx context.Context, client API, id string) (string, string, error) {
+	describeOutput, err := client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
 		SecretId: aws.String(id),
 	})
 
@@ -42,10 +68,103 @@ func findSecret(ctx context.Context, id string) (string, string, error) {
 	return "", "", fmt.Errorf("failed to check secret existence: %w", err)
 }
 
+// describeSecret returns the secret metadata, or nil if the secret does not exist
+func describeSecret(ctx context.Context, client API, id string) (*secretsmanager.DescribeSecretOutput, error) {
+	output, err := client.DescribeSecret(ctx, &secretsmanager.DescribeSecretInput{
+		SecretId: aws.String(id),
+	})
+	if err == nil {
+		return output, nil
+	}
+
+	var notFoundErr *types.ResourceNotFoundException
+	if errors.As(err, &notFoundErr) {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("failed to describe secret: %w", err)
+}
+
+// currentVersionId returns the version carrying the AWSCURRENT stage
+func currentVersionId(secret *secretsmanager.DescribeSecretOutput) string {
+	for versionId, stages := range secret.VersionIdsToStages {
+		if slices.Contains(stages, "AWSCURRENT") {
+			return versionId
+		}
+	}
+	return ""
+}
+
+// restoreSecret cancels the scheduled deletion of the secret
+func restoreSecret(ctx context.Context, client API, secretArn string) error {
+	if _, err := client.RestoreSecret(ctx, &secretsmanager.RestoreSecretInput{
+		SecretId: aws.String(secretArn),
+	}); err != nil {
+		return fmt.Errorf("failed to restore secret: %w", err)
+	}
+
+	logf.FromContext(ctx).Info("Restored secret scheduled for deletion", "secretArn", secretArn)
+	return nil
+}
+
+// untagSecret removes tags from the secret
+func untagSecret(ctx context.Context, client API, secretArn string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := client.UntagResource(ctx, &secretsmanager.UntagResourceInput{
+		SecretId: aws.String(secretArn),
+		TagKeys:  keys,
+	})
+	if err != nil {
+		var notFoundErr *types.ResourceNotFoundException
+		if errors.As(err, &notFoundErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to untag secret: %w", err)
+	}
+	return nil
+}
+
+// fromSMTags converts Secrets Manager tags to a map
+func fromSMTags(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+// moveCurrentVersion moves the AWSCURRENT stage from one version of the secret to another
+func moveCurrentVersion(ctx context.Context, client API, secretArn, fromVersionId, toVersionId string) error {
+	if _, err := client.UpdateSecretVersionStage(ctx, &secretsmanager.UpdateSecretVersionStageInput{
+		SecretId:            aws.String(secretArn),
+		VersionStage:        aws.String("AWSCURRENT"),
+		RemoveFromVersionId: aws.String(fromVersionId),
+		MoveToVersionId:     aws.String(toVersionId),
+	}); err != nil {
+		return fmt.Errorf("failed to move AWSCURRENT to version %s: %w", toVersionId, err)
+	}
+
+	logf.FromContext(ctx).Info("Moved AWSCURRENT stage", "secretArn", secretArn, "from", fromVersionId, "to", toVersionId)
+	return nil
+}
+
+// getSecretValue reads the current value of the secret
+func getSecretValue(ctx context.Context, client API, secretArn string) (string, error) {
+	output, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretArn),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret value: %w", err)
+	}
+	return aws.ToString(output.SecretString), nil
+}
+
 // createSecret creates a new secret in AWS Secrets Manager
 // Returns: secretArn, versionId, error
 func createSecret(
-	ctx context.Context, name string, value map[string]string, tags map[string]string, kmsKeyId string) (string, string, error) {
+	ctx context.Context, client API, name string, value map[string]string, tags map[string]string, kmsKeyId string) (string, string, error) {
 
 	log := logf.FromContext(ctx)
 
@@ -69,7 +188,7 @@ func createSecret(
 		createInput.KmsKeyId = aws.String(kmsKeyId)
 	}
 
-	createOutput, err := smClient.CreateSecret(ctx, createInput)
+	createOutput, err := client.CreateSecret(ctx, createInput)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to create secret: %w", err)
 	}
@@ -83,7 +202,7 @@ func createSecret(
 
 // updateSecret updates an existing secret in AWS Secrets Manager
 // Returns: secretArn, versionId, error
-func updateSecret(ctx context.Context, name string, value map[string]string) (string, string, error) {
+func updateSecret(ctx context.Context, client API, name string, value map[string]string) (string, string, error) {
 	log := logf.FromContext(ctx)
 
 	valueJSON, err := json.Marshal(value)
@@ -91,7 +210,7 @@ func updateSecret(ctx context.Context, name string, value map[string]string) (st
 		return "", "", fmt.Errorf("failed to marshal secret data: %s", err)
 	}
 
-	updateOutput, err := smClient.UpdateSecret(ctx, &secretsmanager.UpdateSecretInput{
+	updateOutput, err := client.UpdateSecret(ctx, &secretsmanager.UpdateSecretInput{
 		SecretId:     aws.String(name),
 		SecretString: aws.String(string(valueJSON)),
 	})
@@ -108,7 +227,7 @@ func updateSecret(ctx context.Context, name string, value map[string]string) (st
 }
 
 // deleteSecret deletes the secret from AWS Secrets Manager
-func deleteSecret(ctx context.Context, secretArn string) error {
+func deleteSecret(ctx context.Context, client API, secretArn string) error {
 	log := logf.FromContext(ctx)
 
 	deleteInput := &secretsmanager.DeleteSecretInput{
@@ -116,7 +235,7 @@ func deleteSecret(ctx context.Context, secretArn string) error {
 		ForceDeleteWithoutRecovery: aws.Bool(true),
 	}
 
-	_, err := smClient.DeleteSecret(ctx, deleteInput)
+	_, err := client.DeleteSecret(ctx, deleteInput)
 	if err == nil {
 		log.Info("Successfully deleted secret", "secretArn", secretArn)
 		return nil
@@ -132,8 +251,8 @@ func deleteSecret(ctx context.Context, secretArn string) error {
 }
 
 // Generate via AWS GetRandomPassword
-func getRandomPassword(ctx context.Context, spec *GeneratorSpec) (string, error) {
-	result, err := smClient.GetRandomPassword(ctx, &secretsmanager.GetRandomPasswordInput{
+func getRandomPassword(ctx context.Context, client API, spec *GeneratorSpec) (string, error) {
+	result, err := client.GetRandomPassword(ctx, &secretsmanager.GetRandomPasswordInput{
 		PasswordLength:          aws.Int64(spec.PasswordLength),
 		RequireEachIncludedType: aws.Bool(spec.RequireEachIncludedType),
 		ExcludePunctuation:      aws.Bool(spec.ExcludePunctuation),
@@ -152,6 +271,7 @@ func getRandomPassword(ctx context.Context, spec *GeneratorSpec) (string, error)
 }
 
 // awsErrorClassifier wraps the AWS SDK retry logic for use with result builder utilities.
+// Register replaces it when an embedding program supplies WithErrorClassifier.
 var awsErrorClassifier = controller.ErrorClassifier(isRetryable)
 
 // isRetryable determines if an error is retryable using AWS SDK's built-in error classification.
@@ -3,7 +3,11 @@
 
 package secretpush
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+)
 
 const (
 	// UpdatePolicy values
@@ -11,8 +15,8 @@ const (
 	UpdatePolicyAlwaysUpdate = "AlwaysUpdate"
 
 	// DeletionPolicy values
-	DeletionPolicyDelete = "Delete"
-	DeletionPolicyRetain = "Retain"
+	DeletionPolicyDelete = awsclient.DeletionPolicyDelete
+	DeletionPolicyRetain = awsclient.DeletionPolicyRetain
 
 	// Default values
 	DefaultUpdatePolicy   = UpdatePolicyIfNotExists
@@ -22,6 +26,9 @@ const (
 // SecretPushSpec represents the configuration structure for secret-push components
 // that gets unmarshaled from Component.Spec.Config
 type SecretPushSpec struct {
+	// AccessConfig optionally selects the AWS region and a role to assume in another account
+	awsclient.AccessConfig
+
 	// SecretName is the name/path of the secret in AWS Secrets Manager
 	SecretName string `json:"secretName"`
 
@@ -36,8 +43,13 @@ type SecretPushSpec struct {
 	UpdatePolicy string `json:"updatePolicy,omitempty"`
 
 	// DeletionPolicy controls whether to delete secrets on Component deletion
-	// Valid values: DeletionPolicyDelete (default), DeletionPolicyRetain
+	// Valid values: DeletionPolicyDelete (default), DeletionPolicyRetain, which keeps the
+	// secret and removes its ownership tags
 	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+
+	// RemediateDrift makes health checks restore a secret scheduled for deletion and move
+	// AWSCURRENT back to the version written by the provider. By default drift is only reported as Degraded.
+	RemediateDrift bool `json:"remediateDrift,omitempty"`
 }
 
 // FieldSpec defines a single field in the secret
@@ -68,6 +80,13 @@ type SecretPushStatus struct {
 	Region       string `json:"region,omitempty"`
 	LastSyncTime string `json:"lastSyncTime,omitempty"`
 	FieldCount   int    `json:"fieldCount,omitempty"`
+
+	// Access is the AWS target the secret was created in
+	Access *awsclient.AccessConfig `json:"access,omitempty"`
+
+	// Plan lists the changes a dry-run apply would make; it is cleared by a real apply.
+	// Secret values are never included.
+	Plan *awsclient.Plan `json:"plan,omitempty"`
 }
 
 // resolveSpec validates config and applies defaults in-place
@@ -80,6 +99,11 @@ func resolveSpec(config *SecretPushSpec) error {
 		return fmt.Errorf("at least one field is required")
 	}
 
+	// Validate AWS access settings
+	if err := config.AccessConfig.Validate(); err != nil {
+		return err
+	}
+
 	// Validate each field has EXACTLY ONE of value or generator
 	for fieldName, fieldSpec := range config.Fields {
 		hasValue := fieldSpec.Value != ""
@@ -120,6 +144,15 @@ func resolveSpec(config *SecretPushSpec) error {
 	return nil
 }
 
+// resolveAccess returns the AWS target the secret was created in.
+// Falls back to the config for secrets that have not been created yet.
+func resolveAccess(spec SecretPushSpec, status SecretPushStatus) awsclient.AccessConfig {
+	if status.Access != nil {
+		return *status.Access
+	}
+	return smClients.Resolve(spec.AccessConfig)
+}
+
 // resolveEnumValue validates a string field against allowed values and applies default if empty
 func resolveEnumValue(value, fieldName string, defaultValue string, validValues ...string) (string, error) {
 	if value == "" {
@@ -0,0 +1,90 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package secretpush
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator/componentkit/controller"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// checkHealth detects out-of-band changes to pushed secrets.
+// This is called periodically while the Component is in Ready state.
+//
+// Drift is reported as Degraded:
+//   - SecretDeleted: the secret no longer exists or is scheduled for deletion
+//   - SecretVersionChanged: AWSCURRENT no longer points at the version written by the provider
+//
+// With remediateDrift set, a scheduled deletion is cancelled and AWSCURRENT is moved back.
+// A secret that is gone cannot be restored by the health check; it needs a new apply,
+// which generates new passwords.
+func checkHealth(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	spec SecretPushSpec,
+	status SecretPushStatus) (*controller.HealthCheckResult, error) {
+
+	// A dry-run Component does not manage the secret yet, and a secret that was never pushed is missing
+	if status.Plan != nil {
+		if status.Plan.Action == awsclient.PlanCreate {
+			return controller.HealthCheckDegraded("DryRun", "Dry run: "+status.Plan.String())
+		}
+		return controller.HealthCheckHealthy("Dry run: " + status.Plan.String())
+	}
+
+	secretId := status.SecretArn
+	if secretId == "" {
+		secretId = spec.SecretName
+	}
+
+	log := logf.FromContext(ctx).WithValues("secretName", spec.SecretName)
+	client := smClients.Get(resolveAccess(spec, status))
+	drift := driftReporter(ctx, name, spec.RemediateDrift)
+
+	secret, err := describeSecret(ctx, client, secretId)
+	if err != nil {
+		return healthError(ctx, name, err)
+	}
+
+	if secret == nil {
+		return drift.Report(ctx, name, "SecretDeleted",
+			fmt.Sprintf("Secret %s not found in AWS", spec.SecretName), nil)
+	}
+
+	if secret.DeletedDate != nil {
+		return drift.Report(ctx, name, "SecretDeleted",
+			fmt.Sprintf("Secret %s is scheduled for deletion", spec.SecretName),
+			func() error {
+				return restoreSecret(ctx, client, aws.ToString(secret.ARN))
+			})
+	}
+
+	currentVersion := currentVersionId(secret)
+	if status.VersionId != "" && currentVersion != status.VersionId {
+		return drift.Report(ctx, name, "SecretVersionChanged",
+			fmt.Sprintf("AWSCURRENT of secret %s moved from version %s to %s", spec.SecretName, status.VersionId, currentVersion),
+			func() error {
+				return moveCurrentVersion(ctx, client, aws.ToString(secret.ARN), currentVersion, status.VersionId)
+			})
+	}
+
+	log.V(1).Info("Secret matches config", "versionId", currentVersion)
+	return controller.HealthCheckHealthy(fmt.Sprintf("Secret %s is current (version %s)", spec.SecretName, currentVersion))
+}
+
+// driftReporter reports drift on the named Component. Remediation is disabled while
+// the Component is in dry-run mode, since it would change AWS.
+func driftReporter(ctx context.Context, name k8stypes.NamespacedName, remediate bool) *awsclient.DriftReporter {
+	if remediate {
+		if dryRun, err := smDryRun.Enabled(ctx, name); err != nil || dryRun {
+			remediate = false
+		}
+	}
+	return awsclient.NewDriftReporter(smEvents, remediate)
+}
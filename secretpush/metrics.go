@@ -0,0 +1,28 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package secretpush
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Apply outcomes reported by secretApplies
+const (
+	outcomeCreated = "created"
+	outcomeUpdated = "updated"
+	outcomeSkipped = "skipped"
+)
+
+// secretApplies counts successful applies by update policy and what happened to the secret
+var secretApplies = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: awsclient.MetricsNamespace,
+	Name:      "secretpush_applies_total",
+	Help:      "Successful secret-push applies by update policy and outcome (created, updated, skipped).",
+}, []string{"update_policy", "outcome"})
+
+func init() {
+	metrics.Registry.MustRegister(secretApplies)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	"github.com/rinswind/componator/componentkit/functional"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -24,34 +25,65 @@ func applyAction(
 		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
 	}
 
+	// The secret cannot follow a region or account change
+	access := smClients.Resolve(spec.AccessConfig)
+	if err := access.CheckUnchanged(status.Access); err != nil {
+		return functional.ActionFailure(status, fmt.Sprintf("config validation failed: %v", err))
+	}
+
+	dryRun, err := smDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+
 	log := logf.FromContext(ctx).WithValues("secretName", spec.SecretName)
-	log.Info("Starting secret-push deployment")
+	log.Info("Starting secret-push deployment", "dryRun", dryRun)
+
+	client := smClients.Get(access)
+
+	// In dry-run mode only record what would change, without generating passwords
+	if dryRun {
+		existingArn, _, err := findSecret(ctx, client, spec.SecretName)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		plan, err := planSecret(ctx, client, &spec, existingArn)
+		if err != nil {
+			return actionError(ctx, name, status, err)
+		}
+		status.Plan = plan
+		log.Info("Dry run, not applying changes", "plan", plan.String())
+		return functional.ActionSuccess(status, "Dry run: "+plan.String())
+	}
+	status.Plan = nil
 
 	// Build secret data: generate passwords and combine with static fields
-	secretData, generatedCount, staticCount, err := buildSecretData(ctx, spec)
+	secretData, generatedCount, staticCount, err := buildSecretData(ctx, client, spec)
 	if err != nil {
-		return functional.ActionResultForError(status, err, awsErrorClassifier)
+		return actionError(ctx, name, status, err)
 	}
 
 	// Check if secret exists
-	existingArn, _, err := findSecret(ctx, spec.SecretName)
+	existingArn, _, err := findSecret(ctx, client, spec.SecretName)
 	if err != nil {
-		return functional.ActionResultForError(status, err, awsErrorClassifier)
+		return actionError(ctx, name, status, err)
 	}
 
 	var secretArn, versionId string
-	var details string
+	var details, outcome string
 
 	if existingArn == "" {
 		// Create new secret
 		tags := buildSecretTags(name)
-		secretArn, versionId, err = createSecret(ctx, spec.SecretName, secretData, tags, spec.KmsKeyId)
+		secretArn, versionId, err = createSecret(ctx, client, spec.SecretName, secretData, tags, spec.KmsKeyId)
 		if err != nil {
-			return functional.ActionResultForError(status, err, awsErrorClassifier)
+			return actionError(ctx, name, status, err)
 		}
 
 		details = fmt.Sprintf("Created secret %s with %d fields (%d generated, %d static)",
 			spec.SecretName, generatedCount+staticCount, generatedCount, staticCount)
+		outcome = outcomeCreated
+		smEvents.Normal(ctx, name, awsclient.ReasonCreated, "Created secret %s", secretArn)
 	} else if spec.UpdatePolicy == UpdatePolicyIfNotExists {
 		// Secret exists and update policy is IfNotExists - skip update
 		log.Info("Secret exists and updatePolicy is IfNotExists, skipping update")
@@ -59,24 +91,30 @@ func applyAction(
 
 		// Keep existing versionId empty - we're not modifying the secret
 		details = fmt.Sprintf("Secret %s exists, update skipped (IfNotExists policy)", spec.SecretName)
+		outcome = outcomeSkipped
 	} else {
 		// Update existing secret
-		secretArn, versionId, err = updateSecret(ctx, spec.SecretName, secretData)
+		secretArn, versionId, err = updateSecret(ctx, client, spec.SecretName, secretData)
 		if err != nil {
-			return functional.ActionResultForError(status, err, awsErrorClassifier)
+			return actionError(ctx, name, status, err)
 		}
 
 		details = fmt.Sprintf("Updated secret %s with %d fields (%d generated, %d static)",
 			spec.SecretName, generatedCount+staticCount, generatedCount, staticCount)
+		outcome = outcomeUpdated
+		smEvents.Normal(ctx, name, awsclient.ReasonUpdated, "Updated secret %s (version %s)", secretArn, versionId)
 	}
 
 	// Update status
 	status.SecretArn = secretArn
 	status.SecretName = spec.SecretName
 	status.VersionId = versionId
-	status.Region = awsConfig.Region
+	status.Region = access.Region
+	status.Access = &access
 	status.FieldCount = len(spec.Fields)
 
+	secretApplies.WithLabelValues(spec.UpdatePolicy, outcome).Inc()
+
 	return functional.ActionSuccess(status, details)
 }
 
@@ -95,29 +133,78 @@ func deleteAction(
 		return functional.ActionSuccess(status, "No secret to delete")
 	}
 
+	retain := spec.DeletionPolicy == DeletionPolicyRetain
+
+	dryRun, err := smDryRun.Enabled(ctx, name)
+	if err != nil {
+		return functional.ActionResultForError(status, fmt.Errorf("dry-run check failed: %w", err), awsclient.RetryDryRun)
+	}
+	if dryRun {
+		status.Plan = awsclient.NewDeletePlan(status.SecretArn)
+		if retain {
+			status.Plan = awsclient.NewRetainPlan(status.SecretArn)
+		}
+		log.Info("Dry run, holding back deletion of secret", "plan", status.Plan.String())
+		return functional.ActionResultForError(status, awsclient.DeletionHeld(status.Plan), awsclient.RetryDryRun)
+	}
+
+	client := smClients.Get(resolveAccess(spec, status))
+
 	// Check deletion policy
-	if spec.DeletionPolicy == DeletionPolicyRetain {
-		log.Info("DeletionPolicy is Retain, skipping secret deletion", "secretArn", status.SecretArn)
-		details := fmt.Sprintf("Secret %s retained (Retain policy)", spec.SecretName)
-		return functional.ActionSuccess(status, details)
+	if retain {
+		return retainSecret(ctx, name, client, spec, status)
 	}
 
 	log.Info("Starting secret deletion")
 
 	// Delete secret from AWS
-	err := deleteSecret(ctx, status.SecretArn)
-	if err != nil {
-		return functional.ActionResultForError(status, err, awsErrorClassifier)
+	if err := deleteSecret(ctx, client, status.SecretArn); err != nil {
+		return actionError(ctx, name, status, err)
 	}
 
+	smEvents.Normal(ctx, name, awsclient.ReasonDeleted, "Deleted secret %s", status.SecretArn)
+
 	// Status remains unchanged (no update needed for deletion)
 	details := fmt.Sprintf("Deleted secret %s", spec.SecretName)
 	return functional.ActionSuccess(status, details)
 }
 
+// retainSecret keeps the secret in AWS and removes the ownership tags and markers,
+// so that another Component can adopt it
+func retainSecret(
+	ctx context.Context,
+	name k8stypes.NamespacedName,
+	client API,
+	spec SecretPushSpec,
+	status SecretPushStatus) (*functional.ActionResult[SecretPushStatus], error) {
+
+	log := logf.FromContext(ctx).WithValues("secretName", spec.SecretName, "secretArn", status.SecretArn)
+
+	secret, err := describeSecret(ctx, client, status.SecretArn)
+	if err != nil {
+		return actionError(ctx, name, status, err)
+	}
+
+	// Tags of a secret taken over by another Component since are left alone
+	if secret != nil && smOwnership.Owns(name, fromSMTags(secret.Tags)) {
+		keys := append(smOwnership.TagKeys(), "managed-by", "component")
+		if err := untagSecret(ctx, client, status.SecretArn, keys); err != nil {
+			return actionError(ctx, name, status, err)
+		}
+	}
+
+	log.Info("DeletionPolicy is Retain, skipping secret deletion")
+	smEvents.Normal(ctx, name, awsclient.ReasonRetained, "Retained secret %s", status.SecretArn)
+
+	details := fmt.Sprintf("Secret %s retained (Retain policy)", spec.SecretName)
+	return functional.ActionSuccess(status, details)
+}
+
 // buildSecretData generates passwords and combines with static fields
 // Returns a flat map suitable for JSON marshaling, plus counts of generated and static fields
-func buildSecretData(ctx context.Context, spec SecretPushSpec) (map[string]string, int, int, error) {
+func buildSecretData(
+	ctx context.Context, client API, spec SecretPushSpec) (map[string]string, int, int, error) {
+
 	log := logf.FromContext(ctx)
 
 	var generatedCount, staticCount int
@@ -133,7 +220,7 @@ func buildSecretData(ctx context.Context, spec SecretPushSpec) (map[string]strin
 			log.V(1).Info("Added static field", "field", fieldName)
 		} else if fieldSpec.Generator != nil {
 			// Generate via AWS GetRandomPassword
-			password, err := getRandomPassword(ctx, fieldSpec.Generator)
+			password, err := getRandomPassword(ctx, client, fieldSpec.Generator)
 			if err != nil {
 				return nil, 0, 0, fmt.Errorf("failed to generate password for field %s: %w", fieldName, err)
 			}
@@ -150,8 +237,8 @@ func buildSecretData(ctx context.Context, spec SecretPushSpec) (map[string]strin
 }
 
 func buildSecretTags(name k8stypes.NamespacedName) map[string]string {
-	return map[string]string{
+	return smOwnership.WithTags(name, map[string]string{
 		"managed-by": "componator",
 		"component":  name.Namespace + "/" + name.Name,
-	}
+	})
 }
@@ -0,0 +1,257 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package secretpush
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	"github.com/rinswind/componator-aws-providers/awsfake"
+	"github.com/rinswind/componator/componentkit/controller"
+)
+
+func TestSecretPush(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Secret Push Suite")
+}
+
+var _ = Describe("Secret Push Operations", func() {
+	var (
+		ctx  context.Context
+		fake *awsfake.SecretsManager
+		name k8stypes.NamespacedName
+		spec SecretPushSpec
+	)
+
+	secretValue := func() map[string]string {
+		secret := fake.Secret("app/db")
+		Expect(secret).NotTo(BeNil())
+
+		var value map[string]string
+		Expect(json.Unmarshal([]byte(secret.Value), &value)).To(Succeed())
+		return value
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		fake = awsfake.NewSecretsManager()
+		smClients = awsclient.NewCache(aws.Config{Region: awsfake.Region}, awsclient.Endpoints{}, awsclient.ServiceSecretsManager, func(aws.Config) API { return fake })
+
+		name = k8stypes.NamespacedName{Namespace: "default", Name: "app-secret"}
+		spec = SecretPushSpec{
+			SecretName: "app/db",
+			Fields: map[string]FieldSpec{
+				"username": {Value: "app"},
+				"password": {Generator: &GeneratorSpec{PasswordLength: 24}},
+			},
+		}
+	})
+
+	It("should create a secret with static and generated fields", func() {
+		_, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		value := secretValue()
+		Expect(value).To(HaveKeyWithValue("username", "app"))
+		Expect(value["password"]).To(HaveLen(24))
+		Expect(fake.Secret("app/db").Tags).To(HaveKeyWithValue("component", "default/app-secret"))
+	})
+
+	It("should leave an existing secret alone with IfNotExists", func() {
+		_, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		versionId := fake.Secret("app/db").VersionId
+
+		spec.Fields["username"] = FieldSpec{Value: "changed"}
+		_, err = applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fake.Secret("app/db").VersionId).To(Equal(versionId))
+		Expect(secretValue()).To(HaveKeyWithValue("username", "app"))
+		Expect(fake.CallCount("UpdateSecret")).To(BeZero())
+	})
+
+	It("should overwrite an existing secret with AlwaysUpdate", func() {
+		_, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		versionId := fake.Secret("app/db").VersionId
+
+		spec.UpdatePolicy = UpdatePolicyAlwaysUpdate
+		spec.Fields["username"] = FieldSpec{Value: "changed"}
+		_, err = applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fake.Secret("app/db").VersionId).NotTo(Equal(versionId))
+		Expect(secretValue()).To(HaveKeyWithValue("username", "changed"))
+	})
+
+	It("should delete the secret unless retained", func() {
+		_, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		status := SecretPushStatus{SecretArn: fake.Secret("app/db").ARN}
+
+		spec.DeletionPolicy = DeletionPolicyRetain
+		_, err = deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Secret("app/db")).NotTo(BeNil())
+
+		spec.DeletionPolicy = DeletionPolicyDelete
+		_, err = deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Secret("app/db")).To(BeNil())
+
+		Expect(deleteSecret(ctx, fake, status.SecretArn)).To(Succeed())
+	})
+
+	It("should remove ownership tags and markers from a retained secret", func() {
+		smOwnership = awsclient.NewOwnership("test", DefaultProviderName)
+		DeferCleanup(func() { smOwnership = nil })
+
+		_, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		status := SecretPushStatus{SecretArn: fake.Secret("app/db").ARN}
+		Expect(fake.Secret("app/db").Tags).To(HaveKeyWithValue(awsclient.TagComponent, "default/app-secret"))
+
+		spec.DeletionPolicy = DeletionPolicyRetain
+		_, err = deleteAction(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fake.Secret("app/db")).NotTo(BeNil())
+		Expect(fake.Secret("app/db").Tags).To(BeEmpty())
+		Expect(fake.CallCount("DeleteSecret")).To(BeZero())
+	})
+
+	It("should record events for created, updated and deleted secrets", func() {
+		recorder := record.NewFakeRecorder(10)
+		smEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { smEvents = nil })
+
+		_, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		secretArn := fake.Secret("app/db").ARN
+		Expect(recorder.Events).To(Receive(Equal("Normal Created Created secret " + secretArn)))
+
+		_, err = applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).NotTo(Receive())
+
+		spec.UpdatePolicy = UpdatePolicyAlwaysUpdate
+		_, err = applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(HavePrefix("Normal Updated Updated secret " + secretArn)))
+
+		_, err = deleteAction(ctx, name, spec, SecretPushStatus{SecretArn: secretArn})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive(Equal("Normal Deleted Deleted secret " + secretArn)))
+	})
+
+	It("should plan field changes without values in dry-run mode", func() {
+		_, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		secret := fake.Secret("app/db")
+
+		smDryRun = awsclient.NewDryRun(awsfake.NewComponents(name), true)
+		DeferCleanup(func() { smDryRun = nil })
+
+		spec.UpdatePolicy = UpdatePolicyAlwaysUpdate
+		spec.Fields["host"] = FieldSpec{Value: "db.local"}
+		spec.Fields["username"] = FieldSpec{Value: "changed"}
+		delete(spec.Fields, "password")
+		result, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.Plan).To(Equal(&awsclient.Plan{
+			Action:   awsclient.PlanUpdate,
+			Resource: secret.ARN,
+			Changes: []awsclient.Change{
+				{Field: "host", Action: awsclient.ChangeAdd},
+				{Field: "username", Action: awsclient.ChangeModify},
+				{Field: "password", Action: awsclient.ChangeRemove},
+			},
+		}))
+		Expect(fake.Secret("app/db").VersionId).To(Equal(secret.VersionId))
+		Expect(fake.CallCount("UpdateSecret")).To(BeZero())
+		Expect(fake.CallCount("GetRandomPassword")).To(Equal(1))
+	})
+
+	It("should report and remediate secret drift", func() {
+		recorder := record.NewFakeRecorder(10)
+		smEvents = awsclient.NewEventRecorder(awsfake.NewComponents(name), recorder)
+		DeferCleanup(func() { smEvents = nil })
+
+		result, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorder.Events).To(Receive())
+		status := result.Status
+		written := fake.Secret("app/db").Value
+
+		health, err := checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health.Healthy).To(BeTrue())
+
+		By("detecting a value written outside the provider")
+		fake.PutValue("app/db", `{"username":"intruder"}`)
+		moved := fake.Secret("app/db").VersionId
+
+		message := "AWSCURRENT of secret app/db moved from version " + status.VersionId + " to " + moved
+		health, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ := controller.HealthCheckDegraded("SecretVersionChanged", message)
+		Expect(health).To(Equal(degraded))
+		Expect(recorder.Events).To(Receive(Equal("Warning DriftDetected " + message)))
+
+		By("moving AWSCURRENT back when remediation is enabled")
+		spec.RemediateDrift = true
+		health, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health.Healthy).To(BeTrue())
+		Expect(fake.Secret("app/db").VersionId).To(Equal(status.VersionId))
+		Expect(fake.Secret("app/db").Value).To(Equal(written))
+
+		By("restoring a secret scheduled for deletion")
+		_, err = fake.DeleteSecret(ctx, &secretsmanager.DeleteSecretInput{SecretId: aws.String(status.SecretArn)})
+		Expect(err).NotTo(HaveOccurred())
+		health, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health.Healthy).To(BeTrue())
+		Expect(fake.Secret("app/db").ScheduledForDeletion).To(BeFalse())
+
+		By("reporting a deleted secret without remediation")
+		Expect(deleteSecret(ctx, fake, status.SecretArn)).To(Succeed())
+		health, err = checkHealth(ctx, name, spec, status)
+		Expect(err).NotTo(HaveOccurred())
+		degraded, _ = controller.HealthCheckDegraded("SecretDeleted", "Secret app/db not found in AWS")
+		Expect(health).To(Equal(degraded))
+	})
+
+	It("should not check the version of a secret it did not write", func() {
+		spec.UpdatePolicy = UpdatePolicyIfNotExists
+		_, _, err := createSecret(ctx, fake, "app/db", map[string]string{"username": "existing"}, nil, "")
+		Expect(err).NotTo(HaveOccurred())
+		result, err := applyAction(ctx, name, spec, SecretPushStatus{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Status.VersionId).To(BeEmpty())
+
+		fake.PutValue("app/db", `{"username":"rotated"}`)
+		health, err := checkHealth(ctx, name, spec, result.Status)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(health.Healthy).To(BeTrue())
+	})
+
+	It("should surface AWS errors from create", func() {
+		fake.FailNext("CreateSecret", errors.New("boom"))
+
+		_, _, err := createSecret(ctx, fake, "app/db", map[string]string{"k": "v"}, nil, "")
+		Expect(err).To(MatchError(ContainSubstring("boom")))
+		Expect(fake.Secret("app/db")).To(BeNil())
+	})
+})
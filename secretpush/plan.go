@@ -0,0 +1,67 @@
+// Copyright 2025.
+// SPDX-License-Identifier: Apache-2.0
+
+package secretpush
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/rinswind/componator-aws-providers/awsclient"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Package-level dry-run switch initialized during registration; nil never plans
+var smDryRun *awsclient.DryRun
+
+// planSecret computes which secret fields applyAction would add, change or remove.
+// Only field names are recorded; secret values never leave Secrets Manager.
+// An empty secretArn plans the creation of the secret.
+func planSecret(ctx context.Context, client API, spec *SecretPushSpec, secretArn string) (*awsclient.Plan, error) {
+	if secretArn == "" {
+		plan := awsclient.NewPlan(spec.SecretName, false)
+		for _, field := range slices.Sorted(maps.Keys(spec.Fields)) {
+			plan.Add(field, "")
+		}
+		return plan, nil
+	}
+
+	plan := awsclient.NewPlan(secretArn, true)
+	if spec.UpdatePolicy == UpdatePolicyIfNotExists {
+		return plan, nil
+	}
+
+	value, err := getSecretValue(ctx, client, secretArn)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &current); err != nil {
+		// The whole value is replaced by the configured fields
+		logf.FromContext(ctx).Info("Current secret value is not a JSON object, planning all fields as new")
+		current = map[string]string{}
+	}
+
+	for _, field := range slices.Sorted(maps.Keys(spec.Fields)) {
+		currentValue, exists := current[field]
+		fieldSpec := spec.Fields[field]
+		switch {
+		case !exists:
+			plan.Add(field, "")
+		case fieldSpec.Generator != nil:
+			// Generated fields get a new password on every update
+			plan.Modify(field, "", "")
+		case currentValue != fieldSpec.Value:
+			plan.Modify(field, "", "")
+		}
+	}
+	for _, field := range slices.Sorted(maps.Keys(current)) {
+		if _, desired := spec.Fields[field]; !desired {
+			plan.Remove(field, "")
+		}
+	}
+	return plan, nil
+}
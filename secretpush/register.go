@@ -5,10 +5,10 @@ package secretpush
 
 import (
 	"context"
-	"fmt"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/rinswind/componator-aws-providers/awsclient"
 	v1beta1 "github.com/rinswind/componator/api/v1beta1"
 	"github.com/rinswind/componator/componentkit/functional"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -20,18 +20,28 @@ const (
 	DefaultProviderName = "secret-push"
 )
 
+// smRegistration refuses a second Register, which would take over the package state of the first
+var smRegistration awsclient.Registration
+
 // Register registers the secret-push Component provider with the controller manager.
 //
-// The providerName parameter specifies the unique name used for Component claiming.
-// Pass empty string to use the default "secret-push". For setkit embedding, use a
-// prefixed name (e.g., "wordpress-secret-push") to avoid conflicts with other providers.
+// Without options the provider is claimed as "secret-push" and initializes AWS Secrets Manager clients
+// using the default credential chain (environment variables, EC2 instance metadata, etc.).
+// Components may override the region and assume a role in another account; one client
+// is cached per target. Embedding programs customize the name, AWS config or client,
+// timings and error classification through options.
 //
 // Provider names must be unique across all providers in the cluster. Multiple providers
 // with the same name will conflict during Component claiming.
 //
-// Initializes AWS Secrets Manager client using the default credential chain
-// (environment variables, EC2 instance metadata, etc.).
-func Register(mgr ctrl.Manager, providerName string) error {
+// The provider keeps its clients, ownership and dry-run settings in package state, so a package
+// can only be registered once per process; a second Register fails.
+func Register(mgr ctrl.Manager, opts ...Option) error {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	// Ensure required schemes are registered (safe to call multiple times)
 	if err := clientgoscheme.AddToScheme(mgr.GetScheme()); err != nil {
 		return err
@@ -40,33 +50,54 @@ func Register(mgr ctrl.Manager, providerName string) error {
 		return err
 	}
 
-	// Use default provider name if not specified
-	if providerName == "" {
-		providerName = DefaultProviderName
+	// An injected client needs no credentials of its own
+	cfg := aws.Config{}
+	if o.client == nil || o.AWSConfig != nil {
+		var err error
+		if cfg, err = awsclient.BaseConfig(context.Background(), o.AWSConfig, o.Endpoints); err != nil {
+			return err
+		}
 	}
 
-	// Load AWS config with default chain (uses AWS_REGION, EC2 metadata, etc.)
-	// Disable retries - controller handles requeue
-	// Use WithEC2IMDSRegion to auto-detect region from EC2 metadata when in EKS
-	cfg, err := awsconfig.LoadDefaultConfig(
-		context.Background(),
-		awsconfig.WithRetryMaxAttempts(1),
-		awsconfig.WithEC2IMDSRegion(),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to load AWS configuration: %w", err)
+	if err := smRegistration.Claim(o.ProviderName); err != nil {
+		return err
 	}
 
-	awsConfig = cfg
-	smClient = secretsmanager.NewFromConfig(cfg)
+	smClients = awsclient.NewCache(cfg, o.Endpoints, awsclient.ServiceSecretsManager, func(cfg aws.Config) API {
+		if o.client != nil {
+			return o.client
+		}
+		return secretsmanager.NewFromConfig(cfg)
+	})
+	awsErrorClassifier = o.ErrorClassifier
+	smEvents = awsclient.NewEventRecorder(mgr.GetClient(), mgr.GetEventRecorderFor(o.ProviderName))
+	smDryRun = awsclient.NewDryRun(mgr.GetClient(), o.DryRun)
+	smOwnership = awsclient.NewOwnership(o.ClusterID, o.ProviderName)
 
 	// Log client initialization
 	log := logf.Log.WithName("secret-push")
-	log.Info("Initialized AWS Secrets Manager client", "region", cfg.Region)
+	log.Info("Initialized AWS Secrets Manager client cache",
+		"defaultRegion", cfg.Region, "endpointOverride", o.Endpoints.IsSet(), "injectedClient", o.client != nil)
 
 	// Register with functional API (immediate operations - no progress checks)
-	return functional.NewBuilder[SecretPushSpec, SecretPushStatus](providerName).
+	builder := functional.NewBuilder[SecretPushSpec, SecretPushStatus](o.ProviderName).
 		WithApply(applyAction).
 		WithDelete(deleteAction).
-		Register(mgr)
+		WithHealthCheck(checkHealth)
+
+	if o.HealthCheckInterval > 0 {
+		builder = builder.WithHealthCheckInterval(o.HealthCheckInterval)
+	}
+	if o.ErrorRequeue > 0 {
+		builder = builder.WithErrorRequeue(o.ErrorRequeue)
+	}
+	if o.DefaultRequeue > 0 {
+		builder = builder.WithDefaultRequeue(o.DefaultRequeue)
+	}
+
+	if err := builder.Register(mgr); err != nil {
+		smRegistration.Release()
+		return err
+	}
+	return nil
 }